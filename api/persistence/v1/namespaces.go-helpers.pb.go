@@ -116,6 +116,154 @@ func (this *NamespaceConfig) Equal(that interface{}) bool {
 	return proto.Equal(this, that1)
 }
 
+// Marshal an object of type WorkflowRuleUpdate to the protobuf v3 wire format
+func (val *WorkflowRuleUpdate) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type WorkflowRuleUpdate from the protobuf v3 wire format
+func (val *WorkflowRuleUpdate) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *WorkflowRuleUpdate) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two WorkflowRuleUpdate values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *WorkflowRuleUpdate) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *WorkflowRuleUpdate
+	switch t := that.(type) {
+	case *WorkflowRuleUpdate:
+		that1 = t
+	case WorkflowRuleUpdate:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type AsyncUpdateOverride to the protobuf v3 wire format
+func (val *AsyncUpdateOverride) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type AsyncUpdateOverride from the protobuf v3 wire format
+func (val *AsyncUpdateOverride) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *AsyncUpdateOverride) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two AsyncUpdateOverride values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *AsyncUpdateOverride) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *AsyncUpdateOverride
+	switch t := that.(type) {
+	case *AsyncUpdateOverride:
+		that1 = t
+	case AsyncUpdateOverride:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type ReplicationWorkflowTypeFilter to the protobuf v3 wire format
+func (val *ReplicationWorkflowTypeFilter) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type ReplicationWorkflowTypeFilter from the protobuf v3 wire format
+func (val *ReplicationWorkflowTypeFilter) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *ReplicationWorkflowTypeFilter) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two ReplicationWorkflowTypeFilter values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *ReplicationWorkflowTypeFilter) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *ReplicationWorkflowTypeFilter
+	switch t := that.(type) {
+	case *ReplicationWorkflowTypeFilter:
+		that1 = t
+	case ReplicationWorkflowTypeFilter:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type ArchivalBatchConfig to the protobuf v3 wire format
+func (val *ArchivalBatchConfig) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type ArchivalBatchConfig from the protobuf v3 wire format
+func (val *ArchivalBatchConfig) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *ArchivalBatchConfig) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two ArchivalBatchConfig values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *ArchivalBatchConfig) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *ArchivalBatchConfig
+	switch t := that.(type) {
+	case *ArchivalBatchConfig:
+		that1 = t
+	case ArchivalBatchConfig:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
 // Marshal an object of type NamespaceReplicationConfig to the protobuf v3 wire format
 func (val *NamespaceReplicationConfig) Marshal() ([]byte, error) {
 	return proto.Marshal(val)
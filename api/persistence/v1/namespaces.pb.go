@@ -215,8 +215,28 @@ type NamespaceConfig struct {
 	VisibilityArchivalUri        string                       `protobuf:"bytes,7,opt,name=visibility_archival_uri,json=visibilityArchivalUri,proto3" json:"visibility_archival_uri,omitempty"`
 	CustomSearchAttributeAliases map[string]string            `protobuf:"bytes,8,rep,name=custom_search_attribute_aliases,json=customSearchAttributeAliases,proto3" json:"custom_search_attribute_aliases,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	WorkflowRules                map[string]*v12.WorkflowRule `protobuf:"bytes,9,rep,name=workflow_rules,json=workflowRules,proto3" json:"workflow_rules,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields                protoimpl.UnknownFields
-	sizeCache                    protoimpl.SizeCache
+	ArchivalBatchConfig          *ArchivalBatchConfig         `protobuf:"bytes,10,opt,name=archival_batch_config,json=archivalBatchConfig,proto3" json:"archival_batch_config,omitempty"`
+	// Name of the visibility store used by this namespace. Empty means the cluster default.
+	VisibilityStore string `protobuf:"bytes,11,opt,name=visibility_store,json=visibilityStore,proto3" json:"visibility_store,omitempty"`
+	// Version of the config schema the config was last written with. Zero means the config
+	// predates schema versioning.
+	SchemaVersion                 int32                          `protobuf:"varint,12,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	ReplicationWorkflowTypeFilter *ReplicationWorkflowTypeFilter `protobuf:"bytes,13,opt,name=replication_workflow_type_filter,json=replicationWorkflowTypeFilter,proto3" json:"replication_workflow_type_filter,omitempty"`
+	// Maximum number of concurrently open workflows in the namespace. Zero means unlimited.
+	MaxConcurrentOpenWorkflows int64 `protobuf:"varint,14,opt,name=max_concurrent_open_workflows,json=maxConcurrentOpenWorkflows,proto3" json:"max_concurrent_open_workflows,omitempty"`
+	// Overrides the cluster default for accepting workflow updates asynchronously. Unset means the
+	// cluster default applies.
+	AsyncUpdateOverride *AsyncUpdateOverride `protobuf:"bytes,15,opt,name=async_update_override,json=asyncUpdateOverride,proto3" json:"async_update_override,omitempty"`
+	// Last updates of the rules in workflow_rules which were changed after their creation, keyed by rule ID.
+	WorkflowRuleUpdates map[string]*WorkflowRuleUpdate `protobuf:"bytes,16,rep,name=workflow_rule_updates,json=workflowRuleUpdates,proto3" json:"workflow_rule_updates,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Maximum rate of signals and queries per second to workflows in the namespace. Zero means unlimited.
+	MaxSignalRps float64 `protobuf:"fixed64,17,opt,name=max_signal_rps,json=maxSignalRps,proto3" json:"max_signal_rps,omitempty"`
+	MaxQueryRps  float64 `protobuf:"fixed64,18,opt,name=max_query_rps,json=maxQueryRps,proto3" json:"max_query_rps,omitempty"`
+	// Times of the recent config changes of the namespace, oldest first, checked by the namespace config change
+	// rate limit. They are updated together with the change, so the limit holds across frontend hosts.
+	ConfigChangeTimes []*timestamppb.Timestamp `protobuf:"bytes,19,rep,name=config_change_times,json=configChangeTimes,proto3" json:"config_change_times,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *NamespaceConfig) Reset() {
@@ -312,19 +332,297 @@ func (x *NamespaceConfig) GetWorkflowRules() map[string]*v12.WorkflowRule {
 	return nil
 }
 
+func (x *NamespaceConfig) GetArchivalBatchConfig() *ArchivalBatchConfig {
+	if x != nil {
+		return x.ArchivalBatchConfig
+	}
+	return nil
+}
+
+func (x *NamespaceConfig) GetVisibilityStore() string {
+	if x != nil {
+		return x.VisibilityStore
+	}
+	return ""
+}
+
+func (x *NamespaceConfig) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *NamespaceConfig) GetReplicationWorkflowTypeFilter() *ReplicationWorkflowTypeFilter {
+	if x != nil {
+		return x.ReplicationWorkflowTypeFilter
+	}
+	return nil
+}
+
+func (x *NamespaceConfig) GetMaxConcurrentOpenWorkflows() int64 {
+	if x != nil {
+		return x.MaxConcurrentOpenWorkflows
+	}
+	return 0
+}
+
+func (x *NamespaceConfig) GetAsyncUpdateOverride() *AsyncUpdateOverride {
+	if x != nil {
+		return x.AsyncUpdateOverride
+	}
+	return nil
+}
+
+func (x *NamespaceConfig) GetWorkflowRuleUpdates() map[string]*WorkflowRuleUpdate {
+	if x != nil {
+		return x.WorkflowRuleUpdates
+	}
+	return nil
+}
+
+func (x *NamespaceConfig) GetMaxSignalRps() float64 {
+	if x != nil {
+		return x.MaxSignalRps
+	}
+	return 0
+}
+
+func (x *NamespaceConfig) GetMaxQueryRps() float64 {
+	if x != nil {
+		return x.MaxQueryRps
+	}
+	return 0
+}
+
+func (x *NamespaceConfig) GetConfigChangeTimes() []*timestamppb.Timestamp {
+	if x != nil {
+		return x.ConfigChangeTimes
+	}
+	return nil
+}
+
+// The last update of a workflow rule, which the public WorkflowRule message has no fields for.
+type WorkflowRuleUpdate struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	LastUpdateTime        *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=last_update_time,json=lastUpdateTime,proto3" json:"last_update_time,omitempty"`
+	LastUpdatedByIdentity string                 `protobuf:"bytes,2,opt,name=last_updated_by_identity,json=lastUpdatedByIdentity,proto3" json:"last_updated_by_identity,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *WorkflowRuleUpdate) Reset() {
+	*x = WorkflowRuleUpdate{}
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowRuleUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowRuleUpdate) ProtoMessage() {}
+
+func (x *WorkflowRuleUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowRuleUpdate.ProtoReflect.Descriptor instead.
+func (*WorkflowRuleUpdate) Descriptor() ([]byte, []int) {
+	return file_temporal_server_api_persistence_v1_namespaces_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WorkflowRuleUpdate) GetLastUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdateTime
+	}
+	return nil
+}
+
+func (x *WorkflowRuleUpdate) GetLastUpdatedByIdentity() string {
+	if x != nil {
+		return x.LastUpdatedByIdentity
+	}
+	return ""
+}
+
+type AsyncUpdateOverride struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AsyncUpdateOverride) Reset() {
+	*x = AsyncUpdateOverride{}
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AsyncUpdateOverride) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AsyncUpdateOverride) ProtoMessage() {}
+
+func (x *AsyncUpdateOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AsyncUpdateOverride.ProtoReflect.Descriptor instead.
+func (*AsyncUpdateOverride) Descriptor() ([]byte, []int) {
+	return file_temporal_server_api_persistence_v1_namespaces_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AsyncUpdateOverride) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+// Selects the workflows of a namespace which are force replicated to other clusters by workflow type.
+// If included_workflow_types is set, only workflows of these types are replicated. Workflows of a type
+// in excluded_workflow_types are never replicated, even if the type is also included.
+type ReplicationWorkflowTypeFilter struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	IncludedWorkflowTypes []string               `protobuf:"bytes,1,rep,name=included_workflow_types,json=includedWorkflowTypes,proto3" json:"included_workflow_types,omitempty"`
+	ExcludedWorkflowTypes []string               `protobuf:"bytes,2,rep,name=excluded_workflow_types,json=excludedWorkflowTypes,proto3" json:"excluded_workflow_types,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ReplicationWorkflowTypeFilter) Reset() {
+	*x = ReplicationWorkflowTypeFilter{}
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicationWorkflowTypeFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicationWorkflowTypeFilter) ProtoMessage() {}
+
+func (x *ReplicationWorkflowTypeFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicationWorkflowTypeFilter.ProtoReflect.Descriptor instead.
+func (*ReplicationWorkflowTypeFilter) Descriptor() ([]byte, []int) {
+	return file_temporal_server_api_persistence_v1_namespaces_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReplicationWorkflowTypeFilter) GetIncludedWorkflowTypes() []string {
+	if x != nil {
+		return x.IncludedWorkflowTypes
+	}
+	return nil
+}
+
+func (x *ReplicationWorkflowTypeFilter) GetExcludedWorkflowTypes() []string {
+	if x != nil {
+		return x.ExcludedWorkflowTypes
+	}
+	return nil
+}
+
+// Per-namespace tuning for the archival worker. Unset fields fall back to the cluster defaults.
+type ArchivalBatchConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchSize     int32                  `protobuf:"varint,1,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	FlushInterval *durationpb.Duration   `protobuf:"bytes,2,opt,name=flush_interval,json=flushInterval,proto3" json:"flush_interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchivalBatchConfig) Reset() {
+	*x = ArchivalBatchConfig{}
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchivalBatchConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivalBatchConfig) ProtoMessage() {}
+
+func (x *ArchivalBatchConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivalBatchConfig.ProtoReflect.Descriptor instead.
+func (*ArchivalBatchConfig) Descriptor() ([]byte, []int) {
+	return file_temporal_server_api_persistence_v1_namespaces_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ArchivalBatchConfig) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *ArchivalBatchConfig) GetFlushInterval() *durationpb.Duration {
+	if x != nil {
+		return x.FlushInterval
+	}
+	return nil
+}
+
 type NamespaceReplicationConfig struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	ActiveClusterName string                 `protobuf:"bytes,1,opt,name=active_cluster_name,json=activeClusterName,proto3" json:"active_cluster_name,omitempty"`
 	Clusters          []string               `protobuf:"bytes,2,rep,name=clusters,proto3" json:"clusters,omitempty"`
 	State             v1.ReplicationState    `protobuf:"varint,3,opt,name=state,proto3,enum=temporal.api.enums.v1.ReplicationState" json:"state,omitempty"`
 	FailoverHistory   []*FailoverStatus      `protobuf:"bytes,8,rep,name=failover_history,json=failoverHistory,proto3" json:"failover_history,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// All failovers of the namespace ordered by failover version, unlike failover_history which only keeps the
+	// most recent ones. The oldest entries are dropped beyond a size which bounds the namespace record size.
+	FailoverLog   []*FailoverStatus `protobuf:"bytes,9,rep,name=failover_log,json=failoverLog,proto3" json:"failover_log,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *NamespaceReplicationConfig) Reset() {
 	*x = NamespaceReplicationConfig{}
-	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[3]
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -336,7 +634,7 @@ func (x *NamespaceReplicationConfig) String() string {
 func (*NamespaceReplicationConfig) ProtoMessage() {}
 
 func (x *NamespaceReplicationConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[3]
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -349,7 +647,7 @@ func (x *NamespaceReplicationConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NamespaceReplicationConfig.ProtoReflect.Descriptor instead.
 func (*NamespaceReplicationConfig) Descriptor() ([]byte, []int) {
-	return file_temporal_server_api_persistence_v1_namespaces_proto_rawDescGZIP(), []int{3}
+	return file_temporal_server_api_persistence_v1_namespaces_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *NamespaceReplicationConfig) GetActiveClusterName() string {
@@ -380,6 +678,13 @@ func (x *NamespaceReplicationConfig) GetFailoverHistory() []*FailoverStatus {
 	return nil
 }
 
+func (x *NamespaceReplicationConfig) GetFailoverLog() []*FailoverStatus {
+	if x != nil {
+		return x.FailoverLog
+	}
+	return nil
+}
+
 // Represents a historical replication status of a Namespace
 type FailoverStatus struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -391,7 +696,7 @@ type FailoverStatus struct {
 
 func (x *FailoverStatus) Reset() {
 	*x = FailoverStatus{}
-	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[4]
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -403,7 +708,7 @@ func (x *FailoverStatus) String() string {
 func (*FailoverStatus) ProtoMessage() {}
 
 func (x *FailoverStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[4]
+	mi := &file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -416,7 +721,7 @@ func (x *FailoverStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FailoverStatus.ProtoReflect.Descriptor instead.
 func (*FailoverStatus) Descriptor() ([]byte, []int) {
-	return file_temporal_server_api_persistence_v1_namespaces_proto_rawDescGZIP(), []int{4}
+	return file_temporal_server_api_persistence_v1_namespaces_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *FailoverStatus) GetFailoverTime() *timestamppb.Timestamp {
@@ -455,7 +760,7 @@ const file_temporal_server_api_persistence_v1_namespaces_proto_rawDesc = "" +
 	"\x04data\x18\x06 \x03(\v2;.temporal.server.api.persistence.v1.NamespaceInfo.DataEntryR\x04data\x1a7\n" +
 	"\tDataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xac\a\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc1\x0e\n" +
 	"\x0fNamespaceConfig\x127\n" +
 	"\tretention\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\tretention\x12'\n" +
 	"\x0farchival_bucket\x18\x02 \x01(\tR\x0earchivalBucket\x12I\n" +
@@ -465,18 +770,45 @@ const file_temporal_server_api_persistence_v1_namespaces_proto_rawDesc = "" +
 	"\x19visibility_archival_state\x18\x06 \x01(\x0e2$.temporal.api.enums.v1.ArchivalStateR\x17visibilityArchivalState\x126\n" +
 	"\x17visibility_archival_uri\x18\a \x01(\tR\x15visibilityArchivalUri\x12\x9c\x01\n" +
 	"\x1fcustom_search_attribute_aliases\x18\b \x03(\v2U.temporal.server.api.persistence.v1.NamespaceConfig.CustomSearchAttributeAliasesEntryR\x1ccustomSearchAttributeAliases\x12m\n" +
-	"\x0eworkflow_rules\x18\t \x03(\v2F.temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRulesEntryR\rworkflowRules\x1aO\n" +
+	"\x0eworkflow_rules\x18\t \x03(\v2F.temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRulesEntryR\rworkflowRules\x12k\n" +
+	"\x15archival_batch_config\x18\n" +
+	" \x01(\v27.temporal.server.api.persistence.v1.ArchivalBatchConfigR\x13archivalBatchConfig\x12)\n" +
+	"\x10visibility_store\x18\v \x01(\tR\x0fvisibilityStore\x12%\n" +
+	"\x0eschema_version\x18\f \x01(\x05R\rschemaVersion\x12\x8a\x01\n" +
+	" replication_workflow_type_filter\x18\r \x01(\v2A.temporal.server.api.persistence.v1.ReplicationWorkflowTypeFilterR\x1dreplicationWorkflowTypeFilter\x12A\n" +
+	"\x1dmax_concurrent_open_workflows\x18\x0e \x01(\x03R\x1amaxConcurrentOpenWorkflows\x12k\n" +
+	"\x15async_update_override\x18\x0f \x01(\v27.temporal.server.api.persistence.v1.AsyncUpdateOverrideR\x13asyncUpdateOverride\x12\x80\x01\n" +
+	"\x15workflow_rule_updates\x18\x10 \x03(\v2L.temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRuleUpdatesEntryR\x13workflowRuleUpdates\x12$\n" +
+	"\x0emax_signal_rps\x18\x11 \x01(\x01R\fmaxSignalRps\x12\"\n" +
+	"\rmax_query_rps\x18\x12 \x01(\x01R\vmaxQueryRps\x12J\n" +
+	"\x13config_change_times\x18\x13 \x03(\v2\x1a.google.protobuf.TimestampR\x11configChangeTimes\x1aO\n" +
 	"!CustomSearchAttributeAliasesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1ae\n" +
 	"\x12WorkflowRulesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x129\n" +
-	"\x05value\x18\x02 \x01(\v2#.temporal.api.rules.v1.WorkflowRuleR\x05value:\x028\x01\"\x86\x02\n" +
+	"\x05value\x18\x02 \x01(\v2#.temporal.api.rules.v1.WorkflowRuleR\x05value:\x028\x01\x1a~\n" +
+	"\x18WorkflowRuleUpdatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12L\n" +
+	"\x05value\x18\x02 \x01(\v26.temporal.server.api.persistence.v1.WorkflowRuleUpdateR\x05value:\x028\x01\"\x93\x01\n" +
+	"\x12WorkflowRuleUpdate\x12D\n" +
+	"\x10last_update_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastUpdateTime\x127\n" +
+	"\x18last_updated_by_identity\x18\x02 \x01(\tR\x15lastUpdatedByIdentity\"/\n" +
+	"\x13AsyncUpdateOverride\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\"\x8f\x01\n" +
+	"\x1dReplicationWorkflowTypeFilter\x126\n" +
+	"\x17included_workflow_types\x18\x01 \x03(\tR\x15includedWorkflowTypes\x126\n" +
+	"\x17excluded_workflow_types\x18\x02 \x03(\tR\x15excludedWorkflowTypes\"v\n" +
+	"\x13ArchivalBatchConfig\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x01 \x01(\x05R\tbatchSize\x12@\n" +
+	"\x0eflush_interval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\rflushInterval\"\xdd\x02\n" +
 	"\x1aNamespaceReplicationConfig\x12.\n" +
 	"\x13active_cluster_name\x18\x01 \x01(\tR\x11activeClusterName\x12\x1a\n" +
 	"\bclusters\x18\x02 \x03(\tR\bclusters\x12=\n" +
 	"\x05state\x18\x03 \x01(\x0e2'.temporal.api.enums.v1.ReplicationStateR\x05state\x12]\n" +
-	"\x10failover_history\x18\b \x03(\v22.temporal.server.api.persistence.v1.FailoverStatusR\x0ffailoverHistory\"|\n" +
+	"\x10failover_history\x18\b \x03(\v22.temporal.server.api.persistence.v1.FailoverStatusR\x0ffailoverHistory\x12U\n" +
+	"\ffailover_log\x18\t \x03(\v22.temporal.server.api.persistence.v1.FailoverStatusR\vfailoverLog\"|\n" +
 	"\x0eFailoverStatus\x12?\n" +
 	"\rfailover_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ffailoverTime\x12)\n" +
 	"\x10failover_version\x18\x02 \x01(\x03R\x0ffailoverVersionB6Z4go.temporal.io/server/api/persistence/v1;persistenceb\x06proto3"
@@ -493,46 +825,60 @@ func file_temporal_server_api_persistence_v1_namespaces_proto_rawDescGZIP() []by
 	return file_temporal_server_api_persistence_v1_namespaces_proto_rawDescData
 }
 
-var file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_temporal_server_api_persistence_v1_namespaces_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_temporal_server_api_persistence_v1_namespaces_proto_goTypes = []any{
-	(*NamespaceDetail)(nil),            // 0: temporal.server.api.persistence.v1.NamespaceDetail
-	(*NamespaceInfo)(nil),              // 1: temporal.server.api.persistence.v1.NamespaceInfo
-	(*NamespaceConfig)(nil),            // 2: temporal.server.api.persistence.v1.NamespaceConfig
-	(*NamespaceReplicationConfig)(nil), // 3: temporal.server.api.persistence.v1.NamespaceReplicationConfig
-	(*FailoverStatus)(nil),             // 4: temporal.server.api.persistence.v1.FailoverStatus
-	nil,                                // 5: temporal.server.api.persistence.v1.NamespaceInfo.DataEntry
-	nil,                                // 6: temporal.server.api.persistence.v1.NamespaceConfig.CustomSearchAttributeAliasesEntry
-	nil,                                // 7: temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRulesEntry
-	(*timestamppb.Timestamp)(nil),      // 8: google.protobuf.Timestamp
-	(v1.NamespaceState)(0),             // 9: temporal.api.enums.v1.NamespaceState
-	(*durationpb.Duration)(nil),        // 10: google.protobuf.Duration
-	(*v11.BadBinaries)(nil),            // 11: temporal.api.namespace.v1.BadBinaries
-	(v1.ArchivalState)(0),              // 12: temporal.api.enums.v1.ArchivalState
-	(v1.ReplicationState)(0),           // 13: temporal.api.enums.v1.ReplicationState
-	(*v12.WorkflowRule)(nil),           // 14: temporal.api.rules.v1.WorkflowRule
+	(*NamespaceDetail)(nil),               // 0: temporal.server.api.persistence.v1.NamespaceDetail
+	(*NamespaceInfo)(nil),                 // 1: temporal.server.api.persistence.v1.NamespaceInfo
+	(*NamespaceConfig)(nil),               // 2: temporal.server.api.persistence.v1.NamespaceConfig
+	(*WorkflowRuleUpdate)(nil),            // 3: temporal.server.api.persistence.v1.WorkflowRuleUpdate
+	(*AsyncUpdateOverride)(nil),           // 4: temporal.server.api.persistence.v1.AsyncUpdateOverride
+	(*ReplicationWorkflowTypeFilter)(nil), // 5: temporal.server.api.persistence.v1.ReplicationWorkflowTypeFilter
+	(*ArchivalBatchConfig)(nil),           // 6: temporal.server.api.persistence.v1.ArchivalBatchConfig
+	(*NamespaceReplicationConfig)(nil),    // 7: temporal.server.api.persistence.v1.NamespaceReplicationConfig
+	(*FailoverStatus)(nil),                // 8: temporal.server.api.persistence.v1.FailoverStatus
+	nil,                                   // 9: temporal.server.api.persistence.v1.NamespaceInfo.DataEntry
+	nil,                                   // 10: temporal.server.api.persistence.v1.NamespaceConfig.CustomSearchAttributeAliasesEntry
+	nil,                                   // 11: temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRulesEntry
+	nil,                                   // 12: temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRuleUpdatesEntry
+	(*timestamppb.Timestamp)(nil),         // 13: google.protobuf.Timestamp
+	(v1.NamespaceState)(0),                // 14: temporal.api.enums.v1.NamespaceState
+	(*durationpb.Duration)(nil),           // 15: google.protobuf.Duration
+	(*v11.BadBinaries)(nil),               // 16: temporal.api.namespace.v1.BadBinaries
+	(v1.ArchivalState)(0),                 // 17: temporal.api.enums.v1.ArchivalState
+	(v1.ReplicationState)(0),              // 18: temporal.api.enums.v1.ReplicationState
+	(*v12.WorkflowRule)(nil),              // 19: temporal.api.rules.v1.WorkflowRule
 }
 var file_temporal_server_api_persistence_v1_namespaces_proto_depIdxs = []int32{
 	1,  // 0: temporal.server.api.persistence.v1.NamespaceDetail.info:type_name -> temporal.server.api.persistence.v1.NamespaceInfo
 	2,  // 1: temporal.server.api.persistence.v1.NamespaceDetail.config:type_name -> temporal.server.api.persistence.v1.NamespaceConfig
-	3,  // 2: temporal.server.api.persistence.v1.NamespaceDetail.replication_config:type_name -> temporal.server.api.persistence.v1.NamespaceReplicationConfig
-	8,  // 3: temporal.server.api.persistence.v1.NamespaceDetail.failover_end_time:type_name -> google.protobuf.Timestamp
-	9,  // 4: temporal.server.api.persistence.v1.NamespaceInfo.state:type_name -> temporal.api.enums.v1.NamespaceState
-	5,  // 5: temporal.server.api.persistence.v1.NamespaceInfo.data:type_name -> temporal.server.api.persistence.v1.NamespaceInfo.DataEntry
-	10, // 6: temporal.server.api.persistence.v1.NamespaceConfig.retention:type_name -> google.protobuf.Duration
-	11, // 7: temporal.server.api.persistence.v1.NamespaceConfig.bad_binaries:type_name -> temporal.api.namespace.v1.BadBinaries
-	12, // 8: temporal.server.api.persistence.v1.NamespaceConfig.history_archival_state:type_name -> temporal.api.enums.v1.ArchivalState
-	12, // 9: temporal.server.api.persistence.v1.NamespaceConfig.visibility_archival_state:type_name -> temporal.api.enums.v1.ArchivalState
-	6,  // 10: temporal.server.api.persistence.v1.NamespaceConfig.custom_search_attribute_aliases:type_name -> temporal.server.api.persistence.v1.NamespaceConfig.CustomSearchAttributeAliasesEntry
-	7,  // 11: temporal.server.api.persistence.v1.NamespaceConfig.workflow_rules:type_name -> temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRulesEntry
-	13, // 12: temporal.server.api.persistence.v1.NamespaceReplicationConfig.state:type_name -> temporal.api.enums.v1.ReplicationState
-	4,  // 13: temporal.server.api.persistence.v1.NamespaceReplicationConfig.failover_history:type_name -> temporal.server.api.persistence.v1.FailoverStatus
-	8,  // 14: temporal.server.api.persistence.v1.FailoverStatus.failover_time:type_name -> google.protobuf.Timestamp
-	14, // 15: temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRulesEntry.value:type_name -> temporal.api.rules.v1.WorkflowRule
-	16, // [16:16] is the sub-list for method output_type
-	16, // [16:16] is the sub-list for method input_type
-	16, // [16:16] is the sub-list for extension type_name
-	16, // [16:16] is the sub-list for extension extendee
-	0,  // [0:16] is the sub-list for field type_name
+	7,  // 2: temporal.server.api.persistence.v1.NamespaceDetail.replication_config:type_name -> temporal.server.api.persistence.v1.NamespaceReplicationConfig
+	13, // 3: temporal.server.api.persistence.v1.NamespaceDetail.failover_end_time:type_name -> google.protobuf.Timestamp
+	14, // 4: temporal.server.api.persistence.v1.NamespaceInfo.state:type_name -> temporal.api.enums.v1.NamespaceState
+	9,  // 5: temporal.server.api.persistence.v1.NamespaceInfo.data:type_name -> temporal.server.api.persistence.v1.NamespaceInfo.DataEntry
+	15, // 6: temporal.server.api.persistence.v1.NamespaceConfig.retention:type_name -> google.protobuf.Duration
+	16, // 7: temporal.server.api.persistence.v1.NamespaceConfig.bad_binaries:type_name -> temporal.api.namespace.v1.BadBinaries
+	17, // 8: temporal.server.api.persistence.v1.NamespaceConfig.history_archival_state:type_name -> temporal.api.enums.v1.ArchivalState
+	17, // 9: temporal.server.api.persistence.v1.NamespaceConfig.visibility_archival_state:type_name -> temporal.api.enums.v1.ArchivalState
+	10, // 10: temporal.server.api.persistence.v1.NamespaceConfig.custom_search_attribute_aliases:type_name -> temporal.server.api.persistence.v1.NamespaceConfig.CustomSearchAttributeAliasesEntry
+	11, // 11: temporal.server.api.persistence.v1.NamespaceConfig.workflow_rules:type_name -> temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRulesEntry
+	6,  // 12: temporal.server.api.persistence.v1.NamespaceConfig.archival_batch_config:type_name -> temporal.server.api.persistence.v1.ArchivalBatchConfig
+	5,  // 13: temporal.server.api.persistence.v1.NamespaceConfig.replication_workflow_type_filter:type_name -> temporal.server.api.persistence.v1.ReplicationWorkflowTypeFilter
+	4,  // 14: temporal.server.api.persistence.v1.NamespaceConfig.async_update_override:type_name -> temporal.server.api.persistence.v1.AsyncUpdateOverride
+	12, // 15: temporal.server.api.persistence.v1.NamespaceConfig.workflow_rule_updates:type_name -> temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRuleUpdatesEntry
+	13, // 16: temporal.server.api.persistence.v1.NamespaceConfig.config_change_times:type_name -> google.protobuf.Timestamp
+	13, // 17: temporal.server.api.persistence.v1.WorkflowRuleUpdate.last_update_time:type_name -> google.protobuf.Timestamp
+	15, // 18: temporal.server.api.persistence.v1.ArchivalBatchConfig.flush_interval:type_name -> google.protobuf.Duration
+	18, // 19: temporal.server.api.persistence.v1.NamespaceReplicationConfig.state:type_name -> temporal.api.enums.v1.ReplicationState
+	8,  // 20: temporal.server.api.persistence.v1.NamespaceReplicationConfig.failover_history:type_name -> temporal.server.api.persistence.v1.FailoverStatus
+	8,  // 21: temporal.server.api.persistence.v1.NamespaceReplicationConfig.failover_log:type_name -> temporal.server.api.persistence.v1.FailoverStatus
+	13, // 22: temporal.server.api.persistence.v1.FailoverStatus.failover_time:type_name -> google.protobuf.Timestamp
+	19, // 23: temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRulesEntry.value:type_name -> temporal.api.rules.v1.WorkflowRule
+	3,  // 24: temporal.server.api.persistence.v1.NamespaceConfig.WorkflowRuleUpdatesEntry.value:type_name -> temporal.server.api.persistence.v1.WorkflowRuleUpdate
+	25, // [25:25] is the sub-list for method output_type
+	25, // [25:25] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_temporal_server_api_persistence_v1_namespaces_proto_init() }
@@ -546,7 +892,7 @@ func file_temporal_server_api_persistence_v1_namespaces_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_temporal_server_api_persistence_v1_namespaces_proto_rawDesc), len(file_temporal_server_api_persistence_v1_namespaces_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
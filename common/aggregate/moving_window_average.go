@@ -8,11 +8,16 @@ import (
 type (
 	MovingWindowAverage interface {
 		Record(val int64)
+		// RecordWeighted records a value that counts as weight values in the average and the count.
+		// Non-positive weights are ignored.
+		RecordWeighted(val int64, weight int64)
 		Average() float64
+		Count() int64
 	}
 
 	timestampedData struct {
 		value     int64
+		weight    int64
 		timestamp time.Time
 	}
 
@@ -40,19 +45,27 @@ func NewMovingWindowAvgImpl(
 }
 
 func (a *MovingWindowAvgImpl) Record(val int64) {
+	a.RecordWeighted(val, 1)
+}
+
+func (a *MovingWindowAvgImpl) RecordWeighted(val int64, weight int64) {
+	if weight <= 0 {
+		return
+	}
+
 	a.Lock()
 	defer a.Unlock()
 
-	a.buffer[a.tailIdx] = timestampedData{timestamp: time.Now(), value: val}
+	a.buffer[a.tailIdx] = timestampedData{timestamp: time.Now(), value: val, weight: weight}
 	a.tailIdx = (a.tailIdx + 1) % a.maxBufferSize
 
-	a.sum += val
-	a.count++
+	a.sum += val * weight
+	a.count += weight
 
 	if a.tailIdx == a.headIdx {
 		// buffer full, expire oldest element
-		a.sum -= a.buffer[a.headIdx].value
-		a.count--
+		a.sum -= a.buffer[a.headIdx].value * a.buffer[a.headIdx].weight
+		a.count -= a.buffer[a.headIdx].weight
 		a.headIdx = (a.headIdx + 1) % a.maxBufferSize
 	}
 }
@@ -68,12 +81,21 @@ func (a *MovingWindowAvgImpl) Average() float64 {
 	return float64(a.sum) / float64(a.count)
 }
 
+// Count returns the number of values recorded within the window, counting weighted values by their weight.
+func (a *MovingWindowAvgImpl) Count() int64 {
+	a.Lock()
+	defer a.Unlock()
+
+	a.expireOldValuesLocked()
+	return a.count
+}
+
 func (a *MovingWindowAvgImpl) expireOldValuesLocked() {
 	for ; a.headIdx != a.tailIdx; a.headIdx = (a.headIdx + 1) % a.maxBufferSize {
 		if time.Since(a.buffer[a.headIdx].timestamp) < a.windowSize {
 			break
 		}
-		a.sum -= a.buffer[a.headIdx].value
-		a.count--
+		a.sum -= a.buffer[a.headIdx].value * a.buffer[a.headIdx].weight
+		a.count -= a.buffer[a.headIdx].weight
 	}
 }
@@ -0,0 +1,26 @@
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovingWindowAvgImpl_RecordWeighted(t *testing.T) {
+	avg := NewMovingWindowAvgImpl(time.Minute, 3)
+
+	avg.Record(10)
+	avg.RecordWeighted(40, 3)
+	require.InDelta(t, 32.5, avg.Average(), 0.001)
+	require.Equal(t, int64(4), avg.Count())
+
+	// non-positive weights are ignored
+	avg.RecordWeighted(1000, 0)
+	require.Equal(t, int64(4), avg.Count())
+
+	// the buffer holds 2 values, the oldest one is expired together with its weight
+	avg.RecordWeighted(100, 2)
+	require.InDelta(t, 64.0, avg.Average(), 0.001)
+	require.Equal(t, int64(5), avg.Count())
+}
@@ -10,4 +10,8 @@ func newNoopMovingWindowAverage() *noopMovingWindowAverage { return &noopMovingW
 
 func (a *noopMovingWindowAverage) Record(_ int64) {}
 
+func (a *noopMovingWindowAverage) RecordWeighted(_ int64, _ int64) {}
+
 func (a *noopMovingWindowAverage) Average() float64 { return 0 }
+
+func (a *noopMovingWindowAverage) Count() int64 { return 0 }
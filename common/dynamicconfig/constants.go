@@ -224,6 +224,36 @@ response to a StartWorkflowExecution request and skipping the trip through match
 		5000,
 		`PersistenceHealthSignalBufferSize is the maximum number of persistence signals to buffer in memory per signal key`,
 	)
+	PersistenceHealthSignalSlowRequestThreshold = NewGlobalDurationSetting(
+		"system.persistenceHealthSignalSlowRequestThreshold",
+		0,
+		`PersistenceHealthSignalSlowRequestThreshold is the latency above which a successful persistence request is
+considered unhealthy when computing the persistence error ratio. A value of 0 disables latency based classification.`,
+	)
+	PersistenceHealthSignalSlowRequestErrorWeight = NewGlobalFloatSetting(
+		"system.persistenceHealthSignalSlowRequestErrorWeight",
+		1.0,
+		`PersistenceHealthSignalSlowRequestErrorWeight is the fraction (between 0 and 1) of an error that a slow but
+successful persistence request contributes to the persistence error ratio. See PersistenceHealthSignalSlowRequestThreshold.`,
+	)
+	PersistenceHealthSignalMaxTrackedNamespaces = NewGlobalIntSetting(
+		"system.persistenceHealthSignalMaxTrackedNamespaces",
+		100,
+		`PersistenceHealthSignalMaxTrackedNamespaces is the maximum number of namespaces whose persistence error ratio is
+tracked. The least recently updated namespace is evicted once the limit is exceeded.`,
+	)
+	PersistencePerShardRPSWarnLimit = NewGlobalIntSetting(
+		"system.persistencePerShardRPSWarnLimit",
+		0,
+		`PersistencePerShardRPSWarnLimit is the persistence RPS of a shard above which a warning is logged. A value of 0
+disables the warning.`,
+	)
+	PersistencePerShardPerNamespaceRPSWarnLimit = NewGlobalIntSetting(
+		"system.persistencePerShardPerNamespaceRPSWarnLimit",
+		0,
+		`PersistencePerShardPerNamespaceRPSWarnLimit is the persistence RPS of a namespace on a single shard above which a
+warning is logged. A value of 0 disables the warning.`,
+	)
 	OperatorRPSRatio = NewGlobalFloatSetting(
 		"system.operatorRPSRatio",
 		0.2,
@@ -730,6 +760,54 @@ This config is EXPERIMENTAL and may be changed or removed in a later release.`,
 		10,
 		`FrontendMaxBadBinaries is the max number of bad binaries in namespace config`,
 	)
+	FrontendRetentionReductionGuardEnabled = NewNamespaceBoolSetting(
+		"frontend.retentionReductionGuardEnabled",
+		false,
+		`FrontendRetentionReductionGuardEnabled rejects UpdateNamespace requests which reduce the namespace retention by more
+than frontend.maxRetentionReductionPercent or frontend.maxRetentionReduction, unless the reduction is forced`,
+	)
+	FrontendMaxRetentionReductionPercent = NewNamespaceIntSetting(
+		"frontend.maxRetentionReductionPercent",
+		50,
+		`FrontendMaxRetentionReductionPercent is the max percentage by which a single UpdateNamespace request may reduce
+the namespace retention when frontend.retentionReductionGuardEnabled is true. Zero or less means no limit.`,
+	)
+	FrontendMaxRetentionReduction = NewNamespaceDurationSetting(
+		"frontend.maxRetentionReduction",
+		0,
+		`FrontendMaxRetentionReduction is the max duration by which a single UpdateNamespace request may reduce the
+namespace retention when frontend.retentionReductionGuardEnabled is true. Zero or less means no limit.`,
+	)
+	MaxReplicationClustersPerNamespace = NewNamespaceIntSetting(
+		"frontend.maxReplicationClustersPerNamespace",
+		10,
+		`MaxReplicationClustersPerNamespace is the max number of replication clusters of a global namespace`,
+	)
+	FrontendNamespaceDataMaxKeys = NewNamespaceIntSetting(
+		"frontend.namespaceDataMaxKeys",
+		100,
+		`FrontendNamespaceDataMaxKeys is the max number of keys in the data of a namespace. A non-positive value
+disables the limit. Namespaces already over a namespace data limit can still be updated, as long as the update
+does not add keys or grow the data.`,
+	)
+	FrontendNamespaceDataMaxKeyLength = NewNamespaceIntSetting(
+		"frontend.namespaceDataMaxKeyLength",
+		256,
+		`FrontendNamespaceDataMaxKeyLength is the max length in bytes of a key in the data of a namespace. A
+non-positive value disables the limit.`,
+	)
+	FrontendNamespaceDataMaxValueLength = NewNamespaceIntSetting(
+		"frontend.namespaceDataMaxValueLength",
+		4*1024,
+		`FrontendNamespaceDataMaxValueLength is the max length in bytes of a value in the data of a namespace. A
+non-positive value disables the limit.`,
+	)
+	FrontendNamespaceDataMaxSize = NewNamespaceIntSetting(
+		"frontend.namespaceDataMaxSize",
+		64*1024,
+		`FrontendNamespaceDataMaxSize is the max total length in bytes of the keys and values in the data of a
+namespace. A non-positive value disables the limit.`,
+	)
 	FrontendMaskInternalErrorDetails = NewNamespaceBoolSetting(
 		"frontend.maskInternalErrorDetails",
 		true,
@@ -2745,6 +2823,138 @@ WorkerActivitiesPerSecond, MaxConcurrentActivityTaskPollers.
 		`Maximum number of workflow rules in a given namespace`,
 	)
 
+	FrontendDescribeNamespaceRPS = NewGlobalIntSetting(
+		"frontend.describeNamespaceRPS",
+		0,
+		`FrontendDescribeNamespaceRPS is the per instance rate limit for DescribeNamespace requests. Requests over
+the limit fail with ResourceExhausted. Zero or less means no limit.`,
+	)
+	FrontendDescribeNamespacePerNamespaceRPS = NewNamespaceIntSetting(
+		"frontend.describeNamespacePerNamespaceRPS",
+		0,
+		`FrontendDescribeNamespacePerNamespaceRPS is the per instance rate limit for DescribeNamespace requests
+for a single namespace. Requests over the limit fail with ResourceExhausted. Zero or less means no limit.`,
+	)
+	FrontendListNamespacesRPS = NewGlobalIntSetting(
+		"frontend.listNamespacesRPS",
+		0,
+		`FrontendListNamespacesRPS is the per instance rate limit for ListNamespaces and CountNamespaces requests.
+Requests over the limit fail with ResourceExhausted. Zero or less means no limit.`,
+	)
+	FrontendListWorkflowRulesAcrossNamespacesRPS = NewGlobalIntSetting(
+		"frontend.listWorkflowRulesAcrossNamespacesRPS",
+		10,
+		`FrontendListWorkflowRulesAcrossNamespacesRPS is the per instance rate limit for pages of the cluster-wide
+workflow rules listing. Each page scans a page of namespaces. Requests over the limit fail with ResourceExhausted.`,
+	)
+	FrontendDescribeNamespaceCacheEnabled = NewGlobalBoolSetting(
+		"frontend.describeNamespaceCacheEnabled",
+		false,
+		`FrontendDescribeNamespaceCacheEnabled enables caching of DescribeNamespace responses for
+frontend.describeNamespaceCacheTTL. The cache is per frontend instance and is only invalidated by updates made
+through that instance, so a response may be stale for up to the TTL after an update through another instance.
+Cached responses still count against the DescribeNamespace rate limits.`,
+	)
+	FrontendDescribeNamespaceCacheTTL = NewGlobalDurationSetting(
+		"frontend.describeNamespaceCacheTTL",
+		5*time.Second,
+		`FrontendDescribeNamespaceCacheTTL is how long a cached DescribeNamespace response is served. Namespace
+updates on other frontend instances may not be visible for up to this long. Changes require a restart.`,
+	)
+	FrontendNamespaceOperationTimeout = NewGlobalDurationSetting(
+		"frontend.namespaceOperationTimeout",
+		0,
+		`FrontendNamespaceOperationTimeout bounds namespace handler operations whose caller did not set a deadline, so
+that a slow metadata store cannot hang them. Operations over it fail with DeadlineExceeded. It also bounds operations
+which wait by design, e.g. a graceful failover waiting for replication to catch up, so it must be set above their
+longest expected wait. Zero or less disables it.`,
+	)
+	FrontendListNamespacesByArchivalStateRPS = NewGlobalIntSetting(
+		"frontend.listNamespacesByArchivalStateRPS",
+		10,
+		`FrontendListNamespacesByArchivalStateRPS is the per instance rate limit for pages of the namespace listing
+filtered by archival state. Each page scans a page of namespaces. Requests over the limit fail with ResourceExhausted.`,
+	)
+	FrontendBatchFailoverNamespacesRPS = NewGlobalIntSetting(
+		"frontend.batchFailoverNamespacesRPS",
+		10,
+		`FrontendBatchFailoverNamespacesRPS is the per instance rate of namespace failovers done by a batch failover.
+Failovers over the limit wait for their turn rather than fail.`,
+	)
+	FrontendNamespaceMaxConcurrentOpenWorkflowsCeiling = NewGlobalIntSetting(
+		"frontend.namespaceMaxConcurrentOpenWorkflowsCeiling",
+		0,
+		`FrontendNamespaceMaxConcurrentOpenWorkflowsCeiling is the highest per-namespace limit on concurrently open
+workflows which can be set with UpdateNamespace. Zero means there is no ceiling.`,
+	)
+	FrontendNamespaceMaxSignalRPSCeiling = NewGlobalFloatSetting(
+		"frontend.namespaceMaxSignalRPSCeiling",
+		0,
+		`FrontendNamespaceMaxSignalRPSCeiling is the highest per-namespace limit on the rate of signals per second
+which can be set with UpdateNamespace. Zero means there is no ceiling.`,
+	)
+	FrontendNamespaceMaxQueryRPSCeiling = NewGlobalFloatSetting(
+		"frontend.namespaceMaxQueryRPSCeiling",
+		0,
+		`FrontendNamespaceMaxQueryRPSCeiling is the highest per-namespace limit on the rate of queries per second
+which can be set with UpdateNamespace. Zero means there is no ceiling.`,
+	)
+	FrontendReplicationHealthThresholds = NewGlobalTypedSetting(
+		"frontend.replicationHealthThresholds",
+		DefaultReplicationHealthThresholds,
+		`FrontendReplicationHealthThresholds are the thresholds of the namespace replication health reported by
+DescribeNamespaceReplicationHealth. Fields: DegradedLatency, UnhealthyLatency, DegradedErrorRatio, UnhealthyErrorRatio,
+DegradedPendingTasks, UnhealthyPendingTasks. See ReplicationHealthThresholds comments for more details.`,
+	)
+	FrontendNamespaceNamePattern = NewGlobalStringSetting(
+		"frontend.namespaceNamePattern",
+		"",
+		`FrontendNamespaceNamePattern is a regular expression the whole name of a new namespace must match,
+e.g. "[a-z]+-(dev|staging|prod)-[a-z0-9-]+". Empty allows any name. Existing namespaces are not affected.`,
+	)
+	FrontendFailoverVersionAllocator = NewNamespaceStringSetting(
+		"frontend.failoverVersionAllocator",
+		"",
+		`FrontendFailoverVersionAllocator is the name of the failover version allocator used when registering or failing
+over the namespace. The allocator must be registered with the frontend service. Empty uses the cluster failover
+version scheme.`,
+	)
+	FrontendAllowNamespaceUndeprecation = NewNamespaceBoolSetting(
+		"frontend.allowNamespaceUndeprecation",
+		false,
+		`FrontendAllowNamespaceUndeprecation allows UpdateNamespace to change the state of a deprecated namespace back to
+registered, e.g. to undo a mistaken deprecation. Namespaces in handover or being deleted can never be undeprecated.
+Only enable it while undoing the deprecation.`,
+	)
+	FrontendNamespaceConfigChangeMaxCount = NewNamespaceIntSetting(
+		"frontend.namespaceConfigChangeMaxCount",
+		0,
+		`FrontendNamespaceConfigChangeMaxCount is the number of config changes a namespace can get within
+frontend.namespaceConfigChangeWindow, counting registration, updates, failovers, deprecation and workflow rule changes
+together. Changes over the limit fail with ResourceExhausted, protecting namespace replication from automation loops.
+The times of the recent changes are persisted with the namespace, so the limit holds across frontend instances.
+Zero means there is no limit.`,
+	)
+	FrontendNamespaceConfigChangeWindow = NewNamespaceDurationSetting(
+		"frontend.namespaceConfigChangeWindow",
+		time.Minute,
+		`FrontendNamespaceConfigChangeWindow is the sliding window of frontend.namespaceConfigChangeMaxCount.`,
+	)
+	FrontendMaxNamespaceFailoverHistorySize = NewGlobalIntSetting(
+		"frontend.maxNamespaceFailoverHistorySize",
+		10,
+		`FrontendMaxNamespaceFailoverHistorySize is the number of most recent failovers kept in the failover history of
+a namespace. Older failovers are still kept in the failover log of the namespace.`,
+	)
+	FrontendEnableNamespaceNameReuse = NewGlobalBoolSetting(
+		"frontend.enableNamespaceNameReuse",
+		false,
+		`FrontendEnableNamespaceNameReuse allows registering a namespace with the name of a deleted namespace, which
+gets a new namespace ID. The name is reused only once the namespace deletion renamed the deleted namespace: the
+deletion renames the namespace by name after marking it DELETED, so registrations of the name of a namespace in
+DELETED state are rejected as the deletion is still in progress.`,
+	)
+
 	SlowRequestLoggingThreshold = NewGlobalDurationSetting(
 		"rpc.slowRequestLoggingThreshold",
 		5*time.Second,
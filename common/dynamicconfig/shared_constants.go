@@ -90,6 +90,31 @@ var DefaultDynamicRateLimitingParams = DynamicRateLimitingParams{
 	RateMultiMax:         1.0,
 }
 
+// ReplicationHealthThresholds are the thresholds of the signals making up the replication health of a namespace.
+// A signal at or above its degraded threshold makes the namespace degraded, and at or above its unhealthy threshold
+// unhealthy. A zero threshold is not checked.
+type ReplicationHealthThresholds struct {
+	// DegradedLatency and UnhealthyLatency are persistence average latencies in ms.
+	DegradedLatency  float64
+	UnhealthyLatency float64
+	// DegradedErrorRatio and UnhealthyErrorRatio are persistence error ratios between 0 and 1.
+	DegradedErrorRatio  float64
+	UnhealthyErrorRatio float64
+	// DegradedPendingTasks and UnhealthyPendingTasks are numbers of namespace replication tasks not yet applied
+	// by a replication cluster of the namespace.
+	DegradedPendingTasks  int64
+	UnhealthyPendingTasks int64
+}
+
+var DefaultReplicationHealthThresholds = ReplicationHealthThresholds{
+	DegradedLatency:       500,
+	UnhealthyLatency:      2000,
+	DegradedErrorRatio:    0.05,
+	UnhealthyErrorRatio:   0.2,
+	DegradedPendingTasks:  10,
+	UnhealthyPendingTasks: 100,
+}
+
 type CircuitBreakerSettings struct {
 	// MaxRequests: Maximum number of requests allowed to pass through when
 	// it is in half-open state (default 1).
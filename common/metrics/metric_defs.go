@@ -1140,6 +1140,10 @@ var (
 	AddSearchAttributesFailuresCount                = NewCounterDef("add_search_attributes_failures")
 
 	// Delete Namespace metrics.
+	DeleteNamespaceRecordOrphanedArchivalFailureCount = NewCounterDef(
+		"delete_namespace_record_orphaned_archival_failure",
+		WithDescription("Incremented every time when DeleteNamespace workflow fails to record the orphaned archival URIs of a deleted namespace"),
+	)
 	ReclaimResourcesNamespaceDeleteSuccessCount = NewCounterDef(
 		"reclaim_resources_namespace_delete_success",
 		WithDescription("Incremented every time when ReclaimResources workflow deletes a namespace successfully"),
@@ -1260,6 +1264,9 @@ var (
 	EncounterNotFoundWorkflowCount      = NewCounterDef("encounter_not_found_workflow_count")
 	EncounterPassRetentionWorkflowCount = NewCounterDef("encounter_pass_retention_workflow_count")
 	GenerateReplicationTasksLatency     = NewTimerDef("generate_replication_tasks_latency")
+	ForceReplicationTaskGenerated       = NewCounterDef("force_replication_task_generated")
+	ForceReplicationTaskSkipped         = NewCounterDef("force_replication_task_skipped")
+	ForceReplicationTaskFailed          = NewCounterDef("force_replication_task_failed")
 	VerifyReplicationTaskSuccess        = NewCounterDef("verify_replication_task_success")
 	VerifyReplicationTaskNotFound       = NewCounterDef("verify_replication_task_not_found")
 	VerifyReplicationTaskFailed         = NewCounterDef("verify_replication_task_failed")
@@ -1271,6 +1278,19 @@ var (
 	NamespaceReplicationDLQAckLevelGauge  = NewGaugeDef("namespace_dlq_ack_level")
 	NamespaceReplicationDLQMaxLevelGauge  = NewGaugeDef("namespace_dlq_max_level")
 
+	NamespaceHandlerRequests = NewCounterDef(
+		"namespace_handler_requests",
+		WithDescription("Namespace handler requests, keyed by `operation`, `namespace` and `outcome`"),
+	)
+	NamespaceHandlerErrorWithType = NewCounterDef(
+		"namespace_handler_error_with_type",
+		WithDescription("Namespace handler errors, keyed by `operation`, `namespace` and `error_type`"),
+	)
+	NamespaceHandlerLatency = NewTimerDef(
+		"namespace_handler_latency",
+		WithDescription("Namespace handler latency, keyed by `operation` and `namespace`"),
+	)
+
 	// Persistence
 	PersistenceRequests = NewCounterDef(
 		"persistence_requests",
@@ -1285,6 +1305,22 @@ var (
 		"persistence_latency",
 		WithDescription("Persistence latency, keyed by `operation`"),
 	)
+	PersistenceHealthReadLatency = NewGaugeDef(
+		"persistence_health_read_latency",
+		WithDescription("Average latency in milliseconds of persistence reads in the health signal window"),
+	)
+	PersistenceHealthWriteLatency = NewGaugeDef(
+		"persistence_health_write_latency",
+		WithDescription("Average latency in milliseconds of persistence writes in the health signal window"),
+	)
+	PersistenceHealthNamespaceErrorRatio = NewGaugeDef(
+		"persistence_health_namespace_error_ratio",
+		WithDescription("Persistence error ratio of a namespace in the health signal window, keyed by `namespace`"),
+	)
+	PersistenceShardCallerTypeRPS = NewDimensionlessHistogramDef(
+		"persistence_shard_caller_type_rps",
+		WithDescription("Persistence RPS of a shard from a single caller type, keyed by `caller_type`"),
+	)
 	PersistenceShardRPS                    = NewDimensionlessHistogramDef("persistence_shard_rps")
 	PersistenceErrResourceExhaustedCounter = NewCounterDef("persistence_errors_resource_exhausted")
 	VisibilityPersistenceRequests          = NewCounterDef("visibility_persistence_requests")
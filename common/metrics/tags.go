@@ -24,6 +24,7 @@ const (
 	namespace      = "namespace"
 	namespaceID    = "namespace_id"
 	namespaceState = "namespace_state"
+	callerType     = "caller_type"
 	sourceCluster  = "source_cluster"
 	targetCluster  = "target_cluster"
 	fromCluster    = "from_cluster"
@@ -324,6 +325,14 @@ func VersionedTag(versioned string) Tag {
 	return &tagImpl{key: versionedTagName, value: versioned}
 }
 
+// CallerTypeTag returns a new caller type tag, see headers.CallerInfo.
+func CallerTypeTag(value string) Tag {
+	if len(value) == 0 {
+		value = unknownValue
+	}
+	return &tagImpl{key: callerType, value: value}
+}
+
 func ServiceErrorTypeTag(err error) Tag {
 	return &tagImpl{key: ErrorTypeTagName, value: strings.TrimPrefix(util.ErrorType(err), errorPrefix)}
 }
@@ -19,7 +19,7 @@ type (
 	}
 
 	// ArchivalConfigEvent represents a change request to archival config state
-	// the only restriction placed on events is that defaultURI is not empty
+	// the only restriction placed on events is that defaultURI is not empty when the state has no URI
 	// state can be nil, enabled, or disabled (nil indicates no update by user is being attempted)
 	ArchivalConfigEvent struct {
 		DefaultURI string
@@ -96,8 +96,12 @@ func (s *ArchivalConfigState) GetNextState(
 	if err := s.validate(); err != nil {
 		return nil, false, err
 	}
-	if err := e.Validate(); err != nil {
-		return nil, false, err
+	// the default URI is only needed when there is no existing URI to fall back on,
+	// disabling and re-enabling archival keeps using the existing URI
+	if len(s.URI) == 0 {
+		if err := e.Validate(); err != nil {
+			return nil, false, err
+		}
 	}
 
 	/**
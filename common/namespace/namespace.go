@@ -272,6 +272,48 @@ func (ns *Namespace) Retention() time.Duration {
 	return ns.config.Retention.AsDuration()
 }
 
+// ArchivalBatchConfig returns the archival batching overrides for this namespace, or nil if the
+// cluster defaults should be used.
+func (ns *Namespace) ArchivalBatchConfig() *persistencespb.ArchivalBatchConfig {
+	return ns.config.GetArchivalBatchConfig()
+}
+
+// VisibilityStore returns the name of the visibility store selected for this namespace, or an empty
+// string if the cluster default should be used.
+func (ns *Namespace) VisibilityStore() string {
+	return ns.config.GetVisibilityStore()
+}
+
+// ReplicationWorkflowTypeFilter returns the workflow types selected for force replication, or nil if
+// all workflows of the namespace are replicated.
+func (ns *Namespace) ReplicationWorkflowTypeFilter() *persistencespb.ReplicationWorkflowTypeFilter {
+	return ns.config.GetReplicationWorkflowTypeFilter()
+}
+
+// MaxConcurrentOpenWorkflows returns the maximum number of concurrently open workflows in the namespace,
+// zero means unlimited.
+func (ns *Namespace) MaxConcurrentOpenWorkflows() int64 {
+	return ns.config.GetMaxConcurrentOpenWorkflows()
+}
+
+// MaxSignalRPS returns the maximum rate of signals per second to workflows in the namespace, zero means
+// unlimited.
+func (ns *Namespace) MaxSignalRPS() float64 {
+	return ns.config.GetMaxSignalRps()
+}
+
+// MaxQueryRPS returns the maximum rate of queries per second to workflows in the namespace, zero means
+// unlimited.
+func (ns *Namespace) MaxQueryRPS() float64 {
+	return ns.config.GetMaxQueryRps()
+}
+
+// AsyncUpdateOverride returns the namespace override of the cluster default for accepting workflow updates
+// asynchronously, or nil if the cluster default applies.
+func (ns *Namespace) AsyncUpdateOverride() *persistencespb.AsyncUpdateOverride {
+	return ns.config.GetAsyncUpdateOverride()
+}
+
 // CustomSearchAttributesMapper is a part of temporary solution. Do not use this method.
 func (ns *Namespace) CustomSearchAttributesMapper() CustomSearchAttributesMapper {
 	return ns.customSearchAttributesMapper
@@ -1,12 +1,23 @@
 package nsmanager
 
 import (
+	"time"
+
 	enumspb "go.temporal.io/api/enums/v1"
 	"go.temporal.io/api/serviceerror"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
 	"go.temporal.io/server/common/cluster"
 )
 
+const (
+	// MinArchivalBatchSize and MaxArchivalBatchSize bound the per-namespace archival batch size.
+	MinArchivalBatchSize = 1
+	MaxArchivalBatchSize = 1000
+	// MinArchivalFlushInterval and MaxArchivalFlushInterval bound the per-namespace archival flush interval.
+	MinArchivalFlushInterval = time.Second
+	MaxArchivalFlushInterval = time.Hour
+)
+
 type (
 	Validator struct {
 		clusterMetadata cluster.Metadata
@@ -29,6 +40,30 @@ func (d *Validator) ValidateNamespaceConfig(config *persistencespb.NamespaceConf
 	if config.VisibilityArchivalState == enumspb.ARCHIVAL_STATE_ENABLED && len(config.VisibilityArchivalUri) == 0 {
 		return errInvalidArchivalConfig
 	}
+	if err := d.validateArchivalBatchConfig(config.ArchivalBatchConfig); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *Validator) validateArchivalBatchConfig(batchConfig *persistencespb.ArchivalBatchConfig) error {
+	if batchConfig == nil {
+		return nil
+	}
+	if batchSize := batchConfig.GetBatchSize(); batchSize != 0 &&
+		(batchSize < MinArchivalBatchSize || batchSize > MaxArchivalBatchSize) {
+		return serviceerror.NewInvalidArgumentf(
+			"Archival batch size must be between %d and %d, got %d.",
+			MinArchivalBatchSize, MaxArchivalBatchSize, batchSize)
+	}
+	if batchConfig.FlushInterval != nil {
+		flushInterval := batchConfig.GetFlushInterval().AsDuration()
+		if flushInterval < MinArchivalFlushInterval || flushInterval > MaxArchivalFlushInterval {
+			return serviceerror.NewInvalidArgumentf(
+				"Archival flush interval must be between %v and %v, got %v.",
+				MinArchivalFlushInterval, MaxArchivalFlushInterval, flushInterval)
+		}
+	}
 	return nil
 }
 
@@ -2,12 +2,14 @@ package nsmanager
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 	"go.temporal.io/api/serviceerror"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
 	"go.temporal.io/server/common/cluster"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type (
@@ -159,3 +161,54 @@ func (s *attrValidatorSuite) TestValidateNamespaceReplicationConfigForGlobalName
 	)
 	s.NoError(err)
 }
+
+func (s *attrValidatorSuite) TestValidateNamespaceConfig_ArchivalBatchConfig() {
+	testCases := []struct {
+		name        string
+		batchConfig *persistencespb.ArchivalBatchConfig
+		expectErr   bool
+	}{
+		{name: "unset", batchConfig: nil},
+		{name: "empty", batchConfig: &persistencespb.ArchivalBatchConfig{}},
+		{
+			name: "within bounds",
+			batchConfig: &persistencespb.ArchivalBatchConfig{
+				BatchSize:     MaxArchivalBatchSize,
+				FlushInterval: durationpb.New(MinArchivalFlushInterval),
+			},
+		},
+		{
+			name:        "negative batch size",
+			batchConfig: &persistencespb.ArchivalBatchConfig{BatchSize: -1},
+			expectErr:   true,
+		},
+		{
+			name:        "batch size too large",
+			batchConfig: &persistencespb.ArchivalBatchConfig{BatchSize: MaxArchivalBatchSize + 1},
+			expectErr:   true,
+		},
+		{
+			name:        "flush interval too small",
+			batchConfig: &persistencespb.ArchivalBatchConfig{FlushInterval: durationpb.New(0)},
+			expectErr:   true,
+		},
+		{
+			name:        "flush interval too large",
+			batchConfig: &persistencespb.ArchivalBatchConfig{FlushInterval: durationpb.New(MaxArchivalFlushInterval + time.Second)},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			err := s.validator.ValidateNamespaceConfig(&persistencespb.NamespaceConfig{
+				ArchivalBatchConfig: tc.batchConfig,
+			})
+			if tc.expectErr {
+				s.IsType(&serviceerror.InvalidArgument{}, err)
+			} else {
+				s.NoError(err)
+			}
+		})
+	}
+}
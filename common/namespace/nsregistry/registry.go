@@ -144,6 +144,11 @@ func (r *registry) GetRegistrySize() (idToNamespaceSize int64, nameToIDSize int6
 	return int64(len(r.idToNamespace)), int64(len(r.nameToID))
 }
 
+// GetAllNamespaces returns the namespaces in the by-ID map.
+func (r *registry) GetAllNamespaces() []*namespace.Namespace {
+	return r.getAllNamespace()
+}
+
 func (r *registry) RefreshNamespaceById(id namespace.ID) (*namespace.Namespace, error) {
 	r.readthroughLock.Lock()
 	defer r.readthroughLock.Unlock()
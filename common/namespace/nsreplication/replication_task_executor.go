@@ -4,6 +4,7 @@ package nsreplication
 
 import (
 	"context"
+	"math"
 
 	enumspb "go.temporal.io/api/enums/v1"
 	replicationpb "go.temporal.io/api/replication/v1"
@@ -16,6 +17,9 @@ import (
 	"go.temporal.io/server/common/persistence"
 )
 
+// MaxFailoverLogSize is the number of entries kept in the failover log of a namespace.
+const MaxFailoverLogSize = 1000
+
 var (
 	// ErrEmptyNamespaceReplicationTask is the error to indicate empty replication task
 	ErrEmptyNamespaceReplicationTask = serviceerror.NewInvalidArgument("empty namespace replication task")
@@ -151,6 +155,14 @@ func (h *taskExecutorImpl) handleNamespaceCreationReplicationTask(
 				VisibilityArchivalState:      task.Config.GetVisibilityArchivalState(),
 				VisibilityArchivalUri:        task.Config.GetVisibilityArchivalUri(),
 				CustomSearchAttributeAliases: task.Config.GetCustomSearchAttributeAliases(),
+				ArchivalBatchConfig:          task.GetArchivalBatchConfig(),
+				VisibilityStore:              task.GetVisibilityStore(),
+
+				ReplicationWorkflowTypeFilter: task.GetReplicationWorkflowTypeFilter(),
+				MaxConcurrentOpenWorkflows:    task.GetMaxConcurrentOpenWorkflows(),
+				AsyncUpdateOverride:           task.GetAsyncUpdateOverride(),
+				MaxSignalRps:                  task.GetMaxSignalRps(),
+				MaxQueryRps:                   task.GetMaxQueryRps(),
 			},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: task.ReplicationConfig.GetActiveClusterName(),
@@ -282,6 +294,14 @@ func (h *taskExecutorImpl) handleNamespaceUpdateReplicationTask(
 			VisibilityArchivalState:      task.Config.GetVisibilityArchivalState(),
 			VisibilityArchivalUri:        task.Config.GetVisibilityArchivalUri(),
 			CustomSearchAttributeAliases: task.Config.GetCustomSearchAttributeAliases(),
+			ArchivalBatchConfig:          task.GetArchivalBatchConfig(),
+			VisibilityStore:              task.GetVisibilityStore(),
+
+			ReplicationWorkflowTypeFilter: task.GetReplicationWorkflowTypeFilter(),
+			MaxConcurrentOpenWorkflows:    task.GetMaxConcurrentOpenWorkflows(),
+			AsyncUpdateOverride:           task.GetAsyncUpdateOverride(),
+			MaxSignalRps:                  task.GetMaxSignalRps(),
+			MaxQueryRps:                   task.GetMaxQueryRps(),
 		}
 		if task.Config.GetBadBinaries() != nil {
 			request.Namespace.Config.BadBinaries = task.Config.GetBadBinaries()
@@ -295,6 +315,10 @@ func (h *taskExecutorImpl) handleNamespaceUpdateReplicationTask(
 		request.Namespace.FailoverVersion = task.GetFailoverVersion()
 		request.Namespace.FailoverNotificationVersion = notificationVersion
 		request.Namespace.ReplicationConfig.FailoverHistory = ConvertFailoverHistoryToPersistenceProto(task.GetFailoverHistory())
+		request.Namespace.ReplicationConfig.FailoverLog = AppendFailoverLog(
+			request.Namespace.ReplicationConfig.GetFailoverLog(),
+			request.Namespace.ReplicationConfig.FailoverHistory,
+		)
 	}
 
 	if !recordUpdated {
@@ -343,6 +367,26 @@ func ConvertFailoverHistoryToPersistenceProto(failoverHistory []*replicationpb.F
 	return res
 }
 
+// AppendFailoverLog appends the entries of failoverHistory with a higher failover version than the last entry of
+// failoverLog, and drops the oldest entries beyond MaxFailoverLogSize. It keeps the failover log of a namespace up
+// to date with its failover history, which only has the most recent failovers.
+func AppendFailoverLog(failoverLog []*persistencespb.FailoverStatus, failoverHistory []*persistencespb.FailoverStatus) []*persistencespb.FailoverStatus {
+	lastFailoverVersion := int64(math.MinInt64)
+	if l := len(failoverLog); l > 0 {
+		lastFailoverVersion = failoverLog[l-1].GetFailoverVersion()
+	}
+	for _, status := range failoverHistory {
+		if status.GetFailoverVersion() > lastFailoverVersion {
+			failoverLog = append(failoverLog, status)
+			lastFailoverVersion = status.GetFailoverVersion()
+		}
+	}
+	if l := len(failoverLog); l > MaxFailoverLogSize {
+		failoverLog = failoverLog[l-MaxFailoverLogSize:]
+	}
+	return failoverLog
+}
+
 func (h *taskExecutorImpl) validateNamespaceStatus(input enumspb.NamespaceState) error {
 	switch input {
 	case enumspb.NAMESPACE_STATE_REGISTERED, enumspb.NAMESPACE_STATE_DEPRECATED:
@@ -415,6 +415,10 @@ func (s *namespaceReplicationTaskExecutorSuite) TestExecute_UpdateNamespaceTask_
 		ConfigVersion:   updateConfigVersion,
 		FailoverVersion: updateFailoverVersion,
 		FailoverHistory: failoverHistory,
+		ArchivalBatchConfig: &persistencespb.ArchivalBatchConfig{
+			BatchSize:     50,
+			FlushInterval: durationpb.New(time.Minute),
+		},
 	}
 
 	s.namespaceReplicator.currentCluster = updateClusterStandby
@@ -445,11 +449,13 @@ func (s *namespaceReplicationTaskExecutorSuite) TestExecute_UpdateNamespaceTask_
 				HistoryArchivalUri:      updateTask.Config.HistoryArchivalUri,
 				VisibilityArchivalState: updateTask.Config.VisibilityArchivalState,
 				VisibilityArchivalUri:   updateTask.Config.VisibilityArchivalUri,
+				ArchivalBatchConfig:     updateTask.ArchivalBatchConfig,
 			},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: updateTask.ReplicationConfig.ActiveClusterName,
 				Clusters:          []string{updateClusterActive, updateClusterStandby},
 				FailoverHistory:   ConvertFailoverHistoryToPersistenceProto(failoverHistory),
+				FailoverLog:       ConvertFailoverHistoryToPersistenceProto(failoverHistory),
 			},
 			ConfigVersion:               updateConfigVersion,
 			FailoverNotificationVersion: updateFailoverVersion,
@@ -707,3 +713,28 @@ func (s *namespaceReplicationTaskExecutorSuite) TestExecute_UpdateNamespaceTask_
 	err := s.namespaceReplicator.Execute(context.Background(), updateTask)
 	s.Nil(err)
 }
+
+func (s *namespaceReplicationTaskExecutorSuite) TestAppendFailoverLog() {
+	newFailoverLog := func(versions ...int64) []*persistencespb.FailoverStatus {
+		var failoverLog []*persistencespb.FailoverStatus
+		for _, version := range versions {
+			failoverLog = append(failoverLog, &persistencespb.FailoverStatus{FailoverVersion: version})
+		}
+		return failoverLog
+	}
+
+	// an empty log is seeded with the whole history
+	s.Equal(newFailoverLog(1, 2), AppendFailoverLog(nil, newFailoverLog(1, 2)))
+	// entries which are already logged are skipped
+	s.Equal(newFailoverLog(1, 2, 3, 4), AppendFailoverLog(newFailoverLog(1, 2), newFailoverLog(2, 3, 4)))
+	s.Equal(newFailoverLog(1, 2), AppendFailoverLog(newFailoverLog(1, 2), newFailoverLog(1, 2)))
+
+	versions := make([]int64, MaxFailoverLogSize)
+	for i := range versions {
+		versions[i] = int64(i)
+	}
+	failoverLog := AppendFailoverLog(newFailoverLog(versions...), newFailoverLog(MaxFailoverLogSize))
+	s.Len(failoverLog, MaxFailoverLogSize)
+	s.Equal(int64(1), failoverLog[0].GetFailoverVersion())
+	s.Equal(int64(MaxFailoverLogSize), failoverLog[MaxFailoverLogSize-1].GetFailoverVersion())
+}
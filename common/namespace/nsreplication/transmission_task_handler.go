@@ -1,3 +1,5 @@
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination transmission_task_handler_mock.go
+
 package nsreplication
 
 import (
@@ -99,9 +101,17 @@ func (r *replicator) HandleTransmissionTask(
 				ActiveClusterName: replicationConfig.ActiveClusterName,
 				Clusters:          convertClusterReplicationConfigToProto(replicationConfig.Clusters),
 			},
-			ConfigVersion:   configVersion,
-			FailoverVersion: failoverVersion,
-			FailoverHistory: convertFailoverHistoryToReplicationProto(failoverHistoy),
+			ConfigVersion:       configVersion,
+			FailoverVersion:     failoverVersion,
+			FailoverHistory:     convertFailoverHistoryToReplicationProto(failoverHistoy),
+			ArchivalBatchConfig: config.ArchivalBatchConfig,
+			VisibilityStore:     config.VisibilityStore,
+
+			ReplicationWorkflowTypeFilter: config.ReplicationWorkflowTypeFilter,
+			MaxConcurrentOpenWorkflows:    config.MaxConcurrentOpenWorkflows,
+			AsyncUpdateOverride:           config.AsyncUpdateOverride,
+			MaxSignalRps:                  config.MaxSignalRps,
+			MaxQueryRps:                   config.MaxQueryRps,
 		},
 	}
 
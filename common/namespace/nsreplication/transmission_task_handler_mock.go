@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: transmission_task_handler.go
+//
+// Generated by this command:
+//
+//	mockgen -package nsreplication -source transmission_task_handler.go -destination transmission_task_handler_mock.go
+//
+
+// Package nsreplication is a generated GoMock package.
+package nsreplication
+
+import (
+	context "context"
+	reflect "reflect"
+
+	enums "go.temporal.io/server/api/enums/v1"
+	persistence "go.temporal.io/server/api/persistence/v1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReplicator is a mock of Replicator interface.
+type MockReplicator struct {
+	ctrl     *gomock.Controller
+	recorder *MockReplicatorMockRecorder
+	isgomock struct{}
+}
+
+// MockReplicatorMockRecorder is the mock recorder for MockReplicator.
+type MockReplicatorMockRecorder struct {
+	mock *MockReplicator
+}
+
+// NewMockReplicator creates a new mock instance.
+func NewMockReplicator(ctrl *gomock.Controller) *MockReplicator {
+	mock := &MockReplicator{ctrl: ctrl}
+	mock.recorder = &MockReplicatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReplicator) EXPECT() *MockReplicatorMockRecorder {
+	return m.recorder
+}
+
+// HandleTransmissionTask mocks base method.
+func (m *MockReplicator) HandleTransmissionTask(ctx context.Context, namespaceOperation enums.NamespaceOperation, info *persistence.NamespaceInfo, config *persistence.NamespaceConfig, replicationConfig *persistence.NamespaceReplicationConfig, replicationClusterListUpdated bool, configVersion, failoverVersion int64, isGlobalNamespace bool, failoverHistoy []*persistence.FailoverStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleTransmissionTask", ctx, namespaceOperation, info, config, replicationConfig, replicationClusterListUpdated, configVersion, failoverVersion, isGlobalNamespace, failoverHistoy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleTransmissionTask indicates an expected call of HandleTransmissionTask.
+func (mr *MockReplicatorMockRecorder) HandleTransmissionTask(ctx, namespaceOperation, info, config, replicationConfig, replicationClusterListUpdated, configVersion, failoverVersion, isGlobalNamespace, failoverHistoy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleTransmissionTask", reflect.TypeOf((*MockReplicator)(nil).HandleTransmissionTask), ctx, namespaceOperation, info, config, replicationConfig, replicationClusterListUpdated, configVersion, failoverVersion, isGlobalNamespace, failoverHistoy)
+}
@@ -216,6 +216,10 @@ func (s *transmissionTaskSuite) TestHandleTransmissionTask_UpdateNamespaceTask_I
 	configVersion := int64(0)
 	failoverVersion := int64(59)
 	clusters := []string{clusterActive, clusterStandby}
+	archivalBatchConfig := &persistencespb.ArchivalBatchConfig{
+		BatchSize:     100,
+		FlushInterval: durationpb.New(time.Minute),
+	}
 
 	namespaceOperation := enumsspb.NAMESPACE_OPERATION_UPDATE
 	info := &persistencespb.NamespaceInfo{
@@ -233,6 +237,7 @@ func (s *transmissionTaskSuite) TestHandleTransmissionTask_UpdateNamespaceTask_I
 		VisibilityArchivalState: visibilityArchivalState,
 		VisibilityArchivalUri:   visibilityArchivalURI,
 		BadBinaries:             &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+		ArchivalBatchConfig:     archivalBatchConfig,
 	}
 	replicationConfig := &persistencespb.NamespaceReplicationConfig{
 		ActiveClusterName: clusterActive,
@@ -265,8 +270,9 @@ func (s *transmissionTaskSuite) TestHandleTransmissionTask_UpdateNamespaceTask_I
 					ActiveClusterName: clusterActive,
 					Clusters:          convertClusterReplicationConfigToProto(clusters),
 				},
-				ConfigVersion:   configVersion,
-				FailoverVersion: failoverVersion,
+				ConfigVersion:       configVersion,
+				FailoverVersion:     failoverVersion,
+				ArchivalBatchConfig: archivalBatchConfig,
 			},
 		},
 	}).Return(nil)
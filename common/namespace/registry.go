@@ -23,6 +23,9 @@ type (
 		GetNamespaceID(name Name) (ID, error)
 		GetNamespaceName(id ID) (Name, error)
 		GetRegistrySize() (sizeOfCacheByName int64, sizeOfCacheByID int64)
+		// GetAllNamespaces returns the namespaces in the in-memory registry, deleted namespaces included. It
+		// does not read through to persistence, so namespaces created since the last refresh may be missing.
+		GetAllNamespaces() []*Namespace
 		// Registers callback for namespace state changes.
 		// StateChangeCallbackFn will be invoked for a new/deleted namespace or namespace that has
 		// State, ReplicationState, ActiveCluster, or isGlobalNamespace config changed.
@@ -55,6 +55,16 @@ type (
 	}
 
 	FactoryProviderFn func(NewFactoryParams) Factory
+
+	HealthSignalAggregatorParams struct {
+		fx.In
+
+		DynamicCollection *dynamicconfig.Collection
+		MetricsHandler    metrics.Handler
+		Logger            log.ThrottledLogger
+		// OnWarnLimitExceeded is provided by servers which react to persistence RPS warn limits being exceeded.
+		OnWarnLimitExceeded persistence.WarnLimitExceededCallback `optional:"true"`
+	}
 )
 
 var Module = fx.Options(
@@ -72,6 +82,7 @@ var Module = fx.Options(
 
 	fx.Provide(ClusterNameProvider),
 	fx.Provide(HealthSignalAggregatorProvider),
+	fx.Provide(CompositeHealthSignalAggregatorProvider),
 	fx.Provide(persistence.NewDLQMetricsEmitter),
 	fx.Provide(EventBlobCacheProvider),
 )
@@ -137,24 +148,36 @@ func FactoryProvider(
 	)
 }
 
-func HealthSignalAggregatorProvider(
-	dynamicCollection *dynamicconfig.Collection,
-	metricsHandler metrics.Handler,
-	logger log.ThrottledLogger,
-) persistence.HealthSignalAggregator {
+func HealthSignalAggregatorProvider(params HealthSignalAggregatorParams) persistence.HealthSignalAggregator {
+	dynamicCollection := params.DynamicCollection
 	if dynamicconfig.PersistenceHealthSignalMetricsEnabled.Get(dynamicCollection)() {
 		return persistence.NewHealthSignalAggregator(
 			dynamicconfig.PersistenceHealthSignalAggregationEnabled.Get(dynamicCollection)(),
 			dynamicconfig.PersistenceHealthSignalWindowSize.Get(dynamicCollection)(),
 			dynamicconfig.PersistenceHealthSignalBufferSize.Get(dynamicCollection)(),
-			metricsHandler,
-			logger,
+			dynamicconfig.PersistenceHealthSignalSlowRequestThreshold.Get(dynamicCollection),
+			dynamicconfig.PersistenceHealthSignalSlowRequestErrorWeight.Get(dynamicCollection),
+			dynamicconfig.PersistenceHealthSignalMaxTrackedNamespaces.Get(dynamicCollection),
+			dynamicconfig.PersistencePerShardRPSWarnLimit.Get(dynamicCollection),
+			dynamicconfig.PersistencePerShardPerNamespaceRPSWarnLimit.Get(dynamicCollection),
+			params.OnWarnLimitExceeded,
+			params.MetricsHandler,
+			params.Logger,
 		)
 	}
 
 	return persistence.NoopHealthSignalAggregator
 }
 
+// CompositeHealthSignalAggregatorProvider provides the host-level view of the persistence health signals.
+func CompositeHealthSignalAggregatorProvider(
+	healthSignals persistence.HealthSignalAggregator,
+) *persistence.CompositeHealthSignalAggregator {
+	return persistence.NewCompositeHealthSignalAggregator(map[string]persistence.HealthSignalAggregator{
+		"persistence": healthSignals,
+	})
+}
+
 func DataStoreFactoryProvider(
 	clusterName ClusterName,
 	r resolver.ServiceResolver,
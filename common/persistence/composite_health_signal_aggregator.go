@@ -0,0 +1,172 @@
+package persistence
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+type (
+	// HealthSignal is a point-in-time view of a HealthSignalAggregator.
+	HealthSignal struct {
+		AverageLatency float64
+		ErrorRatio     float64
+		RequestCount   int64
+	}
+
+	// HostHealthSignals is a read-only host-level view of the persistence health signals.
+	HostHealthSignals interface {
+		AverageLatency() float64
+		AverageLatencyByClass(operationClass OperationClass) float64
+		ErrorRatio() float64
+		ErrorRatioForNamespace(namespace string) float64
+		RequestCount() int64
+		RequestCountByClass(operationClass OperationClass) int64
+		Snapshot() HealthSignalSnapshot
+		// Components returns the health signal of each component, keyed by component name.
+		Components() map[string]HealthSignal
+	}
+
+	// CompositeHealthSignalAggregator combines several HealthSignalAggregators, e.g. one per persistence
+	// client on a host, into a single host-level view. Averages are weighted by the request count of
+	// each component. It only reads the components: signals are recorded by, and the components are started
+	// and stopped by, the persistence clients owning them, so Record, RecordWeighted, Start and Stop are no-ops.
+	CompositeHealthSignalAggregator struct {
+		names      []string
+		components map[string]HealthSignalAggregator
+	}
+)
+
+var _ HealthSignalAggregator = (*CompositeHealthSignalAggregator)(nil)
+var _ HostHealthSignals = (*CompositeHealthSignalAggregator)(nil)
+
+func NewCompositeHealthSignalAggregator(
+	components map[string]HealthSignalAggregator,
+) *CompositeHealthSignalAggregator {
+	return &CompositeHealthSignalAggregator{
+		names:      slices.Sorted(maps.Keys(components)),
+		components: maps.Clone(components),
+	}
+}
+
+// Start is a no-op, the component aggregators are started by their owners.
+func (c *CompositeHealthSignalAggregator) Start() {}
+
+// Stop is a no-op, the component aggregators are stopped by their owners.
+func (c *CompositeHealthSignalAggregator) Stop() {}
+
+// Record is a no-op, signals are recorded by the persistence clients owning the component aggregators.
+func (c *CompositeHealthSignalAggregator) Record(_ int32, _ string, _ string, _ OperationClass, _ time.Duration, _ error) {
+}
+
+// RecordWeighted is a no-op, see Record.
+func (c *CompositeHealthSignalAggregator) RecordWeighted(
+	_ int32,
+	_ string,
+	_ string,
+	_ OperationClass,
+	_ time.Duration,
+	_ error,
+	_ int,
+) {
+}
+
+func (c *CompositeHealthSignalAggregator) AverageLatency() float64 {
+	return c.weightedAverage(HealthSignalAggregator.AverageLatency, HealthSignalAggregator.RequestCount)
+}
+
+func (c *CompositeHealthSignalAggregator) AverageLatencyByClass(operationClass OperationClass) float64 {
+	return c.weightedAverage(
+		func(component HealthSignalAggregator) float64 { return component.AverageLatencyByClass(operationClass) },
+		func(component HealthSignalAggregator) int64 { return component.RequestCountByClass(operationClass) },
+	)
+}
+
+func (c *CompositeHealthSignalAggregator) ErrorRatio() float64 {
+	return c.weightedAverage(HealthSignalAggregator.ErrorRatio, HealthSignalAggregator.RequestCount)
+}
+
+// ErrorRatioForNamespace returns the highest error ratio of the namespace among the component aggregators.
+// Components do not expose per-namespace request counts, so the ratios cannot be weighted.
+func (c *CompositeHealthSignalAggregator) ErrorRatioForNamespace(namespace string) float64 {
+	var ratio float64
+	for _, name := range c.names {
+		ratio = max(ratio, c.components[name].ErrorRatioForNamespace(namespace))
+	}
+	return ratio
+}
+
+func (c *CompositeHealthSignalAggregator) RequestCount() int64 {
+	var count int64
+	for _, name := range c.names {
+		count += c.components[name].RequestCount()
+	}
+	return count
+}
+
+func (c *CompositeHealthSignalAggregator) RequestCountByClass(operationClass OperationClass) int64 {
+	var count int64
+	for _, name := range c.names {
+		count += c.components[name].RequestCountByClass(operationClass)
+	}
+	return count
+}
+
+// Snapshot returns the weighted averages of the component snapshots, with the shard RPS summed across the
+// components.
+func (c *CompositeHealthSignalAggregator) Snapshot() HealthSignalSnapshot {
+	snapshot := HealthSignalSnapshot{
+		Timestamp: time.Now().UTC(),
+		ShardRPS:  make(map[int32]int64),
+	}
+	var latencySum, errorRatioSum float64
+	var totalCount int64
+	for _, name := range c.names {
+		component := c.components[name]
+		componentSnapshot := component.Snapshot()
+		count := component.RequestCount()
+		latencySum += componentSnapshot.AverageLatency * float64(count)
+		errorRatioSum += componentSnapshot.ErrorRatio * float64(count)
+		totalCount += count
+		for shardID, rps := range componentSnapshot.ShardRPS {
+			snapshot.ShardRPS[shardID] += rps
+		}
+	}
+	if totalCount > 0 {
+		snapshot.AverageLatency = latencySum / float64(totalCount)
+		snapshot.ErrorRatio = errorRatioSum / float64(totalCount)
+	}
+	return snapshot
+}
+
+// Components returns the health signal of each component aggregator, keyed by component name.
+func (c *CompositeHealthSignalAggregator) Components() map[string]HealthSignal {
+	signals := make(map[string]HealthSignal, len(c.components))
+	for _, name := range c.names {
+		component := c.components[name]
+		signals[name] = HealthSignal{
+			AverageLatency: component.AverageLatency(),
+			ErrorRatio:     component.ErrorRatio(),
+			RequestCount:   component.RequestCount(),
+		}
+	}
+	return signals
+}
+
+func (c *CompositeHealthSignalAggregator) weightedAverage(
+	value func(HealthSignalAggregator) float64,
+	weight func(HealthSignalAggregator) int64,
+) float64 {
+	var sum float64
+	var totalCount int64
+	for _, name := range c.names {
+		component := c.components[name]
+		count := weight(component)
+		sum += value(component) * float64(count)
+		totalCount += count
+	}
+	if totalCount == 0 {
+		return 0
+	}
+	return sum / float64(totalCount)
+}
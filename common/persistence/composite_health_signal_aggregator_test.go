@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+)
+
+func TestCompositeHealthSignalAggregator(t *testing.T) {
+	history := NewHealthSignalAggregator(true, time.Minute, 100, dynamicconfig.GetDurationPropertyFn(0), dynamicconfig.GetFloatPropertyFn(1), dynamicconfig.GetIntPropertyFn(100), dynamicconfig.GetIntPropertyFn(0), dynamicconfig.GetIntPropertyFn(0), nil, metrics.NoopMetricsHandler, log.NewNoopLogger())
+	visibility := NewHealthSignalAggregator(true, time.Minute, 100, dynamicconfig.GetDurationPropertyFn(0), dynamicconfig.GetFloatPropertyFn(1), dynamicconfig.GetIntPropertyFn(100), dynamicconfig.GetIntPropertyFn(0), dynamicconfig.GetIntPropertyFn(0), nil, metrics.NoopMetricsHandler, log.NewNoopLogger())
+
+	// 3 history requests: 10ms each, one of them failed
+	history.Record(CallerSegmentMissing, "", "", OperationClassRead, 10*time.Millisecond, nil)
+	history.Record(CallerSegmentMissing, "", "", OperationClassRead, 10*time.Millisecond, nil)
+	history.Record(CallerSegmentMissing, "", "", OperationClassRead, 10*time.Millisecond, context.DeadlineExceeded)
+	// 1 visibility request: 50ms
+	visibility.Record(CallerSegmentMissing, "", "", OperationClassRead, 50*time.Millisecond, nil)
+
+	composite := NewCompositeHealthSignalAggregator(map[string]HealthSignalAggregator{
+		"history":    history,
+		"visibility": visibility,
+	})
+
+	// recording through the composite is a no-op, signals are recorded by the component owners
+	composite.Record(CallerSegmentMissing, "", "", OperationClassRead, time.Second, context.DeadlineExceeded)
+	composite.RecordWeighted(CallerSegmentMissing, "", "", OperationClassRead, time.Second, context.DeadlineExceeded, 10)
+
+	require.Equal(t, int64(4), composite.RequestCount())
+	require.InDelta(t, 20.0, composite.AverageLatency(), 0.001)
+	require.InDelta(t, 0.25, composite.ErrorRatio(), 0.001)
+
+	components := composite.Components()
+	require.Len(t, components, 2)
+	require.Equal(t, int64(3), components["history"].RequestCount)
+	require.InDelta(t, 10.0, components["history"].AverageLatency, 0.001)
+	require.InDelta(t, 1.0/3, components["history"].ErrorRatio, 0.001)
+	require.Equal(t, int64(1), components["visibility"].RequestCount)
+	require.InDelta(t, 50.0, components["visibility"].AverageLatency, 0.001)
+	require.Zero(t, components["visibility"].ErrorRatio)
+
+	snapshot := composite.Snapshot()
+	require.InDelta(t, 20.0, snapshot.AverageLatency, 0.001)
+	require.InDelta(t, 0.25, snapshot.ErrorRatio, 0.001)
+	require.Empty(t, snapshot.ShardRPS)
+}
+
+func TestCompositeHealthSignalAggregator_NoRequests(t *testing.T) {
+	composite := NewCompositeHealthSignalAggregator(map[string]HealthSignalAggregator{
+		"noop": NoopHealthSignalAggregator,
+	})
+
+	require.Zero(t, composite.RequestCount())
+	require.Zero(t, composite.AverageLatency())
+	require.Zero(t, composite.ErrorRatio())
+}
+
+func TestCompositeHealthSignalAggregator_OperationClass(t *testing.T) {
+	history := NewHealthSignalAggregator(true, time.Minute, 100, dynamicconfig.GetDurationPropertyFn(0), dynamicconfig.GetFloatPropertyFn(1), dynamicconfig.GetIntPropertyFn(100), dynamicconfig.GetIntPropertyFn(0), dynamicconfig.GetIntPropertyFn(0), nil, metrics.NoopMetricsHandler, log.NewNoopLogger())
+	visibility := NewHealthSignalAggregator(true, time.Minute, 100, dynamicconfig.GetDurationPropertyFn(0), dynamicconfig.GetFloatPropertyFn(1), dynamicconfig.GetIntPropertyFn(100), dynamicconfig.GetIntPropertyFn(0), dynamicconfig.GetIntPropertyFn(0), nil, metrics.NoopMetricsHandler, log.NewNoopLogger())
+
+	history.Record(CallerSegmentMissing, "", "", OperationClassRead, 10*time.Millisecond, nil)
+	history.Record(CallerSegmentMissing, "", "", OperationClassWrite, 100*time.Millisecond, nil)
+	visibility.Record(CallerSegmentMissing, "", "", OperationClassRead, 40*time.Millisecond, nil)
+	visibility.Record(CallerSegmentMissing, "", "", OperationClassRead, 40*time.Millisecond, nil)
+
+	composite := NewCompositeHealthSignalAggregator(map[string]HealthSignalAggregator{
+		"history":    history,
+		"visibility": visibility,
+	})
+
+	// Class averages are weighted by the request count of the class, not the total request count.
+	require.Equal(t, int64(3), composite.RequestCountByClass(OperationClassRead))
+	require.InDelta(t, 30.0, composite.AverageLatencyByClass(OperationClassRead), 0.001)
+	require.Equal(t, int64(1), composite.RequestCountByClass(OperationClassWrite))
+	require.InDelta(t, 100.0, composite.AverageLatencyByClass(OperationClassWrite), 0.001)
+	require.InDelta(t, 47.5, composite.AverageLatency(), 0.001)
+}
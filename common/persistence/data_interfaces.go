@@ -682,6 +682,13 @@ type (
 		PageSize       int
 		NextPageToken  []byte
 		IncludeDeleted bool
+		// States only lists the namespaces in one of the states if set. Deleted namespaces still require
+		// IncludeDeleted.
+		States []enumspb.NamespaceState
+		// NamePrefix only lists the namespaces with a name starting with the case-sensitive prefix if set.
+		// With States or NamePrefix set, each page reads a single page of the store, so it may be short or
+		// even empty while more namespaces remain.
+		NamePrefix string
 	}
 
 	// ListNamespacesResponse is the response for GetNamespace
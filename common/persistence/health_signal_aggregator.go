@@ -7,64 +7,185 @@ import (
 
 	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/aggregate"
+	"go.temporal.io/server/common/dynamicconfig"
 	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
 	"go.temporal.io/server/common/metrics"
 )
 
 const (
 	emitMetricsInterval = 30 * time.Second
+
+	// errorRatioScale is the value recorded for a fully unhealthy request. The error ratio window only
+	// stores integers, so fractional contributions from slow requests are recorded in these units.
+	errorRatioScale = 1000
+)
+
+const (
+	OperationClassRead  OperationClass = "read"
+	OperationClassWrite OperationClass = "write"
+)
+
+const (
+	// WarnKindShardRPS is reported when the persistence RPS of a shard exceeds the per-shard warn limit.
+	WarnKindShardRPS WarnKind = "shard_rps"
+	// WarnKindShardNamespaceRPS is reported when the persistence RPS of a namespace on a shard exceeds the
+	// per-shard-per-namespace warn limit.
+	WarnKindShardNamespaceRPS WarnKind = "shard_namespace_rps"
 )
 
 type (
+	// OperationClass tells whether a persistence operation reads or writes. Latencies are aggregated per
+	// class as well as combined, since slow writes and slow reads point at different problems.
+	OperationClass string
+
+	// WarnKind tells which persistence RPS warn limit was exceeded.
+	WarnKind string
+
+	// WarnLimitExceededCallback is invoked, in addition to the warning log, whenever a shard or a namespace on a
+	// shard exceeds its persistence RPS warn limit. For WarnKindShardRPS the namespace is empty.
+	WarnLimitExceededCallback func(shardID int32, namespace string, rps int64, kind WarnKind)
+
 	HealthSignalAggregator interface {
-		Record(callerSegment int32, latency time.Duration, err error)
+		// Record records a persistence request. The caller type is the one of headers.CallerInfo.
+		Record(
+			callerSegment int32,
+			namespace string,
+			callerType string,
+			operationClass OperationClass,
+			latency time.Duration,
+			err error,
+		)
+		// RecordWeighted records a request whose latency and error samples count as weight requests in the
+		// averages, e.g. a batch operation touching many rows. Record is RecordWeighted with a weight of 1.
+		RecordWeighted(
+			callerSegment int32,
+			namespace string,
+			callerType string,
+			operationClass OperationClass,
+			latency time.Duration,
+			err error,
+			weight int,
+		)
+		// AverageLatency returns the average latency of all operations.
 		AverageLatency() float64
+		// AverageLatencyByClass returns the average latency of the operations of the given class.
+		AverageLatencyByClass(operationClass OperationClass) float64
 		ErrorRatio() float64
+		// ErrorRatioForNamespace returns the error ratio of the requests of the given namespace, or 0 if the
+		// namespace is not tracked.
+		ErrorRatioForNamespace(namespace string) float64
+		// RequestCount returns the number of requests the averages are computed over.
+		RequestCount() int64
+		// RequestCountByClass returns the number of requests of the given class the class average is computed over.
+		RequestCountByClass(operationClass OperationClass) int64
+		// Snapshot returns a consistent view of the aggregated signals.
+		Snapshot() HealthSignalSnapshot
 		Start()
 		Stop()
 	}
 
+	// HealthSignalSnapshot is a point-in-time view of the signals of a HealthSignalAggregator, for rendering
+	// health endpoints.
+	HealthSignalSnapshot struct {
+		Timestamp      time.Time
+		AverageLatency float64
+		ErrorRatio     float64
+		// ShardRPS is the persistence RPS of each shard since the shard request counts were last emitted.
+		ShardRPS map[int32]int64
+	}
+
 	healthSignalAggregatorImpl struct {
 		status     int32
 		shutdownCh chan struct{}
 
-		// map of shardID -> request count
-		requestCounts map[int32]int64
-		requestsLock  sync.Mutex
+		// map of shardID -> caller type -> namespace -> request count, counted since requestCountsStartTime
+		requestCounts          map[int32]map[string]map[string]int64
+		requestCountsStartTime time.Time
+		requestsLock           sync.Mutex
+
+		// onWarnLimitExceeded is optional.
+		onWarnLimitExceeded WarnLimitExceededCallback
 
-		aggregationEnabled bool
-		latencyAverage     aggregate.MovingWindowAverage
-		errorRatio         aggregate.MovingWindowAverage
+		aggregationEnabled  bool
+		latencyAverage      aggregate.MovingWindowAverage
+		readLatencyAverage  aggregate.MovingWindowAverage
+		writeLatencyAverage aggregate.MovingWindowAverage
+		errorRatio          aggregate.MovingWindowAverage
+
+		// Error ratio of each tracked namespace, map of namespace -> *namespaceErrorRatio. Recording for a
+		// tracked namespace does not lock, namespaceLock is only held to add a namespace and to evict the least
+		// recently updated one once maxTrackedNamespaces is exceeded.
+		namespaceErrorRatios     sync.Map
+		namespaceErrorRatioCount int
+		namespaceUpdateSequence  atomic.Int64
+		namespaceLock            sync.Mutex
+
+		windowSize    time.Duration
+		maxBufferSize int
+
+		slowRequestThreshold   dynamicconfig.DurationPropertyFn
+		slowRequestErrorWeight dynamicconfig.FloatPropertyFn
+		maxTrackedNamespaces   dynamicconfig.IntPropertyFn
+
+		perShardRPSWarnLimit             dynamicconfig.IntPropertyFn
+		perShardPerNamespaceRPSWarnLimit dynamicconfig.IntPropertyFn
 
 		metricsHandler   metrics.Handler
 		emitMetricsTimer *time.Ticker
 
 		logger log.Logger
 	}
+
+	namespaceErrorRatio struct {
+		namespace  string
+		errorRatio aggregate.MovingWindowAverage
+		// lastUpdate is the namespaceUpdateSequence of the last request recorded for the namespace.
+		lastUpdate atomic.Int64
+	}
 )
 
 func NewHealthSignalAggregator(
 	aggregationEnabled bool,
 	windowSize time.Duration,
 	maxBufferSize int,
+	slowRequestThreshold dynamicconfig.DurationPropertyFn,
+	slowRequestErrorWeight dynamicconfig.FloatPropertyFn,
+	maxTrackedNamespaces dynamicconfig.IntPropertyFn,
+	perShardRPSWarnLimit dynamicconfig.IntPropertyFn,
+	perShardPerNamespaceRPSWarnLimit dynamicconfig.IntPropertyFn,
+	onWarnLimitExceeded WarnLimitExceededCallback,
 	metricsHandler metrics.Handler,
 	logger log.Logger,
 ) *healthSignalAggregatorImpl {
 	ret := &healthSignalAggregatorImpl{
-		status:             common.DaemonStatusInitialized,
-		shutdownCh:         make(chan struct{}),
-		requestCounts:      make(map[int32]int64),
-		metricsHandler:     metricsHandler,
-		emitMetricsTimer:   time.NewTicker(emitMetricsInterval),
-		logger:             logger,
-		aggregationEnabled: aggregationEnabled,
+		status:                           common.DaemonStatusInitialized,
+		shutdownCh:                       make(chan struct{}),
+		requestCounts:                    make(map[int32]map[string]map[string]int64),
+		requestCountsStartTime:           time.Now().UTC(),
+		windowSize:                       windowSize,
+		maxBufferSize:                    maxBufferSize,
+		metricsHandler:                   metricsHandler,
+		emitMetricsTimer:                 time.NewTicker(emitMetricsInterval),
+		logger:                           logger,
+		aggregationEnabled:               aggregationEnabled,
+		slowRequestThreshold:             slowRequestThreshold,
+		slowRequestErrorWeight:           slowRequestErrorWeight,
+		maxTrackedNamespaces:             maxTrackedNamespaces,
+		perShardRPSWarnLimit:             perShardRPSWarnLimit,
+		perShardPerNamespaceRPSWarnLimit: perShardPerNamespaceRPSWarnLimit,
+		onWarnLimitExceeded:              onWarnLimitExceeded,
 	}
 
 	if aggregationEnabled {
 		ret.latencyAverage = aggregate.NewMovingWindowAvgImpl(windowSize, maxBufferSize)
+		ret.readLatencyAverage = aggregate.NewMovingWindowAvgImpl(windowSize, maxBufferSize)
+		ret.writeLatencyAverage = aggregate.NewMovingWindowAvgImpl(windowSize, maxBufferSize)
 		ret.errorRatio = aggregate.NewMovingWindowAvgImpl(windowSize, maxBufferSize)
 	} else {
 		ret.latencyAverage = aggregate.NoopMovingWindowAverage
+		ret.readLatencyAverage = aggregate.NoopMovingWindowAverage
+		ret.writeLatencyAverage = aggregate.NoopMovingWindowAverage
 		ret.errorRatio = aggregate.NoopMovingWindowAverage
 	}
 
@@ -86,19 +207,42 @@ func (s *healthSignalAggregatorImpl) Stop() {
 	s.emitMetricsTimer.Stop()
 }
 
-func (s *healthSignalAggregatorImpl) Record(callerSegment int32, latency time.Duration, err error) {
+func (s *healthSignalAggregatorImpl) Record(
+	callerSegment int32,
+	namespace string,
+	callerType string,
+	operationClass OperationClass,
+	latency time.Duration,
+	err error,
+) {
+	s.RecordWeighted(callerSegment, namespace, callerType, operationClass, latency, err, 1)
+}
+
+func (s *healthSignalAggregatorImpl) RecordWeighted(
+	callerSegment int32,
+	namespace string,
+	callerType string,
+	operationClass OperationClass,
+	latency time.Duration,
+	err error,
+	weight int,
+) {
 	if s.aggregationEnabled {
-		s.latencyAverage.Record(latency.Milliseconds())
+		sampleWeight := int64(max(weight, 1))
+		s.latencyAverage.RecordWeighted(latency.Milliseconds(), sampleWeight)
+		if classAverage := s.classLatencyAverage(operationClass); classAverage != nil {
+			classAverage.RecordWeighted(latency.Milliseconds(), sampleWeight)
+		}
 
-		if isUnhealthyError(err) {
-			s.errorRatio.Record(1)
-		} else {
-			s.errorRatio.Record(0)
+		errorWeight := s.errorWeight(latency, err)
+		s.errorRatio.RecordWeighted(errorWeight, sampleWeight)
+		if namespace != "" {
+			s.recordNamespaceErrorWeight(namespace, errorWeight, sampleWeight)
 		}
 	}
 
 	if callerSegment != CallerSegmentMissing {
-		s.incrementShardRequestCount(callerSegment)
+		s.incrementShardRequestCount(callerSegment, callerType, namespace)
 	}
 }
 
@@ -106,37 +250,244 @@ func (s *healthSignalAggregatorImpl) AverageLatency() float64 {
 	return s.latencyAverage.Average()
 }
 
+func (s *healthSignalAggregatorImpl) AverageLatencyByClass(operationClass OperationClass) float64 {
+	if classAverage := s.classLatencyAverage(operationClass); classAverage != nil {
+		return classAverage.Average()
+	}
+	return 0
+}
+
 func (s *healthSignalAggregatorImpl) ErrorRatio() float64 {
-	return s.errorRatio.Average()
+	return s.errorRatio.Average() / errorRatioScale
 }
 
-func (s *healthSignalAggregatorImpl) incrementShardRequestCount(shardID int32) {
+func (s *healthSignalAggregatorImpl) ErrorRatioForNamespace(namespace string) float64 {
+	value, ok := s.namespaceErrorRatios.Load(namespace)
+	if !ok {
+		return 0
+	}
+	return value.(*namespaceErrorRatio).errorRatio.Average() / errorRatioScale
+}
+
+func (s *healthSignalAggregatorImpl) RequestCount() int64 {
+	return s.latencyAverage.Count()
+}
+
+func (s *healthSignalAggregatorImpl) RequestCountByClass(operationClass OperationClass) int64 {
+	if classAverage := s.classLatencyAverage(operationClass); classAverage != nil {
+		return classAverage.Count()
+	}
+	return 0
+}
+
+func (s *healthSignalAggregatorImpl) Snapshot() HealthSignalSnapshot {
 	s.requestsLock.Lock()
 	defer s.requestsLock.Unlock()
-	s.requestCounts[shardID]++
+
+	now := time.Now().UTC()
+	elapsed := max(now.Sub(s.requestCountsStartTime), time.Second)
+	shardRPS := make(map[int32]int64, len(s.requestCounts))
+	for shardID, callerTypeCounts := range s.requestCounts {
+		var count int64
+		for _, namespaceCounts := range callerTypeCounts {
+			for _, namespaceCount := range namespaceCounts {
+				count += namespaceCount
+			}
+		}
+		shardRPS[shardID] = int64(float64(count) / elapsed.Seconds())
+	}
+	return HealthSignalSnapshot{
+		Timestamp:      now,
+		AverageLatency: s.AverageLatency(),
+		ErrorRatio:     s.ErrorRatio(),
+		ShardRPS:       shardRPS,
+	}
+}
+
+// classLatencyAverage returns the latency average of an operation class, or nil for an unknown class.
+func (s *healthSignalAggregatorImpl) classLatencyAverage(operationClass OperationClass) aggregate.MovingWindowAverage {
+	switch operationClass {
+	case OperationClassRead:
+		return s.readLatencyAverage
+	case OperationClassWrite:
+		return s.writeLatencyAverage
+	default:
+		return nil
+	}
+}
+
+// errorWeight returns how much a request contributes to the error ratio, in units of errorRatioScale.
+// Unhealthy errors count fully, while successful requests slower than the configured threshold
+// count as the configured fraction of an error.
+func (s *healthSignalAggregatorImpl) errorWeight(latency time.Duration, err error) int64 {
+	if isUnhealthyError(err) {
+		return errorRatioScale
+	}
+	if err != nil || !isSlowRequest(latency, s.slowRequestThreshold()) {
+		return 0
+	}
+	weight := max(0, min(1, s.slowRequestErrorWeight()))
+	return int64(weight * errorRatioScale)
+}
+
+// recordNamespaceErrorWeight records the error weight of a request in the error ratio of its namespace, and
+// marks the namespace as the most recently updated one. Namespaces are not tracked if maxTrackedNamespaces is
+// zero or less.
+func (s *healthSignalAggregatorImpl) recordNamespaceErrorWeight(namespace string, errorWeight int64, sampleWeight int64) {
+	maxTrackedNamespaces := s.maxTrackedNamespaces()
+	if maxTrackedNamespaces <= 0 {
+		return
+	}
+
+	value, ok := s.namespaceErrorRatios.Load(namespace)
+	if !ok {
+		value = s.trackNamespace(namespace, maxTrackedNamespaces)
+	}
+	ratio := value.(*namespaceErrorRatio)
+	ratio.lastUpdate.Store(s.namespaceUpdateSequence.Add(1))
+	ratio.errorRatio.RecordWeighted(errorWeight, sampleWeight)
+}
+
+// trackNamespace starts tracking the error ratio of a namespace, and evicts the least recently updated
+// namespaces until at most maxTrackedNamespaces are tracked. It returns the *namespaceErrorRatio of the namespace.
+func (s *healthSignalAggregatorImpl) trackNamespace(namespace string, maxTrackedNamespaces int) any {
+	s.namespaceLock.Lock()
+	defer s.namespaceLock.Unlock()
+
+	if value, ok := s.namespaceErrorRatios.Load(namespace); ok {
+		return value
+	}
+	ratio := &namespaceErrorRatio{
+		namespace:  namespace,
+		errorRatio: aggregate.NewMovingWindowAvgImpl(s.windowSize, s.maxBufferSize),
+	}
+	ratio.lastUpdate.Store(s.namespaceUpdateSequence.Add(1))
+	s.namespaceErrorRatios.Store(namespace, ratio)
+	s.namespaceErrorRatioCount++
+
+	for s.namespaceErrorRatioCount > maxTrackedNamespaces {
+		var oldest *namespaceErrorRatio
+		s.namespaceErrorRatios.Range(func(_, value any) bool {
+			if candidate := value.(*namespaceErrorRatio); oldest == nil || candidate.lastUpdate.Load() < oldest.lastUpdate.Load() {
+				oldest = candidate
+			}
+			return true
+		})
+		s.namespaceErrorRatios.Delete(oldest.namespace)
+		s.namespaceErrorRatioCount--
+	}
+	return ratio
+}
+
+// namespaceErrorRatioSnapshot returns the error ratio of each tracked namespace.
+func (s *healthSignalAggregatorImpl) namespaceErrorRatioSnapshot() map[string]float64 {
+	snapshot := make(map[string]float64)
+	s.namespaceErrorRatios.Range(func(_, value any) bool {
+		ratio := value.(*namespaceErrorRatio)
+		snapshot[ratio.namespace] = ratio.errorRatio.Average() / errorRatioScale
+		return true
+	})
+	return snapshot
+}
+
+func (s *healthSignalAggregatorImpl) incrementShardRequestCount(shardID int32, callerType string, namespace string) {
+	s.requestsLock.Lock()
+	defer s.requestsLock.Unlock()
+
+	callerTypeCounts, ok := s.requestCounts[shardID]
+	if !ok {
+		callerTypeCounts = make(map[string]map[string]int64)
+		s.requestCounts[shardID] = callerTypeCounts
+	}
+	namespaceCounts, ok := callerTypeCounts[callerType]
+	if !ok {
+		namespaceCounts = make(map[string]int64)
+		callerTypeCounts[callerType] = namespaceCounts
+	}
+	namespaceCounts[namespace]++
 }
 
-// Traverse through all shards and get the per-namespace persistence RPS for all shards.
-// If that is over the limit, print a log line. Per-shard-per-namespace RPC limit for namespaces
-// is configured in dynamic config. This will allow us to see if some namespaces had hit
-// this limit in any of the shards.
 func (s *healthSignalAggregatorImpl) emitMetricsLoop() {
 	for {
 		select {
 		case <-s.shutdownCh:
 			return
 		case <-s.emitMetricsTimer.C:
-			s.requestsLock.Lock()
-			requestCounts := s.requestCounts
-			s.requestCounts = make(map[int32]int64, len(requestCounts))
-			s.requestsLock.Unlock()
-
-			for _, count := range requestCounts {
-				shardRPS := int64(float64(count) / emitMetricsInterval.Seconds())
-				s.metricsHandler.Histogram(metrics.PersistenceShardRPS.Name(), metrics.PersistenceShardRPS.Unit()).Record(shardRPS)
+			s.emitMetrics()
+		}
+	}
+}
+
+// Traverse through all shards and get the per-namespace persistence RPS for all shards.
+// If that is over the limit, print a log line and invoke onWarnLimitExceeded. Per-shard-per-namespace RPC limit for namespaces
+// is configured in dynamic config. This will allow us to see if some namespaces had hit
+// this limit in any of the shards.
+func (s *healthSignalAggregatorImpl) emitMetrics() {
+	s.requestsLock.Lock()
+	requestCounts := s.requestCounts
+	s.requestCounts = make(map[int32]map[string]map[string]int64, len(requestCounts))
+	s.requestCountsStartTime = time.Now().UTC()
+	s.requestsLock.Unlock()
+
+	shardRPSWarnLimit := int64(s.perShardRPSWarnLimit())
+	namespaceRPSWarnLimit := int64(s.perShardPerNamespaceRPSWarnLimit())
+	for shardID, callerTypeCounts := range requestCounts {
+		var shardCount int64
+		shardNamespaceCounts := make(map[string]int64)
+		for callerType, namespaceCounts := range callerTypeCounts {
+			var callerTypeCount int64
+			for namespace, count := range namespaceCounts {
+				callerTypeCount += count
+				shardNamespaceCounts[namespace] += count
+			}
+			shardCount += callerTypeCount
+			metrics.PersistenceShardCallerTypeRPS.With(s.metricsHandler.WithTags(metrics.CallerTypeTag(callerType))).
+				Record(requestsPerSecond(callerTypeCount))
+		}
+
+		shardRPS := requestsPerSecond(shardCount)
+		metrics.PersistenceShardRPS.With(s.metricsHandler).Record(shardRPS)
+		if shardRPSWarnLimit > 0 && shardRPS > shardRPSWarnLimit {
+			s.warnLimitExceeded(shardID, "", shardRPS, WarnKindShardRPS)
+		}
+
+		if namespaceRPSWarnLimit <= 0 {
+			continue
+		}
+		for namespace, count := range shardNamespaceCounts {
+			namespaceRPS := requestsPerSecond(count)
+			if namespace != "" && namespaceRPS > namespaceRPSWarnLimit {
+				s.warnLimitExceeded(shardID, namespace, namespaceRPS, WarnKindShardNamespaceRPS)
 			}
 		}
 	}
+
+	if s.aggregationEnabled {
+		metrics.PersistenceHealthReadLatency.With(s.metricsHandler).Record(s.readLatencyAverage.Average())
+		metrics.PersistenceHealthWriteLatency.With(s.metricsHandler).Record(s.writeLatencyAverage.Average())
+		for namespace, errorRatio := range s.namespaceErrorRatioSnapshot() {
+			metrics.PersistenceHealthNamespaceErrorRatio.With(s.metricsHandler).Record(
+				errorRatio,
+				metrics.NamespaceTag(namespace),
+			)
+		}
+	}
+}
+
+func (s *healthSignalAggregatorImpl) warnLimitExceeded(shardID int32, namespace string, rps int64, kind WarnKind) {
+	tags := []tag.Tag{tag.ShardID(shardID), tag.NewInt64("rps", rps), tag.NewStringTag("warn-kind", string(kind))}
+	if namespace != "" {
+		tags = append(tags, tag.WorkflowNamespace(namespace))
+	}
+	s.logger.Warn("Persistence RPS warn limit exceeded", tags...)
+	if s.onWarnLimitExceeded != nil {
+		s.onWarnLimitExceeded(shardID, namespace, rps, kind)
+	}
+}
+
+// requestsPerSecond returns the RPS of a request count counted over emitMetricsInterval.
+func requestsPerSecond(count int64) int64 {
+	return int64(float64(count) / emitMetricsInterval.Seconds())
 }
 
 func isUnhealthyError(err error) bool {
@@ -157,3 +508,7 @@ func isUnhealthyError(err error) bool {
 	}
 	return false
 }
+
+func isSlowRequest(latency time.Duration, threshold time.Duration) bool {
+	return threshold > 0 && latency > threshold
+}
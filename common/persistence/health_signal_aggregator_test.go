@@ -1,9 +1,19 @@
 package persistence
 
 import (
+	"maps"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/require"
 	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/headers"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/metrics/metricstest"
 )
 
 func Test_isUnhealthyError(t *testing.T) {
@@ -57,3 +67,358 @@ func Test_isUnhealthyError(t *testing.T) {
 		})
 	}
 }
+
+func TestHealthSignalAggregator_SlowRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		weight    float64
+		latency   time.Duration
+		err       error
+		want      float64
+	}{
+		{
+			name:      "latency classification disabled",
+			threshold: 0,
+			weight:    1,
+			latency:   time.Hour,
+			want:      0,
+		},
+		{
+			name:      "fast request",
+			threshold: time.Second,
+			weight:    1,
+			latency:   time.Millisecond,
+			want:      0,
+		},
+		{
+			name:      "slow request full weight",
+			threshold: time.Second,
+			weight:    1,
+			latency:   2 * time.Second,
+			want:      1,
+		},
+		{
+			name:      "slow request partial weight",
+			threshold: time.Second,
+			weight:    0.25,
+			latency:   2 * time.Second,
+			want:      0.25,
+		},
+		{
+			name:      "slow request weight clamped",
+			threshold: time.Second,
+			weight:    5,
+			latency:   2 * time.Second,
+			want:      1,
+		},
+		{
+			name:      "slow request with healthy error",
+			threshold: time.Second,
+			weight:    1,
+			latency:   2 * time.Second,
+			err:       &InvalidPersistenceRequestError{Msg: "invalid persistence request"},
+			want:      0,
+		},
+		{
+			name:      "fast request with unhealthy error",
+			threshold: time.Second,
+			weight:    0.25,
+			latency:   time.Millisecond,
+			err:       &TimeoutError{Msg: "timeout"},
+			want:      1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregator := NewHealthSignalAggregator(
+				true,
+				time.Minute,
+				100,
+				dynamicconfig.GetDurationPropertyFn(tt.threshold),
+				dynamicconfig.GetFloatPropertyFn(tt.weight),
+				dynamicconfig.GetIntPropertyFn(100),
+				dynamicconfig.GetIntPropertyFn(0),
+				dynamicconfig.GetIntPropertyFn(0),
+				nil,
+				metrics.NoopMetricsHandler,
+				log.NewNoopLogger(),
+			)
+			aggregator.Record(CallerSegmentMissing, "", "", OperationClassRead, tt.latency, tt.err)
+			aggregator.Record(CallerSegmentMissing, "", "", OperationClassRead, time.Millisecond, nil)
+			require.InDelta(t, tt.want/2, aggregator.ErrorRatio(), 0.001)
+		})
+	}
+}
+
+func TestHealthSignalAggregator_SlowRequestThresholdUpdate(t *testing.T) {
+	threshold := time.Second
+	aggregator := NewHealthSignalAggregator(
+		true,
+		time.Minute,
+		100,
+		func() time.Duration { return threshold },
+		dynamicconfig.GetFloatPropertyFn(1),
+		dynamicconfig.GetIntPropertyFn(100),
+		dynamicconfig.GetIntPropertyFn(0),
+		dynamicconfig.GetIntPropertyFn(0),
+		nil,
+		metrics.NoopMetricsHandler,
+		log.NewNoopLogger(),
+	)
+
+	aggregator.Record(CallerSegmentMissing, "", "", OperationClassRead, 500*time.Millisecond, nil)
+	require.Zero(t, aggregator.ErrorRatio())
+
+	threshold = 100 * time.Millisecond
+	aggregator.Record(CallerSegmentMissing, "", "", OperationClassRead, 500*time.Millisecond, nil)
+	require.InDelta(t, 0.5, aggregator.ErrorRatio(), 0.001)
+}
+
+func TestHealthSignalAggregator_OperationClass(t *testing.T) {
+	aggregator := NewHealthSignalAggregator(
+		true,
+		time.Minute,
+		100,
+		dynamicconfig.GetDurationPropertyFn(0),
+		dynamicconfig.GetFloatPropertyFn(1),
+		dynamicconfig.GetIntPropertyFn(100),
+		dynamicconfig.GetIntPropertyFn(0),
+		dynamicconfig.GetIntPropertyFn(0),
+		nil,
+		metrics.NoopMetricsHandler,
+		log.NewNoopLogger(),
+	)
+
+	aggregator.Record(CallerSegmentMissing, "", "", OperationClassRead, 10*time.Millisecond, nil)
+	aggregator.Record(CallerSegmentMissing, "", "", OperationClassRead, 20*time.Millisecond, nil)
+	aggregator.Record(CallerSegmentMissing, "", "", OperationClassWrite, 90*time.Millisecond, nil)
+
+	require.InDelta(t, 15.0, aggregator.AverageLatencyByClass(OperationClassRead), 0.001)
+	require.Equal(t, int64(2), aggregator.RequestCountByClass(OperationClassRead))
+	require.InDelta(t, 90.0, aggregator.AverageLatencyByClass(OperationClassWrite), 0.001)
+	require.Equal(t, int64(1), aggregator.RequestCountByClass(OperationClassWrite))
+	// The combined average covers both classes.
+	require.InDelta(t, 40.0, aggregator.AverageLatency(), 0.001)
+	require.Equal(t, int64(3), aggregator.RequestCount())
+	require.Zero(t, aggregator.AverageLatencyByClass("unknown"))
+}
+
+func TestHealthSignalAggregator_NamespaceErrorRatio(t *testing.T) {
+	aggregator := NewHealthSignalAggregator(
+		true,
+		time.Minute,
+		100,
+		dynamicconfig.GetDurationPropertyFn(0),
+		dynamicconfig.GetFloatPropertyFn(1),
+		dynamicconfig.GetIntPropertyFn(2),
+		dynamicconfig.GetIntPropertyFn(0),
+		dynamicconfig.GetIntPropertyFn(0),
+		nil,
+		metrics.NoopMetricsHandler,
+		log.NewNoopLogger(),
+	)
+
+	timeoutErr := &TimeoutError{Msg: "timeout"}
+	aggregator.Record(CallerSegmentMissing, "ns-a", "", OperationClassRead, time.Millisecond, timeoutErr)
+	aggregator.Record(CallerSegmentMissing, "ns-a", "", OperationClassRead, time.Millisecond, nil)
+	aggregator.Record(CallerSegmentMissing, "ns-b", "", OperationClassRead, time.Millisecond, timeoutErr)
+	// requests without a namespace only count towards the overall error ratio
+	aggregator.Record(CallerSegmentMissing, "", "", OperationClassRead, time.Millisecond, nil)
+
+	require.InDelta(t, 0.5, aggregator.ErrorRatioForNamespace("ns-a"), 0.001)
+	require.InDelta(t, 1.0, aggregator.ErrorRatioForNamespace("ns-b"), 0.001)
+	require.InDelta(t, 0.5, aggregator.ErrorRatio(), 0.001)
+
+	// ns-b becomes the least recently updated namespace and is evicted by ns-c
+	aggregator.Record(CallerSegmentMissing, "ns-a", "", OperationClassRead, time.Millisecond, nil)
+	aggregator.Record(CallerSegmentMissing, "ns-c", "", OperationClassRead, time.Millisecond, timeoutErr)
+
+	require.Zero(t, aggregator.ErrorRatioForNamespace("ns-b"))
+	require.InDelta(t, 1.0/3, aggregator.ErrorRatioForNamespace("ns-a"), 0.001)
+	require.InDelta(t, 1.0, aggregator.ErrorRatioForNamespace("ns-c"), 0.001)
+	require.Equal(t, []string{"ns-a", "ns-c"}, slices.Sorted(maps.Keys(aggregator.namespaceErrorRatioSnapshot())))
+}
+
+func TestHealthSignalAggregator_NamespaceErrorRatioDisabled(t *testing.T) {
+	aggregator := NewHealthSignalAggregator(
+		true,
+		time.Minute,
+		100,
+		dynamicconfig.GetDurationPropertyFn(0),
+		dynamicconfig.GetFloatPropertyFn(1),
+		dynamicconfig.GetIntPropertyFn(0),
+		dynamicconfig.GetIntPropertyFn(0),
+		dynamicconfig.GetIntPropertyFn(0),
+		nil,
+		metrics.NoopMetricsHandler,
+		log.NewNoopLogger(),
+	)
+
+	aggregator.Record(CallerSegmentMissing, "ns-a", "", OperationClassRead, time.Millisecond, &TimeoutError{Msg: "timeout"})
+
+	require.Zero(t, aggregator.ErrorRatioForNamespace("ns-a"))
+	require.Empty(t, aggregator.namespaceErrorRatioSnapshot())
+	require.InDelta(t, 1.0, aggregator.ErrorRatio(), 0.001)
+}
+
+func TestHealthSignalAggregator_Snapshot(t *testing.T) {
+	aggregator := NewHealthSignalAggregator(
+		true,
+		time.Minute,
+		100,
+		dynamicconfig.GetDurationPropertyFn(0),
+		dynamicconfig.GetFloatPropertyFn(1),
+		dynamicconfig.GetIntPropertyFn(100),
+		dynamicconfig.GetIntPropertyFn(0),
+		dynamicconfig.GetIntPropertyFn(0),
+		nil,
+		metrics.NoopMetricsHandler,
+		log.NewNoopLogger(),
+	)
+
+	before := time.Now().UTC()
+	aggregator.Record(1, "", "", OperationClassRead, 10*time.Millisecond, nil)
+	aggregator.Record(1, "", "", OperationClassWrite, 30*time.Millisecond, &TimeoutError{Msg: "timeout"})
+	aggregator.Record(2, "", "", OperationClassRead, 20*time.Millisecond, nil)
+
+	snapshot := aggregator.Snapshot()
+	require.False(t, snapshot.Timestamp.Before(before))
+	require.InDelta(t, 20.0, snapshot.AverageLatency, 0.001)
+	require.InDelta(t, 1.0/3, snapshot.ErrorRatio, 0.001)
+	// the requests were counted within the last second
+	require.Equal(t, map[int32]int64{1: 2, 2: 1}, snapshot.ShardRPS)
+
+	// the returned map is a copy
+	snapshot.ShardRPS[1] = 100
+	delete(snapshot.ShardRPS, 2)
+	require.Equal(t, map[int32]int64{1: 2, 2: 1}, aggregator.Snapshot().ShardRPS)
+}
+
+func TestHealthSignalAggregator_OnWarnLimitExceeded(t *testing.T) {
+	type warning struct {
+		shardID   int32
+		namespace string
+		rps       int64
+		kind      WarnKind
+	}
+
+	var aggregator *healthSignalAggregatorImpl
+	var warnings []warning
+	aggregator = NewHealthSignalAggregator(
+		true,
+		time.Minute,
+		100,
+		dynamicconfig.GetDurationPropertyFn(0),
+		dynamicconfig.GetFloatPropertyFn(1),
+		dynamicconfig.GetIntPropertyFn(100),
+		dynamicconfig.GetIntPropertyFn(2),
+		dynamicconfig.GetIntPropertyFn(1),
+		func(shardID int32, namespace string, rps int64, kind WarnKind) {
+			// the callback is invoked outside of the lock guarding the request counts
+			aggregator.Record(3, "ns-c", headers.CallerTypeAPI, OperationClassRead, time.Millisecond, nil)
+			warnings = append(warnings, warning{shardID: shardID, namespace: namespace, rps: rps, kind: kind})
+		},
+		metrics.NoopMetricsHandler,
+		log.NewNoopLogger(),
+	)
+
+	recordRPS := func(shardID int32, namespace string, rps int) {
+		for range rps * int(emitMetricsInterval.Seconds()) {
+			aggregator.Record(shardID, namespace, headers.CallerTypeAPI, OperationClassRead, time.Millisecond, nil)
+		}
+	}
+	// shard 1 exceeds the shard limit, and ns-a exceeds the per-namespace limit on it
+	recordRPS(1, "ns-a", 2)
+	recordRPS(1, "ns-b", 1)
+	// shard 2 is within both limits, requests without a namespace only count towards the shard
+	recordRPS(2, "ns-a", 1)
+	recordRPS(2, "", 1)
+
+	aggregator.emitMetrics()
+
+	slices.SortFunc(warnings, func(a, b warning) int {
+		return strings.Compare(string(a.kind), string(b.kind))
+	})
+	require.Equal(t, []warning{
+		{shardID: 1, namespace: "ns-a", rps: 2, kind: WarnKindShardNamespaceRPS},
+		{shardID: 1, rps: 3, kind: WarnKindShardRPS},
+	}, warnings)
+}
+
+func TestHealthSignalAggregator_RecordWeighted(t *testing.T) {
+	aggregator := NewHealthSignalAggregator(
+		true,
+		time.Minute,
+		100,
+		dynamicconfig.GetDurationPropertyFn(0),
+		dynamicconfig.GetFloatPropertyFn(1),
+		dynamicconfig.GetIntPropertyFn(100),
+		dynamicconfig.GetIntPropertyFn(0),
+		dynamicconfig.GetIntPropertyFn(0),
+		nil,
+		metrics.NoopMetricsHandler,
+		log.NewNoopLogger(),
+	)
+
+	aggregator.Record(CallerSegmentMissing, "ns", "", OperationClassRead, 10*time.Millisecond, nil)
+	aggregator.RecordWeighted(CallerSegmentMissing, "ns", "", OperationClassWrite, 100*time.Millisecond, nil, 9)
+
+	require.InDelta(t, 91.0, aggregator.AverageLatency(), 0.001)
+	require.Equal(t, int64(10), aggregator.RequestCount())
+	require.InDelta(t, 100.0, aggregator.AverageLatencyByClass(OperationClassWrite), 0.001)
+	require.Equal(t, int64(9), aggregator.RequestCountByClass(OperationClassWrite))
+
+	aggregator.RecordWeighted(CallerSegmentMissing, "ns", "", OperationClassWrite, time.Millisecond, &TimeoutError{Msg: "timeout"}, 10)
+	require.InDelta(t, 0.5, aggregator.ErrorRatio(), 0.001)
+	require.InDelta(t, 0.5, aggregator.ErrorRatioForNamespace("ns"), 0.001)
+
+	// non-positive weights count as a single request
+	aggregator.RecordWeighted(CallerSegmentMissing, "ns", "", OperationClassRead, time.Millisecond, nil, 0)
+	require.Equal(t, int64(21), aggregator.RequestCount())
+}
+
+func TestHealthSignalAggregator_CallerTypeRPS(t *testing.T) {
+	metricsHandler := metricstest.NewCaptureHandler()
+	capture := metricsHandler.StartCapture()
+	defer metricsHandler.StopCapture(capture)
+	aggregator := NewHealthSignalAggregator(
+		true,
+		time.Minute,
+		100,
+		dynamicconfig.GetDurationPropertyFn(0),
+		dynamicconfig.GetFloatPropertyFn(1),
+		dynamicconfig.GetIntPropertyFn(100),
+		dynamicconfig.GetIntPropertyFn(0),
+		dynamicconfig.GetIntPropertyFn(0),
+		nil,
+		metricsHandler,
+		log.NewNoopLogger(),
+	)
+
+	recordRPS := func(callerType string, namespace string, rps int) {
+		for range rps * int(emitMetricsInterval.Seconds()) {
+			aggregator.Record(1, namespace, callerType, OperationClassRead, time.Millisecond, nil)
+		}
+	}
+	recordRPS(headers.CallerTypeAPI, "ns-a", 3)
+	recordRPS(headers.CallerTypeAPI, "ns-b", 1)
+	recordRPS(headers.CallerTypeBackgroundHigh, headers.CallerNameSystem, 2)
+	recordRPS("", "ns-a", 1)
+
+	aggregator.emitMetrics()
+
+	callerTypeRPS := make(map[string]int64)
+	for _, recording := range capture.Snapshot()[metrics.PersistenceShardCallerTypeRPS.Name()] {
+		callerTypeRPS[recording.Tags["caller_type"]] = recording.Value.(int64)
+	}
+	require.Equal(t, map[string]int64{
+		headers.CallerTypeAPI:            4,
+		headers.CallerTypeBackgroundHigh: 2,
+		"_unknown_":                      1,
+	}, callerTypeRPS)
+
+	shardRPS := capture.Snapshot()[metrics.PersistenceShardRPS.Name()]
+	require.Len(t, shardRPS, 1)
+	require.Equal(t, int64(7), shardRPS[0].Value)
+}
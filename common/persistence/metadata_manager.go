@@ -2,6 +2,8 @@ package persistence
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	enumspb "go.temporal.io/api/enums/v1"
 	namespacepb "go.temporal.io/api/namespace/v1"
@@ -183,14 +185,17 @@ func (m *metadataManagerImpl) ListNamespaces(
 		if err != nil {
 			return nil, err
 		}
-		deletedNamespacesCount := 0
+		filteredNamespacesCount := 0
 		for _, d := range resp.Namespaces {
 			ret, err := m.ConvertInternalGetResponse(d)
 			if err != nil {
 				return nil, err
 			}
-			if ret.Namespace.Info.State == enumspb.NAMESPACE_STATE_DELETED && !request.IncludeDeleted {
-				deletedNamespacesCount++
+			state := ret.Namespace.Info.State
+			if state == enumspb.NAMESPACE_STATE_DELETED && !request.IncludeDeleted ||
+				len(request.States) > 0 && !slices.Contains(request.States, state) ||
+				!strings.HasPrefix(ret.Namespace.Info.Name, request.NamePrefix) {
+				filteredNamespacesCount++
 				continue
 			}
 			namespaces = append(namespaces, ret)
@@ -200,11 +205,16 @@ func (m *metadataManagerImpl) ListNamespaces(
 			// Page wasn't full, no more namespaces in DB.
 			break
 		}
-		if deletedNamespacesCount == 0 {
+		if filteredNamespacesCount == 0 {
 			break
 		}
-		// Page was full but few namespaces weren't added. Read number of deleted namespaces for DB again.
-		pageSize = deletedNamespacesCount
+		if len(request.States) > 0 || request.NamePrefix != "" {
+			// The state and name prefix filters may filter out most of the namespaces, refilling the page could
+			// read the whole table. The page is returned short, or even empty, with the next page token instead.
+			break
+		}
+		// Page was full but few namespaces weren't added. Read number of filtered namespaces for DB again.
+		pageSize = filteredNamespacesCount
 	}
 
 	return &ListNamespacesResponse{
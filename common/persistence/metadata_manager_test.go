@@ -0,0 +1,127 @@
+package persistence_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	enumspb "go.temporal.io/api/enums/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/mock"
+	"go.temporal.io/server/common/persistence/serialization"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMetadataManager_ListNamespaces_States(t *testing.T) {
+	t.Parallel()
+
+	manager := newListNamespacesTestManager(t, []*persistencespb.NamespaceInfo{
+		{Name: "ns-1", State: enumspb.NAMESPACE_STATE_REGISTERED},
+		{Name: "ns-2", State: enumspb.NAMESPACE_STATE_DEPRECATED},
+		{Name: "ns-3", State: enumspb.NAMESPACE_STATE_DELETED},
+		{Name: "ns-4", State: enumspb.NAMESPACE_STATE_DEPRECATED},
+		{Name: "ns-5", State: enumspb.NAMESPACE_STATE_REGISTERED},
+		{Name: "ns-6", State: enumspb.NAMESPACE_STATE_DEPRECATED},
+	})
+
+	// filtered rows are not refilled from the next rows of the store, each page reads a single page of the store
+	require.Equal(t, [][]string{{"ns-2"}, {"ns-4"}, {"ns-6"}}, listNamespacePages(t, manager, &persistence.ListNamespacesRequest{
+		PageSize: 2,
+		States:   []enumspb.NamespaceState{enumspb.NAMESPACE_STATE_DEPRECATED},
+	}))
+	require.Equal(t, [][]string{{"ns-1"}, {"ns-3"}, {"ns-5"}}, listNamespacePages(t, manager, &persistence.ListNamespacesRequest{
+		PageSize:       2,
+		IncludeDeleted: true,
+		States:         []enumspb.NamespaceState{enumspb.NAMESPACE_STATE_REGISTERED, enumspb.NAMESPACE_STATE_DELETED},
+	}))
+	// deleted namespaces still require IncludeDeleted, every row is filtered out
+	require.Equal(t, [][]string{nil, nil, nil}, listNamespacePages(t, manager, &persistence.ListNamespacesRequest{
+		PageSize: 2,
+		States:   []enumspb.NamespaceState{enumspb.NAMESPACE_STATE_DELETED},
+	}))
+}
+
+func TestMetadataManager_ListNamespaces_NamePrefix(t *testing.T) {
+	t.Parallel()
+
+	manager := newListNamespacesTestManager(t, []*persistencespb.NamespaceInfo{
+		{Name: "orders-1", State: enumspb.NAMESPACE_STATE_REGISTERED},
+		{Name: "Orders-2", State: enumspb.NAMESPACE_STATE_REGISTERED},
+		{Name: "payments-3", State: enumspb.NAMESPACE_STATE_REGISTERED},
+		{Name: "orders-4", State: enumspb.NAMESPACE_STATE_DELETED},
+		{Name: "orders-5", State: enumspb.NAMESPACE_STATE_REGISTERED},
+	})
+
+	// the prefix is case-sensitive
+	require.Equal(t, [][]string{{"orders-1"}, nil, {"orders-5"}}, listNamespacePages(t, manager, &persistence.ListNamespacesRequest{
+		PageSize:   2,
+		NamePrefix: "orders",
+	}))
+	require.Equal(t, [][]string{{"Orders-2"}, nil, nil}, listNamespacePages(t, manager, &persistence.ListNamespacesRequest{
+		PageSize:   2,
+		NamePrefix: "Orders",
+	}))
+	// pages where every row is filtered out are empty but keep the next page token, and the pages end with the store
+	require.Equal(t, [][]string{nil, nil, nil}, listNamespacePages(t, manager, &persistence.ListNamespacesRequest{
+		PageSize:   2,
+		NamePrefix: "refunds",
+	}))
+}
+
+// newListNamespacesTestManager returns a metadata manager over a store paging through the given namespaces, with
+// the offset of the next namespace as the page token.
+func newListNamespacesTestManager(t *testing.T, infos []*persistencespb.NamespaceInfo) persistence.MetadataManager {
+	serializer := serialization.NewSerializer()
+	var rows []*persistence.InternalGetNamespaceResponse
+	for _, info := range infos {
+		blob, err := serializer.NamespaceDetailToBlob(&persistencespb.NamespaceDetail{
+			Info:              info,
+			Config:            &persistencespb.NamespaceConfig{},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+		})
+		require.NoError(t, err)
+		rows = append(rows, &persistence.InternalGetNamespaceResponse{Namespace: blob})
+	}
+
+	store := mock.NewMockMetadataStore(gomock.NewController(t))
+	store.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.InternalListNamespacesRequest) (*persistence.InternalListNamespacesResponse, error) {
+			offset := 0
+			if len(request.NextPageToken) > 0 {
+				offset, _ = strconv.Atoi(string(request.NextPageToken))
+			}
+			end := min(offset+request.PageSize, len(rows))
+			response := &persistence.InternalListNamespacesResponse{Namespaces: rows[offset:end]}
+			if end < len(rows) {
+				response.NextPageToken = []byte(strconv.Itoa(end))
+			}
+			return response, nil
+		},
+	).AnyTimes()
+	return persistence.NewMetadataManagerImpl(store, serializer, log.NewNoopLogger(), "active")
+}
+
+// listNamespacePages returns the namespace names of each page listed with the request.
+func listNamespacePages(
+	t *testing.T,
+	manager persistence.MetadataManager,
+	request *persistence.ListNamespacesRequest,
+) [][]string {
+	var pages [][]string
+	for {
+		response, err := manager.ListNamespaces(context.Background(), request)
+		require.NoError(t, err)
+		var names []string
+		for _, ns := range response.Namespaces {
+			names = append(names, ns.Namespace.Info.Name)
+		}
+		pages = append(pages, names)
+		if len(response.NextPageToken) == 0 {
+			return pages
+		}
+		request.NextPageToken = response.NextPageToken
+	}
+}
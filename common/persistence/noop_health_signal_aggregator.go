@@ -16,12 +16,44 @@ func (a *noopSignalAggregator) Start() {}
 
 func (a *noopSignalAggregator) Stop() {}
 
-func (a *noopSignalAggregator) Record(_ int32, _ time.Duration, _ error) {}
+func (a *noopSignalAggregator) Record(_ int32, _ string, _ string, _ OperationClass, _ time.Duration, _ error) {
+}
+
+func (a *noopSignalAggregator) RecordWeighted(
+	_ int32,
+	_ string,
+	_ string,
+	_ OperationClass,
+	_ time.Duration,
+	_ error,
+	_ int,
+) {
+}
 
 func (a *noopSignalAggregator) AverageLatency() float64 {
 	return 0
 }
 
+func (a *noopSignalAggregator) AverageLatencyByClass(_ OperationClass) float64 {
+	return 0
+}
+
 func (*noopSignalAggregator) ErrorRatio() float64 {
 	return 0
 }
+
+func (*noopSignalAggregator) ErrorRatioForNamespace(_ string) float64 {
+	return 0
+}
+
+func (*noopSignalAggregator) RequestCount() int64 {
+	return 0
+}
+
+func (*noopSignalAggregator) RequestCountByClass(_ OperationClass) int64 {
+	return 0
+}
+
+func (*noopSignalAggregator) Snapshot() HealthSignalSnapshot {
+	return HealthSignalSnapshot{Timestamp: time.Now().UTC(), ShardRPS: map[int32]int64{}}
+}
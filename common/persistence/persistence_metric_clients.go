@@ -164,12 +164,12 @@ func (p *shardPersistenceClient) GetOrCreateShard(
 	ctx context.Context,
 	request *GetOrCreateShardRequest,
 ) (_ *GetOrCreateShardResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
 		latency := time.Since(startTime)
-		p.healthSignals.Record(request.ShardID, latency, retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetOrCreateShardScope, caller, latency, retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, latency, retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetOrCreateShardScope, callerInfo.CallerName, latency, retErr)
 	}()
 	return p.persistence.GetOrCreateShard(ctx, request)
 }
@@ -178,11 +178,11 @@ func (p *shardPersistenceClient) UpdateShard(
 	ctx context.Context,
 	request *UpdateShardRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardInfo.GetShardId(), time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceUpdateShardScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardInfo.GetShardId(), callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceUpdateShardScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.UpdateShard(ctx, request)
 }
@@ -191,11 +191,11 @@ func (p *shardPersistenceClient) AssertShardOwnership(
 	ctx context.Context,
 	request *AssertShardOwnershipRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceAssertShardOwnershipScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceAssertShardOwnershipScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.AssertShardOwnership(ctx, request)
 }
@@ -216,11 +216,11 @@ func (p *executionPersistenceClient) CreateWorkflowExecution(
 	ctx context.Context,
 	request *CreateWorkflowExecutionRequest,
 ) (_ *CreateWorkflowExecutionResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceCreateWorkflowExecutionScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceCreateWorkflowExecutionScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.CreateWorkflowExecution(ctx, request)
 }
@@ -229,11 +229,11 @@ func (p *executionPersistenceClient) GetWorkflowExecution(
 	ctx context.Context,
 	request *GetWorkflowExecutionRequest,
 ) (_ *GetWorkflowExecutionResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetWorkflowExecutionScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetWorkflowExecutionScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetWorkflowExecution(ctx, request)
 }
@@ -242,11 +242,11 @@ func (p *executionPersistenceClient) SetWorkflowExecution(
 	ctx context.Context,
 	request *SetWorkflowExecutionRequest,
 ) (_ *SetWorkflowExecutionResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceSetWorkflowExecutionScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceSetWorkflowExecutionScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.SetWorkflowExecution(ctx, request)
 }
@@ -255,11 +255,11 @@ func (p *executionPersistenceClient) UpdateWorkflowExecution(
 	ctx context.Context,
 	request *UpdateWorkflowExecutionRequest,
 ) (_ *UpdateWorkflowExecutionResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceUpdateWorkflowExecutionScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceUpdateWorkflowExecutionScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.UpdateWorkflowExecution(ctx, request)
 }
@@ -268,11 +268,11 @@ func (p *executionPersistenceClient) ConflictResolveWorkflowExecution(
 	ctx context.Context,
 	request *ConflictResolveWorkflowExecutionRequest,
 ) (_ *ConflictResolveWorkflowExecutionResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceConflictResolveWorkflowExecutionScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceConflictResolveWorkflowExecutionScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.ConflictResolveWorkflowExecution(ctx, request)
 }
@@ -281,11 +281,11 @@ func (p *executionPersistenceClient) DeleteWorkflowExecution(
 	ctx context.Context,
 	request *DeleteWorkflowExecutionRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceDeleteWorkflowExecutionScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceDeleteWorkflowExecutionScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.DeleteWorkflowExecution(ctx, request)
 }
@@ -294,11 +294,11 @@ func (p *executionPersistenceClient) DeleteCurrentWorkflowExecution(
 	ctx context.Context,
 	request *DeleteCurrentWorkflowExecutionRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceDeleteCurrentWorkflowExecutionScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceDeleteCurrentWorkflowExecutionScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.DeleteCurrentWorkflowExecution(ctx, request)
 }
@@ -307,11 +307,11 @@ func (p *executionPersistenceClient) GetCurrentExecution(
 	ctx context.Context,
 	request *GetCurrentExecutionRequest,
 ) (_ *GetCurrentExecutionResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetCurrentExecutionScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetCurrentExecutionScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetCurrentExecution(ctx, request)
 }
@@ -320,11 +320,11 @@ func (p *executionPersistenceClient) ListConcreteExecutions(
 	ctx context.Context,
 	request *ListConcreteExecutionsRequest,
 ) (_ *ListConcreteExecutionsResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceListConcreteExecutionsScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceListConcreteExecutionsScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.ListConcreteExecutions(ctx, request)
 }
@@ -333,11 +333,11 @@ func (p *executionPersistenceClient) AddHistoryTasks(
 	ctx context.Context,
 	request *AddHistoryTasksRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceAddTasksScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceAddTasksScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.AddHistoryTasks(ctx, request)
 }
@@ -364,11 +364,11 @@ func (p *executionPersistenceClient) GetHistoryTasks(
 		return nil, serviceerror.NewInternalf("unknown task category type: %v", request.TaskCategory)
 	}
 
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(operation, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(operation, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetHistoryTasks(ctx, request)
 }
@@ -395,11 +395,11 @@ func (p *executionPersistenceClient) CompleteHistoryTask(
 		return serviceerror.NewInternalf("unknown task category type: %v", request.TaskCategory)
 	}
 
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(operation, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(operation, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.CompleteHistoryTask(ctx, request)
 }
@@ -426,11 +426,11 @@ func (p *executionPersistenceClient) RangeCompleteHistoryTasks(
 		return serviceerror.NewInternalf("unknown task category type: %v", request.TaskCategory)
 	}
 
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(operation, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(operation, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.RangeCompleteHistoryTasks(ctx, request)
 }
@@ -439,11 +439,11 @@ func (p *executionPersistenceClient) PutReplicationTaskToDLQ(
 	ctx context.Context,
 	request *PutReplicationTaskToDLQRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistencePutReplicationTaskToDLQScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistencePutReplicationTaskToDLQScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.PutReplicationTaskToDLQ(ctx, request)
 }
@@ -452,11 +452,11 @@ func (p *executionPersistenceClient) GetReplicationTasksFromDLQ(
 	ctx context.Context,
 	request *GetReplicationTasksFromDLQRequest,
 ) (_ *GetHistoryTasksResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetReplicationTasksFromDLQScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetReplicationTasksFromDLQScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetReplicationTasksFromDLQ(ctx, request)
 }
@@ -465,11 +465,11 @@ func (p *executionPersistenceClient) DeleteReplicationTaskFromDLQ(
 	ctx context.Context,
 	request *DeleteReplicationTaskFromDLQRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceDeleteReplicationTaskFromDLQScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceDeleteReplicationTaskFromDLQScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.DeleteReplicationTaskFromDLQ(ctx, request)
 }
@@ -478,11 +478,11 @@ func (p *executionPersistenceClient) RangeDeleteReplicationTaskFromDLQ(
 	ctx context.Context,
 	request *RangeDeleteReplicationTaskFromDLQRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceRangeDeleteReplicationTaskFromDLQScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceRangeDeleteReplicationTaskFromDLQScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.RangeDeleteReplicationTaskFromDLQ(ctx, request)
 }
@@ -491,11 +491,11 @@ func (p *executionPersistenceClient) IsReplicationDLQEmpty(
 	ctx context.Context,
 	request *GetReplicationTasksFromDLQRequest,
 ) (_ bool, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(request.ShardID, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetReplicationTasksFromDLQScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(request.ShardID, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetReplicationTasksFromDLQScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.IsReplicationDLQEmpty(ctx, request)
 }
@@ -512,11 +512,11 @@ func (p *taskPersistenceClient) CreateTasks(
 	ctx context.Context,
 	request *CreateTasksRequest,
 ) (_ *CreateTasksResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceCreateTasksScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceCreateTasksScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.CreateTasks(ctx, request)
 }
@@ -525,11 +525,11 @@ func (p *taskPersistenceClient) GetTasks(
 	ctx context.Context,
 	request *GetTasksRequest,
 ) (_ *GetTasksResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetTasksScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetTasksScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetTasks(ctx, request)
 }
@@ -538,11 +538,11 @@ func (p *taskPersistenceClient) CompleteTasksLessThan(
 	ctx context.Context,
 	request *CompleteTasksLessThanRequest,
 ) (_ int, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceCompleteTasksLessThanScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceCompleteTasksLessThanScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.CompleteTasksLessThan(ctx, request)
 }
@@ -551,11 +551,11 @@ func (p *taskPersistenceClient) CreateTaskQueue(
 	ctx context.Context,
 	request *CreateTaskQueueRequest,
 ) (_ *CreateTaskQueueResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceCreateTaskQueueScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceCreateTaskQueueScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.CreateTaskQueue(ctx, request)
 }
@@ -564,11 +564,11 @@ func (p *taskPersistenceClient) UpdateTaskQueue(
 	ctx context.Context,
 	request *UpdateTaskQueueRequest,
 ) (_ *UpdateTaskQueueResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceUpdateTaskQueueScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceUpdateTaskQueueScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.UpdateTaskQueue(ctx, request)
 }
@@ -577,11 +577,11 @@ func (p *taskPersistenceClient) GetTaskQueue(
 	ctx context.Context,
 	request *GetTaskQueueRequest,
 ) (_ *GetTaskQueueResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetTaskQueueScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetTaskQueueScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetTaskQueue(ctx, request)
 }
@@ -590,11 +590,11 @@ func (p *taskPersistenceClient) ListTaskQueue(
 	ctx context.Context,
 	request *ListTaskQueueRequest,
 ) (_ *ListTaskQueueResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceListTaskQueueScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceListTaskQueueScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.ListTaskQueue(ctx, request)
 }
@@ -603,11 +603,11 @@ func (p *taskPersistenceClient) DeleteTaskQueue(
 	ctx context.Context,
 	request *DeleteTaskQueueRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceDeleteTaskQueueScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceDeleteTaskQueueScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.DeleteTaskQueue(ctx, request)
 }
@@ -616,11 +616,11 @@ func (p *taskPersistenceClient) GetTaskQueueUserData(
 	ctx context.Context,
 	request *GetTaskQueueUserDataRequest,
 ) (_ *GetTaskQueueUserDataResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetTaskQueueUserDataScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetTaskQueueUserDataScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetTaskQueueUserData(ctx, request)
 }
@@ -629,11 +629,11 @@ func (p *taskPersistenceClient) UpdateTaskQueueUserData(
 	ctx context.Context,
 	request *UpdateTaskQueueUserDataRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceUpdateTaskQueueUserDataScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceUpdateTaskQueueUserDataScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.UpdateTaskQueueUserData(ctx, request)
 }
@@ -642,31 +642,31 @@ func (p *taskPersistenceClient) ListTaskQueueUserDataEntries(
 	ctx context.Context,
 	request *ListTaskQueueUserDataEntriesRequest,
 ) (_ *ListTaskQueueUserDataEntriesResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceListTaskQueueUserDataEntriesScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceListTaskQueueUserDataEntriesScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.ListTaskQueueUserDataEntries(ctx, request)
 }
 
 func (p *taskPersistenceClient) GetTaskQueuesByBuildId(ctx context.Context, request *GetTaskQueuesByBuildIdRequest) (_ []string, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetTaskQueuesByBuildIdScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetTaskQueuesByBuildIdScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetTaskQueuesByBuildId(ctx, request)
 }
 
 func (p *taskPersistenceClient) CountTaskQueuesByBuildId(ctx context.Context, request *CountTaskQueuesByBuildIdRequest) (_ int, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceCountTaskQueuesByBuildIdScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceCountTaskQueuesByBuildIdScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.CountTaskQueuesByBuildId(ctx, request)
 }
@@ -683,11 +683,11 @@ func (p *metadataPersistenceClient) CreateNamespace(
 	ctx context.Context,
 	request *CreateNamespaceRequest,
 ) (_ *CreateNamespaceResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceCreateNamespaceScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceCreateNamespaceScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.CreateNamespace(ctx, request)
 }
@@ -696,11 +696,11 @@ func (p *metadataPersistenceClient) GetNamespace(
 	ctx context.Context,
 	request *GetNamespaceRequest,
 ) (_ *GetNamespaceResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetNamespaceScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetNamespaceScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetNamespace(ctx, request)
 }
@@ -709,11 +709,11 @@ func (p *metadataPersistenceClient) UpdateNamespace(
 	ctx context.Context,
 	request *UpdateNamespaceRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceUpdateNamespaceScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceUpdateNamespaceScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.UpdateNamespace(ctx, request)
 }
@@ -722,11 +722,11 @@ func (p *metadataPersistenceClient) RenameNamespace(
 	ctx context.Context,
 	request *RenameNamespaceRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceRenameNamespaceScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceRenameNamespaceScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.RenameNamespace(ctx, request)
 }
@@ -735,11 +735,11 @@ func (p *metadataPersistenceClient) DeleteNamespace(
 	ctx context.Context,
 	request *DeleteNamespaceRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceDeleteNamespaceScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceDeleteNamespaceScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.DeleteNamespace(ctx, request)
 }
@@ -748,11 +748,11 @@ func (p *metadataPersistenceClient) DeleteNamespaceByName(
 	ctx context.Context,
 	request *DeleteNamespaceByNameRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceDeleteNamespaceByNameScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceDeleteNamespaceByNameScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.DeleteNamespaceByName(ctx, request)
 }
@@ -761,11 +761,11 @@ func (p *metadataPersistenceClient) ListNamespaces(
 	ctx context.Context,
 	request *ListNamespacesRequest,
 ) (_ *ListNamespacesResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceListNamespacesScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceListNamespacesScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.ListNamespaces(ctx, request)
 }
@@ -773,11 +773,11 @@ func (p *metadataPersistenceClient) ListNamespaces(
 func (p *metadataPersistenceClient) GetMetadata(
 	ctx context.Context,
 ) (_ *GetMetadataResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetMetadataScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetMetadataScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetMetadata(ctx)
 }
@@ -791,11 +791,11 @@ func (p *executionPersistenceClient) AppendHistoryNodes(
 	ctx context.Context,
 	request *AppendHistoryNodesRequest,
 ) (_ *AppendHistoryNodesResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceAppendHistoryNodesScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceAppendHistoryNodesScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.AppendHistoryNodes(ctx, request)
 }
@@ -805,11 +805,11 @@ func (p *executionPersistenceClient) AppendRawHistoryNodes(
 	ctx context.Context,
 	request *AppendRawHistoryNodesRequest,
 ) (_ *AppendHistoryNodesResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceAppendRawHistoryNodesScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceAppendRawHistoryNodesScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.AppendRawHistoryNodes(ctx, request)
 }
@@ -896,11 +896,11 @@ func (p *executionPersistenceClient) TrimHistoryBranch(
 	ctx context.Context,
 	request *TrimHistoryBranchRequest,
 ) (_ *TrimHistoryBranchResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceTrimHistoryBranchScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceTrimHistoryBranchScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.TrimHistoryBranch(ctx, request)
 }
@@ -909,11 +909,11 @@ func (p *executionPersistenceClient) GetAllHistoryTreeBranches(
 	ctx context.Context,
 	request *GetAllHistoryTreeBranchesRequest,
 ) (_ *GetAllHistoryTreeBranchesResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetAllHistoryTreeBranchesScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetAllHistoryTreeBranchesScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetAllHistoryTreeBranches(ctx, request)
 }
@@ -929,11 +929,11 @@ func (p *queuePersistenceClient) EnqueueMessage(
 	ctx context.Context,
 	blob *commonpb.DataBlob,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceEnqueueMessageScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceEnqueueMessageScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.EnqueueMessage(ctx, blob)
 }
@@ -943,11 +943,11 @@ func (p *queuePersistenceClient) ReadMessages(
 	lastMessageID int64,
 	maxCount int,
 ) (_ []*QueueMessage, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceReadQueueMessagesScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceReadQueueMessagesScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.ReadMessages(ctx, lastMessageID, maxCount)
 }
@@ -956,11 +956,11 @@ func (p *queuePersistenceClient) UpdateAckLevel(
 	ctx context.Context,
 	metadata *InternalQueueMetadata,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceUpdateAckLevelScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceUpdateAckLevelScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.UpdateAckLevel(ctx, metadata)
 }
@@ -968,11 +968,11 @@ func (p *queuePersistenceClient) UpdateAckLevel(
 func (p *queuePersistenceClient) GetAckLevels(
 	ctx context.Context,
 ) (_ *InternalQueueMetadata, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetAckLevelScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetAckLevelScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetAckLevels(ctx)
 }
@@ -981,11 +981,11 @@ func (p *queuePersistenceClient) DeleteMessagesBefore(
 	ctx context.Context,
 	messageID int64,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceDeleteMessagesBeforeScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceDeleteMessagesBeforeScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.DeleteMessagesBefore(ctx, messageID)
 }
@@ -994,11 +994,11 @@ func (p *queuePersistenceClient) EnqueueMessageToDLQ(
 	ctx context.Context,
 	blob *commonpb.DataBlob,
 ) (_ int64, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceEnqueueMessageToDLQScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceEnqueueMessageToDLQScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.EnqueueMessageToDLQ(ctx, blob)
 }
@@ -1010,11 +1010,11 @@ func (p *queuePersistenceClient) ReadMessagesFromDLQ(
 	pageSize int,
 	pageToken []byte,
 ) (_ []*QueueMessage, _ []byte, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceReadMessagesFromDLQScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceReadMessagesFromDLQScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.ReadMessagesFromDLQ(ctx, firstMessageID, lastMessageID, pageSize, pageToken)
 }
@@ -1023,11 +1023,11 @@ func (p *queuePersistenceClient) DeleteMessageFromDLQ(
 	ctx context.Context,
 	messageID int64,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceDeleteMessageFromDLQScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceDeleteMessageFromDLQScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.DeleteMessageFromDLQ(ctx, messageID)
 }
@@ -1037,11 +1037,11 @@ func (p *queuePersistenceClient) RangeDeleteMessagesFromDLQ(
 	firstMessageID int64,
 	lastMessageID int64,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceRangeDeleteMessagesFromDLQScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceRangeDeleteMessagesFromDLQScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.RangeDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID)
 }
@@ -1050,11 +1050,11 @@ func (p *queuePersistenceClient) UpdateDLQAckLevel(
 	ctx context.Context,
 	metadata *InternalQueueMetadata,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceUpdateDLQAckLevelScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceUpdateDLQAckLevelScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.UpdateDLQAckLevel(ctx, metadata)
 }
@@ -1062,11 +1062,11 @@ func (p *queuePersistenceClient) UpdateDLQAckLevel(
 func (p *queuePersistenceClient) GetDLQAckLevels(
 	ctx context.Context,
 ) (_ *InternalQueueMetadata, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetDLQAckLevelScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetDLQAckLevelScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetDLQAckLevels(ctx)
 }
@@ -1083,11 +1083,11 @@ func (p *clusterMetadataPersistenceClient) ListClusterMetadata(
 	ctx context.Context,
 	request *ListClusterMetadataRequest,
 ) (_ *ListClusterMetadataResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceListClusterMetadataScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceListClusterMetadataScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.ListClusterMetadata(ctx, request)
 }
@@ -1095,11 +1095,11 @@ func (p *clusterMetadataPersistenceClient) ListClusterMetadata(
 func (p *clusterMetadataPersistenceClient) GetCurrentClusterMetadata(
 	ctx context.Context,
 ) (_ *GetClusterMetadataResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetCurrentClusterMetadataScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetCurrentClusterMetadataScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetCurrentClusterMetadata(ctx)
 }
@@ -1108,11 +1108,11 @@ func (p *clusterMetadataPersistenceClient) GetClusterMetadata(
 	ctx context.Context,
 	request *GetClusterMetadataRequest,
 ) (_ *GetClusterMetadataResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetClusterMetadataScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetClusterMetadataScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetClusterMetadata(ctx, request)
 }
@@ -1121,11 +1121,11 @@ func (p *clusterMetadataPersistenceClient) SaveClusterMetadata(
 	ctx context.Context,
 	request *SaveClusterMetadataRequest,
 ) (_ bool, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceSaveClusterMetadataScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceSaveClusterMetadataScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.SaveClusterMetadata(ctx, request)
 }
@@ -1134,11 +1134,11 @@ func (p *clusterMetadataPersistenceClient) DeleteClusterMetadata(
 	ctx context.Context,
 	request *DeleteClusterMetadataRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceDeleteClusterMetadataScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceDeleteClusterMetadataScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.DeleteClusterMetadata(ctx, request)
 }
@@ -1151,11 +1151,11 @@ func (p *clusterMetadataPersistenceClient) GetClusterMembers(
 	ctx context.Context,
 	request *GetClusterMembersRequest,
 ) (_ *GetClusterMembersResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetClusterMembersScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetClusterMembersScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetClusterMembers(ctx, request)
 }
@@ -1164,11 +1164,11 @@ func (p *clusterMetadataPersistenceClient) UpsertClusterMembership(
 	ctx context.Context,
 	request *UpsertClusterMembershipRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceUpsertClusterMembershipScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceUpsertClusterMembershipScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.UpsertClusterMembership(ctx, request)
 }
@@ -1177,11 +1177,11 @@ func (p *clusterMetadataPersistenceClient) PruneClusterMembership(
 	ctx context.Context,
 	request *PruneClusterMembershipRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistencePruneClusterMembershipScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistencePruneClusterMembershipScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.PruneClusterMembership(ctx, request)
 }
@@ -1190,11 +1190,11 @@ func (p *metadataPersistenceClient) InitializeSystemNamespaces(
 	ctx context.Context,
 	currentClusterName string,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceInitializeSystemNamespaceScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceInitializeSystemNamespaceScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.InitializeSystemNamespaces(ctx, currentClusterName)
 }
@@ -1211,11 +1211,11 @@ func (p *nexusEndpointPersistenceClient) GetNexusEndpoint(
 	ctx context.Context,
 	request *GetNexusEndpointRequest,
 ) (_ *persistencespb.NexusEndpointEntry, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceGetNexusEndpointScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceGetNexusEndpointScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.GetNexusEndpoint(ctx, request)
 }
@@ -1224,11 +1224,11 @@ func (p *nexusEndpointPersistenceClient) ListNexusEndpoints(
 	ctx context.Context,
 	request *ListNexusEndpointsRequest,
 ) (_ *ListNexusEndpointsResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceListNexusEndpointsScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassRead, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceListNexusEndpointsScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.ListNexusEndpoints(ctx, request)
 }
@@ -1237,11 +1237,11 @@ func (p *nexusEndpointPersistenceClient) CreateOrUpdateNexusEndpoint(
 	ctx context.Context,
 	request *CreateOrUpdateNexusEndpointRequest,
 ) (_ *CreateOrUpdateNexusEndpointResponse, retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceCreateOrUpdateNexusEndpointScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceCreateOrUpdateNexusEndpointScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.CreateOrUpdateNexusEndpoint(ctx, request)
 }
@@ -1250,11 +1250,11 @@ func (p *nexusEndpointPersistenceClient) DeleteNexusEndpoint(
 	ctx context.Context,
 	request *DeleteNexusEndpointRequest,
 ) (retErr error) {
-	caller := headers.GetCallerInfo(ctx).CallerName
+	callerInfo := headers.GetCallerInfo(ctx)
 	startTime := time.Now().UTC()
 	defer func() {
-		p.healthSignals.Record(CallerSegmentMissing, time.Since(startTime), retErr)
-		p.recordRequestMetrics(metrics.PersistenceDeleteNexusEndpointScope, caller, time.Since(startTime), retErr)
+		p.healthSignals.Record(CallerSegmentMissing, callerInfo.CallerName, callerInfo.CallerType, OperationClassWrite, time.Since(startTime), retErr)
+		p.recordRequestMetrics(metrics.PersistenceDeleteNexusEndpointScope, callerInfo.CallerName, time.Since(startTime), retErr)
 	}()
 	return p.persistence.DeleteNexusEndpoint(ctx, request)
 }
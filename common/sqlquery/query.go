@@ -2,10 +2,12 @@ package sqlquery
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/temporalio/sqlparser"
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/server/common/primitives/timestamp"
 )
@@ -70,3 +72,42 @@ func ParseValue(sqlValue string) (interface{}, error) {
 
 	return nil, serviceerror.NewInvalidArgumentf("invalid expression: unable to parse %s", sqlValue)
 }
+
+// FilterColumnNames returns the distinct column names referenced by the filter, which is the WHERE clause of a query
+// without the "where" keyword, in order of their first appearance. Unlike an evaluation of the filter, which stops
+// once the result of an AND/OR expression is known, every condition of the filter is visited.
+func FilterColumnNames(filter string) ([]string, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return nil, serviceerror.NewInvalidArgument("filter is empty")
+	}
+	lowerFilter := strings.ToLower(filter)
+	if strings.HasPrefix(lowerFilter, "where ") || strings.HasPrefix(lowerFilter, "select ") {
+		return nil, serviceerror.NewInvalidArgumentf("invalid filter: %s", filter)
+	}
+
+	stmt, err := sqlparser.Parse(fmt.Sprintf(QueryTemplate, filter))
+	if err != nil {
+		return nil, serviceerror.NewInvalidArgumentf("malformed filter: %v", err)
+	}
+	selectStmt, ok := stmt.(*sqlparser.Select)
+	if !ok || selectStmt.Where == nil || selectStmt.Limit != nil {
+		return nil, serviceerror.NewInvalidArgumentf("invalid filter: %s", filter)
+	}
+
+	var names []string
+	err = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		colName, ok := node.(*sqlparser.ColName)
+		if !ok {
+			return true, nil
+		}
+		if name := sqlparser.String(colName); !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+		return false, nil
+	}, selectStmt.Where.Expr)
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
@@ -177,3 +177,55 @@ func TestConvertToTime(t *testing.T) {
 		assert.Zero(t, actualTime)
 	})
 }
+
+func TestFilterColumnNames(t *testing.T) {
+	tests := []struct {
+		name          string
+		filter        string
+		expected      []string
+		expectedError bool
+	}{
+		{
+			name:          "empty filter",
+			filter:        "",
+			expectedError: true,
+		},
+		{
+			name:          "filter with where keyword",
+			filter:        "where WorkflowId = 'workflow_id'",
+			expectedError: true,
+		},
+		{
+			name:          "malformed filter",
+			filter:        "WorkflowId = ",
+			expectedError: true,
+		},
+		{
+			name:     "single condition",
+			filter:   "WorkflowId = 'workflow_id'",
+			expected: []string{"WorkflowId"},
+		},
+		{
+			name:     "all conditions are visited",
+			filter:   "WorkflowId = 'workflow_id' AND (StartTime > '2023-10-26T14:30:00Z' OR WorkflowId starts_with 'w') AND CustomKeywordField = 'value'",
+			expected: []string{"WorkflowId", "StartTime", "CustomKeywordField"},
+		},
+		{
+			name:     "range condition",
+			filter:   "StartTime BETWEEN '2023-10-26T14:30:00Z' AND '2023-10-27T14:30:00Z'",
+			expected: []string{"StartTime"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, err := FilterColumnNames(tt.filter)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
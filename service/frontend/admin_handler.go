@@ -25,6 +25,7 @@ import (
 	clusterspb "go.temporal.io/server/api/cluster/v1"
 	commonspb "go.temporal.io/server/api/common/v1"
 	enumsspb "go.temporal.io/server/api/enums/v1"
+	historyspb "go.temporal.io/server/api/history/v1"
 	"go.temporal.io/server/api/historyservice/v1"
 	"go.temporal.io/server/api/matchingservice/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
@@ -68,6 +69,10 @@ const (
 	getNamespaceReplicationMessageBatchSize = 100
 	defaultLastMessageID                    = -1
 	listClustersPageSize                    = 100
+
+	archivalBacklogPageSize = 1000
+	// archivalBacklogMaxScanSizePerShard bounds the archival tasks read per shard to compute a namespace backlog.
+	archivalBacklogMaxScanSizePerShard = 10000
 )
 
 type (
@@ -104,6 +109,7 @@ type (
 		clusterMetadata            cluster.Metadata
 		healthServer               *health.Server
 		historyHealthChecker       HealthChecker
+		timeSource                 clock.TimeSource
 
 		// DEPRECATED: only history service on server side is supposed to
 		// use the following components.
@@ -111,6 +117,22 @@ type (
 		matchingClient       matchingservice.MatchingServiceClient
 	}
 
+	// NamespaceArchivalBacklog reports the archival tasks of a namespace which are due but not processed yet.
+	NamespaceArchivalBacklog struct {
+		Namespace   string
+		NamespaceID string
+		// ArchivalEnabled is false if neither history nor visibility archival is enabled for the namespace,
+		// or archival is disabled for the cluster. No archival tasks are created then, and the backlog is not set.
+		ArchivalEnabled  bool
+		PendingTaskCount int64
+		// OldestTaskFireTime is the fire time of the oldest pending task, it is zero if there are no pending tasks.
+		OldestTaskFireTime time.Time
+		OldestTaskAge      time.Duration
+		// Truncated is true if the scan of some shard stopped at archivalBacklogMaxScanSizePerShard tasks,
+		// in which case PendingTaskCount is a lower bound.
+		Truncated bool
+	}
+
 	NewAdminHandlerArgs struct {
 		PersistenceConfig                   *config.Persistence
 		Config                              *Config
@@ -222,6 +244,7 @@ func NewAdminHandler(
 		clusterMetadata:      args.ClusterMetadata,
 		healthServer:         args.HealthServer,
 		historyHealthChecker: historyHealthChecker,
+		timeSource:           args.TimeSource,
 		taskCategoryRegistry: args.CategoryRegistry,
 		matchingClient:       args.matchingClient,
 	}
@@ -876,6 +899,88 @@ func (adh *AdminHandler) ListHistoryTasks(
 	return resp.Response, nil
 }
 
+// DescribeNamespaceArchivalBacklog returns the number and age of the archival tasks of the namespace which are due
+// but not processed yet, by scanning the archival queue of every history shard. A growing backlog means archival
+// is falling behind, and history may be deleted by retention before it is archived.
+func (adh *AdminHandler) DescribeNamespaceArchivalBacklog(
+	ctx context.Context,
+	nsName string,
+) (*NamespaceArchivalBacklog, error) {
+	nsEntry, err := adh.namespaceRegistry.GetNamespace(namespace.Name(nsName))
+	if err != nil {
+		return nil, err
+	}
+	backlog := &NamespaceArchivalBacklog{
+		Namespace:   nsEntry.Name().String(),
+		NamespaceID: nsEntry.ID().String(),
+	}
+
+	_, clusterArchivalEnabled := adh.taskCategoryRegistry.GetCategoryByID(tasks.CategoryIDArchival)
+	backlog.ArchivalEnabled = clusterArchivalEnabled &&
+		(nsEntry.HistoryArchivalState().State == enumspb.ARCHIVAL_STATE_ENABLED ||
+			nsEntry.VisibilityArchivalState().State == enumspb.ARCHIVAL_STATE_ENABLED)
+	if !backlog.ArchivalEnabled {
+		return backlog, nil
+	}
+
+	now := adh.timeSource.Now()
+	for shardID := int32(1); shardID <= adh.numberOfHistoryShards; shardID++ {
+		if err := adh.scanShardArchivalBacklog(ctx, shardID, now, backlog); err != nil {
+			return nil, err
+		}
+	}
+	if !backlog.OldestTaskFireTime.IsZero() {
+		backlog.OldestTaskAge = now.Sub(backlog.OldestTaskFireTime)
+	}
+	return backlog, nil
+}
+
+func (adh *AdminHandler) scanShardArchivalBacklog(
+	ctx context.Context,
+	shardID int32,
+	now time.Time,
+	backlog *NamespaceArchivalBacklog,
+) error {
+	var nextPageToken []byte
+	scanned := 0
+	for {
+		resp, err := adh.historyClient.ListTasks(ctx, &historyservice.ListTasksRequest{
+			Request: &adminservice.ListHistoryTasksRequest{
+				ShardId:  shardID,
+				Category: tasks.CategoryIDArchival,
+				TaskRange: &historyspb.TaskRange{
+					InclusiveMinTaskKey: &historyspb.TaskKey{FireTime: timestamppb.New(tasks.MinimumKey.FireTime)},
+					ExclusiveMaxTaskKey: &historyspb.TaskKey{FireTime: timestamppb.New(now)},
+				},
+				BatchSize:     archivalBacklogPageSize,
+				NextPageToken: nextPageToken,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		for _, task := range resp.GetResponse().GetTasks() {
+			if task.GetNamespaceId() != backlog.NamespaceID {
+				continue
+			}
+			backlog.PendingTaskCount++
+			fireTime := task.GetFireTime().AsTime()
+			if backlog.OldestTaskFireTime.IsZero() || fireTime.Before(backlog.OldestTaskFireTime) {
+				backlog.OldestTaskFireTime = fireTime
+			}
+		}
+		scanned += len(resp.GetResponse().GetTasks())
+		nextPageToken = resp.GetResponse().GetNextPageToken()
+		if len(nextPageToken) == 0 {
+			return nil
+		}
+		if scanned >= archivalBacklogMaxScanSizePerShard {
+			backlog.Truncated = true
+			return nil
+		}
+	}
+}
+
 // DescribeHistoryHost returns information about the internal states of a history host
 func (adh *AdminHandler) DescribeHistoryHost(ctx context.Context, request *adminservice.DescribeHistoryHostRequest) (_ *adminservice.DescribeHistoryHostResponse, retError error) {
 	defer log.CapturePanic(adh.logger, &retError)
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/assert"
@@ -60,6 +61,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type (
@@ -2079,6 +2081,69 @@ func (s *adminHandlerSuite) TestImportWorkflowExecution_WithNonAliasedSearchAttr
 	}
 }
 
+func (s *adminHandlerSuite) TestDescribeNamespaceArchivalBacklog() {
+	now := time.Now().UTC()
+	s.handler.timeSource = clock.NewEventTimeSource().Update(now)
+	categoryRegistry := tasks.NewDefaultTaskCategoryRegistry()
+	categoryRegistry.AddCategory(tasks.CategoryArchival)
+	s.handler.taskCategoryRegistry = categoryRegistry
+
+	nsEntry := namespace.NewNamespaceForTest(
+		&persistencespb.NamespaceInfo{Name: s.namespace.String(), Id: s.namespaceID.String()},
+		&persistencespb.NamespaceConfig{HistoryArchivalState: enumspb.ARCHIVAL_STATE_ENABLED},
+		false,
+		nil,
+		int64(100),
+	)
+	s.mockNamespaceCache.EXPECT().GetNamespace(s.namespace).Return(nsEntry, nil)
+
+	newTask := func(namespaceID namespace.ID, fireTime time.Time) *adminservice.Task {
+		return &adminservice.Task{NamespaceId: namespaceID.String(), FireTime: timestamppb.New(fireTime)}
+	}
+	s.mockHistoryClient.EXPECT().ListTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *historyservice.ListTasksRequest, _ ...grpc.CallOption) (*historyservice.ListTasksResponse, error) {
+			s.Equal(int32(tasks.CategoryIDArchival), request.Request.Category)
+			s.Equal(now, request.Request.TaskRange.ExclusiveMaxTaskKey.FireTime.AsTime())
+			s.Nil(request.Request.NextPageToken)
+			return &historyservice.ListTasksResponse{Response: &adminservice.ListHistoryTasksResponse{
+				Tasks: []*adminservice.Task{
+					newTask(s.namespaceID, now.Add(-time.Minute)),
+					newTask("other-namespace-id", now.Add(-time.Hour)),
+				},
+				NextPageToken: []byte("token"),
+			}}, nil
+		})
+	s.mockHistoryClient.EXPECT().ListTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *historyservice.ListTasksRequest, _ ...grpc.CallOption) (*historyservice.ListTasksResponse, error) {
+			s.Equal([]byte("token"), request.Request.NextPageToken)
+			return &historyservice.ListTasksResponse{Response: &adminservice.ListHistoryTasksResponse{
+				Tasks: []*adminservice.Task{newTask(s.namespaceID, now.Add(-10*time.Minute))},
+			}}, nil
+		})
+
+	backlog, err := s.handler.DescribeNamespaceArchivalBacklog(context.Background(), s.namespace.String())
+	s.NoError(err)
+	s.True(backlog.ArchivalEnabled)
+	s.Equal(int64(2), backlog.PendingTaskCount)
+	s.Equal(now.Add(-10*time.Minute), backlog.OldestTaskFireTime)
+	s.Equal(10*time.Minute, backlog.OldestTaskAge)
+	s.False(backlog.Truncated)
+}
+
+func (s *adminHandlerSuite) TestDescribeNamespaceArchivalBacklog_ArchivalDisabled() {
+	categoryRegistry := tasks.NewDefaultTaskCategoryRegistry()
+	categoryRegistry.AddCategory(tasks.CategoryArchival)
+	s.handler.taskCategoryRegistry = categoryRegistry
+	s.mockNamespaceCache.EXPECT().GetNamespace(s.namespace).Return(s.namespaceEntry, nil)
+	s.mockHistoryClient.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+
+	backlog, err := s.handler.DescribeNamespaceArchivalBacklog(context.Background(), s.namespace.String())
+	s.NoError(err)
+	s.Equal(s.namespaceID.String(), backlog.NamespaceID)
+	s.False(backlog.ArchivalEnabled)
+	s.Zero(backlog.PendingTaskCount)
+}
+
 func (s *adminHandlerSuite) validatePhysicalTaskQueueInfo(expectedPhysicalTaskQueueInfo *taskqueuespb.PhysicalTaskQueueInfo,
 	responsePhysicalTaskQueueInfo *taskqueuespb.PhysicalTaskQueueInfo) {
 
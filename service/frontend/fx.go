@@ -56,6 +56,13 @@ type (
 	namespaceChecker struct {
 		r namespace.Registry
 	}
+
+	// FailoverVersionAllocatorParams are the failover version allocators supplied to the frontend, if any.
+	FailoverVersionAllocatorParams struct {
+		fx.In
+
+		Allocators FailoverVersionAllocators `optional:"true"`
+	}
 )
 
 var Module = fx.Options(
@@ -711,6 +718,7 @@ func HandlerProvider(
 	persistenceExecutionManager persistence.ExecutionManager,
 	clusterMetadataManager persistence.ClusterMetadataManager,
 	persistenceMetadataManager persistence.MetadataManager,
+	healthSignals persistence.HealthSignalAggregator,
 	clientBean client.Bean,
 	historyClient resource.HistoryClient,
 	matchingClient resource.MatchingClient,
@@ -729,6 +737,7 @@ func HandlerProvider(
 	membershipMonitor membership.Monitor,
 	healthInterceptor *interceptor.HealthInterceptor,
 	scheduleSpecBuilder *scheduler.SpecBuilder,
+	failoverVersionAllocatorParams FailoverVersionAllocatorParams,
 ) Handler {
 	wfHandler := NewWorkflowHandler(
 		serviceConfig,
@@ -739,6 +748,7 @@ func HandlerProvider(
 		persistenceExecutionManager.GetName(),
 		clusterMetadataManager,
 		persistenceMetadataManager,
+		healthSignals,
 		historyClient,
 		matchingClient,
 		deploymentStoreClient,
@@ -756,6 +766,8 @@ func HandlerProvider(
 		healthInterceptor,
 		scheduleSpecBuilder,
 		httpEnabled(cfg, serviceName),
+		failoverVersionAllocatorParams.Allocators,
+		metricsHandler,
 	)
 	return wfHandler
 }
@@ -3,7 +3,17 @@
 package frontend
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pborman/uuid"
@@ -14,20 +24,30 @@ import (
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/api/workflowservice/v1"
 	enumsspb "go.temporal.io/server/api/enums/v1"
+	"go.temporal.io/server/api/historyservice/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
 	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/archiver"
 	"go.temporal.io/server/common/archiver/provider"
+	"go.temporal.io/server/common/cache"
 	"go.temporal.io/server/common/clock"
 	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/namespace/nsmanager"
 	"go.temporal.io/server/common/namespace/nsreplication"
 	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/visibility/manager"
 	"go.temporal.io/server/common/primitives/timestamp"
+	"go.temporal.io/server/common/quotas"
+	"go.temporal.io/server/common/searchattribute"
+	"go.temporal.io/server/common/sqlquery"
 	"go.temporal.io/server/common/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -38,6 +58,7 @@ type (
 	namespaceHandler struct {
 		logger                 log.Logger
 		metadataMgr            persistence.MetadataManager
+		namespaceRegistry      namespace.Registry
 		clusterMetadata        cluster.Metadata
 		namespaceReplicator    nsreplication.Replicator
 		namespaceAttrValidator *nsmanager.Validator
@@ -45,11 +66,461 @@ type (
 		archiverProvider       provider.ArchiverProvider
 		timeSource             clock.TimeSource
 		config                 *Config
+		visibilityMgr          manager.VisibilityManager
+		saProvider             searchattribute.Provider
+		saMapperProvider       searchattribute.MapperProvider
+		// namespaceReplicationQueue is nil if global namespaces are disabled.
+		namespaceReplicationQueue persistence.NamespaceReplicationQueue
+		historyClient             historyservice.HistoryServiceClient
+		healthSignals             persistence.HealthSignalAggregator
+		metricsHandler            metrics.Handler
+
+		describeNamespaceRateLimiter             quotas.RateLimiter
+		describeNamespacePerNamespaceRateLimiter quotas.RequestRateLimiter
+		listNamespacesRateLimiter                quotas.RateLimiter
+		listWorkflowRulesAuditRateLimiter        quotas.RateLimiter
+		listArchivalAuditRateLimiter             quotas.RateLimiter
+		batchFailoverRateLimiter                 quotas.RateLimiter
+		describeNamespaceCache                   cache.Cache
+
+		// describeNamespaceCacheGeneration is incremented by every invalidation of describeNamespaceCache, a
+		// description read before an invalidation is not cached as it may predate the mutation. A single
+		// generation for all namespaces keeps it bounded, descriptions read while any namespace is mutated are
+		// just not cached.
+		describeNamespaceCacheLock       sync.Mutex
+		describeNamespaceCacheGeneration int64
+
+		failoverVersionAllocators FailoverVersionAllocators
+	}
+
+	// FailoverVersionAllocator picks the failover version of a global namespace when it is registered or fails
+	// over, for namespaces which need a versioning scheme other than the cluster one (e.g. a reserved range of
+	// versions). It is selected per namespace with the frontend.failoverVersionAllocator dynamic config.
+	//
+	// The returned version must:
+	//   - belong to activeClusterName, i.e. be congruent to the cluster initial failover version modulo the
+	//     failover version increment, so every cluster resolves it to the same active cluster;
+	//   - not be lower than currentVersion, and be higher than it if currentVersion belongs to another cluster,
+	//     as replication drops events and tasks with a lower version than the one already applied;
+	//   - be deterministic for a given input, as UpdateNamespace may be retried.
+	//
+	// currentVersion is zero when the namespace is registered. Versions breaking the first two invariants are
+	// rejected with an Internal error.
+	FailoverVersionAllocator interface {
+		NextFailoverVersion(namespace string, activeClusterName string, currentVersion int64) (int64, error)
+	}
+
+	// FailoverVersionAllocators are the failover version allocators available to namespaces, keyed by the name
+	// they are selected with.
+	FailoverVersionAllocators map[string]FailoverVersionAllocator
+
+	// WorkflowRuleActionType identifies the kind of action a workflow rule takes when it matches.
+	WorkflowRuleActionType string
+
+	// ListWorkflowRulesResponse is a page of the workflow rules of a namespace, ordered by rule ID.
+	ListWorkflowRulesResponse struct {
+		Rules         []*rulespb.WorkflowRule
+		NextPageToken []byte
+	}
+
+	// ListWorkflowRulesAcrossNamespacesRequest pages through the workflow rules of all namespaces
+	// in the cluster.
+	ListWorkflowRulesAcrossNamespacesRequest struct {
+		// NamespacePageSize is the number of namespaces scanned per page. A page may hold no rules
+		// while NextPageToken is still set.
+		NamespacePageSize int
+		NextPageToken     []byte
+		// ActionType, if set, only returns rules which have at least one action of this type.
+		ActionType WorkflowRuleActionType
+	}
+
+	// ListWorkflowRulesAcrossNamespacesResponse is a page of workflow rules attributed to their namespaces.
+	ListWorkflowRulesAcrossNamespacesResponse struct {
+		Rules         []*NamespaceWorkflowRule
+		NextPageToken []byte
+	}
+
+	// GetNamespaceFailoverHistoryRequest pages through the failovers of a namespace, oldest first.
+	GetNamespaceFailoverHistoryRequest struct {
+		Namespace string
+		// StartTime and EndTime, if set, only return the failovers at or after StartTime and before EndTime.
+		StartTime     time.Time
+		EndTime       time.Time
+		PageSize      int
+		NextPageToken []byte
+	}
+
+	// GetNamespaceFailoverHistoryResponse is a page of the failovers of a namespace, ordered by failover version.
+	GetNamespaceFailoverHistoryResponse struct {
+		FailoverHistory []*replicationpb.FailoverStatus
+		NextPageToken   []byte
+	}
+
+	// NamespaceActiveCluster is the part of the replication config of a namespace which routes requests to a
+	// cluster, as returned by GetNamespaceActiveCluster.
+	NamespaceActiveCluster struct {
+		ActiveClusterName string
+		Clusters          []string
+		IsGlobalNamespace bool
+		FailoverVersion   int64
+	}
+
+	// NamespaceWorkflowRule is a workflow rule together with the namespace it belongs to.
+	NamespaceWorkflowRule struct {
+		NamespaceID string
+		Namespace   string
+		Rule        *rulespb.WorkflowRule
+	}
+
+	// WorkflowRuleDetail is a workflow rule together with its last update, which is not part of the public
+	// WorkflowRule. LastUpdateTime is nil if the rule was never updated after its creation.
+	WorkflowRuleDetail struct {
+		*rulespb.WorkflowRule
+		LastUpdateTime        *timestamppb.Timestamp
+		LastUpdatedByIdentity string
+		// RuleCount is the number of unexpired workflow rules of the namespace, which CreateWorkflowRule keeps at
+		// most MaxRules. Both are only set by DescribeWorkflowRuleDetail with IncludeWorkflowRuleLimit.
+		RuleCount int
+		MaxRules  int
+	}
+
+	// ListNamespacesByArchivalStateResponse is a page of namespaces matching the requested archival states.
+	ListNamespacesByArchivalStateResponse struct {
+		Namespaces    []*NamespaceArchivalConfig
+		NextPageToken []byte
+	}
+
+	// NamespaceArchivalConfig is the archival configuration of a namespace.
+	NamespaceArchivalConfig struct {
+		NamespaceID             string
+		Namespace               string
+		HistoryArchivalState    enumspb.ArchivalState
+		HistoryArchivalURI      string
+		VisibilityArchivalState enumspb.ArchivalState
+		VisibilityArchivalURI   string
+	}
+
+	// NamespaceReplicationStatus reports how far each cluster of a namespace is in applying the namespace
+	// replication tasks published by this cluster.
+	NamespaceReplicationStatus struct {
+		NamespaceID     string
+		Namespace       string
+		ConfigVersion   int64
+		FailoverVersion int64
+		Clusters        []*ClusterReplicationStatus
+	}
+
+	// NamespaceReplicationSummary is the persisted replication configuration of a namespace, see
+	// GetNamespaceReplicationStatus.
+	NamespaceReplicationSummary struct {
+		NamespaceID       string
+		Namespace         string
+		IsGlobalNamespace bool
+		ActiveClusterName string
+		State             enumspb.ReplicationState
+		Clusters          []string
+		FailoverVersion   int64
+		// LastFailover is the latest failover of the namespace, nil if the namespace never failed over.
+		LastFailover *replicationpb.FailoverStatus
+	}
+
+	// ClusterReplicationStatus is the replication status of a namespace in a single cluster. Nil fields mean
+	// the value is unknown, e.g. because the cluster never polled the namespace replication queue.
+	ClusterReplicationStatus struct {
+		ClusterName string
+		// AckLevel is the ID of the last namespace replication queue message acknowledged by the cluster.
+		// It is not set for the current cluster, which does not replicate from its own queue.
+		AckLevel *int64
+		// PendingTaskCount is the number of replication tasks of the namespace not acknowledged by the cluster yet.
+		PendingTaskCount *int64
+		// LastAppliedFailoverVersion is the namespace failover version applied in the cluster. It is only known
+		// when the cluster has no pending tasks for the namespace.
+		LastAppliedFailoverVersion *int64
+	}
+
+	// NamespaceReplicationHealth tells whether a namespace is safe to fail over, together with the signals it is
+	// based on. The persistence signals are those of this frontend host, which are shared by all namespaces.
+	NamespaceReplicationHealth struct {
+		Namespace string
+		Status    ReplicationHealthStatus
+		// Factors describe the signals over their thresholds, it is empty when the namespace is healthy.
+		Factors []string
+
+		PersistenceAverageLatency float64 // ms
+		PersistenceErrorRatio     float64
+		PersistenceRequestCount   int64
+		ReplicationStatus         *NamespaceReplicationStatus
+	}
+
+	// ReplicationHealthStatus is the replication health of a namespace.
+	ReplicationHealthStatus string
+
+	// describeNamespaceCacheKey identifies a DescribeNamespace request by the namespace name and ID it was made with.
+	describeNamespaceCacheKey struct {
+		name string
+		id   string
+	}
+
+	// UpdateNamespaceOption configures UpdateNamespace inputs which are not part of the public
+	// UpdateNamespaceRequest.
+	UpdateNamespaceOption func(*updateNamespaceOptions)
+
+	// DescribeNamespaceOption configures DescribeNamespaceDetail inputs which are not part of the public
+	// DescribeNamespaceRequest.
+	DescribeNamespaceOption func(*describeNamespaceOptions)
+
+	describeNamespaceOptions struct {
+		includeWorkflowRules bool
+	}
+
+	// ListNamespacesOption configures ListNamespaces inputs which are not part of the public
+	// ListNamespacesRequest.
+	ListNamespacesOption func(*listNamespacesOptions)
+
+	listNamespacesOptions struct {
+		states     []enumspb.NamespaceState
+		namePrefix string
+		sortBy     NamespaceSortBy
+		descending bool
+	}
+
+	// NamespaceSortBy is the field ListNamespaces sorts namespaces by, see WithSortBy.
+	NamespaceSortBy string
+
+	// WorkflowRulesOption configures ListWorkflowRules and DescribeWorkflowRule.
+	WorkflowRulesOption func(*workflowRulesOptions)
+
+	workflowRulesOptions struct {
+		includeExpired   bool
+		includeRuleLimit bool
+	}
+
+	updateNamespaceOptions struct {
+		archivalBatchConfig     *persistencespb.ArchivalBatchConfig
+		visibilityStore         string
+		migrateRunningWorkflows bool
+
+		replicationWorkflowTypeFilter *persistencespb.ReplicationWorkflowTypeFilter
+		maxConcurrentOpenWorkflows    *int64
+		maxSignalRPS                  *float64
+		maxQueryRPS                   *float64
+		asyncUpdateOverrideSet        bool
+		asyncUpdateOverride           *bool
+		deleteBadBinaries             []string
+		dryRun                        bool
+		forceRetentionReduction       bool
+		// skipConfigChangeRateLimit is set by the steps of a multi-step operation which counts as a single
+		// config change.
+		skipConfigChangeRateLimit bool
+
+		removeCustomSearchAttributeAliases []string
+		removeData                         []string
+	}
+
+	// NamespaceDescription is a DescribeNamespaceResponse together with the namespace settings
+	// which are persisted by the server but are not part of the public API.
+	NamespaceDescription struct {
+		*workflowservice.DescribeNamespaceResponse
+		ArchivalBatchConfig *persistencespb.ArchivalBatchConfig
+		VisibilityStore     string
+
+		ReplicationWorkflowTypeFilter *persistencespb.ReplicationWorkflowTypeFilter
+		MaxConcurrentOpenWorkflows    int64   // zero means unlimited
+		MaxSignalRPS                  float64 // zero means unlimited
+		MaxQueryRPS                   float64 // zero means unlimited
+		// BadBinaryCount is the number of bad binaries of the namespace, which UpdateNamespace keeps at most
+		// MaxBadBinaries.
+		BadBinaryCount int
+		MaxBadBinaries int
+		// LastFailoverTime is the time of the newest failover history entry and TimeSinceLastFailover is the time
+		// elapsed since then when the namespace was described. Both are nil if the namespace never failed over.
+		LastFailoverTime      *timestamppb.Timestamp
+		TimeSinceLastFailover *durationpb.Duration
+		// WorkflowRules are the workflow rules of the namespace ordered by rule ID, only set with
+		// IncludeWorkflowRules.
+		WorkflowRules []*WorkflowRuleDetail
+		// AsyncUpdateSource tells whether the reported AsyncUpdate capability is the cluster default or a
+		// namespace override.
+		AsyncUpdateSource AsyncUpdateSource
+	}
+
+	// AsyncUpdateSource is where the effective async update acceptance setting of a namespace comes from.
+	AsyncUpdateSource string
+
+	// CountNamespacesFilter selects the namespaces counted by CountNamespaces.
+	CountNamespacesFilter struct {
+		// NamePrefix only counts the namespaces with a name starting with the case-sensitive prefix if set.
+		NamePrefix string
+	}
+
+	// NamespaceCounts are the namespace counts of CountNamespaces, deleted namespaces included.
+	NamespaceCounts struct {
+		Total   int64
+		ByState map[enumspb.NamespaceState]int64
+		Global  int64
+		Local   int64
+	}
+
+	// EffectiveNamespaceConfig is the configuration workflows of a namespace actually run with, after resolving
+	// the namespace config against the cluster config.
+	EffectiveNamespaceConfig struct {
+		Retention               EffectiveConfigValue[time.Duration]
+		HistoryArchivalState    EffectiveConfigValue[enumspb.ArchivalState]
+		HistoryArchivalURI      EffectiveConfigValue[string]
+		VisibilityArchivalState EffectiveConfigValue[enumspb.ArchivalState]
+		VisibilityArchivalURI   EffectiveConfigValue[string]
+		EagerWorkflowStart      EffectiveConfigValue[bool]
+		SyncUpdate              EffectiveConfigValue[bool]
+		AsyncUpdate             EffectiveConfigValue[bool]
+		Schedules               EffectiveConfigValue[bool]
+	}
+
+	// EffectiveConfigValue is a resolved namespace setting together with where it comes from.
+	EffectiveConfigValue[T any] struct {
+		Value  T
+		Source ConfigSource
+	}
+
+	// ConfigSource is where an effective namespace setting comes from.
+	ConfigSource string
+
+	// UpdateNamespaceResult is an UpdateNamespaceResponse together with the fields changed by the update.
+	UpdateNamespaceResult struct {
+		*workflowservice.UpdateNamespaceResponse
+		Diff []NamespaceFieldChange
+		// ChangedFields lists the top-level namespace fields changed by the update, in the order of Diff.
+		ChangedFields []NamespaceField
+		// DryRun is true if the update was only validated, see WithDryRun.
+		DryRun bool
+	}
+
+	// NamespaceFieldChange is a namespace field changed by UpdateNamespace. Custom search attribute aliases and
+	// bad binaries are reported per entry, with an empty old value if the entry was added and an empty new value
+	// if it was removed.
+	NamespaceFieldChange struct {
+		Field    string
+		OldValue string
+		NewValue string
+	}
+
+	// NamespaceField is a top-level namespace field which may be changed by UpdateNamespace.
+	NamespaceField string
+
+	// NamespaceFailoverResult is the outcome of failing over a single namespace of BatchFailoverNamespaces.
+	NamespaceFailoverResult struct {
+		Namespace string
+		// FailoverVersion is the failover version of the namespace after a successful failover.
+		FailoverVersion int64
+		// Err is set if the namespace was not failed over.
+		Err error
+	}
+
+	// GracefulFailoverNamespaceRequest is the input of GracefulFailoverNamespace.
+	GracefulFailoverNamespaceRequest struct {
+		Namespace     string
+		TargetCluster string
+		// MaxPendingTasks is the number of history replication tasks which may still be pending for the target
+		// cluster on each history shard when the active cluster is changed.
+		MaxPendingTasks int64
+		// Timeout bounds the wait for the target cluster to catch up, defaultGracefulFailoverTimeout if not set.
+		Timeout time.Duration
+		// PollInterval is the interval between replication status checks, defaultGracefulFailoverPollInterval
+		// if not set.
+		PollInterval time.Duration
+	}
+
+	// ArchivalMigrationReport is the outcome of ValidateArchivalUpdate. A field is nil if the request does not
+	// change the corresponding archival URI.
+	ArchivalMigrationReport struct {
+		History    *ArchivalURIMigrationReport
+		Visibility *ArchivalURIMigrationReport
+	}
+
+	// ArchivalURIMigrationReport tells whether the archival of a namespace can be moved from OldURI to NewURI.
+	ArchivalURIMigrationReport struct {
+		OldURI string
+		NewURI string
+		// NewURIErr is set if the new URI is not valid for its archiver.
+		NewURIErr error
+		// OldURIReadErr is set if the data archived at the old URI could not be read.
+		OldURIReadErr error
+		// UpdateErr is set if UpdateNamespace would reject the archival config of the request, e.g. because an
+		// archival URI cannot be changed once set.
+		UpdateErr error
+	}
+
+	// NamespaceRegisterResult is the outcome of registering a single namespace of RegisterNamespaces.
+	NamespaceRegisterResult struct {
+		Namespace string
+		// Err is set if the namespace was not registered.
+		Err error
+	}
+
+	// DeprecateNamespaceResult is a DeprecateNamespaceResponse together with the outcome of the
+	// deprecation, so callers can confirm it was persisted and replicated.
+	DeprecateNamespaceResult struct {
+		*workflowservice.DeprecateNamespaceResponse
+		State         enumspb.NamespaceState
+		ConfigVersion int64
+		// Replicated is true if a namespace replication task was sent to the other clusters.
+		Replicated bool
 	}
 )
 
 const (
-	maxReplicationHistorySize = 10
+	// namespaceConfigSchemaVersion is the version of the NamespaceConfig schema understood by this server.
+	// Bump it when adding config fields which need defaults filled in for configs written by older servers.
+	namespaceConfigSchemaVersion int32 = 1
+
+	describeNamespaceCacheMaxSize = 1000
+
+	// see GenerateDeletedNamespaceNameActivity of the namespace deletion workflow
+	deletedNamespaceNameInfix = "-deleted-"
+
+	// archivalProbeWorkflowID is the ID of the non-existent workflow read by ValidateArchivalUpdate to probe
+	// the read access to a history archival URI.
+	archivalProbeWorkflowID = "temporal-sys-archival-probe"
+
+	// namespaceReplicationStatusMaxScanSize bounds the number of queue messages read per cluster to count
+	// pending replication tasks. The pending task count is reported as unknown if it is exceeded.
+	namespaceReplicationStatusMaxScanSize = 1000
+	namespaceReplicationStatusPageSize    = 100
+
+	defaultGracefulFailoverTimeout      = time.Minute
+	defaultGracefulFailoverPollInterval = time.Second
+
+	// workflowRuleUpdateMaxAttempts bounds the attempts of a workflow rule change whose namespace
+	// update failed because of a concurrent namespace update.
+	workflowRuleUpdateMaxAttempts = 3
+
+	defaultWorkflowRulesPageSize               = 100
+	defaultFailoverHistoryPageSize             = 100
+	defaultWorkflowRulesAuditNamespacePageSize = 100
+	archivalAuditNamespacePageSize             = 100
+
+	WorkflowRuleActionTypeActivityPause WorkflowRuleActionType = "ActivityPause"
+
+	AsyncUpdateSourceClusterDefault    AsyncUpdateSource = "ClusterDefault"
+	AsyncUpdateSourceNamespaceOverride AsyncUpdateSource = "NamespaceOverride"
+
+	ConfigSourceNamespace      ConfigSource = "Namespace"
+	ConfigSourceClusterDefault ConfigSource = "ClusterDefault"
+
+	NamespaceFieldRetention                    NamespaceField = "Retention"
+	NamespaceFieldHistoryArchival              NamespaceField = "HistoryArchival"
+	NamespaceFieldVisibilityArchival           NamespaceField = "VisibilityArchival"
+	NamespaceFieldClusters                     NamespaceField = "Clusters"
+	NamespaceFieldActiveCluster                NamespaceField = "ActiveCluster"
+	NamespaceFieldState                        NamespaceField = "State"
+	NamespaceFieldCustomSearchAttributeAliases NamespaceField = "CustomSearchAttributeAliases"
+	NamespaceFieldBadBinaries                  NamespaceField = "BadBinaries"
+
+	NamespaceSortByName  NamespaceSortBy = "Name"
+	NamespaceSortByState NamespaceSortBy = "State"
+
+	ReplicationHealthStatusHealthy   ReplicationHealthStatus = "Healthy"
+	ReplicationHealthStatusDegraded  ReplicationHealthStatus = "Degraded"
+	ReplicationHealthStatusUnhealthy ReplicationHealthStatus = "Unhealthy"
 )
 
 var (
@@ -59,23 +530,79 @@ var (
 	errInvalidRetentionPeriod             = serviceerror.NewInvalidArgument("A valid retention period is not set on request.")
 	errInvalidNamespaceStateUpdate        = serviceerror.NewInvalidArgument("Invalid namespace state update.")
 
+	// err indicating that global namespaces are not enabled on this cluster, so cannot register, promote,
+	// update or fail over a global namespace
+	errGlobalNamespaceNotEnabled = serviceerror.NewInvalidArgument("Global namespace is not enabled on this cluster.")
+
 	errCustomSearchAttributeFieldAlreadyAllocated = serviceerror.NewInvalidArgument("Custom search attribute field name already allocated.")
+
+	errDescribeNamespaceRateLimited = &serviceerror.ResourceExhausted{
+		Cause:   enumspb.RESOURCE_EXHAUSTED_CAUSE_RPS_LIMIT,
+		Scope:   enumspb.RESOURCE_EXHAUSTED_SCOPE_SYSTEM,
+		Message: "DescribeNamespace rate limit exceeded",
+	}
+	errDescribeNamespacePerNamespaceRateLimited = &serviceerror.ResourceExhausted{
+		Cause:   enumspb.RESOURCE_EXHAUSTED_CAUSE_RPS_LIMIT,
+		Scope:   enumspb.RESOURCE_EXHAUSTED_SCOPE_NAMESPACE,
+		Message: "DescribeNamespace rate limit exceeded for namespace",
+	}
+	errListNamespacesRateLimited = &serviceerror.ResourceExhausted{
+		Cause:   enumspb.RESOURCE_EXHAUSTED_CAUSE_RPS_LIMIT,
+		Scope:   enumspb.RESOURCE_EXHAUSTED_SCOPE_SYSTEM,
+		Message: "ListNamespaces rate limit exceeded",
+	}
+	errListWorkflowRulesAuditRateLimited = &serviceerror.ResourceExhausted{
+		Cause:   enumspb.RESOURCE_EXHAUSTED_CAUSE_RPS_LIMIT,
+		Scope:   enumspb.RESOURCE_EXHAUSTED_SCOPE_SYSTEM,
+		Message: "Workflow rules listing across namespaces rate limit exceeded",
+	}
+	errListNamespacesByArchivalStateRateLimited = &serviceerror.ResourceExhausted{
+		Cause:   enumspb.RESOURCE_EXHAUSTED_CAUSE_RPS_LIMIT,
+		Scope:   enumspb.RESOURCE_EXHAUSTED_SCOPE_SYSTEM,
+		Message: "Namespace listing by archival state rate limit exceeded",
+	}
+
+	// errWorkflowRulesUnchanged is returned by a workflow rule change which left the rules as they were.
+	errWorkflowRulesUnchanged = errors.New("workflow rules unchanged")
+
+	// workflowRuleActivityPredicateFields are the activity fields the history service matches the predicate of an
+	// activity start trigger against.
+	workflowRuleActivityPredicateFields = []string{
+		"ActivityId",
+		"ActivityType",
+		"ActivityState",
+		"Attempts",
+		"BackoffInterval",
+		"LastFailure",
+		"TaskQueue",
+		"StartedTime",
+	}
 )
 
 // newNamespaceHandler create a new namespace handler
 func newNamespaceHandler(
 	logger log.Logger,
 	metadataMgr persistence.MetadataManager,
+	namespaceRegistry namespace.Registry,
 	clusterMetadata cluster.Metadata,
 	namespaceReplicator nsreplication.Replicator,
 	archivalMetadata archiver.ArchivalMetadata,
 	archiverProvider provider.ArchiverProvider,
 	timeSource clock.TimeSource,
 	config *Config,
+	visibilityMgr manager.VisibilityManager,
+	saProvider searchattribute.Provider,
+	saMapperProvider searchattribute.MapperProvider,
+	namespaceReplicationQueue persistence.NamespaceReplicationQueue,
+	historyClient historyservice.HistoryServiceClient,
+	healthSignals persistence.HealthSignalAggregator,
+	failoverVersionAllocators FailoverVersionAllocators,
+	metricsHandler metrics.Handler,
 ) *namespaceHandler {
 	return &namespaceHandler{
 		logger:                 logger,
 		metadataMgr:            metadataMgr,
+		namespaceRegistry:      namespaceRegistry,
 		clusterMetadata:        clusterMetadata,
 		namespaceReplicator:    namespaceReplicator,
 		namespaceAttrValidator: nsmanager.NewValidator(clusterMetadata),
@@ -83,20 +610,272 @@ func newNamespaceHandler(
 		archiverProvider:       archiverProvider,
 		timeSource:             timeSource,
 		config:                 config,
+		visibilityMgr:          visibilityMgr,
+		saProvider:             saProvider,
+		saMapperProvider:       saMapperProvider,
+
+		namespaceReplicationQueue: namespaceReplicationQueue,
+		historyClient:             historyClient,
+		healthSignals:             healthSignals,
+		metricsHandler:            metricsHandler,
+
+		describeNamespaceRateLimiter: quotas.NewDefaultIncomingRateLimiter(func() float64 {
+			return float64(config.DescribeNamespaceRPS())
+		}),
+		describeNamespacePerNamespaceRateLimiter: quotas.NewNamespaceRequestRateLimiter(func(req quotas.Request) quotas.RequestRateLimiter {
+			return quotas.NewRequestRateLimiterAdapter(quotas.NewDefaultIncomingRateLimiter(func() float64 {
+				return float64(config.DescribeNamespacePerNamespaceRPS(req.Caller))
+			}))
+		}),
+		listNamespacesRateLimiter: quotas.NewDefaultIncomingRateLimiter(func() float64 {
+			return float64(config.ListNamespacesRPS())
+		}),
+		listWorkflowRulesAuditRateLimiter: quotas.NewDefaultIncomingRateLimiter(func() float64 {
+			return float64(config.ListWorkflowRulesAcrossNamespacesRPS())
+		}),
+		listArchivalAuditRateLimiter: quotas.NewDefaultIncomingRateLimiter(func() float64 {
+			return float64(config.ListNamespacesByArchivalStateRPS())
+		}),
+		batchFailoverRateLimiter: quotas.NewDefaultIncomingRateLimiter(func() float64 {
+			return float64(config.BatchFailoverNamespacesRPS())
+		}),
+		describeNamespaceCache: cache.New(describeNamespaceCacheMaxSize, &cache.Options{
+			TTL:        config.DescribeNamespaceCacheTTL(),
+			TimeSource: timeSource,
+		}),
+		failoverVersionAllocators: failoverVersionAllocators,
+	}
+}
+
+// WithArchivalBatchConfig sets the archival batch size and flush interval of the namespace.
+// An empty config resets both to the cluster defaults.
+func WithArchivalBatchConfig(batchConfig *persistencespb.ArchivalBatchConfig) UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.archivalBatchConfig = batchConfig
+	}
+}
+
+// WithVisibilityStore selects the visibility store of the namespace. Switching stores is rejected while the
+// namespace has running workflows, unless migrateRunningWorkflows is set.
+func WithVisibilityStore(storeName string, migrateRunningWorkflows bool) UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.visibilityStore = storeName
+		options.migrateRunningWorkflows = migrateRunningWorkflows
+	}
+}
+
+// WithReplicationWorkflowTypeFilter selects the workflow types which are force replicated to other clusters.
+// Excluded types take precedence over included types. An empty filter replicates all workflows.
+func WithReplicationWorkflowTypeFilter(filter *persistencespb.ReplicationWorkflowTypeFilter) UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.replicationWorkflowTypeFilter = filter
+	}
+}
+
+// WithMaxConcurrentOpenWorkflows limits the number of concurrently open workflows in the namespace, new
+// workflows beyond the limit are rejected. Zero removes the limit, which is only allowed when the cluster has
+// no ceiling, see dynamicconfig.FrontendNamespaceMaxConcurrentOpenWorkflowsCeiling.
+func WithMaxConcurrentOpenWorkflows(limit int64) UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.maxConcurrentOpenWorkflows = &limit
+	}
+}
+
+// IncludeWorkflowRules returns the workflow rules of the namespace in the NamespaceDescription.
+func IncludeWorkflowRules() DescribeNamespaceOption {
+	return func(options *describeNamespaceOptions) {
+		options.includeWorkflowRules = true
+	}
+}
+
+// WithMaxSignalRPS limits the rate of signals per second to workflows in the namespace. Zero removes the limit,
+// which is only allowed when the cluster has no ceiling, see dynamicconfig.FrontendNamespaceMaxSignalRPSCeiling.
+func WithMaxSignalRPS(rps float64) UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.maxSignalRPS = &rps
+	}
+}
+
+// WithMaxQueryRPS limits the rate of queries per second to workflows in the namespace. Zero removes the limit,
+// which is only allowed when the cluster has no ceiling, see dynamicconfig.FrontendNamespaceMaxQueryRPSCeiling.
+func WithMaxQueryRPS(rps float64) UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.maxQueryRPS = &rps
+	}
+}
+
+// WithAsyncUpdateOverride forces accepting workflow updates asynchronously on or off for the namespace,
+// regardless of the cluster default. Nil removes the override. Enabling requires sync updates to be enabled.
+func WithAsyncUpdateOverride(enabled *bool) UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.asyncUpdateOverrideSet = true
+		options.asyncUpdateOverride = enabled
+	}
+}
+
+// WithDeleteBadBinaries deletes the bad binaries with the given checksums, in addition to the DeleteBadBinary
+// of the request. Nothing is deleted if any of the checksums is not a bad binary of the namespace.
+func WithDeleteBadBinaries(checksums ...string) UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.deleteBadBinaries = append(options.deleteBadBinaries, checksums...)
+	}
+}
+
+// WithNamespaceStates only lists the namespaces in one of the given states. Deleted namespaces are listed if
+// NAMESPACE_STATE_DELETED is one of the states, regardless of the IncludeDeleted filter of the request. Pages are
+// not refilled after filtering, so a page may be short or empty while more namespaces remain.
+func WithNamespaceStates(states ...enumspb.NamespaceState) ListNamespacesOption {
+	return func(options *listNamespacesOptions) {
+		options.states = append(options.states, states...)
+	}
+}
+
+// WithNamePrefix only lists the namespaces with a name starting with the given case-sensitive prefix. Pages are
+// not refilled after filtering, so a page may be short or empty while more namespaces remain.
+func WithNamePrefix(prefix string) ListNamespacesOption {
+	return func(options *listNamespacesOptions) {
+		options.namePrefix = prefix
+	}
+}
+
+// WithSortBy sorts the listed namespaces by the given field, in descending order if descending is set. Namespaces
+// with the same state are sorted by name. The metadata store does not order namespaces, so each page is sorted on
+// its own and the page tokens are those of the store: a namespace sorted first in a later page may come before the
+// namespaces of an earlier page. Namespaces are listed in the order of the store by default, which is not their
+// creation order since the creation time of a namespace is not persisted.
+func WithSortBy(sortBy NamespaceSortBy, descending bool) ListNamespacesOption {
+	return func(options *listNamespacesOptions) {
+		options.sortBy = sortBy
+		options.descending = descending
+	}
+}
+
+// IncludeExpiredWorkflowRules returns workflow rules whose expiration time has passed but which were not
+// removed yet. They are skipped by default.
+func IncludeExpiredWorkflowRules() WorkflowRulesOption {
+	return func(options *workflowRulesOptions) {
+		options.includeExpired = true
+	}
+}
+
+// IncludeWorkflowRuleLimit returns the number of workflow rules of the namespace and the max number of rules
+// together with the rule described by DescribeWorkflowRuleDetail, so that tooling can warn before the limit is
+// reached.
+func IncludeWorkflowRuleLimit() WorkflowRulesOption {
+	return func(options *workflowRulesOptions) {
+		options.includeRuleLimit = true
+	}
+}
+
+// WithRemoveCustomSearchAttributeAliases removes the aliases of the given custom search attribute fields. It fails
+// if any of the fields has no alias. Removal wins over an alias set for the same field in the request config.
+func WithRemoveCustomSearchAttributeAliases(fields ...string) UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.removeCustomSearchAttributeAliases = append(options.removeCustomSearchAttributeAliases, fields...)
+	}
+}
+
+// WithRemoveNamespaceData removes the given keys from the namespace data. It fails if any of the keys is not in the
+// namespace data. Removal wins over a value set for the same key in the request data.
+func WithRemoveNamespaceData(keys ...string) UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.removeData = append(options.removeData, keys...)
+	}
+}
+
+// WithForceRetentionReduction allows the update to reduce the namespace retention by more than the reduction
+// allowed by the retention reduction guard.
+func WithForceRetentionReduction() UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.forceRetentionReduction = true
+	}
+}
+
+// WithDryRun validates the update and returns the resulting namespace without persisting or replicating it.
+func WithDryRun() UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.dryRun = true
+	}
+}
+
+// withoutConfigChangeRateLimit excludes the update from the namespace config change rate limit.
+func withoutConfigChangeRateLimit() UpdateNamespaceOption {
+	return func(options *updateNamespaceOptions) {
+		options.skipConfigChangeRateLimit = true
 	}
 }
 
 // RegisterNamespace register a new namespace
-//
-//nolint:revive // cognitive complexity grandfathered
 func (d *namespaceHandler) RegisterNamespace(
 	ctx context.Context,
 	registerRequest *workflowservice.RegisterNamespaceRequest,
-) (*workflowservice.RegisterNamespaceResponse, error) {
+) (_ *workflowservice.RegisterNamespaceResponse, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("RegisterNamespace", registerRequest.GetNamespace(), time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	namespaceRequest, err := d.newCreateNamespaceRequest(ctx, registerRequest)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.createNamespace(ctx, namespaceRequest); err != nil {
+		return nil, err
+	}
+	return &workflowservice.RegisterNamespaceResponse{}, nil
+}
 
+// RegisterNamespaces registers a batch of namespaces, e.g. to bootstrap a new cluster. All the requests are
+// validated before any namespace is created, then each valid namespace is created and replicated as by
+// RegisterNamespace. A failure only fails its own namespace, and a namespace registered more than once in the
+// batch fails all but its first request.
+func (d *namespaceHandler) RegisterNamespaces(
+	ctx context.Context,
+	registerRequests []*workflowservice.RegisterNamespaceRequest,
+) (_ []*NamespaceRegisterResult, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("RegisterNamespaces", "", time.Since(startTime), retErr)
+	}()
+
+	results := make([]*NamespaceRegisterResult, len(registerRequests))
+	namespaceRequests := make([]*persistence.CreateNamespaceRequest, len(registerRequests))
+	names := make(map[string]struct{}, len(registerRequests))
+	for i, registerRequest := range registerRequests {
+		name := registerRequest.GetNamespace()
+		results[i] = &NamespaceRegisterResult{Namespace: name}
+		if _, ok := names[name]; ok {
+			results[i].Err = serviceerror.NewInvalidArgumentf("Namespace %q is registered more than once in the batch.", name)
+			continue
+		}
+		names[name] = struct{}{}
+		registrationCtx, done := d.withOperationTimeout(ctx)
+		namespaceRequests[i], results[i].Err = d.newCreateNamespaceRequest(registrationCtx, registerRequest)
+		done(&results[i].Err)
+	}
+
+	for i, namespaceRequest := range namespaceRequests {
+		if results[i].Err == nil {
+			creationCtx, done := d.withOperationTimeout(ctx)
+			results[i].Err = d.createNamespace(creationCtx, namespaceRequest)
+			done(&results[i].Err)
+		}
+	}
+	return results, nil
+}
+
+// newCreateNamespaceRequest validates a RegisterNamespaceRequest and returns the request creating its namespace.
+//
+//nolint:revive // cognitive complexity grandfathered
+func (d *namespaceHandler) newCreateNamespaceRequest(
+	ctx context.Context,
+	registerRequest *workflowservice.RegisterNamespaceRequest,
+) (*persistence.CreateNamespaceRequest, error) {
 	if !d.clusterMetadata.IsGlobalNamespaceEnabled() {
 		if registerRequest.GetIsGlobalNamespace() {
-			return nil, serviceerror.NewInvalidArgument("Cannot register global namespace when not enabled")
+			return nil, errGlobalNamespaceNotEnabled
 		}
 
 		registerRequest.IsGlobalNamespace = false
@@ -107,6 +886,13 @@ func (d *namespaceHandler) RegisterNamespace(
 		}
 	}
 
+	if err := d.validateNamespaceName(registerRequest.GetNamespace()); err != nil {
+		return nil, err
+	}
+	if err := d.validateNamespaceData(registerRequest.GetNamespace(), nil, registerRequest.GetData()); err != nil {
+		return nil, err
+	}
+
 	if err := validateRetentionDuration(
 		registerRequest.WorkflowExecutionRetentionPeriod,
 		registerRequest.IsGlobalNamespace,
@@ -115,10 +901,20 @@ func (d *namespaceHandler) RegisterNamespace(
 	}
 
 	// first check if the name is already registered as the local namespace
-	_, err := d.metadataMgr.GetNamespace(ctx, &persistence.GetNamespaceRequest{Name: registerRequest.GetNamespace()})
+	existing, err := d.metadataMgr.GetNamespace(ctx, &persistence.GetNamespaceRequest{Name: registerRequest.GetNamespace()})
 	switch err.(type) {
 	case nil:
 		// namespace already exists, cannot proceed
+		if d.config.EnableNamespaceNameReuse() &&
+			existing.Namespace.GetInfo().GetState() == enumspb.NAMESPACE_STATE_DELETED {
+			// The namespace deletion renames the namespace by name after marking it DELETED. The name is reused
+			// once the deletion renamed the namespace, as renaming it here would make the deletion rename the
+			// new namespace instead.
+			return nil, serviceerror.NewNamespaceAlreadyExistsf(
+				"Namespace %q is being deleted, its name can be reused once the deletion completes",
+				registerRequest.GetNamespace(),
+			)
+		}
 		return nil, serviceerror.NewNamespaceAlreadyExistsf("Namespace %q already exists", registerRequest.GetNamespace())
 	case *serviceerror.NamespaceNotFound:
 		// namespace does not exists, proceeds
@@ -197,6 +993,7 @@ func (d *namespaceHandler) RegisterNamespace(
 		VisibilityArchivalUri:        nextVisibilityArchivalState.URI,
 		BadBinaries:                  &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
 		CustomSearchAttributeAliases: nil,
+		SchemaVersion:                namespaceConfigSchemaVersion,
 	}
 	replicationConfig := &persistencespb.NamespaceReplicationConfig{
 		ActiveClusterName: activeClusterName,
@@ -214,6 +1011,9 @@ func (d *namespaceHandler) RegisterNamespace(
 		); err != nil {
 			return nil, err
 		}
+		if err := d.validateReplicationClusterCount(registerRequest.GetNamespace(), replicationConfig); err != nil {
+			return nil, err
+		}
 	} else {
 		if err := d.namespaceAttrValidator.ValidateNamespaceReplicationConfigForLocalNamespace(
 			replicationConfig,
@@ -224,7 +1024,10 @@ func (d *namespaceHandler) RegisterNamespace(
 
 	failoverVersion := common.EmptyVersion
 	if registerRequest.GetIsGlobalNamespace() {
-		failoverVersion = d.clusterMetadata.GetNextFailoverVersion(activeClusterName, 0)
+		failoverVersion, err = d.nextFailoverVersion(registerRequest.GetNamespace(), activeClusterName, 0)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	namespaceRequest := &persistence.CreateNamespaceRequest{
@@ -237,10 +1040,21 @@ func (d *namespaceHandler) RegisterNamespace(
 		},
 		IsGlobalNamespace: isGlobalNamespace,
 	}
+	return namespaceRequest, nil
+}
 
+// createNamespace creates and replicates a namespace validated by newCreateNamespaceRequest.
+func (d *namespaceHandler) createNamespace(
+	ctx context.Context,
+	namespaceRequest *persistence.CreateNamespaceRequest,
+) error {
+	info := namespaceRequest.Namespace.Info
+	if err := d.reserveNamespaceConfigChange(info.Name, namespaceRequest.Namespace.Config); err != nil {
+		return err
+	}
 	namespaceResponse, err := d.metadataMgr.CreateNamespace(ctx, namespaceRequest)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	err = d.namespaceReplicator.HandleTransmissionTask(
@@ -256,32 +1070,56 @@ func (d *namespaceHandler) RegisterNamespace(
 		nil,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	d.logger.Info("Register namespace succeeded",
-		tag.WorkflowNamespace(registerRequest.GetNamespace()),
+		tag.WorkflowNamespace(info.Name),
 		tag.WorkflowNamespaceID(namespaceResponse.ID),
 	)
-
-	return &workflowservice.RegisterNamespaceResponse{}, nil
+	return nil
 }
 
 // ListNamespaces list all namespaces
 func (d *namespaceHandler) ListNamespaces(
 	ctx context.Context,
 	listRequest *workflowservice.ListNamespacesRequest,
-) (*workflowservice.ListNamespacesResponse, error) {
+	opts ...ListNamespacesOption,
+) (_ *workflowservice.ListNamespacesResponse, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("ListNamespaces", "", time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	options := &listNamespacesOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	compareNamespaces, err := namespaceComparison(options.sortBy, options.descending)
+	if err != nil {
+		return nil, err
+	}
+	if d.config.ListNamespacesRPS() > 0 && !d.listNamespacesRateLimiter.Allow() {
+		return nil, errListNamespacesRateLimited
+	}
 
 	pageSize := 100
 	if listRequest.GetPageSize() != 0 {
 		pageSize = int(listRequest.GetPageSize())
 	}
 
+	// the states and name prefix are filtered by the metadata manager, which keeps filling the page with the next
+	// namespaces
 	resp, err := d.metadataMgr.ListNamespaces(ctx, &persistence.ListNamespacesRequest{
-		PageSize:       pageSize,
-		NextPageToken:  listRequest.NextPageToken,
-		IncludeDeleted: listRequest.GetNamespaceFilter().GetIncludeDeleted(),
+		PageSize:      pageSize,
+		NextPageToken: listRequest.NextPageToken,
+		IncludeDeleted: listRequest.GetNamespaceFilter().GetIncludeDeleted() ||
+			slices.Contains(options.states, enumspb.NAMESPACE_STATE_DELETED),
+		States:     options.states,
+		NamePrefix: options.namePrefix,
 	})
 
 	if err != nil {
@@ -301,6 +1139,9 @@ func (d *namespaceHandler) ListNamespaces(
 				namespace.Namespace.ReplicationConfig)
 		namespaces = append(namespaces, desc)
 	}
+	if compareNamespaces != nil {
+		slices.SortStableFunc(namespaces, compareNamespaces)
+	}
 
 	response := &workflowservice.ListNamespacesResponse{
 		Namespaces:    namespaces,
@@ -310,18 +1151,99 @@ func (d *namespaceHandler) ListNamespaces(
 	return response, nil
 }
 
+// CountNamespaces counts the namespaces by state and by global or local. The namespaces are counted from the
+// namespace registry rather than persistence, so the counts may lag namespace changes by up to the registry refresh
+// interval. It is rate limited together with ListNamespaces.
+func (d *namespaceHandler) CountNamespaces(
+	_ context.Context,
+	filter *CountNamespacesFilter,
+) (_ *NamespaceCounts, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("CountNamespaces", "", time.Since(startTime), retErr)
+	}()
+
+	if d.config.ListNamespacesRPS() > 0 && !d.listNamespacesRateLimiter.Allow() {
+		return nil, errListNamespacesRateLimited
+	}
+
+	counts := &NamespaceCounts{ByState: make(map[enumspb.NamespaceState]int64)}
+	for _, ns := range d.namespaceRegistry.GetAllNamespaces() {
+		if filter != nil && !strings.HasPrefix(ns.Name().String(), filter.NamePrefix) {
+			continue
+		}
+		counts.Total++
+		counts.ByState[ns.State()]++
+		if ns.IsGlobalNamespace() {
+			counts.Global++
+		} else {
+			counts.Local++
+		}
+	}
+	return counts, nil
+}
+
 // DescribeNamespace describe the namespace
 func (d *namespaceHandler) DescribeNamespace(
 	ctx context.Context,
 	describeRequest *workflowservice.DescribeNamespaceRequest,
 ) (*workflowservice.DescribeNamespaceResponse, error) {
+	description, err := d.DescribeNamespaceDetail(ctx, describeRequest)
+	if err != nil {
+		return nil, err
+	}
+	return description.DescribeNamespaceResponse, nil
+}
+
+// DescribeNamespaceDetail describe the namespace, including the settings which are not part of the public API
+func (d *namespaceHandler) DescribeNamespaceDetail(
+	ctx context.Context,
+	describeRequest *workflowservice.DescribeNamespaceRequest,
+	opts ...DescribeNamespaceOption,
+) (_ *NamespaceDescription, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("DescribeNamespace", describeRequest.GetNamespace(), time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	options := &describeNamespaceOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
 
 	// TODO, we should migrate the non global namespace to new table, see #773
 	req := &persistence.GetNamespaceRequest{
 		Name: describeRequest.GetNamespace(),
 		ID:   describeRequest.GetId(),
 	}
-	resp, err := d.metadataMgr.GetNamespace(ctx, req)
+
+	if d.config.DescribeNamespaceRPS() > 0 && !d.describeNamespaceRateLimiter.Allow() {
+		return nil, errDescribeNamespaceRateLimited
+	}
+	caller := req.Name
+	if caller == "" {
+		caller = req.ID
+	}
+	if d.config.DescribeNamespacePerNamespaceRPS(caller) > 0 && !d.describeNamespacePerNamespaceRateLimiter.Allow(d.timeSource.Now(), quotas.NewRequest("DescribeNamespace", 1, caller, "", 0, "")) {
+		return nil, errDescribeNamespacePerNamespaceRateLimited
+	}
+
+	// The cache is local to this host and only invalidated by mutations made through it, so an entry may be stale
+	// for up to the cache TTL after a namespace is updated through another frontend.
+	cacheKey := describeNamespaceCacheKey{name: req.Name, id: req.ID}
+	cacheEnabled := d.config.DescribeNamespaceCacheEnabled()
+	if cacheEnabled {
+		if cached, ok := d.describeNamespaceCache.Get(cacheKey).(*NamespaceDescription); ok {
+			description := cached.clone(options)
+			description.setTimeSinceLastFailover(d.timeSource.Now())
+			return description, nil
+		}
+	}
+
+	cacheGeneration := d.getDescribeNamespaceCacheGeneration()
+	resp, err := d.getNamespace(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -332,16 +1254,359 @@ func (d *namespaceHandler) DescribeNamespace(
 	}
 	response.NamespaceInfo, response.Config, response.ReplicationConfig, response.FailoverHistory =
 		d.createResponse(resp.Namespace.Info, resp.Namespace.Config, resp.Namespace.ReplicationConfig)
-	return response, nil
-}
+	description := &NamespaceDescription{
+		DescribeNamespaceResponse: response,
+		ArchivalBatchConfig:       resp.Namespace.Config.GetArchivalBatchConfig(),
+		VisibilityStore:           resp.Namespace.Config.GetVisibilityStore(),
 
-// UpdateNamespace update the namespace
-//
-//nolint:revive // cognitive complexity grandfathered
-func (d *namespaceHandler) UpdateNamespace(
+		ReplicationWorkflowTypeFilter: resp.Namespace.Config.GetReplicationWorkflowTypeFilter(),
+		MaxConcurrentOpenWorkflows:    resp.Namespace.Config.GetMaxConcurrentOpenWorkflows(),
+		MaxSignalRPS:                  resp.Namespace.Config.GetMaxSignalRps(),
+		MaxQueryRPS:                   resp.Namespace.Config.GetMaxQueryRps(),
+		BadBinaryCount:                len(resp.Namespace.Config.GetBadBinaries().GetBinaries()),
+		MaxBadBinaries:                d.config.MaxBadBinaries(resp.Namespace.Info.GetName()),
+		WorkflowRules:                 workflowRuleDetails(resp.Namespace.Config),
+	}
+	if failoverHistory := resp.Namespace.ReplicationConfig.GetFailoverHistory(); len(failoverHistory) > 0 {
+		description.LastFailoverTime = failoverHistory[len(failoverHistory)-1].GetFailoverTime()
+	}
+	_, description.AsyncUpdateSource = effectiveAsyncUpdate(
+		resp.Namespace.Config.GetAsyncUpdateOverride(),
+		d.config.EnableUpdateWorkflowExecutionAsyncAccepted(resp.Namespace.Info.GetName()),
+	)
+	// The cached description always has the workflow rules, so that it can serve requests with and without them.
+	if cacheEnabled {
+		d.putDescribeNamespaceCache(cacheKey, cacheGeneration, description.clone(&describeNamespaceOptions{includeWorkflowRules: true}))
+	}
+	if !options.includeWorkflowRules {
+		description.WorkflowRules = nil
+	}
+	description.setTimeSinceLastFailover(d.timeSource.Now())
+	return description, nil
+}
+
+// GetNamespaceActiveCluster returns the active cluster and the replication clusters of the namespace, without
+// building the full DescribeNamespace response.
+func (d *namespaceHandler) GetNamespaceActiveCluster(
+	ctx context.Context,
+	nsName string,
+) (_ *NamespaceActiveCluster, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("GetNamespaceActiveCluster", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	resp, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: nsName})
+	if err != nil {
+		return nil, err
+	}
+	replicationConfig := resp.Namespace.GetReplicationConfig()
+	return &NamespaceActiveCluster{
+		ActiveClusterName: replicationConfig.GetActiveClusterName(),
+		Clusters:          slices.Clone(replicationConfig.GetClusters()),
+		IsGlobalNamespace: resp.IsGlobalNamespace,
+		FailoverVersion:   resp.Namespace.GetFailoverVersion(),
+	}, nil
+}
+
+// DescribeEffectiveNamespaceConfig returns the configuration workflows of the namespace actually run with,
+// annotating each setting with whether it comes from the namespace or from the cluster. Archival is reported
+// as disabled, whatever the namespace says, when the cluster is not configured for it.
+func (d *namespaceHandler) DescribeEffectiveNamespaceConfig(
+	ctx context.Context,
+	nsName string,
+) (_ *EffectiveNamespaceConfig, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("DescribeEffectiveNamespaceConfig", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	if nsName == "" {
+		return nil, errNamespaceNotSet
+	}
+	resp, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: nsName})
+	if err != nil {
+		return nil, err
+	}
+	config := resp.Namespace.Config
+
+	asyncUpdate, asyncUpdateSource := effectiveAsyncUpdate(
+		config.GetAsyncUpdateOverride(),
+		d.config.EnableUpdateWorkflowExecutionAsyncAccepted(nsName),
+	)
+	asyncUpdateConfigSource := ConfigSourceClusterDefault
+	if asyncUpdateSource == AsyncUpdateSourceNamespaceOverride {
+		asyncUpdateConfigSource = ConfigSourceNamespace
+	}
+
+	effective := &EffectiveNamespaceConfig{
+		Retention: EffectiveConfigValue[time.Duration]{
+			Value:  config.GetRetention().AsDuration(),
+			Source: ConfigSourceNamespace,
+		},
+		EagerWorkflowStart: EffectiveConfigValue[bool]{
+			Value:  d.config.EnableEagerWorkflowStart(nsName),
+			Source: ConfigSourceClusterDefault,
+		},
+		SyncUpdate: EffectiveConfigValue[bool]{
+			Value:  d.config.EnableUpdateWorkflowExecution(nsName),
+			Source: ConfigSourceClusterDefault,
+		},
+		AsyncUpdate: EffectiveConfigValue[bool]{
+			Value:  asyncUpdate,
+			Source: asyncUpdateConfigSource,
+		},
+		Schedules: EffectiveConfigValue[bool]{
+			Value:  d.config.EnableSchedules(nsName),
+			Source: ConfigSourceClusterDefault,
+		},
+	}
+	effective.HistoryArchivalState, effective.HistoryArchivalURI = effectiveArchival(
+		d.archivalMetadata.GetHistoryConfig(),
+		config.GetHistoryArchivalState(),
+		config.GetHistoryArchivalUri(),
+	)
+	effective.VisibilityArchivalState, effective.VisibilityArchivalURI = effectiveArchival(
+		d.archivalMetadata.GetVisibilityConfig(),
+		config.GetVisibilityArchivalState(),
+		config.GetVisibilityArchivalUri(),
+	)
+	return effective, nil
+}
+
+// DescribeNamespaceReplicationStatus returns the replication status of the namespace in each of its clusters,
+// based on the namespace metadata and the ack levels of the namespace replication queue.
+func (d *namespaceHandler) DescribeNamespaceReplicationStatus(
+	ctx context.Context,
+	nsName string,
+) (_ *NamespaceReplicationStatus, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("DescribeNamespaceReplicationStatus", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	resp, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: nsName})
+	if err != nil {
+		return nil, err
+	}
+	detail := resp.Namespace
+
+	status := &NamespaceReplicationStatus{
+		NamespaceID:     detail.Info.Id,
+		Namespace:       detail.Info.Name,
+		ConfigVersion:   detail.ConfigVersion,
+		FailoverVersion: detail.FailoverVersion,
+	}
+
+	var ackLevels map[string]int64
+	if d.namespaceReplicationQueue != nil && resp.IsGlobalNamespace {
+		if ackLevels, err = d.namespaceReplicationQueue.GetAckLevels(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	currentClusterName := d.clusterMetadata.GetCurrentClusterName()
+	for _, clusterName := range detail.ReplicationConfig.GetClusters() {
+		clusterStatus := &ClusterReplicationStatus{ClusterName: clusterName}
+		status.Clusters = append(status.Clusters, clusterStatus)
+
+		if clusterName == currentClusterName {
+			clusterStatus.PendingTaskCount = util.Ptr(int64(0))
+			clusterStatus.LastAppliedFailoverVersion = util.Ptr(detail.FailoverVersion)
+			continue
+		}
+		ackLevel, ok := ackLevels[clusterName]
+		if !ok {
+			continue
+		}
+		clusterStatus.AckLevel = util.Ptr(ackLevel)
+
+		pendingTaskCount, complete, err := d.countPendingNamespaceReplicationTasks(ctx, detail.Info.Id, ackLevel)
+		if err != nil {
+			return nil, err
+		}
+		if !complete {
+			continue
+		}
+		clusterStatus.PendingTaskCount = util.Ptr(pendingTaskCount)
+		if pendingTaskCount == 0 {
+			clusterStatus.LastAppliedFailoverVersion = util.Ptr(detail.FailoverVersion)
+		}
+	}
+	return status, nil
+}
+
+// GetNamespaceReplicationStatus returns the active cluster, replication state, clusters and latest failover of the
+// namespace from its persisted replication config. Unlike DescribeNamespaceReplicationStatus, it does not read the
+// namespace replication queue.
+func (d *namespaceHandler) GetNamespaceReplicationStatus(
+	ctx context.Context,
+	nsName string,
+) (_ *NamespaceReplicationSummary, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("GetNamespaceReplicationStatus", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	resp, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: nsName})
+	if err != nil {
+		return nil, err
+	}
+	detail := resp.Namespace
+	replicationConfig := detail.GetReplicationConfig()
+
+	summary := &NamespaceReplicationSummary{
+		NamespaceID:       detail.Info.Id,
+		Namespace:         detail.Info.Name,
+		IsGlobalNamespace: resp.IsGlobalNamespace,
+		ActiveClusterName: replicationConfig.GetActiveClusterName(),
+		State:             replicationConfig.GetState(),
+		Clusters:          slices.Clone(replicationConfig.GetClusters()),
+		FailoverVersion:   detail.FailoverVersion,
+	}
+	// failovers which happened before the failover log was introduced are only in the failover history
+	failoverLog := nsreplication.AppendFailoverLog(
+		slices.Clone(replicationConfig.GetFailoverLog()),
+		replicationConfig.GetFailoverHistory(),
+	)
+	if len(failoverLog) > 0 {
+		lastFailover := failoverLog[len(failoverLog)-1]
+		summary.LastFailover = &replicationpb.FailoverStatus{
+			FailoverTime:    lastFailover.GetFailoverTime(),
+			FailoverVersion: lastFailover.GetFailoverVersion(),
+		}
+	}
+	return summary, nil
+}
+
+// DescribeNamespaceReplicationHealth assesses whether the namespace is safe to fail over. It combines the
+// persistence health of this host with the namespace replication tasks pending in the replication clusters, against
+// the frontend.replicationHealthThresholds dynamic config. A cluster with an unknown pending task count makes the
+// namespace degraded.
+func (d *namespaceHandler) DescribeNamespaceReplicationHealth(
+	ctx context.Context,
+	nsName string,
+) (_ *NamespaceReplicationHealth, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("DescribeNamespaceReplicationHealth", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	replicationStatus, err := d.DescribeNamespaceReplicationStatus(ctx, nsName)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &NamespaceReplicationHealth{
+		Namespace:                 nsName,
+		Status:                    ReplicationHealthStatusHealthy,
+		PersistenceAverageLatency: d.healthSignals.AverageLatency(),
+		PersistenceErrorRatio:     d.healthSignals.ErrorRatio(),
+		PersistenceRequestCount:   d.healthSignals.RequestCount(),
+		ReplicationStatus:         replicationStatus,
+	}
+	addFactor := func(status ReplicationHealthStatus, format string, args ...any) {
+		health.Factors = append(health.Factors, fmt.Sprintf(format, args...))
+		if status == ReplicationHealthStatusUnhealthy || health.Status == ReplicationHealthStatusHealthy {
+			health.Status = status
+		}
+	}
+	checkThresholds := func(signal string, value float64, degraded float64, unhealthy float64) {
+		switch {
+		case unhealthy > 0 && value >= unhealthy:
+			addFactor(ReplicationHealthStatusUnhealthy, "%s %v is at or above the unhealthy threshold %v", signal, value, unhealthy)
+		case degraded > 0 && value >= degraded:
+			addFactor(ReplicationHealthStatusDegraded, "%s %v is at or above the degraded threshold %v", signal, value, degraded)
+		}
+	}
+
+	thresholds := d.config.ReplicationHealthThresholds()
+	checkThresholds("persistence average latency (ms)", health.PersistenceAverageLatency, thresholds.DegradedLatency, thresholds.UnhealthyLatency)
+	checkThresholds("persistence error ratio", health.PersistenceErrorRatio, thresholds.DegradedErrorRatio, thresholds.UnhealthyErrorRatio)
+	for _, clusterStatus := range replicationStatus.Clusters {
+		if clusterStatus.PendingTaskCount == nil {
+			addFactor(ReplicationHealthStatusDegraded, "pending replication tasks of cluster %s are unknown", clusterStatus.ClusterName)
+			continue
+		}
+		checkThresholds(
+			fmt.Sprintf("pending replication tasks of cluster %s", clusterStatus.ClusterName),
+			float64(*clusterStatus.PendingTaskCount),
+			float64(thresholds.DegradedPendingTasks),
+			float64(thresholds.UnhealthyPendingTasks),
+		)
+	}
+	return health, nil
+}
+
+// countPendingNamespaceReplicationTasks counts the replication tasks of the namespace published after ackLevel.
+// complete is false if the count stopped at namespaceReplicationStatusMaxScanSize messages.
+func (d *namespaceHandler) countPendingNamespaceReplicationTasks(
+	ctx context.Context,
+	namespaceID string,
+	ackLevel int64,
+) (count int64, complete bool, err error) {
+	lastMessageID := ackLevel
+	for scanned := 0; scanned < namespaceReplicationStatusMaxScanSize; {
+		tasks, nextMessageID, err := d.namespaceReplicationQueue.GetReplicationMessages(ctx, lastMessageID, namespaceReplicationStatusPageSize)
+		if err != nil {
+			return 0, false, err
+		}
+		if len(tasks) == 0 {
+			return count, true, nil
+		}
+		for _, task := range tasks {
+			if task.GetNamespaceTaskAttributes().GetId() == namespaceID {
+				count++
+			}
+		}
+		scanned += len(tasks)
+		lastMessageID = nextMessageID
+	}
+	return count, false, nil
+}
+
+// UpdateNamespace update the namespace
+func (d *namespaceHandler) UpdateNamespace(
 	ctx context.Context,
 	updateRequest *workflowservice.UpdateNamespaceRequest,
+	opts ...UpdateNamespaceOption,
 ) (*workflowservice.UpdateNamespaceResponse, error) {
+	result, err := d.UpdateNamespaceDetail(ctx, updateRequest, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return result.UpdateNamespaceResponse, nil
+}
+
+// UpdateNamespaceDetail update the namespace, and report the changed fields
+//
+//nolint:revive // cognitive complexity grandfathered
+func (d *namespaceHandler) UpdateNamespaceDetail(
+	ctx context.Context,
+	updateRequest *workflowservice.UpdateNamespaceRequest,
+	opts ...UpdateNamespaceOption,
+) (_ *UpdateNamespaceResult, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("UpdateNamespace", updateRequest.GetNamespace(), time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	options := &updateNamespaceOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
 
 	// must get the metadata (notificationVersion) first
 	// this version can be regarded as the lock on the v2 namespace table
@@ -352,7 +1617,7 @@ func (d *namespaceHandler) UpdateNamespace(
 		return nil, err
 	}
 	notificationVersion := metadata.NotificationVersion
-	getResponse, err := d.metadataMgr.GetNamespace(ctx, &persistence.GetNamespaceRequest{Name: updateRequest.GetNamespace()})
+	getResponse, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: updateRequest.GetNamespace()})
 	if err != nil {
 		return nil, err
 	}
@@ -360,12 +1625,21 @@ func (d *namespaceHandler) UpdateNamespace(
 	info := getResponse.Namespace.Info
 	config := getResponse.Namespace.Config
 	replicationConfig := getResponse.Namespace.ReplicationConfig
+	existingReplicationConfig := &persistencespb.NamespaceReplicationConfig{
+		ActiveClusterName: replicationConfig.GetActiveClusterName(),
+		Clusters:          slices.Clone(replicationConfig.GetClusters()),
+		State:             replicationConfig.GetState(),
+	}
+	existingNamespace := copyNamespaceDetailForDiff(getResponse.Namespace)
 	failoverHistory := getResponse.Namespace.ReplicationConfig.FailoverHistory
 	configVersion := getResponse.Namespace.ConfigVersion
 	failoverVersion := getResponse.Namespace.FailoverVersion
 	failoverNotificationVersion := getResponse.Namespace.FailoverNotificationVersion
 	isGlobalNamespace := getResponse.IsGlobalNamespace || updateRequest.PromoteNamespace
 	needsNamespacePromotion := !getResponse.IsGlobalNamespace && updateRequest.PromoteNamespace
+	if needsNamespacePromotion && !d.clusterMetadata.IsGlobalNamespaceEnabled() {
+		return nil, errGlobalNamespaceNotEnabled
+	}
 
 	currentHistoryArchivalState := &namespace.ArchivalConfigState{
 		State: config.HistoryArchivalState,
@@ -378,11 +1652,11 @@ func (d *namespaceHandler) UpdateNamespace(
 		cfg := updateRequest.GetConfig()
 		archivalEvent, err := d.toArchivalUpdateEvent(cfg.HistoryArchivalState, cfg.GetHistoryArchivalUri(), clusterHistoryArchivalConfig.GetNamespaceDefaultURI())
 		if err != nil {
-			return nil, err
+			return nil, withFieldPath(err, "config.historyArchivalUri")
 		}
 		nextHistoryArchivalState, historyArchivalConfigChanged, err = currentHistoryArchivalState.GetNextState(archivalEvent, d.validateHistoryArchivalURI)
 		if err != nil {
-			return nil, err
+			return nil, withFieldPath(err, "config.historyArchivalUri")
 		}
 	}
 
@@ -397,11 +1671,11 @@ func (d *namespaceHandler) UpdateNamespace(
 		cfg := updateRequest.GetConfig()
 		archivalEvent, err := d.toArchivalUpdateEvent(cfg.VisibilityArchivalState, cfg.GetVisibilityArchivalUri(), clusterVisibilityArchivalConfig.GetNamespaceDefaultURI())
 		if err != nil {
-			return nil, err
+			return nil, withFieldPath(err, "config.visibilityArchivalUri")
 		}
 		nextVisibilityArchivalState, visibilityArchivalConfigChanged, err = currentVisibilityArchivalState.GetNextState(archivalEvent, d.validateVisibilityArchivalURI)
 		if err != nil {
-			return nil, err
+			return nil, withFieldPath(err, "config.visibilityArchivalUri")
 		}
 	}
 
@@ -411,6 +1685,8 @@ func (d *namespaceHandler) UpdateNamespace(
 	configurationChanged := false
 	// whether replication cluster list is changed
 	clusterListChanged := false
+	// whether a deprecated namespace is registered again
+	undeprecated := false
 
 	if updateRequest.UpdateInfo != nil {
 		updatedInfo := updateRequest.UpdateInfo
@@ -422,16 +1698,13 @@ func (d *namespaceHandler) UpdateNamespace(
 			configurationChanged = true
 			info.Owner = updatedInfo.GetOwnerEmail()
 		}
-		if updatedInfo.Data != nil {
-			configurationChanged = true
-			// only do merging
-			info.Data = d.mergeNamespaceData(info.Data, updatedInfo.Data)
-		}
 		if updatedInfo.State != enumspb.NAMESPACE_STATE_UNSPECIFIED && info.State != updatedInfo.State {
 			configurationChanged = true
-			if err := validateStateUpdate(getResponse, updateRequest); err != nil {
-				return nil, err
+			allowUndeprecation := d.config.AllowNamespaceUndeprecation(info.Name)
+			if err := validateStateUpdate(getResponse, updateRequest, allowUndeprecation); err != nil {
+				return nil, withFieldPath(err, "updateInfo.state")
 			}
+			undeprecated = info.State == enumspb.NAMESPACE_STATE_DEPRECATED
 			info.State = updatedInfo.State
 		}
 	}
@@ -440,12 +1713,21 @@ func (d *namespaceHandler) UpdateNamespace(
 		if updatedConfig.GetWorkflowExecutionRetentionTtl() != nil {
 			configurationChanged = true
 
+			if !options.forceRetentionReduction {
+				if err := d.validateRetentionReduction(
+					updateRequest.GetNamespace(),
+					config.Retention,
+					updatedConfig.GetWorkflowExecutionRetentionTtl(),
+				); err != nil {
+					return nil, withFieldPath(err, "config.workflowExecutionRetentionTtl")
+				}
+			}
 			config.Retention = updatedConfig.GetWorkflowExecutionRetentionTtl()
 			if err := validateRetentionDuration(
 				config.Retention,
 				isGlobalNamespace,
 			); err != nil {
-				return nil, err
+				return nil, withFieldPath(err, "config.workflowExecutionRetentionTtl")
 			}
 		}
 		if historyArchivalConfigChanged {
@@ -464,30 +1746,143 @@ func (d *namespaceHandler) UpdateNamespace(
 			bb := d.mergeBadBinaries(config.BadBinaries.Binaries, updatedConfig.BadBinaries.Binaries, time.Now().UTC())
 			config.BadBinaries = &bb
 			if len(config.BadBinaries.Binaries) > maxLength {
-				return nil, serviceerror.NewInvalidArgumentf("Total resetBinaries cannot exceed the max limit: %v", maxLength)
+				return nil, withFieldPath(serviceerror.NewInvalidArgumentf(
+					"Total resetBinaries count %v exceeds the max limit: %v", len(config.BadBinaries.Binaries), maxLength,
+				), "config.badBinaries")
 			}
 		}
-		if len(updatedConfig.CustomSearchAttributeAliases) > 0 {
-			configurationChanged = true
-			csaAliases, err := d.upsertCustomSearchAttributesAliases(
-				config.CustomSearchAttributeAliases,
-				updatedConfig.CustomSearchAttributeAliases,
-			)
-			if err != nil {
-				return nil, err
+	}
+
+	// a promoted namespace must meet the global retention minimum even if the update does not change its retention
+	if needsNamespacePromotion {
+		if err := validateRetentionDuration(config.Retention, true); err != nil {
+			return nil, withFieldPath(err, "config.workflowExecutionRetentionTtl")
+		}
+	}
+
+	if updateRequest.GetUpdateInfo().GetData() != nil || len(options.removeData) > 0 {
+		configurationChanged = true
+		// only do merging, keys are deleted through WithRemoveNamespaceData
+		existingData := maps.Clone(info.Data)
+		data, err := d.mergeNamespaceData(info.Data, updateRequest.GetUpdateInfo().GetData(), options.removeData)
+		if err != nil {
+			return nil, withFieldPath(err, "updateInfo.data")
+		}
+		if err := d.validateNamespaceData(info.Name, existingData, data); err != nil {
+			return nil, withFieldPath(err, "updateInfo.data")
+		}
+		info.Data = data
+	}
+
+	if len(updateRequest.GetConfig().GetCustomSearchAttributeAliases()) > 0 ||
+		len(options.removeCustomSearchAttributeAliases) > 0 {
+		configurationChanged = true
+		csaAliases, err := d.upsertCustomSearchAttributesAliases(
+			config.CustomSearchAttributeAliases,
+			updateRequest.GetConfig().GetCustomSearchAttributeAliases(),
+			options.removeCustomSearchAttributeAliases,
+		)
+		if err != nil {
+			return nil, withFieldPath(err, "config.customSearchAttributeAliases")
+		}
+		config.CustomSearchAttributeAliases = csaAliases
+	}
+
+	if options.archivalBatchConfig != nil {
+		configurationChanged = true
+		if options.archivalBatchConfig.GetBatchSize() == 0 && options.archivalBatchConfig.GetFlushInterval() == nil {
+			config.ArchivalBatchConfig = nil
+		} else {
+			config.ArchivalBatchConfig = options.archivalBatchConfig
+		}
+	}
+
+	if options.visibilityStore != "" && options.visibilityStore != config.VisibilityStore {
+		if err := d.validateVisibilityStoreUpdate(ctx, info, config.VisibilityStore, options); err != nil {
+			return nil, withFieldPath(err, "config.visibilityStore")
+		}
+		configurationChanged = true
+		config.VisibilityStore = options.visibilityStore
+	}
+
+	if options.replicationWorkflowTypeFilter != nil {
+		if err := validateReplicationWorkflowTypeFilter(options.replicationWorkflowTypeFilter); err != nil {
+			return nil, withFieldPath(err, "config.replicationWorkflowTypeFilter")
+		}
+		configurationChanged = true
+		if len(options.replicationWorkflowTypeFilter.GetIncludedWorkflowTypes()) == 0 &&
+			len(options.replicationWorkflowTypeFilter.GetExcludedWorkflowTypes()) == 0 {
+			config.ReplicationWorkflowTypeFilter = nil
+		} else {
+			config.ReplicationWorkflowTypeFilter = options.replicationWorkflowTypeFilter
+		}
+	}
+
+	if options.maxConcurrentOpenWorkflows != nil {
+		if err := d.validateMaxConcurrentOpenWorkflows(*options.maxConcurrentOpenWorkflows); err != nil {
+			return nil, withFieldPath(err, "config.maxConcurrentOpenWorkflows")
+		}
+		configurationChanged = true
+		config.MaxConcurrentOpenWorkflows = *options.maxConcurrentOpenWorkflows
+	}
+
+	if options.maxSignalRPS != nil {
+		if err := validateMaxRPS("signal", *options.maxSignalRPS, d.config.NamespaceMaxSignalRPSCeiling()); err != nil {
+			return nil, withFieldPath(err, "config.maxSignalRps")
+		}
+		configurationChanged = true
+		config.MaxSignalRps = *options.maxSignalRPS
+	}
+
+	if options.maxQueryRPS != nil {
+		if err := validateMaxRPS("query", *options.maxQueryRPS, d.config.NamespaceMaxQueryRPSCeiling()); err != nil {
+			return nil, withFieldPath(err, "config.maxQueryRps")
+		}
+		configurationChanged = true
+		config.MaxQueryRps = *options.maxQueryRPS
+	}
+
+	if options.asyncUpdateOverrideSet {
+		if options.asyncUpdateOverride == nil {
+			config.AsyncUpdateOverride = nil
+		} else {
+			if *options.asyncUpdateOverride && !d.config.EnableUpdateWorkflowExecution(info.Name) {
+				return nil, withFieldPath(
+					serviceerror.NewInvalidArgument("Async updates cannot be enabled while updates are disabled for the namespace."),
+					"config.asyncUpdateOverride",
+				)
 			}
-			config.CustomSearchAttributeAliases = csaAliases
+			config.AsyncUpdateOverride = &persistencespb.AsyncUpdateOverride{Enabled: *options.asyncUpdateOverride}
 		}
+		configurationChanged = true
 	}
 
+	deleteBadBinaries := options.deleteBadBinaries
 	if updateRequest.GetDeleteBadBinary() != "" {
-		binChecksum := updateRequest.GetDeleteBadBinary()
-		_, ok := config.BadBinaries.Binaries[binChecksum]
-		if !ok {
-			return nil, serviceerror.NewInvalidArgumentf("Bad binary checksum %v doesn't exists.", binChecksum)
+		deleteBadBinaries = append([]string{updateRequest.GetDeleteBadBinary()}, deleteBadBinaries...)
+	}
+	if len(deleteBadBinaries) > 0 {
+		var missingChecksums []string
+		for _, binChecksum := range deleteBadBinaries {
+			_, ok := config.BadBinaries.Binaries[binChecksum]
+			if !ok && !slices.Contains(missingChecksums, binChecksum) {
+				missingChecksums = append(missingChecksums, binChecksum)
+			}
+		}
+		if len(missingChecksums) == 1 {
+			return nil, withFieldPath(
+				serviceerror.NewInvalidArgumentf("Bad binary checksum %v doesn't exists.", missingChecksums[0]),
+				"deleteBadBinary",
+			)
+		} else if len(missingChecksums) > 1 {
+			return nil, withFieldPath(serviceerror.NewInvalidArgumentf(
+				"Bad binary checksums %v don't exist.", strings.Join(missingChecksums, ", "),
+			), "deleteBadBinary")
 		}
 		configurationChanged = true
-		delete(config.BadBinaries.Binaries, binChecksum)
+		for _, binChecksum := range deleteBadBinaries {
+			delete(config.BadBinaries.Binaries, binChecksum)
+		}
 	}
 
 	if updateRequest.ReplicationConfig != nil {
@@ -503,9 +1898,6 @@ func (d *namespaceHandler) UpdateNamespace(
 		}
 		if updateReplicationConfig.State != enumspb.REPLICATION_STATE_UNSPECIFIED &&
 			updateReplicationConfig.State != replicationConfig.State {
-			if err := validateReplicationStateUpdate(getResponse, updateRequest); err != nil {
-				return nil, err
-			}
 			configurationChanged = true
 			replicationConfig.State = updateReplicationConfig.State
 		}
@@ -516,6 +1908,17 @@ func (d *namespaceHandler) UpdateNamespace(
 		}
 	}
 
+	if updateRequest.ReplicationConfig != nil {
+		if err := validateReplicationTransition(
+			existingReplicationConfig,
+			replicationConfig,
+			getResponse.Namespace.Info.GetState(),
+			isGlobalNamespace,
+		); err != nil {
+			return nil, withFieldPath(err, "replicationConfig")
+		}
+	}
+
 	if err := d.namespaceAttrValidator.ValidateNamespaceConfig(config); err != nil {
 		return nil, err
 	}
@@ -523,17 +1926,23 @@ func (d *namespaceHandler) UpdateNamespace(
 		if err := d.namespaceAttrValidator.ValidateNamespaceReplicationConfigForGlobalNamespace(
 			replicationConfig,
 		); err != nil {
-			return nil, err
+			return nil, withFieldPath(err, "replicationConfig")
+		}
+		// only check the limit when the cluster list changes, so that namespaces already
+		// above a lowered limit can still be updated
+		if clusterListChanged {
+			if err := d.validateReplicationClusterCount(updateRequest.GetNamespace(), replicationConfig); err != nil {
+				return nil, withFieldPath(err, "replicationConfig.clusters")
+			}
 		}
 		if !d.clusterMetadata.IsGlobalNamespaceEnabled() {
-			return nil, serviceerror.NewInvalidArgumentf("global namespace is not enabled on this "+
-				"cluster, cannot update global namespace or promote local namespace: %v", updateRequest.Namespace)
+			return nil, errGlobalNamespaceNotEnabled
 		}
 	} else {
 		if err := d.namespaceAttrValidator.ValidateNamespaceReplicationConfigForLocalNamespace(
 			replicationConfig,
 		); err != nil {
-			return nil, err
+			return nil, withFieldPath(err, "replicationConfig")
 		}
 	}
 
@@ -541,10 +1950,14 @@ func (d *namespaceHandler) UpdateNamespace(
 		return nil, errCannotDoNamespaceFailoverAndUpdate
 	} else if configurationChanged || activeClusterChanged || needsNamespacePromotion {
 		if (needsNamespacePromotion || activeClusterChanged) && isGlobalNamespace {
-			failoverVersion = d.clusterMetadata.GetNextFailoverVersion(
+			failoverVersion, err = d.nextFailoverVersion(
+				info.Name,
 				replicationConfig.ActiveClusterName,
 				failoverVersion,
 			)
+			if err != nil {
+				return nil, err
+			}
 			failoverNotificationVersion = notificationVersion
 		}
 		// set the versions
@@ -564,82 +1977,498 @@ func (d *namespaceHandler) UpdateNamespace(
 		}
 
 		replicationConfig.FailoverHistory = failoverHistory
-		updateReq := &persistence.UpdateNamespaceRequest{
-			Namespace: &persistencespb.NamespaceDetail{
-				Info:                        info,
-				Config:                      config,
-				ReplicationConfig:           replicationConfig,
-				ConfigVersion:               configVersion,
-				FailoverVersion:             failoverVersion,
-				FailoverNotificationVersion: failoverNotificationVersion,
-			},
-			IsGlobalNamespace:   isGlobalNamespace,
-			NotificationVersion: notificationVersion,
+		if !options.dryRun {
+			if !options.skipConfigChangeRateLimit {
+				if err := d.reserveNamespaceConfigChange(info.Name, config); err != nil {
+					return nil, err
+				}
+			}
+			updateReq := &persistence.UpdateNamespaceRequest{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info:                        info,
+					Config:                      config,
+					ReplicationConfig:           replicationConfig,
+					ConfigVersion:               configVersion,
+					FailoverVersion:             failoverVersion,
+					FailoverNotificationVersion: failoverNotificationVersion,
+				},
+				IsGlobalNamespace:   isGlobalNamespace,
+				NotificationVersion: notificationVersion,
+			}
+			err = d.metadataMgr.UpdateNamespace(ctx, updateReq)
+			if err != nil {
+				return nil, err
+			}
+			d.invalidateDescribeNamespaceCache(info)
+			if undeprecated {
+				d.logger.Warn("Deprecated namespace was registered again.",
+					tag.WorkflowNamespace(info.Name),
+					tag.WorkflowNamespaceID(info.Id),
+					tag.NewInt64("config-version", configVersion),
+				)
+			}
 		}
-		err = d.metadataMgr.UpdateNamespace(ctx, updateReq)
+	}
+
+	// local namespaces are not replicated, a promoted namespace is global and replicated from its promotion on
+	if !options.dryRun && isGlobalNamespace {
+		err = d.namespaceReplicator.HandleTransmissionTask(
+			ctx,
+			enumsspb.NAMESPACE_OPERATION_UPDATE,
+			info,
+			config,
+			replicationConfig,
+			clusterListChanged,
+			configVersion,
+			failoverVersion,
+			isGlobalNamespace,
+			failoverHistory,
+		)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	err = d.namespaceReplicator.HandleTransmissionTask(
-		ctx,
-		enumsspb.NAMESPACE_OPERATION_UPDATE,
-		info,
-		config,
-		replicationConfig,
-		clusterListChanged,
-		configVersion,
-		failoverVersion,
-		isGlobalNamespace,
-		failoverHistory,
-	)
-	if err != nil {
-		return nil, err
-	}
-
 	response := &workflowservice.UpdateNamespaceResponse{
 		IsGlobalNamespace: isGlobalNamespace,
 		FailoverVersion:   failoverVersion,
 	}
 	response.NamespaceInfo, response.Config, response.ReplicationConfig, _ = d.createResponse(info, config, replicationConfig)
 
-	d.logger.Info("Update namespace succeeded",
-		tag.WorkflowNamespace(info.Name),
-		tag.WorkflowNamespaceID(info.Id),
-	)
-	return response, nil
+	if !options.dryRun {
+		d.logger.Info("Update namespace succeeded",
+			tag.WorkflowNamespace(info.Name),
+			tag.WorkflowNamespaceID(info.Id),
+		)
+	}
+	diff := diffNamespaceDetail(existingNamespace, &persistencespb.NamespaceDetail{
+		Config:            config,
+		ReplicationConfig: replicationConfig,
+	})
+	return &UpdateNamespaceResult{
+		UpdateNamespaceResponse: response,
+		Diff:                    diff,
+		ChangedFields:           changedNamespaceFields(diff),
+		DryRun:                  options.dryRun,
+	}, nil
 }
 
-// DeprecateNamespace deprecates a namespace
-// Deprecated.
-func (d *namespaceHandler) DeprecateNamespace(
+// ValidateArchivalUpdate validates the archival config of an UpdateNamespaceRequest without updating the namespace,
+// to confirm an archival storage migration beforehand. For each archival URI changed by the request, it validates
+// the new URI, probes the archiver for read access to the data archived at the old URI, and checks whether
+// UpdateNamespace would accept the change.
+func (d *namespaceHandler) ValidateArchivalUpdate(
 	ctx context.Context,
-	deprecateRequest *workflowservice.DeprecateNamespaceRequest,
-) (*workflowservice.DeprecateNamespaceResponse, error) {
-
-	clusterMetadata := d.clusterMetadata
-	// TODO remove the IsGlobalNamespaceEnabled check once cross DC is public
-	if clusterMetadata.IsGlobalNamespaceEnabled() && !clusterMetadata.IsMasterCluster() {
-		return nil, errNotMasterCluster
-	}
+	updateRequest *workflowservice.UpdateNamespaceRequest,
+) (_ *ArchivalMigrationReport, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("ValidateArchivalUpdate", updateRequest.GetNamespace(), time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
 
-	// must get the metadata (notificationVersion) first
-	// this version can be regarded as the lock on the v2 namespace table
-	// and since we do not know which table will return the namespace afterwards
-	// this call has to be made
-	metadata, err := d.metadataMgr.GetMetadata(ctx)
-	if err != nil {
-		return nil, err
-	}
-	notificationVersion := metadata.NotificationVersion
-	getResponse, err := d.metadataMgr.GetNamespace(ctx, &persistence.GetNamespaceRequest{Name: deprecateRequest.GetNamespace()})
+	getResponse, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: updateRequest.GetNamespace()})
 	if err != nil {
 		return nil, err
 	}
+	namespaceID := getResponse.Namespace.GetInfo().GetId()
+	config := getResponse.Namespace.GetConfig()
+	requestConfig := updateRequest.GetConfig()
 
-	getResponse.Namespace.ConfigVersion = getResponse.Namespace.ConfigVersion + 1
-	getResponse.Namespace.Info.State = enumspb.NAMESPACE_STATE_DEPRECATED
+	report := &ArchivalMigrationReport{}
+	if uri := requestConfig.GetHistoryArchivalUri(); uri != "" && uri != config.GetHistoryArchivalUri() {
+		report.History = d.newArchivalURIMigrationReport(
+			d.archivalMetadata.GetHistoryConfig(),
+			errClusterIsNotConfiguredForHistoryArchival,
+			&namespace.ArchivalConfigState{State: config.GetHistoryArchivalState(), URI: config.GetHistoryArchivalUri()},
+			requestConfig.GetHistoryArchivalState(),
+			uri,
+			d.validateHistoryArchivalURI,
+			func(uri archiver.URI) error { return d.probeHistoryArchivalURI(ctx, namespaceID, uri) },
+		)
+	}
+	if uri := requestConfig.GetVisibilityArchivalUri(); uri != "" && uri != config.GetVisibilityArchivalUri() {
+		report.Visibility = d.newArchivalURIMigrationReport(
+			d.archivalMetadata.GetVisibilityConfig(),
+			errClusterIsNotConfiguredForVisibilityArchival,
+			&namespace.ArchivalConfigState{State: config.GetVisibilityArchivalState(), URI: config.GetVisibilityArchivalUri()},
+			requestConfig.GetVisibilityArchivalState(),
+			uri,
+			d.validateVisibilityArchivalURI,
+			func(uri archiver.URI) error { return d.probeVisibilityArchivalURI(ctx, namespaceID, uri) },
+		)
+	}
+	return report, nil
+}
+
+// newArchivalURIMigrationReport checks the move of an archival of ValidateArchivalUpdate from the URI of the
+// current state to newURI.
+func (d *namespaceHandler) newArchivalURIMigrationReport(
+	clusterConfig archiver.ArchivalConfig,
+	errClusterNotConfigured error,
+	current *namespace.ArchivalConfigState,
+	state enumspb.ArchivalState,
+	newURI string,
+	validateURI func(string) error,
+	probeURI func(archiver.URI) error,
+) *ArchivalURIMigrationReport {
+	report := &ArchivalURIMigrationReport{
+		OldURI:    current.URI,
+		NewURI:    newURI,
+		NewURIErr: validateURI(newURI),
+	}
+
+	if current.URI != "" {
+		if oldURI, err := archiver.NewURI(current.URI); err != nil {
+			report.OldURIReadErr = err
+		} else {
+			report.OldURIReadErr = probeURI(oldURI)
+		}
+	}
+
+	if !clusterConfig.ClusterConfiguredForArchival() {
+		report.UpdateErr = errClusterNotConfigured
+		return report
+	}
+	event, err := d.toArchivalUpdateEvent(state, newURI, clusterConfig.GetNamespaceDefaultURI())
+	if err == nil {
+		_, _, err = current.GetNextState(event, validateURI)
+	}
+	report.UpdateErr = err
+	return report
+}
+
+// probeHistoryArchivalURI checks that the history archiver can read from the URI, by getting the history of a
+// workflow which does not exist.
+func (d *namespaceHandler) probeHistoryArchivalURI(ctx context.Context, namespaceID string, uri archiver.URI) error {
+	historyArchiver, err := d.archiverProvider.GetHistoryArchiver(uri.Scheme())
+	if err != nil {
+		return err
+	}
+	_, err = historyArchiver.Get(ctx, uri, &archiver.GetHistoryRequest{
+		NamespaceID: namespaceID,
+		WorkflowID:  archivalProbeWorkflowID,
+		RunID:       uuid.New(),
+		PageSize:    1,
+	})
+	if _, ok := err.(*serviceerror.NotFound); ok {
+		return nil
+	}
+	return err
+}
+
+// probeVisibilityArchivalURI checks that the visibility archiver can read from the URI, by querying a single
+// record of the namespace.
+func (d *namespaceHandler) probeVisibilityArchivalURI(ctx context.Context, namespaceID string, uri archiver.URI) error {
+	visibilityArchiver, err := d.archiverProvider.GetVisibilityArchiver(uri.Scheme())
+	if err != nil {
+		return err
+	}
+	_, err = visibilityArchiver.Query(ctx, uri, &archiver.QueryVisibilityRequest{
+		NamespaceID: namespaceID,
+		PageSize:    1,
+	}, searchattribute.NameTypeMap{})
+	if _, ok := err.(*serviceerror.NotFound); ok {
+		return nil
+	}
+	return err
+}
+
+// BatchFailoverNamespaces fails over each of the given global namespaces to targetCluster, e.g. to evacuate a
+// cluster. Each namespace goes through the same failover as UpdateNamespace with a new active cluster, so it gets a
+// new failover version and failover history entry and is replicated to the other clusters. Failovers are paced by
+// frontend.batchFailoverNamespacesRPS and a failure only fails its own namespace. Namespaces already active in
+// targetCluster are left unchanged and reported as successful.
+func (d *namespaceHandler) BatchFailoverNamespaces(
+	ctx context.Context,
+	names []string,
+	targetCluster string,
+) (_ []*NamespaceFailoverResult, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("BatchFailoverNamespaces", "", time.Since(startTime), retErr)
+	}()
+
+	if err := d.validateFailoverTargetCluster(targetCluster); err != nil {
+		return nil, err
+	}
+
+	var results []*NamespaceFailoverResult
+	for _, name := range names {
+		if slices.ContainsFunc(results, func(r *NamespaceFailoverResult) bool { return r.Namespace == name }) {
+			continue
+		}
+		result := &NamespaceFailoverResult{Namespace: name}
+		results = append(results, result)
+		if err := d.batchFailoverRateLimiter.Wait(ctx); err != nil {
+			result.Err = err
+			continue
+		}
+		failoverCtx, done := d.withOperationTimeout(ctx)
+		result.FailoverVersion, result.Err = d.failoverNamespace(failoverCtx, name, targetCluster)
+		done(&result.Err)
+	}
+	return results, nil
+}
+
+// FailoverNamespace fails over the global namespace to targetCluster and returns its failover version. Unlike
+// UpdateNamespace, it only changes the active cluster, so a failover cannot be mixed with configuration changes.
+// The namespace gets a new failover version and failover history entry and is replicated to the other clusters.
+// A namespace already active in targetCluster is left unchanged.
+func (d *namespaceHandler) FailoverNamespace(
+	ctx context.Context,
+	nsName string,
+	targetCluster string,
+) (_ int64, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("FailoverNamespace", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	if err := d.validateFailoverTargetCluster(targetCluster); err != nil {
+		return 0, err
+	}
+	return d.failoverNamespace(ctx, nsName, targetCluster)
+}
+
+// GracefulFailoverNamespace fails over the global namespace to the target cluster through a handover. The
+// namespace is put in REPLICATION_STATE_HANDOVER, which stops it from taking writes, until at most
+// MaxPendingTasks history replication tasks are pending for the target cluster on every shard. The active cluster is
+// then changed and the replication state set back to normal. If the target cluster does not catch up within the
+// timeout, the handover is aborted and the namespace stays active in its current cluster. Returns the failover version
+// of the namespace.
+func (d *namespaceHandler) GracefulFailoverNamespace(
+	ctx context.Context,
+	request *GracefulFailoverNamespaceRequest,
+) (_ int64, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("GracefulFailoverNamespace", request.Namespace, time.Since(startTime), retErr)
+	}()
+
+	if err := d.validateFailoverTargetCluster(request.TargetCluster); err != nil {
+		return 0, err
+	}
+	// the handover may take longer than the operation timeout, only its steps are bounded by it
+	getCtx, done := d.withOperationTimeout(ctx)
+	getResponse, err := d.getNamespace(getCtx, &persistence.GetNamespaceRequest{Name: request.Namespace})
+	done(&err)
+	if err != nil {
+		return 0, err
+	}
+	if !getResponse.IsGlobalNamespace {
+		return 0, serviceerror.NewInvalidArgumentf("Namespace %q is not a global namespace.", request.Namespace)
+	}
+	replicationConfig := getResponse.Namespace.GetReplicationConfig()
+	if replicationConfig.GetState() == enumspb.REPLICATION_STATE_HANDOVER {
+		return 0, serviceerror.NewFailedPreconditionf("Namespace %q is in handover, its failover is already in progress.", request.Namespace)
+	}
+	if replicationConfig.GetActiveClusterName() == request.TargetCluster {
+		return getResponse.Namespace.GetFailoverVersion(), nil
+	}
+	// the handover steps are excluded from the config change rate limit, only the failover counts as a config
+	// change. The limit is checked before the handover starts, so that the failover is rarely throttled after it.
+	if _, err := d.checkNamespaceConfigChangeRate(request.Namespace, getResponse.Namespace.GetConfig()); err != nil {
+		return 0, err
+	}
+
+	if err := d.setReplicationState(ctx, request.Namespace, enumspb.REPLICATION_STATE_HANDOVER); err != nil {
+		return 0, err
+	}
+	handoverDone := false
+	defer func() {
+		if handoverDone {
+			return
+		}
+		// the namespace does not take writes in handover, it must not be left in it
+		if abortErr := d.setReplicationState(context.WithoutCancel(ctx), request.Namespace, enumspb.REPLICATION_STATE_NORMAL); abortErr != nil {
+			d.logger.Error("Failed to abort namespace handover.",
+				tag.WorkflowNamespace(request.Namespace),
+				tag.Error(abortErr))
+		}
+	}()
+
+	if err := d.waitForFailoverTarget(ctx, request); err != nil {
+		return 0, err
+	}
+	resp, err := d.UpdateNamespace(
+		ctx,
+		&workflowservice.UpdateNamespaceRequest{
+			Namespace: request.Namespace,
+			ReplicationConfig: &replicationpb.NamespaceReplicationConfig{
+				ActiveClusterName: request.TargetCluster,
+			},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	if err := d.setReplicationState(ctx, request.Namespace, enumspb.REPLICATION_STATE_NORMAL); err != nil {
+		return 0, err
+	}
+	handoverDone = true
+	return resp.GetFailoverVersion(), nil
+}
+
+// setReplicationState updates the replication state of the namespace. The state change is a step of a handover,
+// it is not subject to the config change rate limit.
+func (d *namespaceHandler) setReplicationState(ctx context.Context, nsName string, state enumspb.ReplicationState) error {
+	_, err := d.UpdateNamespace(
+		ctx,
+		&workflowservice.UpdateNamespaceRequest{
+			Namespace: nsName,
+			ReplicationConfig: &replicationpb.NamespaceReplicationConfig{
+				State: state,
+			},
+		},
+		withoutConfigChangeRateLimit(),
+	)
+	return err
+}
+
+// waitForFailoverTarget polls the history replication status until at most MaxPendingTasks replication tasks are
+// pending for the target cluster on every history shard, or the timeout of the request expires. The namespace is in
+// handover while it waits, so no new tasks of the namespace are created.
+func (d *namespaceHandler) waitForFailoverTarget(ctx context.Context, request *GracefulFailoverNamespaceRequest) error {
+	timeout := cmp.Or(request.Timeout, defaultGracefulFailoverTimeout)
+	pollInterval := cmp.Or(request.PollInterval, defaultGracefulFailoverPollInterval)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return serviceerror.NewDeadlineExceededf(
+				"Cluster %s did not catch up with namespace %q within %v, the handover was aborted.",
+				request.TargetCluster,
+				request.Namespace,
+				timeout,
+			)
+		case <-timer.C:
+		}
+
+		resp, err := d.historyClient.GetReplicationStatus(ctx, &historyservice.GetReplicationStatusRequest{
+			RemoteClusters: []string{request.TargetCluster},
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return err
+		}
+		if d.historyReplicationCaughtUp(resp, request.TargetCluster, request.MaxPendingTasks) {
+			return nil
+		}
+		timer.Reset(pollInterval)
+	}
+}
+
+// historyReplicationCaughtUp tells whether every history shard has at most maxPendingTasks replication tasks not yet
+// acknowledged by the target cluster.
+func (d *namespaceHandler) historyReplicationCaughtUp(
+	resp *historyservice.GetReplicationStatusResponse,
+	targetCluster string,
+	maxPendingTasks int64,
+) bool {
+	if len(resp.GetShards()) < int(d.config.NumHistoryShards) {
+		return false
+	}
+	for _, shard := range resp.GetShards() {
+		clusterInfo, ok := shard.GetRemoteClusters()[targetCluster]
+		if !ok || shard.GetMaxReplicationTaskId()-clusterInfo.GetAckedTaskId() > maxPendingTasks {
+			return false
+		}
+	}
+	return true
+}
+
+// validateFailoverTargetCluster checks that namespaces can be failed over from this cluster to targetCluster.
+func (d *namespaceHandler) validateFailoverTargetCluster(targetCluster string) error {
+	if !d.clusterMetadata.IsGlobalNamespaceEnabled() {
+		return errGlobalNamespaceNotEnabled
+	}
+	if !d.clusterMetadata.IsMasterCluster() {
+		return errNotMasterCluster
+	}
+	if clusterInfo, ok := d.clusterMetadata.GetAllClusterInfo()[targetCluster]; !ok || !clusterInfo.Enabled {
+		return serviceerror.NewInvalidArgumentf("Target cluster %q is not an enabled cluster.", targetCluster)
+	}
+	return nil
+}
+
+// failoverNamespace fails over a single namespace of FailoverNamespace or BatchFailoverNamespaces and returns its
+// failover version.
+func (d *namespaceHandler) failoverNamespace(ctx context.Context, name string, targetCluster string) (int64, error) {
+	getResponse, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: name})
+	if err != nil {
+		return 0, err
+	}
+	if !getResponse.IsGlobalNamespace {
+		return 0, serviceerror.NewInvalidArgumentf("Namespace %q is not a global namespace.", name)
+	}
+	replicationConfig := getResponse.Namespace.GetReplicationConfig()
+	if replicationConfig.GetState() == enumspb.REPLICATION_STATE_HANDOVER {
+		return 0, serviceerror.NewFailedPreconditionf("Namespace %q is in handover, its failover is already in progress.", name)
+	}
+	if replicationConfig.GetActiveClusterName() == targetCluster {
+		return getResponse.Namespace.GetFailoverVersion(), nil
+	}
+
+	resp, err := d.UpdateNamespace(ctx, &workflowservice.UpdateNamespaceRequest{
+		Namespace: name,
+		ReplicationConfig: &replicationpb.NamespaceReplicationConfig{
+			ActiveClusterName: targetCluster,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return resp.GetFailoverVersion(), nil
+}
+
+// DeprecateNamespace deprecates a namespace
+// Deprecated.
+func (d *namespaceHandler) DeprecateNamespace(
+	ctx context.Context,
+	deprecateRequest *workflowservice.DeprecateNamespaceRequest,
+) (_ *DeprecateNamespaceResult, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("DeprecateNamespace", deprecateRequest.GetNamespace(), time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	clusterMetadata := d.clusterMetadata
+	// TODO remove the IsGlobalNamespaceEnabled check once cross DC is public
+	if clusterMetadata.IsGlobalNamespaceEnabled() && !clusterMetadata.IsMasterCluster() {
+		return nil, errNotMasterCluster
+	}
+
+	// must get the metadata (notificationVersion) first
+	// this version can be regarded as the lock on the v2 namespace table
+	// and since we do not know which table will return the namespace afterwards
+	// this call has to be made
+	metadata, err := d.metadataMgr.GetMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	notificationVersion := metadata.NotificationVersion
+	getResponse, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: deprecateRequest.GetNamespace()})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.reserveNamespaceConfigChange(getResponse.Namespace.Info.Name, getResponse.Namespace.Config); err != nil {
+		return nil, err
+	}
+	getResponse.Namespace.ConfigVersion = getResponse.Namespace.ConfigVersion + 1
+	getResponse.Namespace.Info.State = enumspb.NAMESPACE_STATE_DEPRECATED
 	updateReq := &persistence.UpdateNamespaceRequest{
 		Namespace: &persistencespb.NamespaceDetail{
 			Info:                        getResponse.Namespace.Info,
@@ -656,7 +2485,35 @@ func (d *namespaceHandler) DeprecateNamespace(
 	if err != nil {
 		return nil, err
 	}
-	return nil, nil
+	d.invalidateDescribeNamespaceCache(updateReq.Namespace.Info)
+
+	err = d.namespaceReplicator.HandleTransmissionTask(
+		ctx,
+		enumsspb.NAMESPACE_OPERATION_UPDATE,
+		updateReq.Namespace.Info,
+		updateReq.Namespace.Config,
+		updateReq.Namespace.ReplicationConfig,
+		false,
+		updateReq.Namespace.ConfigVersion,
+		updateReq.Namespace.FailoverVersion,
+		updateReq.IsGlobalNamespace,
+		updateReq.Namespace.ReplicationConfig.GetFailoverHistory(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	d.logger.Info("Deprecate namespace succeeded",
+		tag.WorkflowNamespace(updateReq.Namespace.Info.Name),
+		tag.WorkflowNamespaceID(updateReq.Namespace.Info.Id),
+	)
+	return &DeprecateNamespaceResult{
+		DeprecateNamespaceResponse: &workflowservice.DeprecateNamespaceResponse{},
+		State:                      updateReq.Namespace.Info.State,
+		ConfigVersion:              updateReq.Namespace.ConfigVersion,
+		// the replicator only publishes tasks for global namespaces which span more than one cluster
+		Replicated: updateReq.IsGlobalNamespace && len(updateReq.Namespace.ReplicationConfig.GetClusters()) > 1,
+	}, nil
 }
 
 func (d *namespaceHandler) CreateWorkflowRule(
@@ -665,178 +2522,965 @@ func (d *namespaceHandler) CreateWorkflowRule(
 	createdByIdentity string,
 	description string,
 	nsName string,
-) (*rulespb.WorkflowRule, error) {
+) (_ *rulespb.WorkflowRule, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("CreateWorkflowRule", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
 
 	if ruleSpec.GetId() == "" {
 		return nil, serviceerror.NewInvalidArgument("Workflow Rule ID is not set.")
 	}
+	if err := d.validateWorkflowRuleSpec(nsName, ruleSpec); err != nil {
+		return nil, err
+	}
 
+	var workflowRule *rulespb.WorkflowRule
+	err := d.updateWorkflowRules(ctx, nsName, func(config *persistencespb.NamespaceConfig) error {
+		if config.WorkflowRules == nil {
+			config.WorkflowRules = make(map[string]*rulespb.WorkflowRule)
+		} else {
+			// expired rules do not count against the limit
+			d.removeExpiredWorkflowRules(nsName, config)
+			maxRules := d.config.MaxWorkflowRulesPerNamespace(nsName)
+			if len(config.WorkflowRules) >= maxRules {
+				return serviceerror.NewInvalidArgumentf("Workflow Rule limit exceeded. Max: %v", maxRules)
+			}
+		}
+
+		_, ok := config.WorkflowRules[ruleSpec.GetId()]
+		if ok {
+			return serviceerror.NewInvalidArgument("Workflow Rule with this ID already exists.")
+		}
+
+		workflowRule = &rulespb.WorkflowRule{
+			Spec:              ruleSpec,
+			CreateTime:        timestamppb.New(d.timeSource.Now()),
+			CreatedByIdentity: createdByIdentity,
+			Description:       description,
+		}
+		config.WorkflowRules[ruleSpec.GetId()] = workflowRule
+		delete(config.WorkflowRuleUpdates, ruleSpec.GetId())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return workflowRule, nil
+}
+
+// updateWorkflowRules applies the change to the workflow rules of the namespace config and persists it, unless
+// the change returns errWorkflowRulesUnchanged. The namespace update is conditional on the notification version
+// read before the change, so a concurrent namespace update fails it rather than being overwritten. The change is
+// then applied again to a freshly read config, up to workflowRuleUpdateMaxAttempts times. Other update failures are
+// returned immediately.
+func (d *namespaceHandler) updateWorkflowRules(
+	ctx context.Context,
+	nsName string,
+	change func(config *persistencespb.NamespaceConfig) error,
+) error {
+	for attempt := 1; ; attempt++ {
+		metadata, err := d.metadataMgr.GetMetadata(ctx)
+		if err != nil {
+			return err
+		}
+		getNamespaceResponse, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: nsName})
+		if err != nil {
+			return err
+		}
+
+		existingNamespace := getNamespaceResponse.Namespace
+		config := getNamespaceResponse.Namespace.Config
+		if err := change(config); err != nil {
+			if errors.Is(err, errWorkflowRulesUnchanged) {
+				return nil
+			}
+			return err
+		}
+
+		if err := d.reserveNamespaceConfigChange(existingNamespace.Info.Name, config); err != nil {
+			return err
+		}
+		updateReq := &persistence.UpdateNamespaceRequest{
+			Namespace: &persistencespb.NamespaceDetail{
+				Info:                        existingNamespace.Info,
+				Config:                      config,
+				ReplicationConfig:           existingNamespace.ReplicationConfig,
+				ConfigVersion:               existingNamespace.ConfigVersion + 1,
+				FailoverVersion:             existingNamespace.FailoverVersion,
+				FailoverNotificationVersion: existingNamespace.FailoverNotificationVersion,
+			},
+			IsGlobalNamespace:   getNamespaceResponse.IsGlobalNamespace,
+			NotificationVersion: metadata.NotificationVersion,
+		}
+		err = d.metadataMgr.UpdateNamespace(ctx, updateReq)
+		if err == nil {
+			d.invalidateDescribeNamespaceCache(existingNamespace.Info)
+			return nil
+		}
+		if attempt >= workflowRuleUpdateMaxAttempts || !d.namespaceUpdateConflicted(ctx, err, metadata.NotificationVersion) {
+			return err
+		}
+		d.logger.Info("Retrying workflow rule change after namespace update failure.",
+			tag.WorkflowNamespace(nsName),
+			tag.Attempt(int32(attempt)),
+			tag.Error(err))
+	}
+}
+
+// namespaceUpdateConflicted reports whether a namespace update conditional on the notification version failed
+// because another namespace update went first. The persistence stores report a failed conditional update as
+// Unavailable, the same as a store outage, so an Unavailable error only counts as a conflict if the notification
+// version has moved on since it was read.
+func (d *namespaceHandler) namespaceUpdateConflicted(
+	ctx context.Context,
+	updateErr error,
+	notificationVersion int64,
+) bool {
+	var conditionFailedErr *persistence.ConditionFailedError
+	if errors.As(updateErr, &conditionFailedErr) {
+		return true
+	}
+	var unavailableErr *serviceerror.Unavailable
+	if !errors.As(updateErr, &unavailableErr) {
+		return false
+	}
 	metadata, err := d.metadataMgr.GetMetadata(ctx)
+	if err != nil {
+		return false
+	}
+	return metadata.NotificationVersion != notificationVersion
+}
+
+// validateWorkflowRuleSpec rejects rules which would fail every time they are triggered, and rules which can never
+// match because their visibility query is missing or they have already expired. A nil expiration time means the rule
+// never expires.
+func (d *namespaceHandler) validateWorkflowRuleSpec(nsName string, ruleSpec *rulespb.WorkflowRuleSpec) error {
+	if expirationTime := ruleSpec.GetExpirationTime(); expirationTime != nil {
+		if err := expirationTime.CheckValid(); err != nil {
+			return serviceerror.NewInvalidArgumentf("Workflow Rule expiration time is invalid: %v.", err)
+		}
+		if !expirationTime.AsTime().After(d.timeSource.Now()) {
+			return serviceerror.NewInvalidArgumentf(
+				"Workflow Rule expiration time %v is not in the future.", expirationTime.AsTime().Format(time.RFC3339),
+			)
+		}
+	}
+
+	if activityStart := ruleSpec.GetActivityStart(); activityStart != nil {
+		if err := validateWorkflowRuleActivityPredicate(activityStart.GetPredicate()); err != nil {
+			return err
+		}
+	}
+
+	for i, action := range ruleSpec.GetActions() {
+		switch variant := action.GetVariant().(type) {
+		case *rulespb.WorkflowRuleAction_ActivityPause:
+			if ruleSpec.GetActivityStart() == nil {
+				return serviceerror.NewInvalidArgumentf("Workflow Rule action %d: activity pause action requires an activity start trigger.", i)
+			}
+		case nil:
+			return serviceerror.NewInvalidArgumentf("Workflow Rule action %d: action type is not set.", i)
+		default:
+			return serviceerror.NewInvalidArgumentf("Workflow Rule action %d: unsupported action type %T.", i, variant)
+		}
+	}
+
+	query := ruleSpec.GetVisibilityQuery()
+	if query == "" {
+		return serviceerror.NewInvalidArgument("Workflow Rule visibility query is not set.")
+	}
+	return d.validateWorkflowRuleQuery(nsName, query)
+}
+
+// validateWorkflowRuleQuery checks that the visibility query of a workflow rule parses and only references the
+// search attributes the history service matches against the mutable state of a workflow. Other attributes are
+// resolved through the search attribute mapper of the namespace, so that an attribute, or alias, which is not
+// defined for the namespace is told apart from a custom search attribute, which workflow rules do not support.
+func (d *namespaceHandler) validateWorkflowRuleQuery(nsName string, query string) error {
+	names, err := sqlquery.FilterColumnNames(query)
+	if err != nil {
+		return serviceerror.NewInvalidArgumentf("Workflow Rule visibility query is invalid: %v.", err)
+	}
+
+	var saMapper searchattribute.Mapper
+	var saTypeMap searchattribute.NameTypeMap
+	for _, name := range names {
+		switch name {
+		case searchattribute.WorkflowID, searchattribute.WorkflowType, searchattribute.ExecutionStatus, searchattribute.StartTime:
+			continue
+		}
+		if !searchattribute.IsMappable(name) {
+			return serviceerror.NewInvalidArgumentf(
+				"Workflow Rule visibility query is invalid: search attribute %s is not supported by workflow rules. Only %s, %s, %s and %s can be used.",
+				name,
+				searchattribute.WorkflowID,
+				searchattribute.WorkflowType,
+				searchattribute.ExecutionStatus,
+				searchattribute.StartTime,
+			)
+		}
+
+		if saMapper == nil {
+			if saMapper, err = d.saMapperProvider.GetMapper(namespace.Name(nsName)); err != nil {
+				return err
+			}
+			if saTypeMap, err = d.saProvider.GetSearchAttributes(d.visibilityMgr.GetIndexName(), false); err != nil {
+				return serviceerror.NewUnavailablef(errUnableToGetSearchAttributesMessage, err)
+			}
+		}
+		fieldName, err := saMapper.GetFieldName(name, nsName)
+		if err != nil {
+			return serviceerror.NewInvalidArgumentf(
+				"Workflow Rule visibility query is invalid: search attribute %s is not defined for namespace %s.", name, nsName,
+			)
+		}
+		if _, ok := saTypeMap.Custom()[fieldName]; !ok {
+			return serviceerror.NewInvalidArgumentf(
+				"Workflow Rule visibility query is invalid: search attribute %s is not defined for namespace %s.", name, nsName,
+			)
+		}
+		return serviceerror.NewInvalidArgumentf(
+			"Workflow Rule visibility query is invalid: %s is a custom search attribute, custom search attributes are not supported by workflow rules.",
+			name,
+		)
+	}
+	return nil
+}
+
+// validateWorkflowRuleActivityPredicate checks that the predicate of an activity start trigger parses and only
+// references the activity fields the history service matches against a pending activity.
+func validateWorkflowRuleActivityPredicate(predicate string) error {
+	if predicate == "" {
+		return serviceerror.NewInvalidArgument("Workflow Rule activity start predicate is not set.")
+	}
+	names, err := sqlquery.FilterColumnNames(predicate)
+	if err != nil {
+		return serviceerror.NewInvalidArgumentf("Workflow Rule activity start predicate is invalid: %v.", err)
+	}
+	for _, name := range names {
+		if !slices.Contains(workflowRuleActivityPredicateFields, name) {
+			return serviceerror.NewInvalidArgumentf(
+				"Workflow Rule activity start predicate is invalid: unsupported activity field %s. Supported fields are %s.",
+				name,
+				strings.Join(workflowRuleActivityPredicateFields, ", "),
+			)
+		}
+	}
+	return nil
+}
+
+// UpdateWorkflowRule replaces the spec and description of an existing workflow rule. The rule keeps its creation
+// time and creator, and its last update is recorded.
+func (d *namespaceHandler) UpdateWorkflowRule(
+	ctx context.Context,
+	ruleSpec *rulespb.WorkflowRuleSpec,
+	updatedByIdentity string,
+	description string,
+	nsName string,
+) (_ *WorkflowRuleDetail, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("UpdateWorkflowRule", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	if ruleSpec.GetId() == "" {
+		return nil, serviceerror.NewInvalidArgument("Workflow Rule ID is not set.")
+	}
+	if err := d.validateWorkflowRuleSpec(nsName, ruleSpec); err != nil {
+		return nil, err
+	}
+
+	var workflowRule *rulespb.WorkflowRule
+	var ruleUpdate *persistencespb.WorkflowRuleUpdate
+	err := d.updateWorkflowRules(ctx, nsName, func(config *persistencespb.NamespaceConfig) error {
+		existingRule, ok := config.WorkflowRules[ruleSpec.GetId()]
+		if !ok {
+			return serviceerror.NewNotFoundf("Workflow Rule %q not found.", ruleSpec.GetId())
+		}
+
+		workflowRule = &rulespb.WorkflowRule{
+			Spec:              ruleSpec,
+			CreateTime:        existingRule.GetCreateTime(),
+			CreatedByIdentity: existingRule.GetCreatedByIdentity(),
+			Description:       description,
+		}
+		ruleUpdate = &persistencespb.WorkflowRuleUpdate{
+			LastUpdateTime:        timestamppb.New(d.timeSource.Now()),
+			LastUpdatedByIdentity: updatedByIdentity,
+		}
+		config.WorkflowRules[ruleSpec.GetId()] = workflowRule
+		if config.WorkflowRuleUpdates == nil {
+			config.WorkflowRuleUpdates = make(map[string]*persistencespb.WorkflowRuleUpdate)
+		}
+		config.WorkflowRuleUpdates[ruleSpec.GetId()] = ruleUpdate
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorkflowRuleDetail{
+		WorkflowRule:          workflowRule,
+		LastUpdateTime:        ruleUpdate.LastUpdateTime,
+		LastUpdatedByIdentity: ruleUpdate.LastUpdatedByIdentity,
+	}, nil
+}
+
+// RemoveExpiredWorkflowRules removes the workflow rules of the namespace whose expiration time has passed and
+// returns the removed rules. The namespace is not updated if no rule has expired, so the sweep can run
+// repeatedly.
+func (d *namespaceHandler) RemoveExpiredWorkflowRules(
+	ctx context.Context, nsName string,
+) (_ []*rulespb.WorkflowRule, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("RemoveExpiredWorkflowRules", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	var removedRules []*rulespb.WorkflowRule
+	err := d.updateWorkflowRules(ctx, nsName, func(config *persistencespb.NamespaceConfig) error {
+		removedRules = d.removeExpiredWorkflowRules(nsName, config)
+		if len(removedRules) == 0 {
+			return errWorkflowRulesUnchanged
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removedRules, nil
+}
+
+// removeExpiredWorkflowRules removes the workflow rules whose expiration time has passed from the config and
+// returns them ordered by rule ID.
+func (d *namespaceHandler) removeExpiredWorkflowRules(
+	nsName string, config *persistencespb.NamespaceConfig,
+) []*rulespb.WorkflowRule {
+	now := d.timeSource.Now()
+	var removedRules []*rulespb.WorkflowRule
+	for key, rule := range config.WorkflowRules {
+		if !workflowRuleExpired(rule, now) {
+			continue
+		}
+		d.logger.Info(
+			"Removed expired workflow rule",
+			tag.WorkflowRuleID(key),
+			tag.WorkflowNamespace(nsName),
+		)
+		delete(config.WorkflowRules, key)
+		delete(config.WorkflowRuleUpdates, key)
+		removedRules = append(removedRules, rule)
+	}
+	slices.SortFunc(removedRules, func(a, b *rulespb.WorkflowRule) int {
+		return cmp.Compare(a.GetSpec().GetId(), b.GetSpec().GetId())
+	})
+	return removedRules
+}
+
+// workflowRuleExpired returns whether the expiration time of the rule has passed, rules without an expiration
+// time never expire.
+func workflowRuleExpired(rule *rulespb.WorkflowRule, now time.Time) bool {
+	expirationTime := rule.GetSpec().GetExpirationTime()
+	return expirationTime != nil && !expirationTime.AsTime().After(now)
+}
+
+func (d *namespaceHandler) DescribeWorkflowRule(
+	ctx context.Context, ruleID string, nsName string, opts ...WorkflowRulesOption,
+) (*rulespb.WorkflowRule, error) {
+	detail, err := d.DescribeWorkflowRuleDetail(ctx, ruleID, nsName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return detail.WorkflowRule, nil
+}
+
+// DescribeWorkflowRuleDetail is DescribeWorkflowRule, together with the last update of the rule.
+func (d *namespaceHandler) DescribeWorkflowRuleDetail(
+	ctx context.Context, ruleID string, nsName string, opts ...WorkflowRulesOption,
+) (_ *WorkflowRuleDetail, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("DescribeWorkflowRule", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	options := &workflowRulesOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	getNamespaceResponse, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: nsName})
+	if err != nil {
+		return nil, err
+	}
+
+	if getNamespaceResponse.Namespace.Config.WorkflowRules == nil {
+		return nil, serviceerror.NewInvalidArgument("Workflow Rule with this ID not Found.")
+	}
+
+	rule, ok := getNamespaceResponse.Namespace.Config.WorkflowRules[ruleID]
+	if !ok || !options.includeExpired && workflowRuleExpired(rule, d.timeSource.Now()) {
+		return nil, serviceerror.NewInvalidArgument("Workflow Rule with this ID not Found.")
+	}
+
+	detail := workflowRuleDetail(getNamespaceResponse.Namespace.Config, rule)
+	if options.includeRuleLimit {
+		now := d.timeSource.Now()
+		for _, rule := range getNamespaceResponse.Namespace.Config.WorkflowRules {
+			// expired rules do not count against the limit
+			if !workflowRuleExpired(rule, now) {
+				detail.RuleCount++
+			}
+		}
+		detail.MaxRules = d.config.MaxWorkflowRulesPerNamespace(nsName)
+	}
+	return detail, nil
+}
+
+func workflowRuleDetail(config *persistencespb.NamespaceConfig, rule *rulespb.WorkflowRule) *WorkflowRuleDetail {
+	ruleUpdate := config.GetWorkflowRuleUpdates()[rule.GetSpec().GetId()]
+	return &WorkflowRuleDetail{
+		WorkflowRule:          rule,
+		LastUpdateTime:        ruleUpdate.GetLastUpdateTime(),
+		LastUpdatedByIdentity: ruleUpdate.GetLastUpdatedByIdentity(),
+	}
+}
+
+// workflowRuleDetails returns the workflow rules of the namespace ordered by rule ID.
+func workflowRuleDetails(config *persistencespb.NamespaceConfig) []*WorkflowRuleDetail {
+	details := make([]*WorkflowRuleDetail, 0, len(config.GetWorkflowRules()))
+	for _, rule := range config.GetWorkflowRules() {
+		details = append(details, workflowRuleDetail(config, rule))
+	}
+	slices.SortFunc(details, func(a, b *WorkflowRuleDetail) int {
+		return cmp.Compare(a.GetSpec().GetId(), b.GetSpec().GetId())
+	})
+	return details
+}
+
+// DeleteWorkflowRule removes the workflow rule from the namespace and returns the removed rule.
+func (d *namespaceHandler) DeleteWorkflowRule(
+	ctx context.Context, ruleID string, nsName string,
+) (_ *rulespb.WorkflowRule, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("DeleteWorkflowRule", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	if ruleID == "" {
+		return nil, serviceerror.NewInvalidArgument("Workflow Rule ID is not set.")
+	}
+
+	var deletedRule *rulespb.WorkflowRule
+	err := d.updateWorkflowRules(ctx, nsName, func(config *persistencespb.NamespaceConfig) error {
+		if config.WorkflowRules == nil {
+			return serviceerror.NewInvalidArgument("Workflow Rule with this ID not Found.")
+		}
+		rule, ok := config.WorkflowRules[ruleID]
+		if !ok {
+			return serviceerror.NewInvalidArgument("Workflow Rule with this ID not Found.")
+		}
+
+		deletedRule = rule
+		delete(config.WorkflowRules, ruleID)
+		delete(config.WorkflowRuleUpdates, ruleID)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	getNamespaceResponse, err := d.metadataMgr.GetNamespace(ctx, &persistence.GetNamespaceRequest{Name: nsName})
+	return deletedRule, nil
+}
+
+// ListWorkflowRules returns a page of the unexpired workflow rules of the namespace ordered by rule ID, a
+// non-positive pageSize uses the default page size. The page token is the ID of the last returned rule, so paging
+// is consistent while rules are created or deleted: each rule which exists during the whole traversal is returned
+// exactly once.
+func (d *namespaceHandler) ListWorkflowRules(
+	ctx context.Context,
+	nsName string,
+	pageSize int,
+	pageToken []byte,
+	opts ...WorkflowRulesOption,
+) (_ *ListWorkflowRulesResponse, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("ListWorkflowRules", nsName, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	options := &workflowRulesOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	getNamespaceResponse, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: nsName})
 	if err != nil {
 		return nil, err
 	}
 
-	existingNamespace := getNamespaceResponse.Namespace
-	config := getNamespaceResponse.Namespace.Config
+	if pageSize <= 0 {
+		pageSize = defaultWorkflowRulesPageSize
+	}
+	workflowRulesMap := getNamespaceResponse.Namespace.Config.GetWorkflowRules()
+	lastRuleID := string(pageToken)
+	now := d.timeSource.Now()
+	ruleIDs := make([]string, 0, len(workflowRulesMap))
+	for ruleID, rule := range workflowRulesMap {
+		// expired rules are kept until they are removed, but are only listed with IncludeExpiredWorkflowRules
+		if ruleID > lastRuleID && (options.includeExpired || !workflowRuleExpired(rule, now)) {
+			ruleIDs = append(ruleIDs, ruleID)
+		}
+	}
+	slices.Sort(ruleIDs)
+
+	response := &ListWorkflowRulesResponse{
+		Rules: make([]*rulespb.WorkflowRule, 0, min(pageSize, len(ruleIDs))),
+	}
+	if len(ruleIDs) > pageSize {
+		ruleIDs = ruleIDs[:pageSize]
+		response.NextPageToken = []byte(ruleIDs[pageSize-1])
+	}
+	for _, ruleID := range ruleIDs {
+		response.Rules = append(response.Rules, workflowRulesMap[ruleID])
+	}
+	return response, nil
+}
+
+// GetNamespaceFailoverHistory returns the failovers of the namespace from its failover log, which keeps many more
+// failovers than the failover history returned by DescribeNamespace. Failovers are ordered by failover version and
+// the page token is the failover version of the last returned failover, so paging is consistent while the
+// namespace fails over.
+func (d *namespaceHandler) GetNamespaceFailoverHistory(
+	ctx context.Context,
+	request *GetNamespaceFailoverHistoryRequest,
+) (_ *GetNamespaceFailoverHistoryResponse, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("GetNamespaceFailoverHistory", request.Namespace, time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	lastFailoverVersion := int64(math.MinInt64)
+	if len(request.NextPageToken) > 0 {
+		version, err := strconv.ParseInt(string(request.NextPageToken), 10, 64)
+		if err != nil {
+			return nil, serviceerror.NewInvalidArgument("Invalid failover history page token.")
+		}
+		lastFailoverVersion = version
+	}
+	if !request.StartTime.IsZero() && !request.EndTime.IsZero() && !request.StartTime.Before(request.EndTime) {
+		return nil, serviceerror.NewInvalidArgument("Failover history start time must be before end time.")
+	}
+	pageSize := request.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultFailoverHistoryPageSize
+	}
+
+	resp, err := d.getNamespace(ctx, &persistence.GetNamespaceRequest{Name: request.Namespace})
+	if err != nil {
+		return nil, err
+	}
+	// failovers which happened before the failover log was introduced are only in the failover history
+	replicationConfig := resp.Namespace.GetReplicationConfig()
+	failoverLog := nsreplication.AppendFailoverLog(
+		slices.Clone(replicationConfig.GetFailoverLog()),
+		replicationConfig.GetFailoverHistory(),
+	)
+
+	response := &GetNamespaceFailoverHistoryResponse{}
+	for _, status := range failoverLog {
+		if status.GetFailoverVersion() <= lastFailoverVersion {
+			continue
+		}
+		failoverTime := status.GetFailoverTime().AsTime()
+		if !request.StartTime.IsZero() && failoverTime.Before(request.StartTime) ||
+			!request.EndTime.IsZero() && !failoverTime.Before(request.EndTime) {
+			continue
+		}
+		if len(response.FailoverHistory) == pageSize {
+			response.NextPageToken = []byte(strconv.FormatInt(lastFailoverVersion, 10))
+			break
+		}
+		response.FailoverHistory = append(response.FailoverHistory, &replicationpb.FailoverStatus{
+			FailoverTime:    status.GetFailoverTime(),
+			FailoverVersion: status.GetFailoverVersion(),
+		})
+		lastFailoverVersion = status.GetFailoverVersion()
+	}
+	return response, nil
+}
+
+// ListWorkflowRulesAcrossNamespaces returns the workflow rules of all namespaces in the cluster, for auditing.
+// Each call scans one page of namespaces and is rate limited, so a full scan does not overload the metadata store.
+// Deleted namespaces are skipped. Rules are ordered by namespace name and rule ID within a page.
+func (d *namespaceHandler) ListWorkflowRulesAcrossNamespaces(
+	ctx context.Context,
+	request *ListWorkflowRulesAcrossNamespacesRequest,
+) (_ *ListWorkflowRulesAcrossNamespacesResponse, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("ListWorkflowRulesAcrossNamespaces", "", time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	if !d.listWorkflowRulesAuditRateLimiter.Allow() {
+		return nil, errListWorkflowRulesAuditRateLimited
+	}
+
+	pageSize := request.NamespacePageSize
+	if pageSize <= 0 {
+		pageSize = defaultWorkflowRulesAuditNamespacePageSize
+	}
+	resp, err := d.metadataMgr.ListNamespaces(ctx, &persistence.ListNamespacesRequest{
+		PageSize:      pageSize,
+		NextPageToken: request.NextPageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*NamespaceWorkflowRule
+	for _, ns := range resp.Namespaces {
+		info := ns.Namespace.GetInfo()
+		for _, rule := range ns.Namespace.GetConfig().GetWorkflowRules() {
+			if request.ActionType != "" && !workflowRuleHasActionType(rule, request.ActionType) {
+				continue
+			}
+			rules = append(rules, &NamespaceWorkflowRule{
+				NamespaceID: info.GetId(),
+				Namespace:   info.GetName(),
+				Rule:        rule,
+			})
+		}
+	}
+	slices.SortFunc(rules, func(a, b *NamespaceWorkflowRule) int {
+		return cmp.Or(
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Rule.GetSpec().GetId(), b.Rule.GetSpec().GetId()),
+		)
+	})
+
+	return &ListWorkflowRulesAcrossNamespacesResponse{
+		Rules:         rules,
+		NextPageToken: resp.NextPageToken,
+	}, nil
+}
+
+// ListNamespacesByArchivalState returns the namespaces with the given history and visibility archival states,
+// e.g. to audit namespaces which don't archive or archive to a disallowed URI. An unspecified state matches any
+// state. Each call scans one page of namespaces and is rate limited, a page may hold no namespaces while the
+// returned page token is still set.
+func (d *namespaceHandler) ListNamespacesByArchivalState(
+	ctx context.Context,
+	historyState enumspb.ArchivalState,
+	visibilityState enumspb.ArchivalState,
+	pageToken []byte,
+) (_ *ListNamespacesByArchivalStateResponse, retErr error) {
+	startTime := time.Now().UTC()
+	defer func() {
+		d.recordRequestMetrics("ListNamespacesByArchivalState", "", time.Since(startTime), retErr)
+	}()
+	ctx, done := d.withOperationTimeout(ctx)
+	defer done(&retErr)
+
+	if !d.listArchivalAuditRateLimiter.Allow() {
+		return nil, errListNamespacesByArchivalStateRateLimited
+	}
+
+	resp, err := d.metadataMgr.ListNamespaces(ctx, &persistence.ListNamespacesRequest{
+		PageSize:      archivalAuditNamespacePageSize,
+		NextPageToken: pageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var namespaces []*NamespaceArchivalConfig
+	for _, ns := range resp.Namespaces {
+		config := ns.Namespace.GetConfig()
+		if historyState != enumspb.ARCHIVAL_STATE_UNSPECIFIED && config.GetHistoryArchivalState() != historyState {
+			continue
+		}
+		if visibilityState != enumspb.ARCHIVAL_STATE_UNSPECIFIED && config.GetVisibilityArchivalState() != visibilityState {
+			continue
+		}
+		namespaces = append(namespaces, &NamespaceArchivalConfig{
+			NamespaceID:             ns.Namespace.GetInfo().GetId(),
+			Namespace:               ns.Namespace.GetInfo().GetName(),
+			HistoryArchivalState:    config.GetHistoryArchivalState(),
+			HistoryArchivalURI:      config.GetHistoryArchivalUri(),
+			VisibilityArchivalState: config.GetVisibilityArchivalState(),
+			VisibilityArchivalURI:   config.GetVisibilityArchivalUri(),
+		})
+	}
+	slices.SortFunc(namespaces, func(a, b *NamespaceArchivalConfig) int {
+		return cmp.Compare(a.Namespace, b.Namespace)
+	})
+
+	return &ListNamespacesByArchivalStateResponse{
+		Namespaces:    namespaces,
+		NextPageToken: resp.NextPageToken,
+	}, nil
+}
 
-	if config.WorkflowRules == nil {
-		config.WorkflowRules = make(map[string]*rulespb.WorkflowRule)
-	} else {
-		maxRules := d.config.MaxWorkflowRulesPerNamespace(nsName)
-		if len(config.WorkflowRules) >= maxRules {
-			d.removeOldestExpiredWorkflowRule(nsName, config.WorkflowRules)
+// namespaceComparison returns the comparison sorting listed namespaces by sortBy, or nil if sortBy is not set.
+func namespaceComparison(
+	sortBy NamespaceSortBy,
+	descending bool,
+) (func(a, b *workflowservice.DescribeNamespaceResponse) int, error) {
+	var compare func(a, b *workflowservice.DescribeNamespaceResponse) int
+	switch sortBy {
+	case "":
+		return nil, nil
+	case NamespaceSortByName:
+		compare = func(a, b *workflowservice.DescribeNamespaceResponse) int {
+			return cmp.Compare(a.GetNamespaceInfo().GetName(), b.GetNamespaceInfo().GetName())
 		}
-		if len(config.WorkflowRules) >= maxRules {
-			return nil, serviceerror.NewInvalidArgumentf("Workflow Rule limit exceeded. Max: %v", maxRules)
+	case NamespaceSortByState:
+		compare = func(a, b *workflowservice.DescribeNamespaceResponse) int {
+			return cmp.Or(
+				cmp.Compare(a.GetNamespaceInfo().GetState(), b.GetNamespaceInfo().GetState()),
+				cmp.Compare(a.GetNamespaceInfo().GetName(), b.GetNamespaceInfo().GetName()),
+			)
 		}
+	default:
+		return nil, serviceerror.NewInvalidArgumentf("Unknown namespace sort field %q.", sortBy)
 	}
-
-	_, ok := config.WorkflowRules[ruleSpec.GetId()]
-	if ok {
-		return nil, serviceerror.NewInvalidArgument("Workflow Rule with this ID already exists.")
+	if descending {
+		return func(a, b *workflowservice.DescribeNamespaceResponse) int { return compare(b, a) }, nil
 	}
+	return compare, nil
+}
 
-	workflowRule := &rulespb.WorkflowRule{
-		Spec:              ruleSpec,
-		CreateTime:        timestamppb.New(d.timeSource.Now()),
-		CreatedByIdentity: createdByIdentity,
-		Description:       description,
+func workflowRuleHasActionType(rule *rulespb.WorkflowRule, actionType WorkflowRuleActionType) bool {
+	for _, action := range rule.GetSpec().GetActions() {
+		if workflowRuleActionType(action) == actionType {
+			return true
+		}
 	}
-	config.WorkflowRules[ruleSpec.GetId()] = workflowRule
+	return false
+}
 
-	updateReq := &persistence.UpdateNamespaceRequest{
-		Namespace: &persistencespb.NamespaceDetail{
-			Info:                        existingNamespace.Info,
-			Config:                      config,
-			ReplicationConfig:           existingNamespace.ReplicationConfig,
-			ConfigVersion:               existingNamespace.ConfigVersion + 1,
-			FailoverVersion:             existingNamespace.FailoverVersion,
-			FailoverNotificationVersion: existingNamespace.FailoverNotificationVersion,
-		},
-		IsGlobalNamespace:   getNamespaceResponse.IsGlobalNamespace,
-		NotificationVersion: metadata.NotificationVersion,
+func workflowRuleActionType(action *rulespb.WorkflowRuleAction) WorkflowRuleActionType {
+	switch action.GetVariant().(type) {
+	case *rulespb.WorkflowRuleAction_ActivityPause:
+		return WorkflowRuleActionTypeActivityPause
+	default:
+		return ""
 	}
-	err = d.metadataMgr.UpdateNamespace(ctx, updateReq)
+}
+
+// recordRequestMetrics emits the latency and outcome of a namespace handler operation on a namespace, operations
+// on multiple namespaces have no namespace.
+func (d *namespaceHandler) recordRequestMetrics(operation string, nsName string, latency time.Duration, err error) {
+	handler := d.metricsHandler.WithTags(metrics.OperationTag(operation), metrics.NamespaceTag(nsName))
+	metrics.NamespaceHandlerLatency.With(handler).Record(latency)
 	if err != nil {
-		return nil, err
+		metrics.NamespaceHandlerRequests.With(handler).Record(1, metrics.OutcomeTag("error"))
+		metrics.NamespaceHandlerErrorWithType.With(handler).Record(1, metrics.ServiceErrorTypeTag(err))
+		return
 	}
-
-	return workflowRule, nil
+	metrics.NamespaceHandlerRequests.With(handler).Record(1, metrics.OutcomeTag("success"))
 }
 
-func (d *namespaceHandler) removeOldestExpiredWorkflowRule(nsName string, rules map[string]*rulespb.WorkflowRule) {
-	oldestTime := d.timeSource.Now()
-	var oldestKey string
-	found := false
-
-	for key, rule := range rules {
-		expirationTime := rule.GetSpec().GetExpirationTime()
-		if expirationTime == nil {
-			continue
+// withOperationTimeout bounds ctx by frontend.namespaceOperationTimeout if the caller did not set a deadline, so that
+// a slow metadata store cannot hang the operation. The returned func releases the context and turns the error of an
+// operation which ran out of time into DeadlineExceeded.
+func (d *namespaceHandler) withOperationTimeout(ctx context.Context) (context.Context, func(*error)) {
+	timeout := d.config.NamespaceOperationTimeout()
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return ctx, func(*error) {}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func(err *error) {
+		defer cancel()
+		if *err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
 		}
-		if !found || expirationTime.AsTime().Before(oldestTime) {
-			oldestTime = expirationTime.AsTime()
-			oldestKey = key
-			found = true
+		var deadlineExceeded *serviceerror.DeadlineExceeded
+		if !errors.As(*err, &deadlineExceeded) {
+			*err = serviceerror.NewDeadlineExceededf("Namespace operation did not complete within %v: %v", timeout, *err)
 		}
 	}
-
-	if found {
-		d.logger.Info(
-			"Removed expired workflow rule",
-			tag.WorkflowRuleID(oldestKey),
-			tag.WorkflowNamespace(nsName),
-		)
-		delete(rules, oldestKey)
-	}
 }
 
-func (d *namespaceHandler) DescribeWorkflowRule(
-	ctx context.Context, ruleID string, nsName string,
-) (*rulespb.WorkflowRule, error) {
-	getNamespaceResponse, err := d.metadataMgr.GetNamespace(ctx, &persistence.GetNamespaceRequest{Name: nsName})
-	if err != nil {
-		return nil, err
-	}
-
-	if getNamespaceResponse.Namespace.Config.WorkflowRules == nil {
-		return nil, serviceerror.NewInvalidArgument("Workflow Rule with this ID not Found.")
-	}
+// getDescribeNamespaceCacheGeneration returns the generation of describeNamespaceCache, to be passed to
+// putDescribeNamespaceCache with the description read afterwards.
+func (d *namespaceHandler) getDescribeNamespaceCacheGeneration() int64 {
+	d.describeNamespaceCacheLock.Lock()
+	defer d.describeNamespaceCacheLock.Unlock()
+	return d.describeNamespaceCacheGeneration
+}
 
-	rule, ok := getNamespaceResponse.Namespace.Config.WorkflowRules[ruleID]
-	if !ok {
-		return nil, serviceerror.NewInvalidArgument("Workflow Rule with this ID not Found.")
+// putDescribeNamespaceCache caches the description unless the cache was invalidated since generation.
+func (d *namespaceHandler) putDescribeNamespaceCache(
+	key describeNamespaceCacheKey,
+	generation int64,
+	description *NamespaceDescription,
+) {
+	d.describeNamespaceCacheLock.Lock()
+	defer d.describeNamespaceCacheLock.Unlock()
+	if generation != d.describeNamespaceCacheGeneration {
+		return
 	}
+	d.describeNamespaceCache.Put(key, description)
+}
 
-	return rule, nil
+// invalidateDescribeNamespaceCache removes the cached DescribeNamespace responses of the namespace on this host.
+func (d *namespaceHandler) invalidateDescribeNamespaceCache(info *persistencespb.NamespaceInfo) {
+	d.describeNamespaceCacheLock.Lock()
+	defer d.describeNamespaceCacheLock.Unlock()
+	d.describeNamespaceCacheGeneration++
+	d.describeNamespaceCache.Delete(describeNamespaceCacheKey{name: info.GetName()})
+	d.describeNamespaceCache.Delete(describeNamespaceCacheKey{id: info.GetId()})
+	d.describeNamespaceCache.Delete(describeNamespaceCacheKey{name: info.GetName(), id: info.GetId()})
 }
 
-func (d *namespaceHandler) DeleteWorkflowRule(
-	ctx context.Context, ruleID string, nsName string,
-) error {
-	if ruleID == "" {
-		return serviceerror.NewInvalidArgument("Workflow Rule ID is not set.")
+// checkNamespaceConfigChangeRate rejects a config change of the namespace with ResourceExhausted if the namespace
+// already got frontend.namespaceConfigChangeMaxCount changes within frontend.namespaceConfigChangeWindow. It returns
+// the times of the config changes within the window.
+func (d *namespaceHandler) checkNamespaceConfigChangeRate(
+	nsName string,
+	config *persistencespb.NamespaceConfig,
+) ([]*timestamppb.Timestamp, error) {
+	maxCount := d.config.NamespaceConfigChangeMaxCount(nsName)
+	if maxCount <= 0 {
+		return nil, nil
 	}
+	window := d.config.NamespaceConfigChangeWindow(nsName)
+	now := d.timeSource.Now()
 
-	metadata, err := d.metadataMgr.GetMetadata(ctx)
-	if err != nil {
-		return err
+	changeTimes := slices.DeleteFunc(slices.Clone(config.GetConfigChangeTimes()), func(t *timestamppb.Timestamp) bool {
+		return !t.AsTime().Add(window).After(now)
+	})
+	if len(changeTimes) < maxCount {
+		return changeTimes, nil
+	}
+	nextChangeTime := changeTimes[len(changeTimes)-maxCount].AsTime().Add(window)
+	return nil, &serviceerror.ResourceExhausted{
+		Cause: enumspb.RESOURCE_EXHAUSTED_CAUSE_RPS_LIMIT,
+		Scope: enumspb.RESOURCE_EXHAUSTED_SCOPE_NAMESPACE,
+		Message: fmt.Sprintf(
+			"Namespace config change rate limit exceeded: %d changes within %v, next change allowed in %v",
+			maxCount,
+			window,
+			nextChangeTime.Sub(now),
+		),
 	}
+}
 
-	getNamespaceResponse, err := d.metadataMgr.GetNamespace(ctx, &persistence.GetNamespaceRequest{Name: nsName})
+// reserveNamespaceConfigChange checks the config change rate limit of the namespace and records the change in the
+// namespace config about to be persisted. The change times are persisted by the same conditional update as the
+// change, so concurrent changes through any frontend host can not exceed the limit together: of the changes made
+// to the same version of the namespace, only one is persisted.
+func (d *namespaceHandler) reserveNamespaceConfigChange(nsName string, config *persistencespb.NamespaceConfig) error {
+	changeTimes, err := d.checkNamespaceConfigChangeRate(nsName, config)
 	if err != nil {
 		return err
 	}
-
-	existingNamespace := getNamespaceResponse.Namespace
-	config := getNamespaceResponse.Namespace.Config
-	if config.WorkflowRules == nil {
-		return serviceerror.NewInvalidArgument("Workflow Rule with this ID not Found.")
+	maxCount := d.config.NamespaceConfigChangeMaxCount(nsName)
+	if maxCount <= 0 {
+		return nil
 	}
-	_, ok := config.WorkflowRules[ruleID]
-	if !ok {
-		return serviceerror.NewInvalidArgument("Workflow Rule with this ID not Found.")
+	changeTimes = append(changeTimes, timestamppb.New(d.timeSource.Now()))
+	if len(changeTimes) > maxCount {
+		changeTimes = slices.Delete(changeTimes, 0, len(changeTimes)-maxCount)
 	}
+	config.ConfigChangeTimes = changeTimes
+	return nil
+}
 
-	delete(config.WorkflowRules, ruleID)
+// setTimeSinceLastFailover sets TimeSinceLastFailover, which is not cached as it changes with every describe.
+func (n *NamespaceDescription) setTimeSinceLastFailover(now time.Time) {
+	if n.LastFailoverTime == nil {
+		n.TimeSinceLastFailover = nil
+		return
+	}
+	n.TimeSinceLastFailover = durationpb.New(now.Sub(n.LastFailoverTime.AsTime()))
+}
 
-	updateReq := &persistence.UpdateNamespaceRequest{
-		Namespace: &persistencespb.NamespaceDetail{
-			Info:                        existingNamespace.Info,
-			Config:                      config,
-			ReplicationConfig:           existingNamespace.ReplicationConfig,
-			ConfigVersion:               existingNamespace.ConfigVersion + 1,
-			FailoverVersion:             existingNamespace.FailoverVersion,
-			FailoverNotificationVersion: existingNamespace.FailoverNotificationVersion,
-		},
-		IsGlobalNamespace:   getNamespaceResponse.IsGlobalNamespace,
-		NotificationVersion: metadata.NotificationVersion,
+func (n *NamespaceDescription) clone(options *describeNamespaceOptions) *NamespaceDescription {
+	var workflowRules []*WorkflowRuleDetail
+	if options.includeWorkflowRules {
+		workflowRules = make([]*WorkflowRuleDetail, len(n.WorkflowRules))
+		for i, rule := range n.WorkflowRules {
+			workflowRules[i] = &WorkflowRuleDetail{
+				WorkflowRule:          common.CloneProto(rule.WorkflowRule),
+				LastUpdateTime:        common.CloneProto(rule.LastUpdateTime),
+				LastUpdatedByIdentity: rule.LastUpdatedByIdentity,
+			}
+		}
+	}
+	return &NamespaceDescription{
+		DescribeNamespaceResponse: common.CloneProto(n.DescribeNamespaceResponse),
+		ArchivalBatchConfig:       common.CloneProto(n.ArchivalBatchConfig),
+		VisibilityStore:           n.VisibilityStore,
+
+		ReplicationWorkflowTypeFilter: common.CloneProto(n.ReplicationWorkflowTypeFilter),
+		MaxConcurrentOpenWorkflows:    n.MaxConcurrentOpenWorkflows,
+		MaxSignalRPS:                  n.MaxSignalRPS,
+		MaxQueryRPS:                   n.MaxQueryRPS,
+		BadBinaryCount:                n.BadBinaryCount,
+		MaxBadBinaries:                n.MaxBadBinaries,
+		LastFailoverTime:              common.CloneProto(n.LastFailoverTime),
+		WorkflowRules:                 workflowRules,
+		AsyncUpdateSource:             n.AsyncUpdateSource,
 	}
-	return d.metadataMgr.UpdateNamespace(ctx, updateReq)
 }
 
-func (d *namespaceHandler) ListWorkflowRules(
-	ctx context.Context, nsName string,
-) ([]*rulespb.WorkflowRule, error) {
-	getNamespaceResponse, err := d.metadataMgr.GetNamespace(ctx, &persistence.GetNamespaceRequest{Name: nsName})
+// getNamespace reads the namespace from persistence and normalizes its config to the schema version
+// understood by this server.
+func (d *namespaceHandler) getNamespace(
+	ctx context.Context,
+	request *persistence.GetNamespaceRequest,
+) (*persistence.GetNamespaceResponse, error) {
+	resp, err := d.metadataMgr.GetNamespace(ctx, request)
 	if err != nil {
 		return nil, err
 	}
+	d.normalizeNamespaceConfig(resp.Namespace)
+	return resp, nil
+}
+
+// normalizeNamespaceConfig fills defaults for config fields absent in configs written with an older schema
+// version. Configs written with a newer schema version are left as is: fields unknown to this server are
+// preserved by proto unmarshalling and are written back unchanged on update.
+func (d *namespaceHandler) normalizeNamespaceConfig(detail *persistencespb.NamespaceDetail) {
+	if detail.Config == nil {
+		detail.Config = &persistencespb.NamespaceConfig{}
+	}
+	config := detail.Config
+	if config.SchemaVersion > namespaceConfigSchemaVersion {
+		d.logger.Warn("Namespace config schema version is newer than supported by this server.",
+			tag.WorkflowNamespace(detail.GetInfo().GetName()),
+			tag.NewInt32("config-schema-version", config.SchemaVersion),
+			tag.NewInt32("supported-schema-version", namespaceConfigSchemaVersion))
+	}
 
-	workflowRulesMap := getNamespaceResponse.Namespace.Config.WorkflowRules
-	if workflowRulesMap == nil {
-		return []*rulespb.WorkflowRule{}, nil
+	if config.BadBinaries == nil {
+		config.BadBinaries = &namespacepb.BadBinaries{}
+	}
+	if config.BadBinaries.Binaries == nil {
+		config.BadBinaries.Binaries = make(map[string]*namespacepb.BadBinaryInfo)
 	}
 
-	workflowRules := make([]*rulespb.WorkflowRule, 0, len(workflowRulesMap))
-	for _, rule := range workflowRulesMap {
-		workflowRules = append(workflowRules, rule)
+	if config.SchemaVersion < namespaceConfigSchemaVersion {
+		config.SchemaVersion = namespaceConfigSchemaVersion
 	}
-	return workflowRules, nil
 }
 
 func (d *namespaceHandler) createResponse(
@@ -845,6 +3489,7 @@ func (d *namespaceHandler) createResponse(
 	replicationConfig *persistencespb.NamespaceReplicationConfig,
 ) (*namespacepb.NamespaceInfo, *namespacepb.NamespaceConfig, *replicationpb.NamespaceReplicationConfig, []*replicationpb.FailoverStatus) {
 
+	asyncUpdate, _ := effectiveAsyncUpdate(config.GetAsyncUpdateOverride(), d.config.EnableUpdateWorkflowExecutionAsyncAccepted(info.Name))
 	infoResult := &namespacepb.NamespaceInfo{
 		Name:        info.Name,
 		State:       info.State,
@@ -856,7 +3501,7 @@ func (d *namespaceHandler) createResponse(
 		Capabilities: &namespacepb.NamespaceInfo_Capabilities{
 			EagerWorkflowStart: d.config.EnableEagerWorkflowStart(info.Name),
 			SyncUpdate:         d.config.EnableUpdateWorkflowExecution(info.Name),
-			AsyncUpdate:        d.config.EnableUpdateWorkflowExecutionAsyncAccepted(info.Name),
+			AsyncUpdate:        asyncUpdate,
 		},
 		SupportsSchedules: d.config.EnableSchedules(info.Name),
 	}
@@ -894,6 +3539,51 @@ func (d *namespaceHandler) createResponse(
 	return infoResult, configResult, replicationConfigResult, failoverHistory
 }
 
+// validateRetentionReduction rejects a retention reduction larger than the max reduction percentage or duration,
+// if the retention reduction guard is enabled for the namespace. Lowering the retention deletes the history of
+// closed workflows which are older than the new retention.
+func (d *namespaceHandler) validateRetentionReduction(
+	namespaceName string,
+	oldRetention *durationpb.Duration,
+	newRetention *durationpb.Duration,
+) error {
+	if !d.config.RetentionReductionGuardEnabled(namespaceName) {
+		return nil
+	}
+	oldDuration := timestamp.DurationValue(oldRetention)
+	reduction := oldDuration - timestamp.DurationValue(newRetention)
+	if oldDuration <= 0 || reduction <= 0 {
+		return nil
+	}
+	if maxPercent := d.config.MaxRetentionReductionPercent(namespaceName); maxPercent > 0 &&
+		float64(reduction) > float64(oldDuration)*float64(maxPercent)/100 {
+		return serviceerror.NewInvalidArgumentf(
+			"Retention reduction from %v to %v exceeds the max reduction of %v%%, force the update to reduce it anyway.",
+			oldDuration, timestamp.DurationValue(newRetention), maxPercent,
+		)
+	}
+	if maxReduction := d.config.MaxRetentionReduction(namespaceName); maxReduction > 0 && reduction > maxReduction {
+		return serviceerror.NewInvalidArgumentf(
+			"Retention reduction from %v to %v exceeds the max reduction of %v, force the update to reduce it anyway.",
+			oldDuration, timestamp.DurationValue(newRetention), maxReduction,
+		)
+	}
+	return nil
+}
+
+func (d *namespaceHandler) validateReplicationClusterCount(
+	namespaceName string,
+	replicationConfig *persistencespb.NamespaceReplicationConfig,
+) error {
+	maxClusters := d.config.MaxReplicationClustersPerNamespace(namespaceName)
+	if len(replicationConfig.Clusters) > maxClusters {
+		return serviceerror.NewInvalidArgumentf(
+			"Total replication cluster count %v exceeds the max limit: %v", len(replicationConfig.Clusters), maxClusters,
+		)
+	}
+	return nil
+}
+
 func (d *namespaceHandler) mergeBadBinaries(
 	old map[string]*namespacepb.BadBinaryInfo,
 	new map[string]*namespacepb.BadBinaryInfo,
@@ -912,10 +3602,26 @@ func (d *namespaceHandler) mergeBadBinaries(
 	}
 }
 
+// mergeNamespaceData sets the new entries in the old data and deletes the removed keys. Removal wins over a new
+// entry for the same key. Every removed key must be in the old data.
 func (d *namespaceHandler) mergeNamespaceData(
 	old map[string]string,
 	new map[string]string,
-) map[string]string {
+	remove []string,
+) (map[string]string, error) {
+	var missingKeys []string
+	for _, key := range remove {
+		if _, ok := old[key]; !ok && !slices.Contains(missingKeys, key) {
+			missingKeys = append(missingKeys, key)
+		}
+	}
+	if len(missingKeys) == 1 {
+		return nil, serviceerror.NewInvalidArgumentf("Namespace data has no key %v to remove.", missingKeys[0])
+	} else if len(missingKeys) > 1 {
+		return nil, serviceerror.NewInvalidArgumentf(
+			"Namespace data has no keys %v to remove.", strings.Join(missingKeys, ", "),
+		)
+	}
 
 	if old == nil {
 		old = map[string]string{}
@@ -923,15 +3629,76 @@ func (d *namespaceHandler) mergeNamespaceData(
 	for k, v := range new {
 		old[k] = v
 	}
-	return old
+	for _, key := range remove {
+		delete(old, key)
+	}
+	return old, nil
+}
+
+// validateNamespaceData checks the data of the namespace against the frontend.namespaceData* limits. Only the
+// changes from the existing data are checked, so a namespace whose data is already over a limit, e.g. because the
+// limit was lowered, can still be updated as long as the update does not add keys, grow the data or set an entry
+// over the length limits.
+func (d *namespaceHandler) validateNamespaceData(nsName string, existing map[string]string, data map[string]string) error {
+	if maxKeys := d.config.NamespaceDataMaxKeys(nsName); maxKeys > 0 && len(data) > maxKeys && len(data) > len(existing) {
+		return serviceerror.NewInvalidArgumentf("Namespace data has %d keys, the limit is %d.", len(data), maxKeys)
+	}
+	maxKeyLength := d.config.NamespaceDataMaxKeyLength(nsName)
+	maxValueLength := d.config.NamespaceDataMaxValueLength(nsName)
+	size := 0
+	for key, value := range data {
+		size += len(key) + len(value)
+		if existingValue, ok := existing[key]; ok && existingValue == value {
+			continue
+		}
+		if maxKeyLength > 0 && len(key) > maxKeyLength {
+			return serviceerror.NewInvalidArgumentf(
+				"Namespace data key %.32q... is %d bytes long, the limit is %d.", key, len(key), maxKeyLength,
+			)
+		}
+		if maxValueLength > 0 && len(value) > maxValueLength {
+			return serviceerror.NewInvalidArgumentf(
+				"Namespace data value of key %q is %d bytes long, the limit is %d.", key, len(value), maxValueLength,
+			)
+		}
+	}
+	existingSize := 0
+	for key, value := range existing {
+		existingSize += len(key) + len(value)
+	}
+	if maxSize := d.config.NamespaceDataMaxSize(nsName); maxSize > 0 && size > maxSize && size > existingSize {
+		return serviceerror.NewInvalidArgumentf("Namespace data is %d bytes, the limit is %d.", size, maxSize)
+	}
+	return nil
 }
 
+// upsertCustomSearchAttributesAliases returns the current aliases with the upserted and removed ones, keyed by
+// custom search attribute field. An empty upserted alias removes the alias of the field too, removed fields must
+// have an alias and take precedence over upserted ones.
 func (d *namespaceHandler) upsertCustomSearchAttributesAliases(
 	current map[string]string,
 	upsert map[string]string,
+	remove []string,
 ) (map[string]string, error) {
+	var missingFields []string
+	for _, key := range remove {
+		if _, ok := current[key]; !ok && !slices.Contains(missingFields, key) {
+			missingFields = append(missingFields, key)
+		}
+	}
+	if len(missingFields) == 1 {
+		return nil, serviceerror.NewInvalidArgumentf("Custom search attribute field %v has no alias to remove.", missingFields[0])
+	} else if len(missingFields) > 1 {
+		return nil, serviceerror.NewInvalidArgumentf(
+			"Custom search attribute fields %v have no alias to remove.", strings.Join(missingFields, ", "),
+		)
+	}
+
 	result := util.CloneMapNonNil(current)
 	for key, value := range upsert {
+		if slices.Contains(remove, key) {
+			continue
+		}
 		if value == "" {
 			delete(result, key)
 		} else if _, ok := current[key]; !ok {
@@ -940,6 +3707,9 @@ func (d *namespaceHandler) upsertCustomSearchAttributesAliases(
 			return nil, errCustomSearchAttributeFieldAlreadyAllocated
 		}
 	}
+	for _, key := range remove {
+		delete(result, key)
+	}
 	return result, nil
 }
 
@@ -970,14 +3740,13 @@ func (d *namespaceHandler) toArchivalUpdateEvent(
 	defaultURI string,
 ) (*namespace.ArchivalConfigEvent, error) {
 
+	// the default URI is validated by the state machine, it is not needed
+	// when the namespace already has an archival URI
 	event := &namespace.ArchivalConfigEvent{
 		State:      state,
 		URI:        URI,
 		DefaultURI: defaultURI,
 	}
-	if err := event.Validate(); err != nil {
-		return nil, err
-	}
 	return event, nil
 }
 
@@ -1040,12 +3809,86 @@ func (d *namespaceHandler) maybeUpdateFailoverHistory(
 			},
 		)
 	}
-	if l := len(failoverHistory); l > maxReplicationHistorySize {
-		failoverHistory = failoverHistory[l-maxReplicationHistorySize : l]
+	// the log is appended before truncating the history, so that it keeps the failovers dropped from the history
+	namespaceDetail.ReplicationConfig.FailoverLog = nsreplication.AppendFailoverLog(
+		namespaceDetail.ReplicationConfig.GetFailoverLog(),
+		failoverHistory,
+	)
+	// the history keeps at least the current failover, its last entry is the last failover of the namespace
+	maxHistorySize := max(d.config.MaxNamespaceFailoverHistorySize(), 1)
+	if l := len(failoverHistory); l > maxHistorySize {
+		failoverHistory = failoverHistory[l-maxHistorySize : l]
 	}
 	return failoverHistory
 }
 
+// nextFailoverVersion returns the failover version of a global namespace becoming active in activeClusterName.
+// It uses the failover version allocator configured for the namespace, if any, and the cluster failover version
+// scheme otherwise.
+func (d *namespaceHandler) nextFailoverVersion(
+	nsName string,
+	activeClusterName string,
+	currentVersion int64,
+) (int64, error) {
+	clusterVersion := d.clusterMetadata.GetNextFailoverVersion(activeClusterName, currentVersion)
+	allocatorName := d.config.FailoverVersionAllocator(nsName)
+	if allocatorName == "" {
+		return clusterVersion, nil
+	}
+	allocator, ok := d.failoverVersionAllocators[allocatorName]
+	if !ok {
+		return 0, serviceerror.NewInternalf("failover version allocator %q of namespace %v is not registered", allocatorName, nsName)
+	}
+	version, err := allocator.NextFailoverVersion(nsName, activeClusterName, currentVersion)
+	if err != nil {
+		return 0, err
+	}
+	// clusterVersion is the lowest version of the active cluster which is valid after currentVersion, so any
+	// version of the same cluster not below it keeps the invariants of the cluster scheme.
+	if version < clusterVersion || !d.clusterMetadata.IsVersionFromSameCluster(version, clusterVersion) {
+		return 0, serviceerror.NewInternalf(
+			"failover version allocator %q returned invalid failover version %v for namespace %v becoming active in cluster %v with current failover version %v",
+			allocatorName, version, nsName, activeClusterName, currentVersion,
+		)
+	}
+	return version, nil
+}
+
+// validateNamespaceName checks that a new namespace name matches the configured namespace name pattern.
+func (d *namespaceHandler) validateNamespaceName(name string) error {
+	pattern := d.config.NamespaceNamePattern()
+	if pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return serviceerror.NewInternalf("invalid namespace name pattern %q: %v", pattern, err)
+	}
+	if !re.MatchString(name) {
+		return serviceerror.NewInvalidArgumentf("Namespace name %q does not match the required pattern %q.", name, pattern)
+	}
+	return nil
+}
+
+// withFieldPath adds the path of the invalid request field, e.g. "config.workflowExecutionRetentionTtl", to an
+// InvalidArgument error as a BadRequest field violation detail, so that clients can tell which field failed without
+// parsing the message. The message is kept, other errors are returned unchanged.
+func withFieldPath(err error, fieldPath string) error {
+	var invalidArgument *serviceerror.InvalidArgument
+	if !errors.As(err, &invalidArgument) {
+		return err
+	}
+	st, detailsErr := status.New(codes.InvalidArgument, invalidArgument.Message).WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: fieldPath, Description: invalidArgument.Message},
+		},
+	})
+	if detailsErr != nil {
+		return err
+	}
+	return serviceerror.FromStatus(st)
+}
+
 // validateRetentionDuration ensures that retention duration can't be set below a sane minimum.
 func validateRetentionDuration(retention *durationpb.Duration, isGlobalNamespace bool) error {
 	if err := timestamp.ValidateAndCapProtoDuration(retention); err != nil {
@@ -1062,41 +3905,315 @@ func validateRetentionDuration(retention *durationpb.Duration, isGlobalNamespace
 	return nil
 }
 
-func validateReplicationStateUpdate(existingNamespace *persistence.GetNamespaceResponse, nsUpdateRequest *workflowservice.UpdateNamespaceRequest) error {
-	if nsUpdateRequest.ReplicationConfig == nil ||
-		nsUpdateRequest.ReplicationConfig.State == enumspb.REPLICATION_STATE_UNSPECIFIED ||
-		nsUpdateRequest.ReplicationConfig.State == existingNamespace.Namespace.ReplicationConfig.State {
-		return nil // no change
+// effectiveAsyncUpdate returns whether workflow updates may be accepted asynchronously, honoring the namespace
+// override of the cluster default.
+func effectiveAsyncUpdate(override *persistencespb.AsyncUpdateOverride, clusterDefault bool) (bool, AsyncUpdateSource) {
+	if override != nil {
+		return override.GetEnabled(), AsyncUpdateSourceNamespaceOverride
 	}
+	return clusterDefault, AsyncUpdateSourceClusterDefault
+}
 
-	if existingNamespace.Namespace.Info.State != enumspb.NAMESPACE_STATE_REGISTERED {
-		return serviceerror.NewInvalidArgumentf(
-			"update ReplicationState is only supported when namespace is in %s state, current state: %s",
-			enumspb.NAMESPACE_STATE_REGISTERED,
-			existingNamespace.Namespace.Info.State,
-		)
+// effectiveArchival returns the archival state and URI a namespace actually archives with: the namespace
+// settings if the cluster is configured for archival, disabled otherwise.
+func effectiveArchival(
+	clusterConfig archiver.ArchivalConfig,
+	state enumspb.ArchivalState,
+	uri string,
+) (EffectiveConfigValue[enumspb.ArchivalState], EffectiveConfigValue[string]) {
+	if !clusterConfig.ClusterConfiguredForArchival() {
+		return EffectiveConfigValue[enumspb.ArchivalState]{
+			Value:  enumspb.ARCHIVAL_STATE_DISABLED,
+			Source: ConfigSourceClusterDefault,
+		}, EffectiveConfigValue[string]{
+			Source: ConfigSourceClusterDefault,
+		}
+	}
+	return EffectiveConfigValue[enumspb.ArchivalState]{
+		Value:  state,
+		Source: ConfigSourceNamespace,
+	}, EffectiveConfigValue[string]{
+		Value:  uri,
+		Source: ConfigSourceNamespace,
+	}
+}
+
+// copyNamespaceDetailForDiff copies the namespace fields compared by diffNamespaceDetail, UpdateNamespace
+// modifies the namespace read from persistence in place.
+func copyNamespaceDetailForDiff(detail *persistencespb.NamespaceDetail) *persistencespb.NamespaceDetail {
+	config := detail.GetConfig()
+	return &persistencespb.NamespaceDetail{
+		Config: &persistencespb.NamespaceConfig{
+			Retention:                    config.GetRetention(),
+			HistoryArchivalState:         config.GetHistoryArchivalState(),
+			HistoryArchivalUri:           config.GetHistoryArchivalUri(),
+			VisibilityArchivalState:      config.GetVisibilityArchivalState(),
+			VisibilityArchivalUri:        config.GetVisibilityArchivalUri(),
+			CustomSearchAttributeAliases: maps.Clone(config.GetCustomSearchAttributeAliases()),
+			BadBinaries: &namespacepb.BadBinaries{
+				Binaries: maps.Clone(config.GetBadBinaries().GetBinaries()),
+			},
+		},
+		ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+			ActiveClusterName: detail.GetReplicationConfig().GetActiveClusterName(),
+			Clusters:          slices.Clone(detail.GetReplicationConfig().GetClusters()),
+			State:             detail.GetReplicationConfig().GetState(),
+		},
+	}
+}
+
+// diffNamespaceDetail lists the changed retention, archival, cluster, replication state, custom search attribute
+// alias and bad binary fields of the namespace.
+func diffNamespaceDetail(before, after *persistencespb.NamespaceDetail) []NamespaceFieldChange {
+	var diff []NamespaceFieldChange
+	addChange := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			diff = append(diff, NamespaceFieldChange{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+
+	oldConfig, newConfig := before.GetConfig(), after.GetConfig()
+	addChange("retention",
+		timestamp.DurationValue(oldConfig.GetRetention()).String(),
+		timestamp.DurationValue(newConfig.GetRetention()).String())
+	addChange("history_archival_state", oldConfig.GetHistoryArchivalState().String(), newConfig.GetHistoryArchivalState().String())
+	addChange("history_archival_uri", oldConfig.GetHistoryArchivalUri(), newConfig.GetHistoryArchivalUri())
+	addChange("visibility_archival_state", oldConfig.GetVisibilityArchivalState().String(), newConfig.GetVisibilityArchivalState().String())
+	addChange("visibility_archival_uri", oldConfig.GetVisibilityArchivalUri(), newConfig.GetVisibilityArchivalUri())
+
+	oldReplicationConfig, newReplicationConfig := before.GetReplicationConfig(), after.GetReplicationConfig()
+	addChange("clusters",
+		strings.Join(oldReplicationConfig.GetClusters(), ","),
+		strings.Join(newReplicationConfig.GetClusters(), ","))
+	addChange("active_cluster_name", oldReplicationConfig.GetActiveClusterName(), newReplicationConfig.GetActiveClusterName())
+	addChange("replication_state", oldReplicationConfig.GetState().String(), newReplicationConfig.GetState().String())
+
+	oldAliases, newAliases := oldConfig.GetCustomSearchAttributeAliases(), newConfig.GetCustomSearchAttributeAliases()
+	for _, fieldName := range unionOfKeys(oldAliases, newAliases) {
+		addChange("custom_search_attribute_aliases."+fieldName, oldAliases[fieldName], newAliases[fieldName])
+	}
+
+	oldBinaries, newBinaries := oldConfig.GetBadBinaries().GetBinaries(), newConfig.GetBadBinaries().GetBinaries()
+	for _, checksum := range unionOfKeys(oldBinaries, newBinaries) {
+		oldBinary, oldOK := oldBinaries[checksum]
+		newBinary, newOK := newBinaries[checksum]
+		if oldOK != newOK || oldBinary.GetReason() != newBinary.GetReason() {
+			diff = append(diff, NamespaceFieldChange{
+				Field:    "bad_binaries." + checksum,
+				OldValue: oldBinary.GetReason(),
+				NewValue: newBinary.GetReason(),
+			})
+		}
+	}
+	return diff
+}
+
+// changedNamespaceFields lists the top-level namespace fields of the changes returned by diffNamespaceDetail.
+func changedNamespaceFields(diff []NamespaceFieldChange) []NamespaceField {
+	var fields []NamespaceField
+	for _, change := range diff {
+		var field NamespaceField
+		switch {
+		case change.Field == "retention":
+			field = NamespaceFieldRetention
+		case strings.HasPrefix(change.Field, "history_archival_"):
+			field = NamespaceFieldHistoryArchival
+		case strings.HasPrefix(change.Field, "visibility_archival_"):
+			field = NamespaceFieldVisibilityArchival
+		case change.Field == "clusters":
+			field = NamespaceFieldClusters
+		case change.Field == "active_cluster_name":
+			field = NamespaceFieldActiveCluster
+		case change.Field == "replication_state":
+			field = NamespaceFieldState
+		case strings.HasPrefix(change.Field, "custom_search_attribute_aliases."):
+			field = NamespaceFieldCustomSearchAttributeAliases
+		case strings.HasPrefix(change.Field, "bad_binaries."):
+			field = NamespaceFieldBadBinaries
+		default:
+			continue
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// unionOfKeys returns the keys of both maps in sorted order.
+func unionOfKeys[V any](a, b map[string]V) []string {
+	keys := slices.Collect(maps.Keys(a))
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// validateReplicationWorkflowTypeFilter rejects empty and duplicate workflow types. A type may be both
+// included and excluded, in which case it is excluded.
+func validateReplicationWorkflowTypeFilter(filter *persistencespb.ReplicationWorkflowTypeFilter) error {
+	for _, workflowTypes := range [][]string{filter.GetIncludedWorkflowTypes(), filter.GetExcludedWorkflowTypes()} {
+		seen := make(map[string]struct{}, len(workflowTypes))
+		for _, workflowType := range workflowTypes {
+			if workflowType == "" {
+				return serviceerror.NewInvalidArgument("Replication workflow type filter contains an empty workflow type.")
+			}
+			if _, ok := seen[workflowType]; ok {
+				return serviceerror.NewInvalidArgumentf("Replication workflow type filter contains duplicate workflow type %q.", workflowType)
+			}
+			seen[workflowType] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// validateMaxConcurrentOpenWorkflows rejects negative limits and limits above the cluster ceiling.
+func (d *namespaceHandler) validateMaxConcurrentOpenWorkflows(limit int64) error {
+	if limit < 0 {
+		return serviceerror.NewInvalidArgument("Max concurrent open workflows must not be negative.")
+	}
+	ceiling := int64(d.config.NamespaceMaxConcurrentOpenWorkflowsCeiling())
+	if ceiling > 0 && (limit == 0 || limit > ceiling) {
+		return serviceerror.NewInvalidArgumentf("Max concurrent open workflows must be between 1 and the cluster ceiling %d.", ceiling)
+	}
+	return nil
+}
+
+// validateMaxRPS rejects negative rates and rates above the cluster ceiling of the request type.
+func validateMaxRPS(requestType string, rps float64, ceiling float64) error {
+	if rps < 0 || math.IsNaN(rps) || math.IsInf(rps, 0) {
+		return serviceerror.NewInvalidArgumentf("Max %s RPS must be a finite non-negative number.", requestType)
+	}
+	if ceiling > 0 && (rps == 0 || rps > ceiling) {
+		return serviceerror.NewInvalidArgumentf("Max %s RPS must be greater than 0 and at most the cluster ceiling %v.", requestType, ceiling)
+	}
+	return nil
+}
+
+func (d *namespaceHandler) validateVisibilityStoreUpdate(
+	ctx context.Context,
+	info *persistencespb.NamespaceInfo,
+	currentStore string,
+	options *updateNamespaceOptions,
+) error {
+	if !d.visibilityMgr.HasStoreName(options.visibilityStore) {
+		return serviceerror.NewInvalidArgumentf("Visibility store %q is not configured on this cluster.", options.visibilityStore)
+	}
+
+	// A previously selected store which is not serving reads yet means the namespace is still migrating.
+	nsName := namespace.Name(info.Name)
+	if readStore := d.visibilityMgr.GetReadStoreName(nsName); currentStore != "" && currentStore != readStore {
+		return serviceerror.NewFailedPreconditionf(
+			"Namespace is migrating from visibility store %q to %q, cannot select another visibility store.",
+			readStore, currentStore)
+	}
+
+	if options.migrateRunningWorkflows {
+		return nil
+	}
+	resp, err := d.visibilityMgr.CountWorkflowExecutions(ctx, &manager.CountWorkflowExecutionsRequest{
+		NamespaceID: namespace.ID(info.Id),
+		Namespace:   nsName,
+		Query:       fmt.Sprintf(`%s = "Running"`, searchattribute.ExecutionStatus),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Count > 0 {
+		return serviceerror.NewFailedPreconditionf(
+			"Namespace has %d running workflows, visibility store can only be switched with migration enabled.",
+			resp.Count)
 	}
+	return nil
+}
 
-	if nsUpdateRequest.ReplicationConfig.State == enumspb.REPLICATION_STATE_HANDOVER {
-		if !existingNamespace.IsGlobalNamespace {
+// validateReplicationTransition validates the change of a namespace replication config from oldConfig to newConfig
+// as a whole, since the cluster list, replication state and active cluster may all be updated by the same request.
+// namespaceState is the state of the namespace before the update.
+func validateReplicationTransition(
+	oldConfig *persistencespb.NamespaceReplicationConfig,
+	newConfig *persistencespb.NamespaceReplicationConfig,
+	namespaceState enumspb.NamespaceState,
+	isGlobalNamespace bool,
+) error {
+	oldState := normalizeReplicationState(oldConfig.GetState())
+	newState := normalizeReplicationState(newConfig.GetState())
+
+	if oldState != newState {
+		if namespaceState != enumspb.NAMESPACE_STATE_REGISTERED {
+			return serviceerror.NewInvalidArgumentf(
+				"update ReplicationState is only supported when namespace is in %s state, current state: %s",
+				enumspb.NAMESPACE_STATE_REGISTERED,
+				namespaceState,
+			)
+		}
+		if newState == enumspb.REPLICATION_STATE_HANDOVER && !isGlobalNamespace {
 			return serviceerror.NewInvalidArgumentf(
 				"%s can only be set for global namespace",
 				enumspb.REPLICATION_STATE_HANDOVER,
 			)
 		}
-		// verify namespace has more than 1 replication clusters
-		replicationClusterCount := len(existingNamespace.Namespace.ReplicationConfig.Clusters)
-		if len(nsUpdateRequest.ReplicationConfig.Clusters) > 0 {
-			replicationClusterCount = len(nsUpdateRequest.ReplicationConfig.Clusters)
-		}
-		if replicationClusterCount < 2 {
-			return serviceerror.NewInvalidArgumentf("%s require more than one replication clusters", enumspb.REPLICATION_STATE_HANDOVER)
+		if !isLegalReplicationStateTransition(oldState, newState) {
+			return serviceerror.NewInvalidArgumentf(
+				"invalid ReplicationState transition from %s to %s",
+				oldState,
+				newState,
+			)
 		}
 	}
+
+	if newState == enumspb.REPLICATION_STATE_HANDOVER && len(newConfig.GetClusters()) < 2 {
+		return serviceerror.NewInvalidArgumentf("%s require more than one replication clusters", enumspb.REPLICATION_STATE_HANDOVER)
+	}
+
+	if oldConfig.GetActiveClusterName() == newConfig.GetActiveClusterName() &&
+		slices.Contains(oldConfig.GetClusters(), oldConfig.GetActiveClusterName()) &&
+		!slices.Contains(newConfig.GetClusters(), newConfig.GetActiveClusterName()) {
+		return serviceerror.NewInvalidArgumentf(
+			"cannot remove active cluster %s from the replication clusters, fail over the namespace to another cluster first",
+			oldConfig.GetActiveClusterName(),
+		)
+	}
+	if !slices.Contains(newConfig.GetClusters(), newConfig.GetActiveClusterName()) {
+		return serviceerror.NewInvalidArgumentf(
+			"active cluster %s is not one of the replication clusters [%s], the active cluster must be one of the clusters the namespace is replicated to",
+			newConfig.GetActiveClusterName(),
+			strings.Join(newConfig.GetClusters(), ", "),
+		)
+	}
 	return nil
 }
 
-func validateStateUpdate(existingNamespace *persistence.GetNamespaceResponse, nsUpdateRequest *workflowservice.UpdateNamespaceRequest) error {
+// normalizeReplicationState treats an unset replication state as normal, which is the default for namespaces
+// created before replication states were introduced.
+func normalizeReplicationState(state enumspb.ReplicationState) enumspb.ReplicationState {
+	if state == enumspb.REPLICATION_STATE_UNSPECIFIED {
+		return enumspb.REPLICATION_STATE_NORMAL
+	}
+	return state
+}
+
+func isLegalReplicationStateTransition(oldState, newState enumspb.ReplicationState) bool {
+	switch oldState {
+	case enumspb.REPLICATION_STATE_NORMAL:
+		return newState == enumspb.REPLICATION_STATE_HANDOVER
+	case enumspb.REPLICATION_STATE_HANDOVER:
+		return newState == enumspb.REPLICATION_STATE_NORMAL
+	default:
+		return false
+	}
+}
+
+// validateStateUpdate checks the namespace state transition. DEPRECATED -> REGISTERED is only allowed with
+// allowUndeprecation and if the namespace isn't being deleted.
+func validateStateUpdate(
+	existingNamespace *persistence.GetNamespaceResponse,
+	nsUpdateRequest *workflowservice.UpdateNamespaceRequest,
+	allowUndeprecation bool,
+) error {
 	if nsUpdateRequest.UpdateInfo == nil {
 		return nil // no change
 	}
@@ -1124,6 +4241,14 @@ func validateStateUpdate(existingNamespace *persistence.GetNamespaceResponse, ns
 		switch newState {
 		case enumspb.NAMESPACE_STATE_DELETED:
 			return nil
+		case enumspb.NAMESPACE_STATE_REGISTERED:
+			if !allowUndeprecation {
+				return errInvalidNamespaceStateUpdate
+			}
+			if isRenamedForDeletion(existingNamespace.Namespace.Info) {
+				return serviceerror.NewFailedPrecondition("cannot register a namespace which is being deleted")
+			}
+			return nil
 		default:
 			return errInvalidNamespaceStateUpdate
 		}
@@ -1134,3 +4259,14 @@ func validateStateUpdate(existingNamespace *persistence.GetNamespaceResponse, ns
 		return errInvalidNamespaceStateUpdate
 	}
 }
+
+// isRenamedForDeletion returns true if the namespace was renamed by the namespace deletion, which appends
+// "-deleted-" and a prefix of the namespace ID to the name.
+func isRenamedForDeletion(info *persistencespb.NamespaceInfo) bool {
+	i := strings.LastIndex(info.GetName(), deletedNamespaceNameInfix)
+	if i < 0 {
+		return false
+	}
+	idPrefix := info.GetName()[i+len(deletedNamespaceNameInfix):]
+	return idPrefix != "" && strings.HasPrefix(info.GetId(), idPrefix)
+}
@@ -8,3 +8,48 @@
 
 // Package frontend is a generated GoMock package.
 package frontend
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFailoverVersionAllocator is a mock of FailoverVersionAllocator interface.
+type MockFailoverVersionAllocator struct {
+	ctrl     *gomock.Controller
+	recorder *MockFailoverVersionAllocatorMockRecorder
+	isgomock struct{}
+}
+
+// MockFailoverVersionAllocatorMockRecorder is the mock recorder for MockFailoverVersionAllocator.
+type MockFailoverVersionAllocatorMockRecorder struct {
+	mock *MockFailoverVersionAllocator
+}
+
+// NewMockFailoverVersionAllocator creates a new mock instance.
+func NewMockFailoverVersionAllocator(ctrl *gomock.Controller) *MockFailoverVersionAllocator {
+	mock := &MockFailoverVersionAllocator{ctrl: ctrl}
+	mock.recorder = &MockFailoverVersionAllocatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFailoverVersionAllocator) EXPECT() *MockFailoverVersionAllocatorMockRecorder {
+	return m.recorder
+}
+
+// NextFailoverVersion mocks base method.
+func (m *MockFailoverVersionAllocator) NextFailoverVersion(namespace, activeClusterName string, currentVersion int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextFailoverVersion", namespace, activeClusterName, currentVersion)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextFailoverVersion indicates an expected call of NextFailoverVersion.
+func (mr *MockFailoverVersionAllocatorMockRecorder) NextFailoverVersion(namespace, activeClusterName, currentVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextFailoverVersion", reflect.TypeOf((*MockFailoverVersionAllocator)(nil).NextFailoverVersion), namespace, activeClusterName, currentVersion)
+}
@@ -2,7 +2,12 @@ package frontend
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
 	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -15,7 +20,12 @@ import (
 	rulespb "go.temporal.io/api/rules/v1"
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/api/workflowservice/v1"
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	"go.temporal.io/server/api/historyservice/v1"
+	"go.temporal.io/server/api/historyservicemock/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
+	replicationspb "go.temporal.io/server/api/replication/v1"
+	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/archiver"
 	"go.temporal.io/server/common/archiver/provider"
 	"go.temporal.io/server/common/clock"
@@ -23,10 +33,18 @@ import (
 	"go.temporal.io/server/common/config"
 	dc "go.temporal.io/server/common/dynamicconfig"
 	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/metrics/metricstest"
+	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/namespace/nsreplication"
 	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/quotas"
+	"go.temporal.io/server/common/searchattribute"
 	"go.temporal.io/server/common/testing/protoassert"
+	"go.temporal.io/server/common/util"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -46,11 +64,28 @@ type (
 		mockArchiverProvider    *provider.MockArchiverProvider
 		fakeClock               *clock.EventTimeSource
 		config                  *Config
+		mockVisibilityMgr       *manager.MockVisibilityManager
+		mockHistoryClient       *historyservicemock.MockHistoryServiceClient
+		mockNamespaceRegistry   *namespace.MockRegistry
+
+		healthSignals             persistence.HealthSignalAggregator
+		failoverVersionAllocators FailoverVersionAllocators
+		metricsHandler            metrics.Handler
 
 		handler *namespaceHandler
 	}
+
+	failoverVersionAllocatorFunc func(namespace string, activeClusterName string, currentVersion int64) (int64, error)
 )
 
+func (f failoverVersionAllocatorFunc) NextFailoverVersion(
+	namespace string,
+	activeClusterName string,
+	currentVersion int64,
+) (int64, error) {
+	return f(namespace, activeClusterName, currentVersion)
+}
+
 var now = time.Date(2020, 8, 22, 1, 2, 3, 4, time.UTC)
 
 func TestNamespaceHandlerCommonSuite(t *testing.T) {
@@ -84,15 +119,34 @@ func (s *namespaceHandlerCommonSuite) SetupTest() {
 	s.mockArchiverProvider = provider.NewMockArchiverProvider(s.controller)
 	s.fakeClock = clock.NewEventTimeSource()
 	s.config = NewConfig(dc.NewNoopCollection(), 1024)
-	s.handler = newNamespaceHandler(
-		logger,
+	s.mockVisibilityMgr = manager.NewMockVisibilityManager(s.controller)
+	s.mockHistoryClient = historyservicemock.NewMockHistoryServiceClient(s.controller)
+	s.mockNamespaceRegistry = namespace.NewMockRegistry(s.controller)
+	s.healthSignals = persistence.NoopHealthSignalAggregator
+	s.failoverVersionAllocators = nil
+	s.metricsHandler = metrics.NoopMetricsHandler
+	s.handler = s.newHandler()
+}
+
+func (s *namespaceHandlerCommonSuite) newHandler() *namespaceHandler {
+	return newNamespaceHandler(
+		log.NewNoopLogger(),
 		s.mockMetadataMgr,
+		s.mockNamespaceRegistry,
 		s.mockClusterMetadata,
 		s.mockNamespaceReplicator,
 		s.archivalMetadata,
 		s.mockArchiverProvider,
 		s.fakeClock,
 		s.config,
+		s.mockVisibilityMgr,
+		searchattribute.NewTestProvider(),
+		searchattribute.NewTestMapperProvider(&searchattribute.TestMapper{}),
+		s.mockProducer,
+		s.mockHistoryClient,
+		s.healthSignals,
+		s.failoverVersionAllocators,
+		s.metricsHandler,
 	)
 }
 
@@ -101,30 +155,34 @@ func (s *namespaceHandlerCommonSuite) TearDownTest() {
 }
 
 func (s *namespaceHandlerCommonSuite) TestMergeNamespaceData_Overriding() {
-	out := s.handler.mergeNamespaceData(
+	out, err := s.handler.mergeNamespaceData(
 		map[string]string{
 			"k0": "v0",
 		},
 		map[string]string{
 			"k0": "v2",
 		},
+		nil,
 	)
 
+	s.NoError(err)
 	assert.Equal(s.T(), map[string]string{
 		"k0": "v2",
 	}, out)
 }
 
 func (s *namespaceHandlerCommonSuite) TestMergeNamespaceData_Adding() {
-	out := s.handler.mergeNamespaceData(
+	out, err := s.handler.mergeNamespaceData(
 		map[string]string{
 			"k0": "v0",
 		},
 		map[string]string{
 			"k1": "v2",
 		},
+		nil,
 	)
 
+	s.NoError(err)
 	assert.Equal(s.T(), map[string]string{
 		"k0": "v0",
 		"k1": "v2",
@@ -132,7 +190,7 @@ func (s *namespaceHandlerCommonSuite) TestMergeNamespaceData_Adding() {
 }
 
 func (s *namespaceHandlerCommonSuite) TestMergeNamespaceData_Merging() {
-	out := s.handler.mergeNamespaceData(
+	out, err := s.handler.mergeNamespaceData(
 		map[string]string{
 			"k0": "v0",
 		},
@@ -140,8 +198,10 @@ func (s *namespaceHandlerCommonSuite) TestMergeNamespaceData_Merging() {
 			"k0": "v1",
 			"k1": "v2",
 		},
+		nil,
 	)
 
+	s.NoError(err)
 	assert.Equal(s.T(), map[string]string{
 		"k0": "v1",
 		"k1": "v2",
@@ -149,20 +209,76 @@ func (s *namespaceHandlerCommonSuite) TestMergeNamespaceData_Merging() {
 }
 
 func (s *namespaceHandlerCommonSuite) TestMergeNamespaceData_Nil() {
-	out := s.handler.mergeNamespaceData(
+	out, err := s.handler.mergeNamespaceData(
 		nil,
 		map[string]string{
 			"k0": "v1",
 			"k1": "v2",
 		},
+		nil,
 	)
 
+	s.NoError(err)
 	assert.Equal(s.T(), map[string]string{
 		"k0": "v1",
 		"k1": "v2",
 	}, out)
 }
 
+func (s *namespaceHandlerCommonSuite) TestMergeNamespaceData_Removing() {
+	out, err := s.handler.mergeNamespaceData(
+		map[string]string{
+			"k0": "v0",
+			"k1": "v1",
+		},
+		nil,
+		[]string{"k0"},
+	)
+
+	s.NoError(err)
+	assert.Equal(s.T(), map[string]string{
+		"k1": "v1",
+	}, out)
+}
+
+func (s *namespaceHandlerCommonSuite) TestMergeNamespaceData_RemovalWins() {
+	out, err := s.handler.mergeNamespaceData(
+		map[string]string{
+			"k0": "v0",
+		},
+		map[string]string{
+			"k0": "v1",
+			"k1": "v2",
+		},
+		[]string{"k0"},
+	)
+
+	s.NoError(err)
+	assert.Equal(s.T(), map[string]string{
+		"k1": "v2",
+	}, out)
+}
+
+func (s *namespaceHandlerCommonSuite) TestMergeNamespaceData_RemovingMissing() {
+	// a key set by the same request is still missing
+	_, err := s.handler.mergeNamespaceData(
+		map[string]string{
+			"k0": "v0",
+		},
+		map[string]string{
+			"k1": "v1",
+		},
+		[]string{"k1"},
+	)
+	var invalidArgErr *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgErr)
+	s.Equal("Namespace data has no key k1 to remove.", err.Error())
+
+	_, err = s.handler.mergeNamespaceData(nil, nil, []string{"k1", "k2", "k1"})
+	s.ErrorAs(err, &invalidArgErr)
+	s.Equal("Namespace data has no keys k1, k2 to remove.", err.Error())
+}
+
 // test merging bad binaries
 func (s *namespaceHandlerCommonSuite) TestMergeBadBinaries_Overriding() {
 	out := s.handler.mergeBadBinaries(
@@ -485,6 +601,32 @@ func (s *namespaceHandlerCommonSuite) TestRegisterNamespace_WithTwoCluster() {
 	s.NoError(err)
 }
 
+func (s *namespaceHandlerCommonSuite) TestRegisterNamespace_MaxReplicationClusters() {
+	s.config.MaxReplicationClustersPerNamespace = dc.GetIntPropertyFnFilteredByNamespace(1)
+	s.handler = s.newHandler()
+
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestAllClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, &serviceerror.NamespaceNotFound{}).AnyTimes()
+	s.mockMetadataMgr.EXPECT().CreateNamespace(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.handler.RegisterNamespace(context.Background(), &workflowservice.RegisterNamespaceRequest{
+		Namespace:                        "namespace-to-register",
+		WorkflowExecutionRetentionPeriod: durationpb.New(24 * time.Hour),
+		ActiveClusterName:                cluster.TestCurrentClusterName,
+		Clusters: []*replicationpb.ClusterReplicationConfig{
+			{ClusterName: cluster.TestCurrentClusterName},
+			{ClusterName: cluster.TestAlternativeClusterName},
+		},
+		IsGlobalNamespace: true,
+	})
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+	s.Equal("Total replication cluster count 2 exceeds the max limit: 1", err.Error())
+}
+
 func (s *namespaceHandlerCommonSuite) TestRegisterNamespace_InvalidRetentionPeriod() {
 	clusterName := "cluster1"
 	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
@@ -558,7 +700,10 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_InvalidRetentionPeriod
 			},
 		}
 		resp, err := s.handler.UpdateNamespace(context.Background(), updateRequest)
-		s.Equal(errInvalidRetentionPeriod, err)
+		var invalidArgument *serviceerror.InvalidArgument
+		s.ErrorAs(err, &invalidArgument)
+		s.Equal(errInvalidRetentionPeriod.Error(), err.Error())
+		s.Equal([]string{"config.workflowExecutionRetentionTtl"}, fieldViolationPaths(err))
 		s.Nil(resp)
 	}
 }
@@ -577,7 +722,7 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_PromoteLocalNamespace(
 				Id:   nid,
 				Name: namespace,
 			},
-			Config: &persistencespb.NamespaceConfig{},
+			Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: clusterName,
 				Clusters:          []string{clusterName},
@@ -590,7 +735,11 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_PromoteLocalNamespace(
 				Id:   nid,
 				Name: namespace,
 			},
-			Config: &persistencespb.NamespaceConfig{},
+			Config: &persistencespb.NamespaceConfig{
+				Retention:     durationpb.New(24 * time.Hour),
+				BadBinaries:   &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				SchemaVersion: namespaceConfigSchemaVersion,
+			},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: clusterName,
 				Clusters:          []string{clusterName},
@@ -621,6 +770,136 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_PromoteLocalNamespace(
 	s.NoError(err)
 }
 
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_ReplicationTransmission() {
+	mockReplicator := nsreplication.NewMockReplicator(s.controller)
+	s.mockNamespaceReplicator = mockReplicator
+	s.handler = s.newHandler()
+
+	namespace := s.getRandomNamespace()
+	isGlobalNamespace := false
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(1),
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info:   &persistencespb.NamespaceInfo{Id: uuid.New(), Name: namespace},
+					Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+				IsGlobalNamespace: isGlobalNamespace,
+			}, nil
+		},
+	).AnyTimes()
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestAllClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetNextFailoverVersion(cluster.TestCurrentClusterName, int64(0)).Return(int64(1)).AnyTimes()
+	updateDescription := &workflowservice.UpdateNamespaceRequest{
+		Namespace:  namespace,
+		UpdateInfo: &namespacepb.UpdateNamespaceInfo{Description: "new description"},
+	}
+
+	// a local-only change is not transmitted
+	mockReplicator.EXPECT().HandleTransmissionTask(
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(0)
+	_, err := s.handler.UpdateNamespace(context.Background(), updateDescription)
+	s.NoError(err)
+
+	// the promotion of a local namespace is transmitted
+	mockReplicator.EXPECT().HandleTransmissionTask(
+		gomock.Any(), enumsspb.NAMESPACE_OPERATION_UPDATE, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), true, gomock.Any(),
+	).Return(nil).Times(1)
+	_, err = s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace:        namespace,
+		PromoteNamespace: true,
+	})
+	s.NoError(err)
+
+	// a change of a global namespace is transmitted
+	isGlobalNamespace = true
+	mockReplicator.EXPECT().HandleTransmissionTask(
+		gomock.Any(), enumsspb.NAMESPACE_OPERATION_UPDATE, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), true, gomock.Any(),
+	).Return(nil).Times(1)
+	_, err = s.handler.UpdateNamespace(context.Background(), updateDescription)
+	s.NoError(err)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_PromoteLocalNamespace_GlobalNamespaceNotEnabled() {
+	namespace := "local-ns-to-be-promoted"
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(1),
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:   uuid.New(),
+				Name: namespace,
+			},
+			Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters:          []string{cluster.TestCurrentClusterName},
+			},
+		},
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Times(0)
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestAllClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+
+	_, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace:        namespace,
+		PromoteNamespace: true,
+	})
+	s.ErrorIs(err, errGlobalNamespaceNotEnabled)
+
+	_, err = s.handler.RegisterNamespace(context.Background(), &workflowservice.RegisterNamespaceRequest{
+		Namespace:                        "global-ns-to-register",
+		WorkflowExecutionRetentionPeriod: durationpb.New(24 * time.Hour),
+		IsGlobalNamespace:                true,
+	})
+	s.ErrorIs(err, errGlobalNamespaceNotEnabled)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_PromoteLocalNamespace_RetentionBelowGlobalMinimum() {
+	nsName := "local-ns-to-be-promoted"
+	clusterName := "cluster1"
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: 1,
+	}, nil)
+	// the retention is valid for a local namespace, but below the global minimum
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info:   &persistencespb.NamespaceInfo{Id: uuid.New(), Name: nsName},
+			Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(namespace.MinRetentionLocal)},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: clusterName,
+				Clusters:          []string{clusterName},
+			},
+		},
+	}, nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Times(0)
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+
+	_, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace:        nsName,
+		PromoteNamespace: true,
+	})
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+	s.Equal(errInvalidRetentionPeriod.Error(), err.Error())
+	s.Equal([]string{"config.workflowExecutionRetentionTtl"}, fieldViolationPaths(err))
+}
+
 func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_UpdateActiveClusterWithHandoverState() {
 	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).AnyTimes()
 	update1Time := time.Date(2011, 12, 27, 23, 44, 55, 999999, time.UTC)
@@ -669,7 +948,10 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_UpdateActiveClusterWit
 				Name:  namespace,
 				State: enumspb.NAMESPACE_STATE_REGISTERED,
 			},
-			Config: &persistencespb.NamespaceConfig{},
+			Config: &persistencespb.NamespaceConfig{
+				BadBinaries:   &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				SchemaVersion: namespaceConfigSchemaVersion,
+			},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: clusterName2,
 				Clusters:          []string{clusterName1, clusterName2},
@@ -680,6 +962,12 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_UpdateActiveClusterWit
 						FailoverVersion: 2,
 					},
 				},
+				FailoverLog: []*persistencespb.FailoverStatus{
+					{
+						FailoverTime:    timestamppb.New(update1Time),
+						FailoverVersion: 2,
+					},
+				},
 			},
 			ConfigVersion:               int64(0),
 			FailoverNotificationVersion: version,
@@ -731,7 +1019,7 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_ChangeActiveClusterWit
 				Id:   nid,
 				Name: namespace,
 			},
-			Config: &persistencespb.NamespaceConfig{},
+			Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: clusterName1,
 				Clusters:          []string{clusterName1, clusterName2},
@@ -744,7 +1032,11 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_ChangeActiveClusterWit
 				Id:   nid,
 				Name: namespace,
 			},
-			Config: &persistencespb.NamespaceConfig{},
+			Config: &persistencespb.NamespaceConfig{
+				Retention:     durationpb.New(24 * time.Hour),
+				BadBinaries:   &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				SchemaVersion: namespaceConfigSchemaVersion,
+			},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: clusterName2,
 				Clusters:          []string{clusterName1, clusterName2},
@@ -754,6 +1046,12 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_ChangeActiveClusterWit
 						FailoverVersion: 2,
 					},
 				},
+				FailoverLog: []*persistencespb.FailoverStatus{
+					{
+						FailoverTime:    timestamppb.New(update1Time),
+						FailoverVersion: 2,
+					},
+				},
 			},
 			ConfigVersion:               0,
 			FailoverNotificationVersion: version,
@@ -835,7 +1133,7 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_UpdateActiveCluster_Li
 				Id:   nid,
 				Name: namespace,
 			},
-			Config: &persistencespb.NamespaceConfig{},
+			Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: clusterName1,
 				Clusters:          []string{clusterName1, clusterName2},
@@ -855,11 +1153,16 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_UpdateActiveCluster_Li
 				Id:   nid,
 				Name: namespace,
 			},
-			Config: &persistencespb.NamespaceConfig{},
+			Config: &persistencespb.NamespaceConfig{
+				Retention:     durationpb.New(24 * time.Hour),
+				BadBinaries:   &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				SchemaVersion: namespaceConfigSchemaVersion,
+			},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: clusterName2,
 				Clusters:          []string{clusterName1, clusterName2},
 				FailoverHistory:   sizeLimitedFailoverHistory,
+				FailoverLog:       sizeLimitedFailoverHistory,
 			},
 			ConfigVersion:               0,
 			FailoverNotificationVersion: version,
@@ -873,69 +1176,375 @@ func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_UpdateActiveCluster_Li
 	s.NoError(err)
 }
 
-func (s *namespaceHandlerCommonSuite) TestRegisterLocalNamespace_InvalidGlobalNamespace() {
-	namespace := s.getRandomNamespace()
-	description := "some random description"
-	email := "some random email"
-	retention := 7 * time.Hour * 24
-	activeClusterName := cluster.TestCurrentClusterName
-	clusters := []*replicationpb.ClusterReplicationConfig{
-		{
-			ClusterName: activeClusterName,
-		},
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_UpdateActiveCluster_MaxFailoverHistorySize() {
+	s.config.MaxNamespaceFailoverHistorySize = dc.GetIntPropertyFn(3)
+	s.handler = s.newHandler()
+	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).AnyTimes()
+	updateTime := time.Date(2011, 12, 27, 23, 44, 55, 999999, time.UTC)
+	namespace := "global-ns-to-be-migrated"
+	clusterName1 := "cluster1"
+	clusterName2 := "cluster2"
+	var failoverHistory []*persistencespb.FailoverStatus
+	for _, version := range []int64{2, 11, 12, 21, 22} {
+		failoverHistory = append(failoverHistory, &persistencespb.FailoverStatus{
+			FailoverTime:    timestamppb.New(updateTime),
+			FailoverVersion: version,
+		})
 	}
-	data := map[string]string{"some random key": "some random value"}
-	isGlobalNamespace := true
-	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: 100,
+	}, nil)
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
 	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
 	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
-		activeClusterName: {
-			Enabled:                true,
-			InitialFailoverVersion: 1,
-		},
+		clusterName1: {Enabled: true, InitialFailoverVersion: 1},
+		clusterName2: {Enabled: true, InitialFailoverVersion: 2},
 	}).AnyTimes()
-	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(activeClusterName).AnyTimes()
-
-	resp, err := s.handler.RegisterNamespace(context.Background(), &workflowservice.RegisterNamespaceRequest{
-		Namespace:                        namespace,
-		Description:                      description,
-		OwnerEmail:                       email,
-		WorkflowExecutionRetentionPeriod: durationpb.New(retention),
-		Clusters:                         clusters,
-		ActiveClusterName:                activeClusterName,
-		Data:                             data,
-		IsGlobalNamespace:                isGlobalNamespace,
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(clusterName1).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetNextFailoverVersion(clusterName2, int64(0)).Return(int64(32))
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info:   &persistencespb.NamespaceInfo{Id: uuid.New(), Name: namespace},
+			Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: clusterName1,
+				Clusters:          []string{clusterName1, clusterName2},
+				FailoverHistory:   failoverHistory,
+			},
+		},
+	}, nil)
+	failoverVersions := func(history []*persistencespb.FailoverStatus) []int64 {
+		var versions []int64
+		for _, status := range history {
+			versions = append(versions, status.GetFailoverVersion())
+		}
+		return versions
+	}
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			// only the last failovers are kept in the history, the log keeps all of them
+			s.Equal([]int64{21, 22, 32}, failoverVersions(request.Namespace.ReplicationConfig.FailoverHistory))
+			s.Equal([]int64{2, 11, 12, 21, 22, 32}, failoverVersions(request.Namespace.ReplicationConfig.FailoverLog))
+			return nil
+		},
+	)
+	s.fakeClock.Update(updateTime)
+	_, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace: namespace,
+		ReplicationConfig: &replicationpb.NamespaceReplicationConfig{
+			ActiveClusterName: clusterName2,
+		},
+		PromoteNamespace: true,
 	})
-	s.Error(err)
-	s.IsType(&serviceerror.InvalidArgument{}, err)
-	s.Nil(resp)
+	s.NoError(err)
 }
 
-func (s *namespaceHandlerCommonSuite) TestRegisterLocalNamespace_InvalidCluster() {
-	namespace := s.getRandomNamespace()
-	description := "some random description"
-	email := "some random email"
-	retention := 7 * time.Hour * 24
-	activeClusterName := cluster.TestAlternativeClusterName
-	clusters := []*replicationpb.ClusterReplicationConfig{
-		{
-			ClusterName: activeClusterName,
-		},
+func (s *namespaceHandlerCommonSuite) TestValidateNamespaceData() {
+	s.config.NamespaceDataMaxKeys = dc.GetIntPropertyFnFilteredByNamespace(3)
+	s.config.NamespaceDataMaxKeyLength = dc.GetIntPropertyFnFilteredByNamespace(4)
+	s.config.NamespaceDataMaxValueLength = dc.GetIntPropertyFnFilteredByNamespace(5)
+	s.config.NamespaceDataMaxSize = dc.GetIntPropertyFnFilteredByNamespace(20)
+	s.handler = s.newHandler()
+
+	testCases := []struct {
+		name          string
+		data          map[string]string
+		expectedError string
+	}{
+		{name: "nil data", data: nil},
+		{name: "max keys", data: map[string]string{"a": "1", "b": "2", "c": "3"}},
+		{name: "too many keys", data: map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"}, expectedError: "4 keys, the limit is 3"},
+		{name: "max key length", data: map[string]string{"abcd": "1"}},
+		{name: "key too long", data: map[string]string{"abcde": "1"}, expectedError: "is 5 bytes long, the limit is 4"},
+		{name: "max value length", data: map[string]string{"a": "12345"}},
+		{name: "value too long", data: map[string]string{"a": "123456"}, expectedError: "is 6 bytes long, the limit is 5"},
+		{name: "max size", data: map[string]string{"abcd": "12345", "efgh": "123", "ij": "12"}},
+		{name: "too large", data: map[string]string{"abcd": "12345", "efgh": "123", "ij": "123"}, expectedError: "21 bytes, the limit is 20"},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			err := s.handler.validateNamespaceData("ns", nil, tc.data)
+			if tc.expectedError == "" {
+				s.NoError(err)
+				return
+			}
+			var invalidArgument *serviceerror.InvalidArgument
+			s.ErrorAs(err, &invalidArgument)
+			s.ErrorContains(err, tc.expectedError)
+		})
 	}
-	data := map[string]string{"some random key": "some random value"}
-	isGlobalNamespace := false
-	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
-	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
-	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
-		cluster.TestCurrentClusterName: {
-			Enabled:                true,
-			InitialFailoverVersion: 1,
-		},
-	}).AnyTimes()
-	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
-	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, &serviceerror.NamespaceNotFound{})
 
-	resp, err := s.handler.RegisterNamespace(context.Background(), &workflowservice.RegisterNamespaceRequest{
+	// a non-positive limit is disabled
+	s.config.NamespaceDataMaxKeys = dc.GetIntPropertyFnFilteredByNamespace(0)
+	s.handler = s.newHandler()
+	s.NoError(s.handler.validateNamespaceData("ns", nil, map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"}))
+}
+
+func (s *namespaceHandlerCommonSuite) TestValidateNamespaceData_ExistingDataOverLimits() {
+	s.config.NamespaceDataMaxKeys = dc.GetIntPropertyFnFilteredByNamespace(2)
+	s.config.NamespaceDataMaxValueLength = dc.GetIntPropertyFnFilteredByNamespace(3)
+	s.config.NamespaceDataMaxSize = dc.GetIntPropertyFnFilteredByNamespace(10)
+	s.handler = s.newHandler()
+	existing := map[string]string{"a": "1234", "b": "1", "c": "1", "d": "1"}
+	var invalidArgument *serviceerror.InvalidArgument
+
+	// the existing data is over every limit, updates which do not grow it are allowed
+	s.NoError(s.handler.validateNamespaceData("ns", existing, maps.Clone(existing)))
+	s.NoError(s.handler.validateNamespaceData("ns", existing, map[string]string{"a": "1234", "b": "2", "c": "2", "d": "2"}))
+	s.NoError(s.handler.validateNamespaceData("ns", existing, map[string]string{"a": "1234", "b": "1", "c": "1"}))
+
+	// adding a key, growing the data or changing a value over the length limit is not
+	err := s.handler.validateNamespaceData("ns", existing, map[string]string{"a": "1234", "b": "1", "c": "1", "d": "1", "e": "1"})
+	s.ErrorAs(err, &invalidArgument)
+	s.ErrorContains(err, "5 keys, the limit is 2")
+	err = s.handler.validateNamespaceData("ns", existing, map[string]string{"a": "1234", "b": "12", "c": "1", "d": "1"})
+	s.ErrorAs(err, &invalidArgument)
+	s.ErrorContains(err, "12 bytes, the limit is 10")
+	err = s.handler.validateNamespaceData("ns", existing, map[string]string{"a": "4321", "b": "1", "c": "1", "d": "1"})
+	s.ErrorAs(err, &invalidArgument)
+	s.ErrorContains(err, "is 4 bytes long, the limit is 3")
+}
+
+func (s *namespaceHandlerCommonSuite) TestNamespaceDataLimits() {
+	s.config.NamespaceDataMaxKeys = dc.GetIntPropertyFnFilteredByNamespace(2)
+	s.handler = s.newHandler()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	var invalidArgument *serviceerror.InvalidArgument
+
+	// the data of a new namespace is validated before anything is read
+	_, err := s.handler.RegisterNamespace(context.Background(), &workflowservice.RegisterNamespaceRequest{
+		Namespace:                        "ns",
+		WorkflowExecutionRetentionPeriod: durationpb.New(24 * time.Hour),
+		Data:                             map[string]string{"a": "1", "b": "2", "c": "3"},
+	})
+	s.ErrorAs(err, &invalidArgument)
+
+	// the merged data of an updated namespace is validated
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).Times(2)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:   uuid.New(),
+						Name: "ns",
+						Data: map[string]string{"a": "1"},
+					},
+					Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).Times(2)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err = s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace:  "ns",
+		UpdateInfo: &namespacepb.UpdateNamespaceInfo{Data: map[string]string{"b": "2", "c": "3"}},
+	})
+	s.ErrorAs(err, &invalidArgument)
+	_, err = s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace:  "ns",
+		UpdateInfo: &namespacepb.UpdateNamespaceInfo{Data: map[string]string{"a": "2", "b": "2"}},
+	})
+	s.NoError(err)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_RemoveNamespaceData() {
+	s.config.NamespaceDataMaxKeys = dc.GetIntPropertyFnFilteredByNamespace(2)
+	s.handler = s.newHandler()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).Times(3)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:   uuid.New(),
+						Name: "ns",
+						Data: map[string]string{"a": "1", "b": "2"},
+					},
+					Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).Times(3)
+	var updatedData []map[string]string
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			updatedData = append(updatedData, request.Namespace.Info.Data)
+			return nil
+		},
+	).Times(2)
+
+	// keys are removed without any update info
+	_, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace: "ns",
+	}, WithRemoveNamespaceData("a"))
+	s.NoError(err)
+
+	// the removed key makes room for the new one
+	_, err = s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace:  "ns",
+		UpdateInfo: &namespacepb.UpdateNamespaceInfo{Data: map[string]string{"a": "3", "c": "3"}},
+	}, WithRemoveNamespaceData("a"))
+	s.NoError(err)
+	s.Equal([]map[string]string{{"b": "2"}, {"b": "2", "c": "3"}}, updatedData)
+
+	_, err = s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace: "ns",
+	}, WithRemoveNamespaceData("d"))
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+}
+
+func (s *namespaceHandlerCommonSuite) TestRegisterNamespaces() {
+	clusterName := "cluster1"
+	clusterName2 := "cluster2"
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		clusterName:  {Enabled: true, InitialFailoverVersion: 1},
+		clusterName2: {Enabled: true, InitialFailoverVersion: 2},
+	}).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(clusterName).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetNextFailoverVersion(clusterName, int64(0)).Return(int64(1)).AnyTimes()
+
+	newRegisterRequest := func(name string, retention time.Duration) *workflowservice.RegisterNamespaceRequest {
+		return &workflowservice.RegisterNamespaceRequest{
+			Namespace:                        name,
+			WorkflowExecutionRetentionPeriod: durationpb.New(retention),
+			Clusters: []*replicationpb.ClusterReplicationConfig{
+				{ClusterName: clusterName},
+				{ClusterName: clusterName2},
+			},
+			IsGlobalNamespace: true,
+		}
+	}
+
+	var calls []string
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			calls = append(calls, "get "+request.Name)
+			if request.Name == "ns-existing" {
+				return &persistence.GetNamespaceResponse{}, nil
+			}
+			return nil, serviceerror.NewNamespaceNotFound(request.Name)
+		},
+	).Times(3)
+	s.mockMetadataMgr.EXPECT().CreateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.CreateNamespaceRequest) (*persistence.CreateNamespaceResponse, error) {
+			calls = append(calls, "create "+request.Namespace.Info.Name)
+			if request.Namespace.Info.Name == "ns-c" {
+				return nil, serviceerror.NewUnavailable("create failed")
+			}
+			return &persistence.CreateNamespaceResponse{ID: request.Namespace.Info.Id}, nil
+		},
+	).Times(2)
+	// only the created namespace is replicated
+	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	results, err := s.handler.RegisterNamespaces(context.Background(), []*workflowservice.RegisterNamespaceRequest{
+		newRegisterRequest("ns-a", 24*time.Hour),
+		newRegisterRequest("ns-b", time.Hour),
+		newRegisterRequest("ns-a", 24*time.Hour),
+		newRegisterRequest("ns-existing", 24*time.Hour),
+		newRegisterRequest("ns-c", 24*time.Hour),
+	})
+	s.NoError(err)
+	s.Len(results, 5)
+	s.Equal("ns-a", results[0].Namespace)
+	s.NoError(results[0].Err)
+	s.Equal("ns-b", results[1].Namespace)
+	s.Equal(errInvalidRetentionPeriod, results[1].Err)
+	s.Equal("ns-a", results[2].Namespace)
+	s.ErrorContains(results[2].Err, "registered more than once")
+	s.Equal("ns-existing", results[3].Namespace)
+	s.ErrorAs(results[3].Err, new(*serviceerror.NamespaceAlreadyExists))
+	s.Equal("ns-c", results[4].Namespace)
+	s.ErrorAs(results[4].Err, new(*serviceerror.Unavailable))
+	// every request is validated before any namespace is created
+	s.Equal([]string{"get ns-a", "get ns-existing", "get ns-c", "create ns-a", "create ns-c"}, calls)
+}
+
+func (s *namespaceHandlerCommonSuite) TestRegisterLocalNamespace_InvalidGlobalNamespace() {
+	namespace := s.getRandomNamespace()
+	description := "some random description"
+	email := "some random email"
+	retention := 7 * time.Hour * 24
+	activeClusterName := cluster.TestCurrentClusterName
+	clusters := []*replicationpb.ClusterReplicationConfig{
+		{
+			ClusterName: activeClusterName,
+		},
+	}
+	data := map[string]string{"some random key": "some random value"}
+	isGlobalNamespace := true
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		activeClusterName: {
+			Enabled:                true,
+			InitialFailoverVersion: 1,
+		},
+	}).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(activeClusterName).AnyTimes()
+
+	resp, err := s.handler.RegisterNamespace(context.Background(), &workflowservice.RegisterNamespaceRequest{
+		Namespace:                        namespace,
+		Description:                      description,
+		OwnerEmail:                       email,
+		WorkflowExecutionRetentionPeriod: durationpb.New(retention),
+		Clusters:                         clusters,
+		ActiveClusterName:                activeClusterName,
+		Data:                             data,
+		IsGlobalNamespace:                isGlobalNamespace,
+	})
+	s.Error(err)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+	s.Nil(resp)
+}
+
+func (s *namespaceHandlerCommonSuite) TestRegisterLocalNamespace_InvalidCluster() {
+	namespace := s.getRandomNamespace()
+	description := "some random description"
+	email := "some random email"
+	retention := 7 * time.Hour * 24
+	activeClusterName := cluster.TestAlternativeClusterName
+	clusters := []*replicationpb.ClusterReplicationConfig{
+		{
+			ClusterName: activeClusterName,
+		},
+	}
+	data := map[string]string{"some random key": "some random value"}
+	isGlobalNamespace := false
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		cluster.TestCurrentClusterName: {
+			Enabled:                true,
+			InitialFailoverVersion: 1,
+		},
+	}).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, &serviceerror.NamespaceNotFound{})
+
+	resp, err := s.handler.RegisterNamespace(context.Background(), &workflowservice.RegisterNamespaceRequest{
 		Namespace:                        namespace,
 		Description:                      description,
 		OwnerEmail:                       email,
@@ -986,6 +1595,128 @@ func (s *namespaceHandlerCommonSuite) TestRegisterLocalNamespace_AllDefault() {
 	s.Equal(&workflowservice.RegisterNamespaceResponse{}, registerResp)
 }
 
+func (s *namespaceHandlerCommonSuite) TestRegisterLocalNamespace_ReuseDeletedNamespaceName() {
+	const deletedID = "0123456789abcdef"
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		cluster.TestCurrentClusterName: {
+			Enabled:                true,
+			InitialFailoverVersion: 1,
+		},
+	}).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+
+	testCases := []struct {
+		name  string
+		reuse bool
+		// state is the state of the namespace holding the name, if any
+		state         enumspb.NamespaceState
+		expectErr     bool
+		expectMessage string
+	}{
+		{name: "deletion completed", reuse: true},
+		{name: "deletion in progress", reuse: true, state: enumspb.NAMESPACE_STATE_DELETED, expectErr: true, expectMessage: "is being deleted"},
+		{name: "reuse disabled", reuse: false, state: enumspb.NAMESPACE_STATE_DELETED, expectErr: true, expectMessage: "already exists"},
+		{name: "namespace not deleted", reuse: true, state: enumspb.NAMESPACE_STATE_DEPRECATED, expectErr: true, expectMessage: "already exists"},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			nsName := s.getRandomNamespace()
+			s.config.EnableNamespaceNameReuse = dc.GetBoolPropertyFn(tc.reuse)
+			s.handler = s.newHandler()
+
+			if tc.state == enumspb.NAMESPACE_STATE_UNSPECIFIED {
+				// the deletion renamed the deleted namespace, its name is free
+				s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: nsName}).
+					Return(nil, serviceerror.NewNamespaceNotFound(nsName))
+			} else {
+				s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: nsName}).
+					Return(&persistence.GetNamespaceResponse{
+						Namespace: &persistencespb.NamespaceDetail{
+							Info: &persistencespb.NamespaceInfo{Id: deletedID, Name: nsName, State: tc.state},
+						},
+					}, nil)
+			}
+			if !tc.expectErr {
+				s.mockMetadataMgr.EXPECT().CreateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, request *persistence.CreateNamespaceRequest) (*persistence.CreateNamespaceResponse, error) {
+						s.Equal(nsName, request.Namespace.GetInfo().GetName())
+						s.NotEqual(deletedID, request.Namespace.GetInfo().GetId())
+						s.Equal(enumspb.NAMESPACE_STATE_REGISTERED, request.Namespace.GetInfo().GetState())
+						return &persistence.CreateNamespaceResponse{ID: request.Namespace.GetInfo().GetId()}, nil
+					},
+				)
+			}
+
+			_, err := s.handler.RegisterNamespace(context.Background(), &workflowservice.RegisterNamespaceRequest{
+				Namespace:                        nsName,
+				WorkflowExecutionRetentionPeriod: durationpb.New(24 * time.Hour),
+			})
+			if tc.expectErr {
+				s.ErrorAs(err, new(*serviceerror.NamespaceAlreadyExists))
+				s.ErrorContains(err, tc.expectMessage)
+			} else {
+				s.NoError(err)
+			}
+		})
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestRegisterLocalNamespace_ReuseNameOfNamespaceBeingDeleted() {
+	s.config.EnableNamespaceNameReuse = dc.GetBoolPropertyFn(true)
+	s.handler = s.newHandler()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		cluster.TestCurrentClusterName: {
+			Enabled:                true,
+			InitialFailoverVersion: 1,
+		},
+	}).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+
+	// namespaces by name, as updated by the namespace deletion and the registration
+	const deletedID = "0123456789abcdef"
+	nsName := s.getRandomNamespace()
+	namespaces := map[string]*persistencespb.NamespaceInfo{
+		nsName: {Id: deletedID, Name: nsName, State: enumspb.NAMESPACE_STATE_DELETED},
+	}
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			info, ok := namespaces[request.Name]
+			if !ok {
+				return nil, serviceerror.NewNamespaceNotFound(request.Name)
+			}
+			return &persistence.GetNamespaceResponse{Namespace: &persistencespb.NamespaceDetail{Info: info}}, nil
+		},
+	).AnyTimes()
+	s.mockMetadataMgr.EXPECT().CreateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.CreateNamespaceRequest) (*persistence.CreateNamespaceResponse, error) {
+			namespaces[request.Namespace.Info.Name] = request.Namespace.Info
+			return &persistence.CreateNamespaceResponse{ID: request.Namespace.Info.Id}, nil
+		},
+	).MaxTimes(1)
+	registerRequest := &workflowservice.RegisterNamespaceRequest{
+		Namespace:                        nsName,
+		WorkflowExecutionRetentionPeriod: durationpb.New(24 * time.Hour),
+	}
+
+	// the deletion marked the namespace DELETED but did not rename it yet: the registration must not take the
+	// name, otherwise the deletion, which renames the namespace by name, would rename the new namespace
+	_, err := s.handler.RegisterNamespace(context.Background(), registerRequest)
+	s.ErrorAs(err, new(*serviceerror.NamespaceAlreadyExists))
+	s.Equal(deletedID, namespaces[nsName].Id)
+
+	// the deletion renames the namespace
+	namespaces[nsName+"-deleted-01234"] = namespaces[nsName]
+	namespaces[nsName+"-deleted-01234"].Name = nsName + "-deleted-01234"
+	delete(namespaces, nsName)
+
+	_, err = s.handler.RegisterNamespace(context.Background(), registerRequest)
+	s.NoError(err)
+	s.NotEqual(deletedID, namespaces[nsName].Id)
+	s.Equal(deletedID, namespaces[nsName+"-deleted-01234"].Id)
+}
+
 func (s *namespaceHandlerCommonSuite) TestRegisterLocalNamespace_NoDefault() {
 	namespace := s.getRandomNamespace()
 	description := "some random description"
@@ -1135,8 +1866,9 @@ func (s *namespaceHandlerCommonSuite) TestUpdateLocalNamespace_AllAttrSet() {
 				Data:        data,
 			},
 			Config: &persistencespb.NamespaceConfig{
-				Retention:   retention,
-				BadBinaries: &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				Retention:     retention,
+				BadBinaries:   &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				SchemaVersion: namespaceConfigSchemaVersion,
 			},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: activeClusterName,
@@ -1369,8 +2101,9 @@ func (s *namespaceHandlerCommonSuite) TestUpdateGlobalNamespace_AllAttrSet() {
 				Data:        data,
 			},
 			Config: &persistencespb.NamespaceConfig{
-				Retention:   retention,
-				BadBinaries: &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				Retention:     retention,
+				BadBinaries:   &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				SchemaVersion: namespaceConfigSchemaVersion,
 			},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: cluster.TestCurrentClusterName,
@@ -1485,8 +2218,9 @@ func (s *namespaceHandlerCommonSuite) TestUpdateLocalNamespace_NotMaster() {
 				Data:        data,
 			},
 			Config: &persistencespb.NamespaceConfig{
-				Retention:   retention,
-				BadBinaries: &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				Retention:     retention,
+				BadBinaries:   &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				SchemaVersion: namespaceConfigSchemaVersion,
 			},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: activeClusterName,
@@ -1579,8 +2313,9 @@ func (s *namespaceHandlerCommonSuite) TestUpdateGlobalNamespace_NotMaster() {
 				Data:        data,
 			},
 			Config: &persistencespb.NamespaceConfig{
-				Retention:   retention,
-				BadBinaries: &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				Retention:     retention,
+				BadBinaries:   &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				SchemaVersion: namespaceConfigSchemaVersion,
 			},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: cluster.TestCurrentClusterName,
@@ -1643,7 +2378,7 @@ func (s *namespaceHandlerCommonSuite) TestFailoverGlobalNamespace_NotMaster() {
 				Id:   nid,
 				Name: namespace,
 			},
-			Config: &persistencespb.NamespaceConfig{},
+			Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: clusterName1,
 				Clusters:          []string{clusterName1, clusterName2},
@@ -1656,7 +2391,11 @@ func (s *namespaceHandlerCommonSuite) TestFailoverGlobalNamespace_NotMaster() {
 				Id:   nid,
 				Name: namespace,
 			},
-			Config: &persistencespb.NamespaceConfig{},
+			Config: &persistencespb.NamespaceConfig{
+				Retention:     durationpb.New(24 * time.Hour),
+				BadBinaries:   &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{}},
+				SchemaVersion: namespaceConfigSchemaVersion,
+			},
 			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
 				ActiveClusterName: clusterName2,
 				Clusters:          []string{clusterName1, clusterName2},
@@ -1666,6 +2405,12 @@ func (s *namespaceHandlerCommonSuite) TestFailoverGlobalNamespace_NotMaster() {
 						FailoverVersion: 2,
 					},
 				},
+				FailoverLog: []*persistencespb.FailoverStatus{
+					{
+						FailoverTime:    timestamppb.New(update1Time),
+						FailoverVersion: 2,
+					},
+				},
 			},
 			ConfigVersion:               0,
 			FailoverNotificationVersion: version,
@@ -1691,7 +2436,8 @@ func (s *namespaceHandlerCommonSuite) TestCreateWorkflowRule_Acceptance() {
 	identity := "identity"
 	description := "description"
 	spec := &rulespb.WorkflowRuleSpec{
-		Id: "",
+		Id:              "",
+		VisibilityQuery: "WorkflowType = 'workflow'",
 	}
 	version := int64(100)
 
@@ -1724,13 +2470,151 @@ func (s *namespaceHandlerCommonSuite) TestCreateWorkflowRule_Acceptance() {
 	s.Equal(description, rule.Description)
 }
 
+func (s *namespaceHandlerCommonSuite) TestCreateWorkflowRule_InvalidSpec() {
+	s.fakeClock.Update(time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC))
+	activityStart := &rulespb.WorkflowRuleSpec_ActivityStart{
+		ActivityStart: &rulespb.WorkflowRuleSpec_ActivityStartingTrigger{Predicate: "ActivityType = 'activity'"},
+	}
+	activityPause := &rulespb.WorkflowRuleAction{
+		Variant: &rulespb.WorkflowRuleAction_ActivityPause{ActivityPause: &rulespb.WorkflowRuleAction_ActionActivityPause{}},
+	}
+	testCases := []struct {
+		name          string
+		spec          *rulespb.WorkflowRuleSpec
+		expectedError string
+	}{
+		{
+			name: "action type not set",
+			spec: &rulespb.WorkflowRuleSpec{
+				Id:      "test-id",
+				Trigger: activityStart,
+				Actions: []*rulespb.WorkflowRuleAction{activityPause, {}},
+			},
+			expectedError: "Workflow Rule action 1: action type is not set.",
+		},
+		{
+			name: "activity pause without activity trigger",
+			spec: &rulespb.WorkflowRuleSpec{
+				Id:      "test-id",
+				Actions: []*rulespb.WorkflowRuleAction{activityPause},
+			},
+			expectedError: "Workflow Rule action 0: activity pause action requires an activity start trigger.",
+		},
+		{
+			name: "custom search attribute in visibility query",
+			spec: &rulespb.WorkflowRuleSpec{
+				Id:              "test-id",
+				Trigger:         activityStart,
+				VisibilityQuery: "WorkflowType = 'workflow' AND AliasForCustomKeywordField = 'value'",
+				Actions:         []*rulespb.WorkflowRuleAction{activityPause},
+			},
+			expectedError: "AliasForCustomKeywordField is a custom search attribute, custom search attributes are not supported by workflow rules.",
+		},
+		{
+			name: "undefined search attribute in visibility query",
+			spec: &rulespb.WorkflowRuleSpec{
+				Id:              "test-id",
+				Trigger:         activityStart,
+				VisibilityQuery: "WorkflowType = 'workflow' AND AliasForMissingField = 'value'",
+				Actions:         []*rulespb.WorkflowRuleAction{activityPause},
+			},
+			expectedError: "search attribute AliasForMissingField is not defined for namespace test-namespace.",
+		},
+		{
+			name: "unmapped search attribute in visibility query",
+			spec: &rulespb.WorkflowRuleSpec{
+				Id:              "test-id",
+				Trigger:         activityStart,
+				VisibilityQuery: "WorkflowType = 'workflow' OR wrong_alias = 'value'",
+				Actions:         []*rulespb.WorkflowRuleAction{activityPause},
+			},
+			expectedError: "search attribute wrong_alias is not defined for namespace test-namespace.",
+		},
+		{
+			name: "unsupported system search attribute in visibility query",
+			spec: &rulespb.WorkflowRuleSpec{
+				Id:              "test-id",
+				Trigger:         activityStart,
+				VisibilityQuery: "CloseTime > '2023-10-26T14:30:00Z'",
+				Actions:         []*rulespb.WorkflowRuleAction{activityPause},
+			},
+			expectedError: "search attribute CloseTime is not supported by workflow rules.",
+		},
+		{
+			name: "activity start predicate not set",
+			spec: &rulespb.WorkflowRuleSpec{
+				Id: "test-id",
+				Trigger: &rulespb.WorkflowRuleSpec_ActivityStart{
+					ActivityStart: &rulespb.WorkflowRuleSpec_ActivityStartingTrigger{},
+				},
+				VisibilityQuery: "WorkflowType = 'workflow'",
+				Actions:         []*rulespb.WorkflowRuleAction{activityPause},
+			},
+			expectedError: "Workflow Rule activity start predicate is not set.",
+		},
+		{
+			name: "unsupported field in activity start predicate",
+			spec: &rulespb.WorkflowRuleSpec{
+				Id: "test-id",
+				Trigger: &rulespb.WorkflowRuleSpec_ActivityStart{
+					ActivityStart: &rulespb.WorkflowRuleSpec_ActivityStartingTrigger{Predicate: "ActivityType = 'activity' AND WorkflowType = 'workflow'"},
+				},
+				VisibilityQuery: "WorkflowType = 'workflow'",
+				Actions:         []*rulespb.WorkflowRuleAction{activityPause},
+			},
+			expectedError: "Workflow Rule activity start predicate is invalid: unsupported activity field WorkflowType.",
+		},
+		{
+			name: "visibility query not set",
+			spec: &rulespb.WorkflowRuleSpec{
+				Id:      "test-id",
+				Trigger: activityStart,
+				Actions: []*rulespb.WorkflowRuleAction{activityPause},
+			},
+			expectedError: "Workflow Rule visibility query is not set.",
+		},
+		{
+			name: "malformed visibility query",
+			spec: &rulespb.WorkflowRuleSpec{
+				Id:              "test-id",
+				Trigger:         activityStart,
+				VisibilityQuery: "WorkflowType = ",
+				Actions:         []*rulespb.WorkflowRuleAction{activityPause},
+			},
+			expectedError: "Workflow Rule visibility query is invalid",
+		},
+		{
+			name: "expiration time in the past",
+			spec: &rulespb.WorkflowRuleSpec{
+				Id:              "test-id",
+				Trigger:         activityStart,
+				VisibilityQuery: "WorkflowType = 'workflow'",
+				Actions:         []*rulespb.WorkflowRuleAction{activityPause},
+				ExpirationTime:  timestamppb.New(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			expectedError: "Workflow Rule expiration time 2024-02-01T00:00:00Z is not in the future.",
+		},
+	}
+
+	s.mockVisibilityMgr.EXPECT().GetIndexName().Return("").AnyTimes()
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			_, err := s.handler.CreateWorkflowRule(context.Background(), tc.spec, "identity", "description", "test-namespace")
+			var invalidArgument *serviceerror.InvalidArgument
+			s.ErrorAs(err, &invalidArgument)
+			s.ErrorContains(err, tc.expectedError)
+		})
+	}
+}
+
 func (s *namespaceHandlerCommonSuite) TestCreateWorkflowRule_Duplicate() {
 	namespaceName := "test-namespace"
 	identity := "identity"
 	description := "description"
 	ruleId := "test-id"
 	spec := &rulespb.WorkflowRuleSpec{
-		Id: ruleId,
+		Id:              ruleId,
+		VisibilityQuery: "WorkflowType = 'workflow'",
 	}
 	version := int64(100)
 
@@ -1760,18 +2644,212 @@ func (s *namespaceHandlerCommonSuite) TestCreateWorkflowRule_Duplicate() {
 	s.ErrorAs(err, &invalidArgument)
 }
 
-func (s *namespaceHandlerCommonSuite) TestDeleteWorkflowRule() {
+func (s *namespaceHandlerCommonSuite) TestCreateWorkflowRule_ConcurrentUpdate() {
 	namespaceName := "test-namespace"
-	ruleId := "test-id"
-	nsConfig := &persistencespb.NamespaceConfig{
-		WorkflowRules: map[string]*rulespb.WorkflowRule{
-			ruleId: &rulespb.WorkflowRule{
-				Spec: &rulespb.WorkflowRuleSpec{Id: ruleId},
-			},
+	// another rule is created concurrently, after the first read of the namespace
+	notificationVersion := int64(1)
+	rules := map[string]*rulespb.WorkflowRule{}
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).DoAndReturn(
+		func(_ context.Context) (*persistence.GetMetadataResponse, error) {
+			return &persistence.GetMetadataResponse{NotificationVersion: notificationVersion}, nil
 		},
-	}
-
-	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+	).Times(3)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info:              &persistencespb.NamespaceInfo{Id: "1", Name: namespaceName},
+					Config:            &persistencespb.NamespaceConfig{WorkflowRules: maps.Clone(rules)},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+				},
+			}, nil
+		},
+	).Times(2)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			if request.NotificationVersion == 1 {
+				rules["other-rule"] = &rulespb.WorkflowRule{Spec: &rulespb.WorkflowRuleSpec{Id: "other-rule"}}
+				notificationVersion = 2
+				return serviceerror.NewUnavailable("UpdateNamespace operation failed because of conditional failure.")
+			}
+			s.Equal(int64(2), request.NotificationVersion)
+			s.ElementsMatch([]string{"other-rule", "test-id"}, slices.Collect(maps.Keys(request.Namespace.Config.WorkflowRules)))
+			return nil
+		},
+	).Times(2)
+
+	spec := &rulespb.WorkflowRuleSpec{Id: "test-id", VisibilityQuery: "WorkflowType = 'workflow'"}
+	rule, err := s.handler.CreateWorkflowRule(context.Background(), spec, "identity", "", namespaceName)
+	s.NoError(err)
+	s.Equal("test-id", rule.GetSpec().GetId())
+}
+
+func (s *namespaceHandlerCommonSuite) TestDeleteWorkflowRule_ConcurrentUpdateRetriesExhausted() {
+	// every update loses to a concurrent namespace update
+	notificationVersion := int64(1)
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).DoAndReturn(
+		func(_ context.Context) (*persistence.GetMetadataResponse, error) {
+			return &persistence.GetMetadataResponse{NotificationVersion: notificationVersion}, nil
+		},
+	).Times(2*workflowRuleUpdateMaxAttempts - 1)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{Id: "1", Name: "test-namespace"},
+					Config: &persistencespb.NamespaceConfig{
+						WorkflowRules: map[string]*rulespb.WorkflowRule{
+							"test-id": {Spec: &rulespb.WorkflowRuleSpec{Id: "test-id"}},
+						},
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+				},
+			}, nil
+		},
+	).Times(workflowRuleUpdateMaxAttempts)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.UpdateNamespaceRequest) error {
+			notificationVersion++
+			return serviceerror.NewUnavailable("UpdateNamespace operation failed because of conditional failure.")
+		},
+	).Times(workflowRuleUpdateMaxAttempts)
+
+	_, err := s.handler.DeleteWorkflowRule(context.Background(), "test-id", "test-namespace")
+	s.ErrorAs(err, new(*serviceerror.Unavailable))
+}
+
+func (s *namespaceHandlerCommonSuite) TestDeleteWorkflowRule_UnavailableNotRetried() {
+	// the notification version is unchanged, so the failure is not a concurrent update
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(1),
+	}, nil).Times(2)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{Id: "1", Name: "test-namespace"},
+			Config: &persistencespb.NamespaceConfig{
+				WorkflowRules: map[string]*rulespb.WorkflowRule{
+					"test-id": {Spec: &rulespb.WorkflowRuleSpec{Id: "test-id"}},
+				},
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+		},
+	}, nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).
+		Return(serviceerror.NewUnavailable("UpdateNamespace operation failed. Error: timeout"))
+
+	_, err := s.handler.DeleteWorkflowRule(context.Background(), "test-id", "test-namespace")
+	s.ErrorAs(err, new(*serviceerror.Unavailable))
+}
+
+func (s *namespaceHandlerCommonSuite) TestDeleteWorkflowRule_ConditionFailedRetried() {
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(1),
+	}, nil).Times(2)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{Id: "1", Name: "test-namespace"},
+					Config: &persistencespb.NamespaceConfig{
+						WorkflowRules: map[string]*rulespb.WorkflowRule{
+							"test-id": {Spec: &rulespb.WorkflowRuleSpec{Id: "test-id"}},
+						},
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+				},
+			}, nil
+		},
+	).Times(2)
+	gomock.InOrder(
+		s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).
+			Return(&persistence.ConditionFailedError{Msg: "namespace metadata version mismatch"}),
+		s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	rule, err := s.handler.DeleteWorkflowRule(context.Background(), "test-id", "test-namespace")
+	s.NoError(err)
+	s.Equal("test-id", rule.GetSpec().GetId())
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateWorkflowRule() {
+	namespaceName := "test-namespace"
+	createTime := timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	s.fakeClock.Update(time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC))
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: 100,
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:   "1",
+				Name: namespaceName,
+			},
+			Config: &persistencespb.NamespaceConfig{
+				WorkflowRules: map[string]*rulespb.WorkflowRule{
+					"rule-id": {
+						Spec:              &rulespb.WorkflowRuleSpec{Id: "rule-id"},
+						CreateTime:        createTime,
+						CreatedByIdentity: "creator",
+						Description:       "original description",
+					},
+				},
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+			ConfigVersion:     5,
+		},
+	}, nil).AnyTimes()
+
+	newSpec := &rulespb.WorkflowRuleSpec{
+		Id:              "rule-id",
+		VisibilityQuery: "WorkflowType = 'workflow'",
+		ExpirationTime:  timestamppb.New(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			s.Equal(int64(6), request.Namespace.ConfigVersion)
+			rule := request.Namespace.Config.WorkflowRules["rule-id"]
+			protoassert.ProtoEqual(s.T(), newSpec, rule.Spec)
+			s.Equal("new description", rule.Description)
+			protoassert.ProtoEqual(s.T(), createTime, rule.CreateTime)
+			s.Equal("creator", rule.CreatedByIdentity)
+			ruleUpdate := request.Namespace.Config.WorkflowRuleUpdates["rule-id"]
+			s.Equal(s.fakeClock.Now(), ruleUpdate.GetLastUpdateTime().AsTime())
+			s.Equal("updater", ruleUpdate.GetLastUpdatedByIdentity())
+			return nil
+		},
+	)
+
+	detail, err := s.handler.UpdateWorkflowRule(context.Background(), newSpec, "updater", "new description", namespaceName)
+	s.NoError(err)
+	protoassert.ProtoEqual(s.T(), createTime, detail.CreateTime)
+	s.Equal("creator", detail.CreatedByIdentity)
+	s.Equal("new description", detail.Description)
+	s.Equal(s.fakeClock.Now(), detail.LastUpdateTime.AsTime())
+	s.Equal("updater", detail.LastUpdatedByIdentity)
+
+	_, err = s.handler.UpdateWorkflowRule(
+		context.Background(), &rulespb.WorkflowRuleSpec{Id: "missing-rule-id", VisibilityQuery: "WorkflowType = 'workflow'"}, "updater", "", namespaceName,
+	)
+	var notFoundErr *serviceerror.NotFound
+	s.ErrorAs(err, &notFoundErr)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDeleteWorkflowRule() {
+	namespaceName := "test-namespace"
+	ruleId := "test-id"
+	storedRule := &rulespb.WorkflowRule{
+		Spec:              &rulespb.WorkflowRuleSpec{Id: ruleId, VisibilityQuery: "WorkflowType = 'workflow'"},
+		CreateTime:        timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		CreatedByIdentity: "creator",
+		Description:       "description",
+	}
+	nsConfig := &persistencespb.NamespaceConfig{
+		WorkflowRules: map[string]*rulespb.WorkflowRule{
+			ruleId: common.CloneProto(storedRule),
+		},
+	}
+
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
 		NotificationVersion: int64(1),
 	}, nil).AnyTimes()
 
@@ -1787,24 +2865,97 @@ func (s *namespaceHandlerCommonSuite) TestDeleteWorkflowRule() {
 	}, nil).AnyTimes()
 	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
-	// happy path
-	err := s.handler.DeleteWorkflowRule(context.Background(), ruleId, namespaceName)
+	// happy path, the removed rule is returned
+	deletedRule, err := s.handler.DeleteWorkflowRule(context.Background(), ruleId, namespaceName)
 	s.NoError(err)
+	protoassert.ProtoEqual(s.T(), storedRule, deletedRule)
 
 	var invalidArgument *serviceerror.InvalidArgument
 
 	// rule with such id doesn't exist
-	err = s.handler.DeleteWorkflowRule(context.Background(), "not existing rule id", namespaceName)
+	_, err = s.handler.DeleteWorkflowRule(context.Background(), "not existing rule id", namespaceName)
 	s.Error(err)
 	s.ErrorAs(err, &invalidArgument)
 
 	// config is nil
 	nsConfig.WorkflowRules = nil
-	err = s.handler.DeleteWorkflowRule(context.Background(), "not existing rule id", namespaceName)
+	_, err = s.handler.DeleteWorkflowRule(context.Background(), "not existing rule id", namespaceName)
 	s.Error(err)
 	s.ErrorAs(err, &invalidArgument)
 }
 
+func (s *namespaceHandlerCommonSuite) TestNamespaceConfigChangeRateLimit() {
+	s.config.NamespaceConfigChangeMaxCount = dc.GetIntPropertyFnFilteredByNamespace(2)
+	s.config.NamespaceConfigChangeWindow = dc.GetDurationPropertyFnFilteredByNamespace(time.Minute)
+	s.handler = s.newHandler()
+	// another frontend host, sharing the namespace store
+	otherHandler := s.newHandler()
+	s.fakeClock.Update(time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC))
+
+	namespaces := make(map[string]*persistencespb.NamespaceDetail)
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: 1,
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			detail, ok := namespaces[request.Name]
+			if !ok {
+				detail = &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{Id: request.Name + "-id", Name: request.Name},
+					Config: &persistencespb.NamespaceConfig{
+						WorkflowRules: map[string]*rulespb.WorkflowRule{
+							"rule-id": {Spec: &rulespb.WorkflowRuleSpec{Id: "rule-id"}},
+						},
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+				}
+			}
+			return &persistence.GetNamespaceResponse{Namespace: common.CloneProto(detail)}, nil
+		},
+	).AnyTimes()
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			namespaces[request.Namespace.Info.Name] = common.CloneProto(request.Namespace)
+			return nil
+		},
+	).Times(4)
+
+	newRuleSpec := func(id string) *rulespb.WorkflowRuleSpec {
+		return &rulespb.WorkflowRuleSpec{Id: id, VisibilityQuery: "WorkflowType = 'workflow'"}
+	}
+
+	_, err := s.handler.CreateWorkflowRule(context.Background(), newRuleSpec("new-rule-1"), "identity", "", "test-namespace")
+	s.NoError(err)
+	s.fakeClock.Advance(20 * time.Second)
+	_, err = otherHandler.DeleteWorkflowRule(context.Background(), "rule-id", "test-namespace")
+	s.NoError(err)
+
+	// rule changes and other config changes share the limit, which holds across frontend hosts
+	s.fakeClock.Advance(10 * time.Second)
+	for _, handler := range []*namespaceHandler{s.handler, otherHandler} {
+		_, err = handler.CreateWorkflowRule(context.Background(), newRuleSpec("new-rule-2"), "identity", "", "test-namespace")
+		var resourceExhausted *serviceerror.ResourceExhausted
+		s.ErrorAs(err, &resourceExhausted)
+		s.Equal(enumspb.RESOURCE_EXHAUSTED_SCOPE_NAMESPACE, resourceExhausted.Scope)
+		s.ErrorContains(err, "next change allowed in 30s")
+	}
+
+	// other namespaces are not limited
+	_, err = s.handler.CreateWorkflowRule(context.Background(), newRuleSpec("new-rule-2"), "identity", "", "other-namespace")
+	s.NoError(err)
+
+	// the first change is out of the window
+	s.fakeClock.Advance(30 * time.Second)
+	_, err = s.handler.CreateWorkflowRule(context.Background(), newRuleSpec("new-rule-2"), "identity", "", "test-namespace")
+	s.NoError(err)
+	_, err = otherHandler.CreateWorkflowRule(context.Background(), newRuleSpec("new-rule-3"), "identity", "", "test-namespace")
+	var resourceExhausted *serviceerror.ResourceExhausted
+	s.ErrorAs(err, &resourceExhausted)
+	s.ErrorContains(err, "next change allowed in 20s")
+	// only the changes within the window are persisted
+	s.Len(namespaces["test-namespace"].Config.ConfigChangeTimes, 2)
+}
+
 func (s *namespaceHandlerCommonSuite) TestDescribeWorkflowRule() {
 	namespaceName := "test-namespace"
 	ruleId := "test-id"
@@ -1848,6 +2999,56 @@ func (s *namespaceHandlerCommonSuite) TestDescribeWorkflowRule() {
 	s.Nil(rule)
 }
 
+func (s *namespaceHandlerCommonSuite) TestDescribeWorkflowRuleDetail_RuleLimit() {
+	s.config.MaxWorkflowRulesPerNamespace = dc.GetIntPropertyFnFilteredByNamespace(5)
+	s.handler = s.newHandler()
+	s.fakeClock.Update(time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC))
+	nsConfig := &persistencespb.NamespaceConfig{}
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(1),
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info:              &persistencespb.NamespaceInfo{Id: "1", Name: "test-namespace"},
+					Config:            nsConfig,
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+				},
+			}, nil
+		},
+	).AnyTimes()
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			nsConfig = request.Namespace.Config
+			return nil
+		},
+	).AnyTimes()
+
+	for _, ruleID := range []string{"rule-1", "rule-2", "rule-3"} {
+		spec := &rulespb.WorkflowRuleSpec{Id: ruleID, VisibilityQuery: "WorkflowType = 'workflow'"}
+		_, err := s.handler.CreateWorkflowRule(context.Background(), spec, "identity", "", "test-namespace")
+		s.NoError(err)
+	}
+	// expired rules do not count against the limit
+	nsConfig.WorkflowRules["expired"] = &rulespb.WorkflowRule{Spec: &rulespb.WorkflowRuleSpec{
+		Id:             "expired",
+		ExpirationTime: timestamppb.New(s.fakeClock.Now().Add(-time.Minute)),
+	}}
+
+	detail, err := s.handler.DescribeWorkflowRuleDetail(context.Background(), "rule-2", "test-namespace", IncludeWorkflowRuleLimit())
+	s.NoError(err)
+	s.Equal("rule-2", detail.GetSpec().GetId())
+	s.Equal(3, detail.RuleCount)
+	s.Equal(5, detail.MaxRules)
+
+	detail, err = s.handler.DescribeWorkflowRuleDetail(context.Background(), "rule-2", "test-namespace")
+	s.NoError(err)
+	s.Equal("rule-2", detail.GetSpec().GetId())
+	s.Zero(detail.RuleCount)
+	s.Zero(detail.MaxRules)
+}
+
 func (s *namespaceHandlerCommonSuite) TestListWorkflowRules() {
 	namespaceName := "test-namespace"
 	nsConfig := &persistencespb.NamespaceConfig{
@@ -1869,63 +3070,3593 @@ func (s *namespaceHandlerCommonSuite) TestListWorkflowRules() {
 	}, nil).AnyTimes()
 
 	// happy path
-	rules, err := s.handler.ListWorkflowRules(context.Background(), namespaceName)
+	resp, err := s.handler.ListWorkflowRules(context.Background(), namespaceName, 0, nil)
 	s.NoError(err)
-	s.NotNil(rules)
-	s.Equal(2, len(rules))
+	s.NotNil(resp.Rules)
+	s.Equal(2, len(resp.Rules))
+	s.Nil(resp.NextPageToken)
 
 	// config is nil
 	nsConfig.WorkflowRules = nil
-	rules, err = s.handler.ListWorkflowRules(context.Background(), namespaceName)
+	resp, err = s.handler.ListWorkflowRules(context.Background(), namespaceName, 0, nil)
 	s.NoError(err)
-	s.NotNil(rules)
-	s.Equal(0, len(rules))
+	s.NotNil(resp.Rules)
+	s.Equal(0, len(resp.Rules))
+	s.Nil(resp.NextPageToken)
+}
+
+func (s *namespaceHandlerCommonSuite) TestListWorkflowRules_Pagination() {
+	namespaceName := "test-namespace"
+	nsConfig := &persistencespb.NamespaceConfig{
+		WorkflowRules: map[string]*rulespb.WorkflowRule{},
+	}
+	var expectedRuleIDs []string
+	for i := range 7 {
+		ruleID := fmt.Sprintf("rule-%d", i)
+		nsConfig.WorkflowRules[ruleID] = &rulespb.WorkflowRule{Spec: &rulespb.WorkflowRuleSpec{Id: ruleID}}
+		expectedRuleIDs = append(expectedRuleIDs, ruleID)
+	}
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:   "1",
+				Name: namespaceName,
+			},
+			Config:            nsConfig,
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+		},
+	}, nil).AnyTimes()
+
+	var listedRuleIDs []string
+	var pageToken []byte
+	pageCount := 0
+	for {
+		resp, err := s.handler.ListWorkflowRules(context.Background(), namespaceName, 3, pageToken)
+		s.NoError(err)
+		s.LessOrEqual(len(resp.Rules), 3)
+		for _, rule := range resp.Rules {
+			listedRuleIDs = append(listedRuleIDs, rule.GetSpec().GetId())
+		}
+		pageCount++
+		if pageCount == 1 {
+			// rules created before the cursor are not returned, rules after it and deleted rules are
+			delete(nsConfig.WorkflowRules, "rule-0")
+			delete(nsConfig.WorkflowRules, "rule-6")
+			for _, ruleID := range []string{"rule-00", "rule-7"} {
+				nsConfig.WorkflowRules[ruleID] = &rulespb.WorkflowRule{Spec: &rulespb.WorkflowRuleSpec{Id: ruleID}}
+			}
+			expectedRuleIDs = append(slices.DeleteFunc(expectedRuleIDs, func(ruleID string) bool {
+				return ruleID == "rule-6"
+			}), "rule-7")
+		}
+		pageToken = resp.NextPageToken
+		if pageToken == nil {
+			break
+		}
+	}
+
+	s.Equal(3, pageCount)
+	s.Equal(expectedRuleIDs, listedRuleIDs)
 }
 
 func (s *namespaceHandlerCommonSuite) TestWorkflowRuleEviction() {
 	s.fakeClock.Update(time.Now())
 	expiredTime1 := s.fakeClock.Now().Add(-1 * time.Hour)
 	expiredTime2 := s.fakeClock.Now().Add(-2 * time.Hour)
+	futureTime := s.fakeClock.Now().Add(time.Hour)
 
 	tests := []struct {
-		name        string
-		deletedRule string
-		rules       map[string]*rulespb.WorkflowRule
+		name         string
+		deletedRules []string
+		rules        map[string]*rulespb.WorkflowRule
 	}{
 		{
-			name: "empty map", deletedRule: "", rules: map[string]*rulespb.WorkflowRule{},
+			name: "empty map", deletedRules: nil, rules: map[string]*rulespb.WorkflowRule{},
 		},
 		{
-			name: "no rule to delete", deletedRule: "", rules: map[string]*rulespb.WorkflowRule{
+			name: "no rule to delete", deletedRules: nil, rules: map[string]*rulespb.WorkflowRule{
 				"rule 1": {Spec: &rulespb.WorkflowRuleSpec{Id: "rule 1"}},
+				"rule 2": {Spec: &rulespb.WorkflowRuleSpec{Id: "rule 2", ExpirationTime: timestamppb.New(futureTime)}},
 			},
 		},
 		{
-			name: "single rule to delete", deletedRule: "rule 1", rules: map[string]*rulespb.WorkflowRule{
+			name: "single rule to delete", deletedRules: []string{"rule 1"}, rules: map[string]*rulespb.WorkflowRule{
 				"rule 1": {Spec: &rulespb.WorkflowRuleSpec{Id: "rule 1", ExpirationTime: timestamppb.New(expiredTime1)}},
 			},
 		},
 		{
-			name: "two candidates to delete", deletedRule: "rule 2", rules: map[string]*rulespb.WorkflowRule{
+			name: "expired and unexpired rules", deletedRules: []string{"rule 1", "rule 2"}, rules: map[string]*rulespb.WorkflowRule{
 				"rule 1": {Spec: &rulespb.WorkflowRuleSpec{Id: "rule 1", ExpirationTime: timestamppb.New(expiredTime1)}},
 				"rule 2": {Spec: &rulespb.WorkflowRuleSpec{Id: "rule 2", ExpirationTime: timestamppb.New(expiredTime2)}},
+				"rule 3": {Spec: &rulespb.WorkflowRuleSpec{Id: "rule 3", ExpirationTime: timestamppb.New(futureTime)}},
+				"rule 4": {Spec: &rulespb.WorkflowRuleSpec{Id: "rule 4"}},
 			},
 		},
 	}
 
 	for _, tt := range tests {
-		oldLens := len(tt.rules)
-		s.handler.removeOldestExpiredWorkflowRule("", tt.rules)
-		if len(tt.deletedRule) == 0 {
-			s.Equal(oldLens, len(tt.rules))
-		} else {
-			if _, exists := tt.rules[tt.deletedRule]; exists {
-				s.True(false, "Rule was not deleted")
+		s.Run(tt.name, func() {
+			oldLen := len(tt.rules)
+			removedRules := s.handler.removeExpiredWorkflowRules("", &persistencespb.NamespaceConfig{WorkflowRules: tt.rules})
+			var removedRuleIDs []string
+			for _, rule := range removedRules {
+				removedRuleIDs = append(removedRuleIDs, rule.GetSpec().GetId())
+				s.NotContains(tt.rules, rule.GetSpec().GetId())
 			}
+			s.Equal(tt.deletedRules, removedRuleIDs)
+			s.Len(tt.rules, oldLen-len(tt.deletedRules))
+		})
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestRemoveExpiredWorkflowRules() {
+	s.fakeClock.Update(time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC))
+	expired := timestamppb.New(s.fakeClock.Now().Add(-time.Minute))
+	unexpired := timestamppb.New(s.fakeClock.Now().Add(time.Minute))
+	nsConfig := &persistencespb.NamespaceConfig{
+		WorkflowRules: map[string]*rulespb.WorkflowRule{
+			"expired-1": {Spec: &rulespb.WorkflowRuleSpec{Id: "expired-1", ExpirationTime: expired}},
+			"expired-2": {Spec: &rulespb.WorkflowRuleSpec{Id: "expired-2", ExpirationTime: expired}},
+			"unexpired": {Spec: &rulespb.WorkflowRuleSpec{Id: "unexpired", ExpirationTime: unexpired}},
+			"permanent": {Spec: &rulespb.WorkflowRuleSpec{Id: "permanent"}},
+		},
+	}
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(1),
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info:              &persistencespb.NamespaceInfo{Id: "1", Name: "test-namespace"},
+					Config:            common.CloneProto(nsConfig),
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+				},
+			}, nil
+		},
+	).AnyTimes()
+
+	listRuleIDs := func(opts ...WorkflowRulesOption) []string {
+		resp, err := s.handler.ListWorkflowRules(context.Background(), "test-namespace", 0, nil, opts...)
+		s.NoError(err)
+		var ruleIDs []string
+		for _, rule := range resp.Rules {
+			ruleIDs = append(ruleIDs, rule.GetSpec().GetId())
 		}
+		return ruleIDs
 	}
+
+	// expired rules are neither listed nor described by default
+	s.Equal([]string{"permanent", "unexpired"}, listRuleIDs())
+	_, err := s.handler.DescribeWorkflowRule(context.Background(), "expired-1", "test-namespace")
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+
+	s.Equal([]string{"expired-1", "expired-2", "permanent", "unexpired"}, listRuleIDs(IncludeExpiredWorkflowRules()))
+	rule, err := s.handler.DescribeWorkflowRule(context.Background(), "expired-1", "test-namespace", IncludeExpiredWorkflowRules())
+	s.NoError(err)
+	s.Equal("expired-1", rule.GetSpec().GetId())
+
+	// the namespace is only updated by the first sweep
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			nsConfig = request.Namespace.Config
+			return nil
+		},
+	).Times(1)
+	removedRules, err := s.handler.RemoveExpiredWorkflowRules(context.Background(), "test-namespace")
+	s.NoError(err)
+	s.Len(removedRules, 2)
+	s.Equal("expired-1", removedRules[0].GetSpec().GetId())
+	s.Equal("expired-2", removedRules[1].GetSpec().GetId())
+	s.ElementsMatch([]string{"permanent", "unexpired"}, slices.Collect(maps.Keys(nsConfig.WorkflowRules)))
+
+	removedRules, err = s.handler.RemoveExpiredWorkflowRules(context.Background(), "test-namespace")
+	s.NoError(err)
+	s.Empty(removedRules)
+
+	// rules expire with the clock
+	s.fakeClock.Advance(time.Minute)
+	s.Equal([]string{"permanent"}, listRuleIDs())
+	s.Equal([]string{"permanent", "unexpired"}, listRuleIDs(IncludeExpiredWorkflowRules()))
 }
 
-func (s *namespaceHandlerCommonSuite) getRandomNamespace() string {
-	return "namespace" + uuid.New()
+func (s *namespaceHandlerCommonSuite) TestCreateWorkflowRule_ExpiredRulesNotCounted() {
+	s.config.MaxWorkflowRulesPerNamespace = dc.GetIntPropertyFnFilteredByNamespace(2)
+	s.handler = s.newHandler()
+	s.fakeClock.Update(time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC))
+	expired := timestamppb.New(s.fakeClock.Now().Add(-time.Minute))
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(1),
+	}, nil)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{Id: "1", Name: "test-namespace"},
+			Config: &persistencespb.NamespaceConfig{
+				WorkflowRules: map[string]*rulespb.WorkflowRule{
+					"expired-1": {Spec: &rulespb.WorkflowRuleSpec{Id: "expired-1", ExpirationTime: expired}},
+					"expired-2": {Spec: &rulespb.WorkflowRuleSpec{Id: "expired-2", ExpirationTime: expired}},
+				},
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+		},
+	}, nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			// all expired rules are removed, not only the oldest one
+			s.Equal([]string{"new-rule"}, slices.Collect(maps.Keys(request.Namespace.Config.WorkflowRules)))
+			return nil
+		},
+	)
+
+	spec := &rulespb.WorkflowRuleSpec{Id: "new-rule", VisibilityQuery: "WorkflowType = 'workflow'"}
+	_, err := s.handler.CreateWorkflowRule(context.Background(), spec, "identity", "", "test-namespace")
+	s.NoError(err)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_ArchivalBatchConfig() {
+	namespace := s.getRandomNamespace()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).Times(2)
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:   uuid.New(),
+						Name: namespace,
+					},
+					Config: &persistencespb.NamespaceConfig{
+						Retention: durationpb.New(24 * time.Hour),
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).Times(2)
+
+	// out of bounds batch size is rejected
+	_, err := s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+		WithArchivalBatchConfig(&persistencespb.ArchivalBatchConfig{BatchSize: 1000000}),
+	)
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+
+	batchConfig := &persistencespb.ArchivalBatchConfig{
+		BatchSize:     200,
+		FlushInterval: durationpb.New(time.Minute),
+	}
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			protoassert.ProtoEqual(s.T(), batchConfig, request.Namespace.Config.ArchivalBatchConfig)
+			s.Equal(int64(1), request.Namespace.ConfigVersion)
+			return nil
+		},
+	)
+	_, err = s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+		WithArchivalBatchConfig(batchConfig),
+	)
+	s.NoError(err)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_ReplicationWorkflowTypeFilter() {
+	namespace := s.getRandomNamespace()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).Times(3)
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:   uuid.New(),
+						Name: namespace,
+					},
+					Config: &persistencespb.NamespaceConfig{
+						Retention: durationpb.New(24 * time.Hour),
+						ReplicationWorkflowTypeFilter: &persistencespb.ReplicationWorkflowTypeFilter{
+							ExcludedWorkflowTypes: []string{"cron"},
+						},
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).Times(3)
+
+	// duplicate workflow types are rejected
+	_, err := s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+		WithReplicationWorkflowTypeFilter(&persistencespb.ReplicationWorkflowTypeFilter{
+			IncludedWorkflowTypes: []string{"order", "order"},
+		}),
+	)
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+
+	filter := &persistencespb.ReplicationWorkflowTypeFilter{
+		IncludedWorkflowTypes: []string{"order", "payment"},
+		ExcludedWorkflowTypes: []string{"payment"},
+	}
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			protoassert.ProtoEqual(s.T(), filter, request.Namespace.Config.ReplicationWorkflowTypeFilter)
+			return nil
+		},
+	)
+	_, err = s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+		WithReplicationWorkflowTypeFilter(filter),
+	)
+	s.NoError(err)
+
+	// an empty filter replicates all workflows
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			s.Nil(request.Namespace.Config.ReplicationWorkflowTypeFilter)
+			return nil
+		},
+	)
+	_, err = s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+		WithReplicationWorkflowTypeFilter(&persistencespb.ReplicationWorkflowTypeFilter{}),
+	)
+	s.NoError(err)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_MaxConcurrentOpenWorkflows() {
+	s.config.NamespaceMaxConcurrentOpenWorkflowsCeiling = dc.GetIntPropertyFn(1000)
+	s.handler = s.newHandler()
+
+	namespace := s.getRandomNamespace()
+	var persistedLimit int64
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:   uuid.New(),
+						Name: namespace,
+					},
+					Config: &persistencespb.NamespaceConfig{
+						Retention:                  durationpb.New(24 * time.Hour),
+						MaxConcurrentOpenWorkflows: persistedLimit,
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).AnyTimes()
+
+	// negative limits, limits above the ceiling and removing the limit are rejected
+	for _, limit := range []int64{-1, 1001, 0} {
+		_, err := s.handler.UpdateNamespace(
+			context.Background(),
+			&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+			WithMaxConcurrentOpenWorkflows(limit),
+		)
+		var invalidArgument *serviceerror.InvalidArgument
+		s.ErrorAs(err, &invalidArgument, "limit %d", limit)
+	}
+
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			persistedLimit = request.Namespace.Config.MaxConcurrentOpenWorkflows
+			return nil
+		},
+	)
+	_, err := s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+		WithMaxConcurrentOpenWorkflows(1000),
+	)
+	s.NoError(err)
+	s.Equal(int64(1000), persistedLimit)
+
+	description, err := s.handler.DescribeNamespaceDetail(context.Background(), &workflowservice.DescribeNamespaceRequest{
+		Namespace: namespace,
+	})
+	s.NoError(err)
+	s.Equal(int64(1000), description.MaxConcurrentOpenWorkflows)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_MaxSignalQueryRPS() {
+	s.config.NamespaceMaxSignalRPSCeiling = dc.GetFloatPropertyFn(100)
+	s.handler = s.newHandler()
+
+	namespace := s.getRandomNamespace()
+	var persistedSignalRPS, persistedQueryRPS float64
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:   uuid.New(),
+						Name: namespace,
+					},
+					Config: &persistencespb.NamespaceConfig{
+						Retention:    durationpb.New(24 * time.Hour),
+						MaxSignalRps: persistedSignalRPS,
+						MaxQueryRps:  persistedQueryRPS,
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).AnyTimes()
+
+	// negative rates, rates above the ceiling and removing the limit are rejected
+	for _, option := range []UpdateNamespaceOption{
+		WithMaxSignalRPS(-1),
+		WithMaxSignalRPS(100.5),
+		WithMaxSignalRPS(0),
+		WithMaxSignalRPS(math.NaN()),
+		WithMaxQueryRPS(-1),
+		WithMaxQueryRPS(math.Inf(1)),
+	} {
+		_, err := s.handler.UpdateNamespace(
+			context.Background(),
+			&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+			option,
+		)
+		var invalidArgument *serviceerror.InvalidArgument
+		s.ErrorAs(err, &invalidArgument)
+	}
+
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			persistedSignalRPS = request.Namespace.Config.MaxSignalRps
+			persistedQueryRPS = request.Namespace.Config.MaxQueryRps
+			return nil
+		},
+	)
+	// the query rate has no ceiling
+	_, err := s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+		WithMaxSignalRPS(100),
+		WithMaxQueryRPS(1000),
+	)
+	s.NoError(err)
+	s.Equal(float64(100), persistedSignalRPS)
+	s.Equal(float64(1000), persistedQueryRPS)
+
+	description, err := s.handler.DescribeNamespaceDetail(context.Background(), &workflowservice.DescribeNamespaceRequest{
+		Namespace: namespace,
+	})
+	s.NoError(err)
+	s.Equal(float64(100), description.MaxSignalRPS)
+	s.Equal(float64(1000), description.MaxQueryRPS)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_DeleteBadBinaries() {
+	namespace := s.getRandomNamespace()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:   uuid.New(),
+						Name: namespace,
+					},
+					Config: &persistencespb.NamespaceConfig{
+						Retention: durationpb.New(24 * time.Hour),
+						BadBinaries: &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{
+							"checksum-1": {},
+							"checksum-2": {},
+							"checksum-3": {},
+						}},
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).AnyTimes()
+
+	// all missing checksums are reported and nothing is deleted
+	_, err := s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace, DeleteBadBinary: "missing-1"},
+		WithDeleteBadBinaries("checksum-1", "missing-2", "missing-1"),
+	)
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+	s.Equal("Bad binary checksums missing-1, missing-2 don't exist.", err.Error())
+
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			s.Equal([]string{"checksum-3"}, slices.Collect(maps.Keys(request.Namespace.Config.BadBinaries.Binaries)))
+			return nil
+		},
+	)
+	_, err = s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace, DeleteBadBinary: "checksum-1"},
+		WithDeleteBadBinaries("checksum-2", "checksum-1"),
+	)
+	s.NoError(err)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_RemoveCustomSearchAttributeAliases() {
+	namespace := s.getRandomNamespace()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{Id: uuid.New(), Name: namespace},
+					Config: &persistencespb.NamespaceConfig{
+						Retention: durationpb.New(24 * time.Hour),
+						CustomSearchAttributeAliases: map[string]string{
+							"Keyword01": "Customer",
+							"Keyword02": "Order",
+						},
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).AnyTimes()
+
+	// the fields without an alias are reported and nothing is removed
+	_, err := s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+		WithRemoveCustomSearchAttributeAliases("Keyword01", "Keyword04", "Keyword03"),
+	)
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+	s.Equal("Custom search attribute fields Keyword04, Keyword03 have no alias to remove.", err.Error())
+
+	// removal wins over an alias upserted for the same field, which would otherwise fail as already allocated
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			s.Equal(
+				map[string]string{"Keyword03": "Payment"},
+				request.Namespace.Config.CustomSearchAttributeAliases,
+			)
+			return nil
+		},
+	)
+	result, err := s.handler.UpdateNamespaceDetail(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{
+			Namespace: namespace,
+			Config: &namespacepb.NamespaceConfig{
+				CustomSearchAttributeAliases: map[string]string{"Keyword01": "Account", "Keyword03": "Payment"},
+			},
+		},
+		WithRemoveCustomSearchAttributeAliases("Keyword01", "Keyword02"),
+	)
+	s.NoError(err)
+	s.Equal([]NamespaceFieldChange{
+		{Field: "custom_search_attribute_aliases.Keyword01", OldValue: "Customer", NewValue: ""},
+		{Field: "custom_search_attribute_aliases.Keyword02", OldValue: "Order", NewValue: ""},
+		{Field: "custom_search_attribute_aliases.Keyword03", OldValue: "", NewValue: "Payment"},
+	}, result.Diff)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_DryRun() {
+	namespace := "global-ns-to-be-migrated"
+	clusterName1 := "cluster1"
+	clusterName2 := "cluster2"
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: 100,
+	}, nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		clusterName1: {Enabled: true, InitialFailoverVersion: 1},
+		clusterName2: {Enabled: true, InitialFailoverVersion: 2},
+	}).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(clusterName1).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetNextFailoverVersion(clusterName2, int64(11)).Return(int64(12))
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info:   &persistencespb.NamespaceInfo{Id: uuid.New(), Name: namespace},
+					Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: clusterName1,
+						Clusters:          []string{clusterName1, clusterName2},
+					},
+					FailoverVersion: 11,
+				},
+				IsGlobalNamespace: true,
+			}, nil
+		},
+	).AnyTimes()
+
+	// the namespace is neither updated nor replicated, the mocks fail on UpdateNamespace and Publish
+	result, err := s.handler.UpdateNamespaceDetail(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{
+			Namespace:         namespace,
+			ReplicationConfig: &replicationpb.NamespaceReplicationConfig{ActiveClusterName: clusterName2},
+		},
+		WithDryRun(),
+	)
+	s.NoError(err)
+	s.True(result.DryRun)
+	s.Equal(int64(12), result.FailoverVersion)
+	s.Equal(clusterName2, result.ReplicationConfig.GetActiveClusterName())
+	s.Equal([]NamespaceFieldChange{
+		{Field: "active_cluster_name", OldValue: clusterName1, NewValue: clusterName2},
+	}, result.Diff)
+
+	_, err = s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{
+			Namespace: namespace,
+			Config:    &namespacepb.NamespaceConfig{WorkflowExecutionRetentionTtl: durationpb.New(time.Minute)},
+		},
+		WithDryRun(),
+	)
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_MaxBadBinaries() {
+	s.config.MaxBadBinaries = dc.GetIntPropertyFnFilteredByNamespace(2)
+	s.handler = s.newHandler()
+
+	namespace := s.getRandomNamespace()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:   uuid.New(),
+						Name: namespace,
+					},
+					Config: &persistencespb.NamespaceConfig{
+						Retention: durationpb.New(24 * time.Hour),
+						BadBinaries: &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{
+							"checksum-1": {},
+						}},
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).AnyTimes()
+
+	_, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace: namespace,
+		Config: &namespacepb.NamespaceConfig{
+			BadBinaries: &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{
+				"checksum-2": {},
+				"checksum-3": {},
+			}},
+		},
+	})
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+	s.Equal("Total resetBinaries count 3 exceeds the max limit: 2", err.Error())
+
+	description, err := s.handler.DescribeNamespaceDetail(context.Background(), &workflowservice.DescribeNamespaceRequest{
+		Namespace: namespace,
+	})
+	s.NoError(err)
+	s.Equal(1, description.BadBinaryCount)
+	s.Equal(2, description.MaxBadBinaries)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_RetentionReductionGuard() {
+	s.config.RetentionReductionGuardEnabled = dc.GetBoolPropertyFnFilteredByNamespace(true)
+	s.config.MaxRetentionReductionPercent = dc.GetIntPropertyFnFilteredByNamespace(50)
+	s.config.MaxRetentionReduction = dc.GetDurationPropertyFnFilteredByNamespace(5 * 24 * time.Hour)
+	s.handler = s.newHandler()
+
+	namespace := s.getRandomNamespace()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:   uuid.New(),
+						Name: namespace,
+					},
+					Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(12 * 24 * time.Hour)},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).AnyTimes()
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	testCases := []struct {
+		name          string
+		retention     time.Duration
+		opts          []UpdateNamespaceOption
+		expectedError string
+	}{
+		{
+			name:      "small reduction",
+			retention: 8 * 24 * time.Hour,
+		},
+		{
+			name:      "increase",
+			retention: 30 * 24 * time.Hour,
+		},
+		{
+			name:          "reduction above max percent",
+			retention:     24 * time.Hour,
+			expectedError: "Retention reduction from 288h0m0s to 24h0m0s exceeds the max reduction of 50%, force the update to reduce it anyway.",
+		},
+		{
+			name:          "reduction above max duration",
+			retention:     6 * 24 * time.Hour,
+			expectedError: "Retention reduction from 288h0m0s to 144h0m0s exceeds the max reduction of 120h0m0s, force the update to reduce it anyway.",
+		},
+		{
+			name:      "forced reduction",
+			retention: 24 * time.Hour,
+			opts:      []UpdateNamespaceOption{WithForceRetentionReduction()},
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			_, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+				Namespace: namespace,
+				Config: &namespacepb.NamespaceConfig{
+					WorkflowExecutionRetentionTtl: durationpb.New(tc.retention),
+				},
+			}, tc.opts...)
+			if tc.expectedError == "" {
+				s.NoError(err)
+				return
+			}
+			var invalidArgument *serviceerror.InvalidArgument
+			s.ErrorAs(err, &invalidArgument)
+			s.Equal(tc.expectedError, err.Error())
+		})
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_MaxReplicationClusters() {
+	s.config.MaxReplicationClustersPerNamespace = dc.GetIntPropertyFnFilteredByNamespace(1)
+	s.handler = s.newHandler()
+
+	namespace := s.getRandomNamespace()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestAllClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:    uuid.New(),
+						Name:  namespace,
+						State: enumspb.NAMESPACE_STATE_REGISTERED,
+					},
+					Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName, cluster.TestAlternativeClusterName},
+					},
+				},
+				IsGlobalNamespace: true,
+			}, nil
+		},
+	).AnyTimes()
+
+	// a namespace above the limit can still be updated as long as its cluster list doesn't change
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	_, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace:  namespace,
+		UpdateInfo: &namespacepb.UpdateNamespaceInfo{Description: "new description"},
+	})
+	s.NoError(err)
+
+	_, err = s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace: namespace,
+		ReplicationConfig: &replicationpb.NamespaceReplicationConfig{
+			Clusters: []*replicationpb.ClusterReplicationConfig{
+				{ClusterName: cluster.TestCurrentClusterName},
+				{ClusterName: cluster.TestAlternativeClusterName},
+			},
+		},
+	})
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+	s.Equal("Total replication cluster count 2 exceeds the max limit: 1", err.Error())
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_AsyncUpdateOverride() {
+	s.config.EnableUpdateWorkflowExecution = dc.GetBoolPropertyFnFilteredByNamespace(false)
+	s.config.EnableUpdateWorkflowExecutionAsyncAccepted = dc.GetBoolPropertyFnFilteredByNamespace(true)
+	s.handler = s.newHandler()
+
+	namespace := s.getRandomNamespace()
+	var persistedOverride *persistencespb.AsyncUpdateOverride
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:   uuid.New(),
+						Name: namespace,
+					},
+					Config: &persistencespb.NamespaceConfig{
+						Retention:           durationpb.New(24 * time.Hour),
+						AsyncUpdateOverride: persistedOverride,
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).AnyTimes()
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			persistedOverride = request.Namespace.Config.AsyncUpdateOverride
+			return nil
+		},
+	).Times(2)
+
+	describe := func() *NamespaceDescription {
+		description, err := s.handler.DescribeNamespaceDetail(context.Background(), &workflowservice.DescribeNamespaceRequest{
+			Namespace: namespace,
+		})
+		s.NoError(err)
+		return description
+	}
+	description := describe()
+	s.True(description.NamespaceInfo.Capabilities.AsyncUpdate)
+	s.Equal(AsyncUpdateSourceClusterDefault, description.AsyncUpdateSource)
+
+	// async updates require sync updates
+	_, err := s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+		WithAsyncUpdateOverride(util.Ptr(true)),
+	)
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+
+	resp, err := s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+		WithAsyncUpdateOverride(util.Ptr(false)),
+	)
+	s.NoError(err)
+	s.False(resp.NamespaceInfo.Capabilities.AsyncUpdate)
+	description = describe()
+	s.False(description.NamespaceInfo.Capabilities.AsyncUpdate)
+	s.Equal(AsyncUpdateSourceNamespaceOverride, description.AsyncUpdateSource)
+
+	// removing the override restores the cluster default
+	resp, err = s.handler.UpdateNamespace(
+		context.Background(),
+		&workflowservice.UpdateNamespaceRequest{Namespace: namespace},
+		WithAsyncUpdateOverride(nil),
+	)
+	s.NoError(err)
+	s.Nil(persistedOverride)
+	s.True(resp.NamespaceInfo.Capabilities.AsyncUpdate)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespaceDetail_Diff() {
+	namespace := s.getRandomNamespace()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil)
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:   uuid.New(),
+				Name: namespace,
+			},
+			Config: &persistencespb.NamespaceConfig{
+				Retention: durationpb.New(24 * time.Hour),
+				BadBinaries: &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{
+					"checksum-1": {Reason: "reason-1"},
+				}},
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters:          []string{cluster.TestCurrentClusterName},
+			},
+		},
+	}, nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil)
+	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	result, err := s.handler.UpdateNamespaceDetail(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace: namespace,
+		Config: &namespacepb.NamespaceConfig{
+			WorkflowExecutionRetentionTtl: durationpb.New(48 * time.Hour),
+			BadBinaries: &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{
+				"checksum-2": {Reason: "reason-2"},
+				"checksum-3": {Reason: "reason-3"},
+			}},
+		},
+	})
+	s.NoError(err)
+	s.NotNil(result.UpdateNamespaceResponse)
+	s.Equal([]NamespaceFieldChange{
+		{Field: "retention", OldValue: "24h0m0s", NewValue: "48h0m0s"},
+		{Field: "bad_binaries.checksum-2", OldValue: "", NewValue: "reason-2"},
+		{Field: "bad_binaries.checksum-3", OldValue: "", NewValue: "reason-3"},
+	}, result.Diff)
+	s.Equal([]NamespaceField{NamespaceFieldRetention, NamespaceFieldBadBinaries}, result.ChangedFields)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDiffNamespaceDetail() {
+	before := &persistencespb.NamespaceDetail{
+		Config: &persistencespb.NamespaceConfig{
+			HistoryArchivalState:         enumspb.ARCHIVAL_STATE_DISABLED,
+			CustomSearchAttributeAliases: map[string]string{"Keyword01": "Customer", "Keyword02": "Order"},
+			BadBinaries: &namespacepb.BadBinaries{Binaries: map[string]*namespacepb.BadBinaryInfo{
+				"checksum-1": {Reason: "reason-1"},
+			}},
+		},
+		ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+			ActiveClusterName: "cluster-a",
+			Clusters:          []string{"cluster-a"},
+		},
+	}
+	after := &persistencespb.NamespaceDetail{
+		Config: &persistencespb.NamespaceConfig{
+			HistoryArchivalState:         enumspb.ARCHIVAL_STATE_ENABLED,
+			HistoryArchivalUri:           "file:///tmp/history",
+			CustomSearchAttributeAliases: map[string]string{"Keyword01": "Customer", "Keyword03": "Payment"},
+		},
+		ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+			ActiveClusterName: "cluster-b",
+			Clusters:          []string{"cluster-a", "cluster-b"},
+			State:             enumspb.REPLICATION_STATE_HANDOVER,
+		},
+	}
+
+	s.Equal([]NamespaceFieldChange{
+		{Field: "history_archival_state", OldValue: "Disabled", NewValue: "Enabled"},
+		{Field: "history_archival_uri", OldValue: "", NewValue: "file:///tmp/history"},
+		{Field: "clusters", OldValue: "cluster-a", NewValue: "cluster-a,cluster-b"},
+		{Field: "active_cluster_name", OldValue: "cluster-a", NewValue: "cluster-b"},
+		{Field: "replication_state", OldValue: "Unspecified", NewValue: "Handover"},
+		{Field: "custom_search_attribute_aliases.Keyword02", OldValue: "Order", NewValue: ""},
+		{Field: "custom_search_attribute_aliases.Keyword03", OldValue: "", NewValue: "Payment"},
+		{Field: "bad_binaries.checksum-1", OldValue: "reason-1", NewValue: ""},
+	}, diffNamespaceDetail(before, after))
+	s.Equal([]NamespaceField{
+		NamespaceFieldHistoryArchival,
+		NamespaceFieldClusters,
+		NamespaceFieldActiveCluster,
+		NamespaceFieldState,
+		NamespaceFieldCustomSearchAttributeAliases,
+		NamespaceFieldBadBinaries,
+	}, changedNamespaceFields(diffNamespaceDetail(before, after)))
+	s.Empty(diffNamespaceDetail(before, copyNamespaceDetailForDiff(before)))
+}
+
+func (s *namespaceHandlerCommonSuite) TestDescribeNamespaceDetail_ArchivalBatchConfig() {
+	namespace := s.getRandomNamespace()
+	batchConfig := &persistencespb.ArchivalBatchConfig{
+		BatchSize:     200,
+		FlushInterval: durationpb.New(time.Minute),
+	}
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:   uuid.New(),
+				Name: namespace,
+			},
+			Config: &persistencespb.NamespaceConfig{
+				Retention:           durationpb.New(24 * time.Hour),
+				ArchivalBatchConfig: batchConfig,
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters:          []string{cluster.TestCurrentClusterName},
+			},
+		},
+	}, nil)
+
+	description, err := s.handler.DescribeNamespaceDetail(context.Background(), &workflowservice.DescribeNamespaceRequest{
+		Namespace: namespace,
+	})
+	s.NoError(err)
+	s.Equal(namespace, description.GetNamespaceInfo().GetName())
+	protoassert.ProtoEqual(s.T(), batchConfig, description.ArchivalBatchConfig)
+}
+
+func (s *namespaceHandlerCommonSuite) TestGetNamespaceActiveCluster() {
+	namespace := s.getRandomNamespace()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: namespace}).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:   uuid.New(),
+				Name: namespace,
+			},
+			Config: &persistencespb.NamespaceConfig{
+				Retention: durationpb.New(24 * time.Hour),
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestAlternativeClusterName,
+				Clusters:          []string{cluster.TestCurrentClusterName, cluster.TestAlternativeClusterName},
+			},
+			FailoverVersion: 12,
+		},
+		IsGlobalNamespace: true,
+	}, nil).Times(2)
+
+	activeCluster, err := s.handler.GetNamespaceActiveCluster(context.Background(), namespace)
+	s.NoError(err)
+	description, err := s.handler.DescribeNamespaceDetail(context.Background(), &workflowservice.DescribeNamespaceRequest{
+		Namespace: namespace,
+	})
+	s.NoError(err)
+
+	s.Equal(cluster.TestAlternativeClusterName, activeCluster.ActiveClusterName)
+	s.Equal(description.GetReplicationConfig().GetActiveClusterName(), activeCluster.ActiveClusterName)
+	var describedClusters []string
+	for _, replicationCluster := range description.GetReplicationConfig().GetClusters() {
+		describedClusters = append(describedClusters, replicationCluster.GetClusterName())
+	}
+	s.Equal(describedClusters, activeCluster.Clusters)
+	s.True(activeCluster.IsGlobalNamespace)
+	s.Equal(description.GetIsGlobalNamespace(), activeCluster.IsGlobalNamespace)
+	s.Equal(int64(12), activeCluster.FailoverVersion)
+	s.Equal(description.GetFailoverVersion(), activeCluster.FailoverVersion)
+
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewNamespaceNotFound("missing"))
+	_, err = s.handler.GetNamespaceActiveCluster(context.Background(), "missing")
+	s.ErrorAs(err, new(*serviceerror.NamespaceNotFound))
+}
+
+func (s *namespaceHandlerCommonSuite) TestDeprecateNamespace_GlobalNamespace() {
+	namespace := s.getRandomNamespace()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:    uuid.New(),
+				Name:  namespace,
+				State: enumspb.NAMESPACE_STATE_REGISTERED,
+			},
+			Config: &persistencespb.NamespaceConfig{
+				Retention: durationpb.New(24 * time.Hour),
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters:          []string{cluster.TestCurrentClusterName, cluster.TestAlternativeClusterName},
+			},
+			ConfigVersion: 3,
+		},
+		IsGlobalNamespace: true,
+	}, nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil)
+	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := s.handler.DeprecateNamespace(context.Background(), &workflowservice.DeprecateNamespaceRequest{
+		Namespace: namespace,
+	})
+	s.NoError(err)
+	s.NotNil(result.DeprecateNamespaceResponse)
+	s.Equal(enumspb.NAMESPACE_STATE_DEPRECATED, result.State)
+	s.Equal(int64(4), result.ConfigVersion)
+	s.True(result.Replicated)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDeprecateNamespace_LocalNamespace() {
+	namespace := s.getRandomNamespace()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:    uuid.New(),
+				Name:  namespace,
+				State: enumspb.NAMESPACE_STATE_REGISTERED,
+			},
+			Config: &persistencespb.NamespaceConfig{
+				Retention: durationpb.New(24 * time.Hour),
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters:          []string{cluster.TestCurrentClusterName},
+			},
+		},
+	}, nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil)
+	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := s.handler.DeprecateNamespace(context.Background(), &workflowservice.DeprecateNamespaceRequest{
+		Namespace: namespace,
+	})
+	s.NoError(err)
+	s.Equal(enumspb.NAMESPACE_STATE_DEPRECATED, result.State)
+	s.Equal(int64(1), result.ConfigVersion)
+	s.False(result.Replicated)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_Undeprecate() {
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+
+	testCases := []struct {
+		name               string
+		allowUndeprecation bool
+		renamedForDeletion bool
+		replicationState   enumspb.ReplicationState
+		oldState           enumspb.NamespaceState
+		newState           enumspb.NamespaceState
+		expectedErr        error
+	}{
+		{
+			name:               "undeprecate",
+			allowUndeprecation: true,
+			oldState:           enumspb.NAMESPACE_STATE_DEPRECATED,
+			newState:           enumspb.NAMESPACE_STATE_REGISTERED,
+		},
+		{
+			name:        "undeprecate not allowed",
+			oldState:    enumspb.NAMESPACE_STATE_DEPRECATED,
+			newState:    enumspb.NAMESPACE_STATE_REGISTERED,
+			expectedErr: &serviceerror.InvalidArgument{},
+		},
+		{
+			name:               "undeprecate in handover",
+			allowUndeprecation: true,
+			replicationState:   enumspb.REPLICATION_STATE_HANDOVER,
+			oldState:           enumspb.NAMESPACE_STATE_DEPRECATED,
+			newState:           enumspb.NAMESPACE_STATE_REGISTERED,
+			expectedErr:        &serviceerror.InvalidArgument{},
+		},
+		{
+			name:               "undeprecate while deleting",
+			allowUndeprecation: true,
+			renamedForDeletion: true,
+			oldState:           enumspb.NAMESPACE_STATE_DEPRECATED,
+			newState:           enumspb.NAMESPACE_STATE_REGISTERED,
+			expectedErr:        &serviceerror.FailedPrecondition{},
+		},
+		{
+			name:               "register deleted",
+			allowUndeprecation: true,
+			oldState:           enumspb.NAMESPACE_STATE_DELETED,
+			newState:           enumspb.NAMESPACE_STATE_REGISTERED,
+			expectedErr:        &serviceerror.InvalidArgument{},
+		},
+		{
+			name:               "deprecate deleted",
+			allowUndeprecation: true,
+			oldState:           enumspb.NAMESPACE_STATE_DELETED,
+			newState:           enumspb.NAMESPACE_STATE_DEPRECATED,
+			expectedErr:        &serviceerror.InvalidArgument{},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.config.AllowNamespaceUndeprecation = dc.GetBoolPropertyFnFilteredByNamespace(tc.allowUndeprecation)
+			s.handler = s.newHandler()
+
+			nsID := uuid.New()
+			nsName := s.getRandomNamespace()
+			if tc.renamedForDeletion {
+				nsName = fmt.Sprintf("%s-deleted-%s", nsName, nsID[:5])
+			}
+			s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+				NotificationVersion: int64(100),
+			}, nil)
+			s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:    nsID,
+						Name:  nsName,
+						State: tc.oldState,
+					},
+					Config: &persistencespb.NamespaceConfig{
+						Retention: durationpb.New(24 * time.Hour),
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+						State:             tc.replicationState,
+					},
+					ConfigVersion: 3,
+				},
+			}, nil)
+			var updateRequest *persistence.UpdateNamespaceRequest
+			if tc.expectedErr == nil {
+				s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+						updateRequest = request
+						return nil
+					},
+				)
+			}
+
+			_, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+				Namespace:  nsName,
+				UpdateInfo: &namespacepb.UpdateNamespaceInfo{State: tc.newState},
+			})
+			if tc.expectedErr != nil {
+				s.IsType(tc.expectedErr, err)
+				return
+			}
+			s.NoError(err)
+			s.Equal(tc.newState, updateRequest.Namespace.Info.State)
+			s.Equal(int64(4), updateRequest.Namespace.ConfigVersion)
+		})
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_VisibilityStore() {
+	nsName := s.getRandomNamespace()
+	nid := uuid.New()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:   nid,
+						Name: nsName,
+					},
+					Config: &persistencespb.NamespaceConfig{
+						Retention: durationpb.New(24 * time.Hour),
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil
+		},
+	).AnyTimes()
+	s.mockVisibilityMgr.EXPECT().HasStoreName("unknown").Return(false).AnyTimes()
+	s.mockVisibilityMgr.EXPECT().HasStoreName("secondary").Return(true).AnyTimes()
+	s.mockVisibilityMgr.EXPECT().GetReadStoreName(gomock.Any()).Return("primary").AnyTimes()
+	request := &workflowservice.UpdateNamespaceRequest{Namespace: nsName}
+
+	// store is not configured
+	_, err := s.handler.UpdateNamespace(context.Background(), request, WithVisibilityStore("unknown", false))
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+
+	// running workflows prevent switching without migration
+	s.mockVisibilityMgr.EXPECT().CountWorkflowExecutions(gomock.Any(), &manager.CountWorkflowExecutionsRequest{
+		NamespaceID: namespace.ID(nid),
+		Namespace:   namespace.Name(nsName),
+		Query:       `ExecutionStatus = "Running"`,
+	}).Return(&manager.CountWorkflowExecutionsResponse{Count: 3}, nil)
+	_, err = s.handler.UpdateNamespace(context.Background(), request, WithVisibilityStore("secondary", false))
+	var failedPrecondition *serviceerror.FailedPrecondition
+	s.ErrorAs(err, &failedPrecondition)
+
+	// migration flag allows switching with running workflows
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			s.Equal("secondary", request.Namespace.Config.VisibilityStore)
+			return nil
+		},
+	)
+	_, err = s.handler.UpdateNamespace(context.Background(), request, WithVisibilityStore("secondary", true))
+	s.NoError(err)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDescribeNamespaceDetail_OlderConfigSchemaVersion() {
+	namespace := s.getRandomNamespace()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:   uuid.New(),
+				Name: namespace,
+			},
+			// Config written before schema versioning, without any optional fields set.
+			Config: &persistencespb.NamespaceConfig{
+				Retention: durationpb.New(24 * time.Hour),
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters:          []string{cluster.TestCurrentClusterName},
+			},
+		},
+	}, nil)
+
+	description, err := s.handler.DescribeNamespaceDetail(context.Background(), &workflowservice.DescribeNamespaceRequest{
+		Namespace: namespace,
+	})
+	s.NoError(err)
+	s.NotNil(description.GetConfig().GetBadBinaries().GetBinaries())
+	s.Nil(description.ArchivalBatchConfig)
+	s.Empty(description.VisibilityStore)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDeprecateNamespace_ConfigSchemaVersion() {
+	testCases := []struct {
+		name            string
+		storedVersion   int32
+		expectedVersion int32
+	}{
+		{
+			name:            "older version is upgraded",
+			storedVersion:   0,
+			expectedVersion: namespaceConfigSchemaVersion,
+		},
+		{
+			name:            "current version is kept",
+			storedVersion:   namespaceConfigSchemaVersion,
+			expectedVersion: namespaceConfigSchemaVersion,
+		},
+		{
+			name:            "newer version is not downgraded",
+			storedVersion:   namespaceConfigSchemaVersion + 1,
+			expectedVersion: namespaceConfigSchemaVersion + 1,
+		},
+	}
+
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			namespace := s.getRandomNamespace()
+			s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+				NotificationVersion: int64(100),
+			}, nil)
+			s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:    uuid.New(),
+						Name:  namespace,
+						State: enumspb.NAMESPACE_STATE_REGISTERED,
+					},
+					Config: &persistencespb.NamespaceConfig{
+						Retention:     durationpb.New(24 * time.Hour),
+						SchemaVersion: tc.storedVersion,
+					},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName},
+					},
+				},
+			}, nil)
+			s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+					s.Equal(tc.expectedVersion, request.Namespace.Config.SchemaVersion)
+					s.NotNil(request.Namespace.Config.BadBinaries)
+					return nil
+				})
+
+			_, err := s.handler.DeprecateNamespace(context.Background(), &workflowservice.DeprecateNamespaceRequest{
+				Namespace: namespace,
+			})
+			s.NoError(err)
+		})
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestDescribeNamespace_RateLimited() {
+	s.config.DescribeNamespacePerNamespaceRPS = dc.GetIntPropertyFnFilteredByNamespace(1)
+	s.handler = s.newHandler()
+	s.handler.describeNamespacePerNamespaceRateLimiter = quotas.NewRequestRateLimiterAdapter(quotas.NewDefaultIncomingRateLimiter(func() float64 { return 0 }))
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Times(0)
+
+	resp, err := s.handler.DescribeNamespace(context.Background(), &workflowservice.DescribeNamespaceRequest{
+		Namespace: s.getRandomNamespace(),
+	})
+	s.Nil(resp)
+	var resourceExhausted *serviceerror.ResourceExhausted
+	s.ErrorAs(err, &resourceExhausted)
+	s.Equal(enumspb.RESOURCE_EXHAUSTED_SCOPE_NAMESPACE, resourceExhausted.Scope)
+
+	s.config.DescribeNamespacePerNamespaceRPS = dc.GetIntPropertyFnFilteredByNamespace(0)
+	s.config.DescribeNamespaceRPS = dc.GetIntPropertyFn(1)
+	s.handler = s.newHandler()
+	s.handler.describeNamespaceRateLimiter = quotas.NewDefaultIncomingRateLimiter(func() float64 { return 0 })
+	_, err = s.handler.DescribeNamespace(context.Background(), &workflowservice.DescribeNamespaceRequest{
+		Namespace: s.getRandomNamespace(),
+	})
+	s.ErrorAs(err, &resourceExhausted)
+	s.Equal(enumspb.RESOURCE_EXHAUSTED_SCOPE_SYSTEM, resourceExhausted.Scope)
+}
+
+func (s *namespaceHandlerCommonSuite) TestListNamespaces_RateLimited() {
+	s.config.ListNamespacesRPS = dc.GetIntPropertyFn(1)
+	s.handler = s.newHandler()
+	s.handler.listNamespacesRateLimiter = quotas.NewDefaultIncomingRateLimiter(func() float64 { return 0 })
+	s.mockMetadataMgr.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Times(0)
+
+	resp, err := s.handler.ListNamespaces(context.Background(), &workflowservice.ListNamespacesRequest{})
+	s.Nil(resp)
+	var resourceExhausted *serviceerror.ResourceExhausted
+	s.ErrorAs(err, &resourceExhausted)
+	s.Equal(enumspb.RESOURCE_EXHAUSTED_CAUSE_RPS_LIMIT, resourceExhausted.Cause)
+
+	// A limit of zero disables the limiter.
+	s.config.ListNamespacesRPS = dc.GetIntPropertyFn(0)
+	s.mockMetadataMgr.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Return(&persistence.ListNamespacesResponse{}, nil)
+	_, err = s.handler.ListNamespaces(context.Background(), &workflowservice.ListNamespacesRequest{})
+	s.NoError(err)
+}
+
+func (s *namespaceHandlerCommonSuite) TestListNamespaces_SortBy() {
+	newNamespace := func(name string, state enumspb.NamespaceState) *persistence.GetNamespaceResponse {
+		return &persistence.GetNamespaceResponse{
+			Namespace: &persistencespb.NamespaceDetail{
+				Info:              &persistencespb.NamespaceInfo{Id: uuid.New(), Name: name, State: state},
+				Config:            &persistencespb.NamespaceConfig{},
+				ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+			},
+		}
+	}
+	s.mockMetadataMgr.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Return(&persistence.ListNamespacesResponse{
+		Namespaces: []*persistence.GetNamespaceResponse{
+			newNamespace("namespace-b", enumspb.NAMESPACE_STATE_REGISTERED),
+			newNamespace("namespace-c", enumspb.NAMESPACE_STATE_DEPRECATED),
+			newNamespace("namespace-a", enumspb.NAMESPACE_STATE_DEPRECATED),
+		},
+		NextPageToken: []byte("next-token"),
+	}, nil).Times(3)
+	names := func(resp *workflowservice.ListNamespacesResponse) []string {
+		var names []string
+		for _, ns := range resp.Namespaces {
+			names = append(names, ns.GetNamespaceInfo().GetName())
+		}
+		return names
+	}
+
+	resp, err := s.handler.ListNamespaces(context.Background(), &workflowservice.ListNamespacesRequest{},
+		WithSortBy(NamespaceSortByName, false))
+	s.NoError(err)
+	s.Equal([]string{"namespace-a", "namespace-b", "namespace-c"}, names(resp))
+	// the page token of the store is kept
+	s.Equal([]byte("next-token"), resp.NextPageToken)
+
+	resp, err = s.handler.ListNamespaces(context.Background(), &workflowservice.ListNamespacesRequest{},
+		WithSortBy(NamespaceSortByName, true))
+	s.NoError(err)
+	s.Equal([]string{"namespace-c", "namespace-b", "namespace-a"}, names(resp))
+
+	resp, err = s.handler.ListNamespaces(context.Background(), &workflowservice.ListNamespacesRequest{},
+		WithSortBy(NamespaceSortByState, false))
+	s.NoError(err)
+	s.Equal([]string{"namespace-b", "namespace-a", "namespace-c"}, names(resp))
+
+	_, err = s.handler.ListNamespaces(context.Background(), &workflowservice.ListNamespacesRequest{},
+		WithSortBy("CreationTime", false))
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+}
+
+func (s *namespaceHandlerCommonSuite) TestListNamespaces_States() {
+	s.mockMetadataMgr.EXPECT().ListNamespaces(gomock.Any(), &persistence.ListNamespacesRequest{
+		PageSize:      10,
+		NextPageToken: []byte("token"),
+		States:        []enumspb.NamespaceState{enumspb.NAMESPACE_STATE_DEPRECATED},
+	}).Return(&persistence.ListNamespacesResponse{NextPageToken: []byte("next-token")}, nil)
+	// a page without any namespace in the states still has the next page token
+	resp, err := s.handler.ListNamespaces(
+		context.Background(),
+		&workflowservice.ListNamespacesRequest{PageSize: 10, NextPageToken: []byte("token")},
+		WithNamespaceStates(enumspb.NAMESPACE_STATE_DEPRECATED),
+	)
+	s.NoError(err)
+	s.Empty(resp.Namespaces)
+	s.Equal([]byte("next-token"), resp.NextPageToken)
+
+	// listing deleted namespaces includes them without the IncludeDeleted filter
+	s.mockMetadataMgr.EXPECT().ListNamespaces(gomock.Any(), &persistence.ListNamespacesRequest{
+		PageSize:       100,
+		IncludeDeleted: true,
+		States:         []enumspb.NamespaceState{enumspb.NAMESPACE_STATE_REGISTERED, enumspb.NAMESPACE_STATE_DELETED},
+	}).Return(&persistence.ListNamespacesResponse{}, nil)
+	_, err = s.handler.ListNamespaces(
+		context.Background(),
+		&workflowservice.ListNamespacesRequest{},
+		WithNamespaceStates(enumspb.NAMESPACE_STATE_REGISTERED, enumspb.NAMESPACE_STATE_DELETED),
+	)
+	s.NoError(err)
+}
+
+func (s *namespaceHandlerCommonSuite) TestListNamespaces_NamePrefix() {
+	s.mockMetadataMgr.EXPECT().ListNamespaces(gomock.Any(), &persistence.ListNamespacesRequest{
+		PageSize:   100,
+		NamePrefix: "orders-",
+	}).Return(&persistence.ListNamespacesResponse{NextPageToken: []byte("next-token")}, nil)
+
+	resp, err := s.handler.ListNamespaces(
+		context.Background(),
+		&workflowservice.ListNamespacesRequest{},
+		WithNamePrefix("orders-"),
+	)
+	s.NoError(err)
+	s.Empty(resp.Namespaces)
+	s.Equal([]byte("next-token"), resp.NextPageToken)
+}
+
+func (s *namespaceHandlerCommonSuite) TestCountNamespaces() {
+	newNamespace := func(name string, state enumspb.NamespaceState, isGlobal bool) *namespace.Namespace {
+		return namespace.NewNamespaceForTest(
+			&persistencespb.NamespaceInfo{Id: name + "-id", Name: name, State: state},
+			nil,
+			isGlobal,
+			nil,
+			0,
+		)
+	}
+	s.mockNamespaceRegistry.EXPECT().GetAllNamespaces().Return([]*namespace.Namespace{
+		newNamespace("orders-1", enumspb.NAMESPACE_STATE_REGISTERED, true),
+		newNamespace("orders-2", enumspb.NAMESPACE_STATE_REGISTERED, true),
+		newNamespace("orders-3", enumspb.NAMESPACE_STATE_REGISTERED, false),
+		newNamespace("orders-4", enumspb.NAMESPACE_STATE_DELETED, false),
+		newNamespace("Orders-5", enumspb.NAMESPACE_STATE_REGISTERED, true),
+		newNamespace("payments", enumspb.NAMESPACE_STATE_DEPRECATED, false),
+	}).Times(2)
+
+	counts, err := s.handler.CountNamespaces(context.Background(), &CountNamespacesFilter{NamePrefix: "orders-"})
+	s.NoError(err)
+	s.Equal(&NamespaceCounts{
+		Total: 4,
+		ByState: map[enumspb.NamespaceState]int64{
+			enumspb.NAMESPACE_STATE_REGISTERED: 3,
+			enumspb.NAMESPACE_STATE_DELETED:    1,
+		},
+		Global: 2,
+		Local:  2,
+	}, counts)
+
+	counts, err = s.handler.CountNamespaces(context.Background(), nil)
+	s.NoError(err)
+	s.Equal(int64(6), counts.Total)
+	s.Equal(int64(1), counts.ByState[enumspb.NAMESPACE_STATE_DEPRECATED])
+}
+
+func (s *namespaceHandlerCommonSuite) TestCountNamespaces_RateLimited() {
+	s.config.ListNamespacesRPS = dc.GetIntPropertyFn(1)
+	s.handler = s.newHandler()
+	s.handler.listNamespacesRateLimiter = quotas.NewDefaultIncomingRateLimiter(func() float64 { return 0 })
+	s.mockNamespaceRegistry.EXPECT().GetAllNamespaces().Times(0)
+
+	_, err := s.handler.CountNamespaces(context.Background(), nil)
+	var resourceExhausted *serviceerror.ResourceExhausted
+	s.ErrorAs(err, &resourceExhausted)
+}
+
+func (s *namespaceHandlerCommonSuite) TestOperationTimeout() {
+	s.config.NamespaceOperationTimeout = dc.GetDurationPropertyFn(50 * time.Millisecond)
+	s.handler = s.newHandler()
+
+	// the metadata manager blocks until the context it gets is done
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	).Times(2)
+
+	_, err := s.handler.DescribeNamespace(context.Background(), &workflowservice.DescribeNamespaceRequest{
+		Namespace: "blocked-namespace",
+	})
+	var deadlineExceeded *serviceerror.DeadlineExceeded
+	s.ErrorAs(err, &deadlineExceeded)
+
+	// the deadline of the caller is kept
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = s.handler.DescribeNamespace(ctx, &workflowservice.DescribeNamespaceRequest{
+		Namespace: "blocked-namespace",
+	})
+	s.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDescribeNamespace_Cache() {
+	s.config.DescribeNamespaceCacheEnabled = dc.GetBoolPropertyFn(true)
+	s.handler = s.newHandler()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+
+	namespace := s.getRandomNamespace()
+	getNamespaceResponse := func() *persistence.GetNamespaceResponse {
+		return &persistence.GetNamespaceResponse{
+			Namespace: &persistencespb.NamespaceDetail{
+				Info: &persistencespb.NamespaceInfo{
+					Id:    uuid.New(),
+					Name:  namespace,
+					State: enumspb.NAMESPACE_STATE_REGISTERED,
+				},
+				Config: &persistencespb.NamespaceConfig{
+					Retention: durationpb.New(24 * time.Hour),
+				},
+				ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+					ActiveClusterName: cluster.TestCurrentClusterName,
+					Clusters:          []string{cluster.TestCurrentClusterName},
+				},
+			},
+		}
+	}
+	describeRequest := &workflowservice.DescribeNamespaceRequest{Namespace: namespace}
+
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(getNamespaceResponse(), nil).Times(1)
+	first, err := s.handler.DescribeNamespace(context.Background(), describeRequest)
+	s.NoError(err)
+	second, err := s.handler.DescribeNamespace(context.Background(), describeRequest)
+	s.NoError(err)
+	protoassert.ProtoEqual(s.T(), first, second)
+
+	// Updating the namespace invalidates the cached response.
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(getNamespaceResponse(), nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil)
+	_, err = s.handler.DeprecateNamespace(context.Background(), &workflowservice.DeprecateNamespaceRequest{
+		Namespace: namespace,
+	})
+	s.NoError(err)
+
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(getNamespaceResponse(), nil).Times(1)
+	_, err = s.handler.DescribeNamespace(context.Background(), describeRequest)
+	s.NoError(err)
+
+	// Cached responses still count against the rate limits.
+	s.config.DescribeNamespaceRPS = dc.GetIntPropertyFn(1)
+	s.handler.describeNamespaceRateLimiter = quotas.NewDefaultIncomingRateLimiter(func() float64 { return 0 })
+	_, err = s.handler.DescribeNamespace(context.Background(), describeRequest)
+	var resourceExhausted *serviceerror.ResourceExhausted
+	s.ErrorAs(err, &resourceExhausted)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDescribeNamespace_CacheInvalidatedByWorkflowRuleChange() {
+	s.config.DescribeNamespaceCacheEnabled = dc.GetBoolPropertyFn(true)
+	s.handler = s.newHandler()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+
+	namespace := s.getRandomNamespace()
+	detail := &persistencespb.NamespaceDetail{
+		Info: &persistencespb.NamespaceInfo{
+			Id:    uuid.New(),
+			Name:  namespace,
+			State: enumspb.NAMESPACE_STATE_REGISTERED,
+		},
+		Config: &persistencespb.NamespaceConfig{
+			Retention: durationpb.New(24 * time.Hour),
+			WorkflowRules: map[string]*rulespb.WorkflowRule{
+				"rule-a": {Spec: &rulespb.WorkflowRuleSpec{Id: "rule-a", VisibilityQuery: "WorkflowType = 'workflow'"}},
+			},
+		},
+		ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+			ActiveClusterName: cluster.TestCurrentClusterName,
+			Clusters:          []string{cluster.TestCurrentClusterName},
+		},
+	}
+	getNamespaceCalls := 0
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			getNamespaceCalls++
+			return &persistence.GetNamespaceResponse{Namespace: common.CloneProto(detail)}, nil
+		},
+	).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(1),
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			detail = request.Namespace
+			return nil
+		},
+	).Times(1)
+	describeRequest := &workflowservice.DescribeNamespaceRequest{Namespace: namespace}
+
+	description, err := s.handler.DescribeNamespaceDetail(context.Background(), describeRequest, IncludeWorkflowRules())
+	s.NoError(err)
+	s.Len(description.WorkflowRules, 1)
+	_, err = s.handler.DescribeNamespaceDetail(context.Background(), describeRequest, IncludeWorkflowRules())
+	s.NoError(err)
+	s.Equal(1, getNamespaceCalls)
+
+	_, err = s.handler.DeleteWorkflowRule(context.Background(), "rule-a", namespace)
+	s.NoError(err)
+
+	// the stale description with the deleted rule is not served
+	getNamespaceCalls = 0
+	description, err = s.handler.DescribeNamespaceDetail(context.Background(), describeRequest, IncludeWorkflowRules())
+	s.NoError(err)
+	s.Empty(description.WorkflowRules)
+	s.Equal(1, getNamespaceCalls)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDescribeNamespace_CacheNotPopulatedByDescriptionReadBeforeMutation() {
+	s.config.DescribeNamespaceCacheEnabled = dc.GetBoolPropertyFn(true)
+	s.handler = s.newHandler()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+
+	namespace := s.getRandomNamespace()
+	detail := &persistencespb.NamespaceDetail{
+		Info: &persistencespb.NamespaceInfo{
+			Id:    uuid.New(),
+			Name:  namespace,
+			State: enumspb.NAMESPACE_STATE_REGISTERED,
+		},
+		Config: &persistencespb.NamespaceConfig{
+			Retention: durationpb.New(24 * time.Hour),
+			WorkflowRules: map[string]*rulespb.WorkflowRule{
+				"rule-a": {Spec: &rulespb.WorkflowRuleSpec{Id: "rule-a", VisibilityQuery: "WorkflowType = 'workflow'"}},
+			},
+		},
+		ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+			ActiveClusterName: cluster.TestCurrentClusterName,
+			Clusters:          []string{cluster.TestCurrentClusterName},
+		},
+	}
+	getNamespaceCalls := 0
+	var concurrentUpdate func()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			getNamespaceCalls++
+			response := &persistence.GetNamespaceResponse{Namespace: common.CloneProto(detail)}
+			if update := concurrentUpdate; update != nil {
+				// the namespace is updated after the describe read it, and before the describe caches it
+				concurrentUpdate = nil
+				update()
+			}
+			return response, nil
+		},
+	).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(1),
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			detail = request.Namespace
+			return nil
+		},
+	).Times(1)
+	describeRequest := &workflowservice.DescribeNamespaceRequest{Namespace: namespace}
+
+	concurrentUpdate = func() {
+		_, err := s.handler.DeleteWorkflowRule(context.Background(), "rule-a", namespace)
+		s.NoError(err)
+	}
+	description, err := s.handler.DescribeNamespaceDetail(context.Background(), describeRequest, IncludeWorkflowRules())
+	s.NoError(err)
+	s.Len(description.WorkflowRules, 1)
+
+	// the description read before the update is not cached
+	getNamespaceCalls = 0
+	description, err = s.handler.DescribeNamespaceDetail(context.Background(), describeRequest, IncludeWorkflowRules())
+	s.NoError(err)
+	s.Empty(description.WorkflowRules)
+	s.Equal(1, getNamespaceCalls)
+
+	// the description read after the update is
+	description, err = s.handler.DescribeNamespaceDetail(context.Background(), describeRequest, IncludeWorkflowRules())
+	s.NoError(err)
+	s.Empty(description.WorkflowRules)
+	s.Equal(1, getNamespaceCalls)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDescribeNamespaceDetail_IncludeWorkflowRules() {
+	s.config.DescribeNamespaceCacheEnabled = dc.GetBoolPropertyFn(true)
+	s.handler = s.newHandler()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+
+	namespace := s.getRandomNamespace()
+	createTime := timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	updateTime := timestamppb.New(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	newRule := func(ruleID string) *rulespb.WorkflowRule {
+		return &rulespb.WorkflowRule{
+			Spec:              &rulespb.WorkflowRuleSpec{Id: ruleID},
+			CreateTime:        createTime,
+			CreatedByIdentity: "creator",
+			Description:       "description of " + ruleID,
+		}
+	}
+	// the namespace is only read once, the second description is served from the cache
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:    uuid.New(),
+				Name:  namespace,
+				State: enumspb.NAMESPACE_STATE_REGISTERED,
+			},
+			Config: &persistencespb.NamespaceConfig{
+				Retention: durationpb.New(24 * time.Hour),
+				WorkflowRules: map[string]*rulespb.WorkflowRule{
+					"rule-c": newRule("rule-c"),
+					"rule-a": newRule("rule-a"),
+					"rule-b": newRule("rule-b"),
+				},
+				WorkflowRuleUpdates: map[string]*persistencespb.WorkflowRuleUpdate{
+					"rule-b": {LastUpdateTime: updateTime, LastUpdatedByIdentity: "updater"},
+				},
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters:          []string{cluster.TestCurrentClusterName},
+			},
+		},
+	}, nil).Times(1)
+	describeRequest := &workflowservice.DescribeNamespaceRequest{Namespace: namespace}
+
+	description, err := s.handler.DescribeNamespaceDetail(context.Background(), describeRequest)
+	s.NoError(err)
+	s.Nil(description.WorkflowRules)
+
+	description, err = s.handler.DescribeNamespaceDetail(context.Background(), describeRequest, IncludeWorkflowRules())
+	s.NoError(err)
+	s.Len(description.WorkflowRules, 3)
+	for i, ruleID := range []string{"rule-a", "rule-b", "rule-c"} {
+		rule := description.WorkflowRules[i]
+		s.Equal(ruleID, rule.GetSpec().GetId())
+		protoassert.ProtoEqual(s.T(), createTime, rule.GetCreateTime())
+		s.Equal("creator", rule.GetCreatedByIdentity())
+		s.Equal("description of "+ruleID, rule.GetDescription())
+	}
+	protoassert.ProtoEqual(s.T(), updateTime, description.WorkflowRules[1].LastUpdateTime)
+	s.Equal("updater", description.WorkflowRules[1].LastUpdatedByIdentity)
+	s.Nil(description.WorkflowRules[0].LastUpdateTime)
+}
+
+func (s *namespaceHandlerCommonSuite) TestGetNamespaceReplicationStatus() {
+	namespace := s.getRandomNamespace()
+	nid := uuid.New()
+	lastFailoverTime := timestamppb.New(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:   nid,
+				Name: namespace,
+			},
+			Config: &persistencespb.NamespaceConfig{},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: "standby",
+				Clusters:          []string{"active", "standby"},
+				State:             enumspb.REPLICATION_STATE_HANDOVER,
+				FailoverLog: []*persistencespb.FailoverStatus{
+					{FailoverTime: timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), FailoverVersion: 1},
+				},
+				FailoverHistory: []*persistencespb.FailoverStatus{
+					{FailoverTime: timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), FailoverVersion: 1},
+					{FailoverTime: lastFailoverTime, FailoverVersion: 12},
+				},
+			},
+			FailoverVersion: 12,
+		},
+		IsGlobalNamespace: true,
+	}, nil)
+
+	status, err := s.handler.GetNamespaceReplicationStatus(context.Background(), namespace)
+	s.NoError(err)
+	s.Equal(&NamespaceReplicationSummary{
+		NamespaceID:       nid,
+		Namespace:         namespace,
+		IsGlobalNamespace: true,
+		ActiveClusterName: "standby",
+		State:             enumspb.REPLICATION_STATE_HANDOVER,
+		Clusters:          []string{"active", "standby"},
+		FailoverVersion:   12,
+		LastFailover: &replicationpb.FailoverStatus{
+			FailoverTime:    lastFailoverTime,
+			FailoverVersion: 12,
+		},
+	}, status)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDescribeNamespaceReplicationStatus() {
+	namespace := s.getRandomNamespace()
+	nid := uuid.New()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return("active").AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:   nid,
+				Name: namespace,
+			},
+			Config: &persistencespb.NamespaceConfig{},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: "active",
+				Clusters:          []string{"active", "standby", "new-standby"},
+			},
+			ConfigVersion:   3,
+			FailoverVersion: 101,
+		},
+		IsGlobalNamespace: true,
+	}, nil)
+	s.mockProducer.EXPECT().GetAckLevels(gomock.Any()).Return(map[string]int64{"standby": 10}, nil)
+	s.mockProducer.EXPECT().GetReplicationMessages(gomock.Any(), int64(10), gomock.Any()).Return([]*replicationspb.ReplicationTask{
+		{
+			TaskType:   enumsspb.REPLICATION_TASK_TYPE_NAMESPACE_TASK,
+			Attributes: &replicationspb.ReplicationTask_NamespaceTaskAttributes{NamespaceTaskAttributes: &replicationspb.NamespaceTaskAttributes{Id: nid}},
+		},
+		{
+			TaskType:   enumsspb.REPLICATION_TASK_TYPE_NAMESPACE_TASK,
+			Attributes: &replicationspb.ReplicationTask_NamespaceTaskAttributes{NamespaceTaskAttributes: &replicationspb.NamespaceTaskAttributes{Id: uuid.New()}},
+		},
+	}, int64(12), nil)
+	s.mockProducer.EXPECT().GetReplicationMessages(gomock.Any(), int64(12), gomock.Any()).Return(nil, int64(12), nil)
+
+	status, err := s.handler.DescribeNamespaceReplicationStatus(context.Background(), namespace)
+	s.NoError(err)
+	s.Equal(nid, status.NamespaceID)
+	s.Equal(int64(3), status.ConfigVersion)
+	s.Equal(int64(101), status.FailoverVersion)
+	s.Equal([]*ClusterReplicationStatus{
+		{
+			ClusterName:                "active",
+			PendingTaskCount:           util.Ptr(int64(0)),
+			LastAppliedFailoverVersion: util.Ptr(int64(101)),
+		},
+		{
+			ClusterName:      "standby",
+			AckLevel:         util.Ptr(int64(10)),
+			PendingTaskCount: util.Ptr(int64(1)),
+		},
+		{
+			// never polled the queue, so nothing is known
+			ClusterName: "new-standby",
+		},
+	}, status.Clusters)
+}
+
+func (s *namespaceHandlerCommonSuite) getRandomNamespace() string {
+	return "namespace" + uuid.New()
+}
+
+func (s *namespaceHandlerCommonSuite) TestListWorkflowRulesAcrossNamespaces() {
+	activityPause := &rulespb.WorkflowRuleAction{
+		Variant: &rulespb.WorkflowRuleAction_ActivityPause{ActivityPause: &rulespb.WorkflowRuleAction_ActionActivityPause{}},
+	}
+	newRule := func(id string, actions ...*rulespb.WorkflowRuleAction) *rulespb.WorkflowRule {
+		return &rulespb.WorkflowRule{Spec: &rulespb.WorkflowRuleSpec{Id: id, Actions: actions}}
+	}
+	newNamespace := func(id, name string, rules ...*rulespb.WorkflowRule) *persistence.GetNamespaceResponse {
+		config := &persistencespb.NamespaceConfig{}
+		if len(rules) > 0 {
+			config.WorkflowRules = make(map[string]*rulespb.WorkflowRule, len(rules))
+			for _, rule := range rules {
+				config.WorkflowRules[rule.Spec.Id] = rule
+			}
+		}
+		return &persistence.GetNamespaceResponse{
+			Namespace: &persistencespb.NamespaceDetail{
+				Info:   &persistencespb.NamespaceInfo{Id: id, Name: name},
+				Config: config,
+			},
+		}
+	}
+	pauseRule := newRule("pause", activityPause)
+	noActionRule := newRule("no-action")
+	otherPauseRule := newRule("another-pause", activityPause)
+
+	s.mockMetadataMgr.EXPECT().ListNamespaces(gomock.Any(), &persistence.ListNamespacesRequest{
+		PageSize:      2,
+		NextPageToken: []byte("token"),
+	}).Return(&persistence.ListNamespacesResponse{
+		Namespaces: []*persistence.GetNamespaceResponse{
+			newNamespace("id-b", "ns-b", pauseRule, noActionRule),
+			newNamespace("id-a", "ns-a", otherPauseRule),
+			newNamespace("id-c", "ns-c"),
+		},
+		NextPageToken: []byte("next-token"),
+	}, nil).Times(2)
+
+	resp, err := s.handler.ListWorkflowRulesAcrossNamespaces(context.Background(), &ListWorkflowRulesAcrossNamespacesRequest{
+		NamespacePageSize: 2,
+		NextPageToken:     []byte("token"),
+	})
+	s.NoError(err)
+	s.Equal([]byte("next-token"), resp.NextPageToken)
+	s.Equal([]*NamespaceWorkflowRule{
+		{NamespaceID: "id-a", Namespace: "ns-a", Rule: otherPauseRule},
+		{NamespaceID: "id-b", Namespace: "ns-b", Rule: noActionRule},
+		{NamespaceID: "id-b", Namespace: "ns-b", Rule: pauseRule},
+	}, resp.Rules)
+
+	resp, err = s.handler.ListWorkflowRulesAcrossNamespaces(context.Background(), &ListWorkflowRulesAcrossNamespacesRequest{
+		NamespacePageSize: 2,
+		NextPageToken:     []byte("token"),
+		ActionType:        WorkflowRuleActionTypeActivityPause,
+	})
+	s.NoError(err)
+	s.Equal([]*NamespaceWorkflowRule{
+		{NamespaceID: "id-a", Namespace: "ns-a", Rule: otherPauseRule},
+		{NamespaceID: "id-b", Namespace: "ns-b", Rule: pauseRule},
+	}, resp.Rules)
+}
+
+func (s *namespaceHandlerCommonSuite) TestListWorkflowRulesAcrossNamespaces_RateLimited() {
+	s.config.ListWorkflowRulesAcrossNamespacesRPS = dc.GetIntPropertyFn(0)
+	s.handler = s.newHandler()
+	s.mockMetadataMgr.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Times(0)
+
+	resp, err := s.handler.ListWorkflowRulesAcrossNamespaces(context.Background(), &ListWorkflowRulesAcrossNamespacesRequest{})
+	s.Nil(resp)
+	var resourceExhausted *serviceerror.ResourceExhausted
+	s.ErrorAs(err, &resourceExhausted)
+	s.Equal(enumspb.RESOURCE_EXHAUSTED_CAUSE_RPS_LIMIT, resourceExhausted.Cause)
+}
+
+func (s *namespaceHandlerCommonSuite) TestListNamespacesByArchivalState() {
+	newNamespace := func(name string, historyState enumspb.ArchivalState, visibilityState enumspb.ArchivalState) *persistence.GetNamespaceResponse {
+		return &persistence.GetNamespaceResponse{
+			Namespace: &persistencespb.NamespaceDetail{
+				Info: &persistencespb.NamespaceInfo{Id: "id-" + name, Name: name},
+				Config: &persistencespb.NamespaceConfig{
+					HistoryArchivalState:    historyState,
+					HistoryArchivalUri:      "file:///history/" + name,
+					VisibilityArchivalState: visibilityState,
+					VisibilityArchivalUri:   "file:///visibility/" + name,
+				},
+			},
+		}
+	}
+	s.mockMetadataMgr.EXPECT().ListNamespaces(gomock.Any(), &persistence.ListNamespacesRequest{
+		PageSize:      archivalAuditNamespacePageSize,
+		NextPageToken: []byte("token"),
+	}).Return(&persistence.ListNamespacesResponse{
+		Namespaces: []*persistence.GetNamespaceResponse{
+			newNamespace("ns-c", enumspb.ARCHIVAL_STATE_DISABLED, enumspb.ARCHIVAL_STATE_ENABLED),
+			newNamespace("ns-a", enumspb.ARCHIVAL_STATE_DISABLED, enumspb.ARCHIVAL_STATE_DISABLED),
+			newNamespace("ns-b", enumspb.ARCHIVAL_STATE_ENABLED, enumspb.ARCHIVAL_STATE_DISABLED),
+		},
+		NextPageToken: []byte("next-token"),
+	}, nil).Times(2)
+
+	resp, err := s.handler.ListNamespacesByArchivalState(
+		context.Background(),
+		enumspb.ARCHIVAL_STATE_DISABLED,
+		enumspb.ARCHIVAL_STATE_UNSPECIFIED,
+		[]byte("token"),
+	)
+	s.NoError(err)
+	s.Equal([]byte("next-token"), resp.NextPageToken)
+	s.Equal([]*NamespaceArchivalConfig{
+		{
+			NamespaceID:             "id-ns-a",
+			Namespace:               "ns-a",
+			HistoryArchivalState:    enumspb.ARCHIVAL_STATE_DISABLED,
+			HistoryArchivalURI:      "file:///history/ns-a",
+			VisibilityArchivalState: enumspb.ARCHIVAL_STATE_DISABLED,
+			VisibilityArchivalURI:   "file:///visibility/ns-a",
+		},
+		{
+			NamespaceID:             "id-ns-c",
+			Namespace:               "ns-c",
+			HistoryArchivalState:    enumspb.ARCHIVAL_STATE_DISABLED,
+			HistoryArchivalURI:      "file:///history/ns-c",
+			VisibilityArchivalState: enumspb.ARCHIVAL_STATE_ENABLED,
+			VisibilityArchivalURI:   "file:///visibility/ns-c",
+		},
+	}, resp.Namespaces)
+
+	resp, err = s.handler.ListNamespacesByArchivalState(
+		context.Background(),
+		enumspb.ARCHIVAL_STATE_DISABLED,
+		enumspb.ARCHIVAL_STATE_DISABLED,
+		[]byte("token"),
+	)
+	s.NoError(err)
+	s.Len(resp.Namespaces, 1)
+	s.Equal("ns-a", resp.Namespaces[0].Namespace)
+}
+
+func (s *namespaceHandlerCommonSuite) TestListNamespacesByArchivalState_RateLimited() {
+	s.config.ListNamespacesByArchivalStateRPS = dc.GetIntPropertyFn(0)
+	s.handler = s.newHandler()
+	s.mockMetadataMgr.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Times(0)
+
+	resp, err := s.handler.ListNamespacesByArchivalState(
+		context.Background(),
+		enumspb.ARCHIVAL_STATE_DISABLED,
+		enumspb.ARCHIVAL_STATE_UNSPECIFIED,
+		nil,
+	)
+	s.Nil(resp)
+	var resourceExhausted *serviceerror.ResourceExhausted
+	s.ErrorAs(err, &resourceExhausted)
+	s.Equal(enumspb.RESOURCE_EXHAUSTED_CAUSE_RPS_LIMIT, resourceExhausted.Cause)
+}
+
+func (s *namespaceHandlerCommonSuite) TestValidateReplicationTransition() {
+	replicationConfig := func(
+		activeCluster string,
+		state enumspb.ReplicationState,
+		clusters ...string,
+	) *persistencespb.NamespaceReplicationConfig {
+		return &persistencespb.NamespaceReplicationConfig{
+			ActiveClusterName: activeCluster,
+			Clusters:          clusters,
+			State:             state,
+		}
+	}
+	normal := enumspb.REPLICATION_STATE_NORMAL
+	handover := enumspb.REPLICATION_STATE_HANDOVER
+	unspecified := enumspb.REPLICATION_STATE_UNSPECIFIED
+
+	testCases := []struct {
+		name              string
+		oldConfig         *persistencespb.NamespaceReplicationConfig
+		newConfig         *persistencespb.NamespaceReplicationConfig
+		namespaceState    enumspb.NamespaceState
+		isGlobalNamespace bool
+		expectedError     string
+	}{
+		{
+			name:              "add cluster",
+			oldConfig:         replicationConfig("c1", normal, "c1"),
+			newConfig:         replicationConfig("c1", normal, "c1", "c2"),
+			isGlobalNamespace: true,
+		},
+		{
+			name:              "fail over",
+			oldConfig:         replicationConfig("c1", normal, "c1", "c2"),
+			newConfig:         replicationConfig("c2", normal, "c1", "c2"),
+			isGlobalNamespace: true,
+		},
+		{
+			name:              "start handover",
+			oldConfig:         replicationConfig("c1", unspecified, "c1", "c2"),
+			newConfig:         replicationConfig("c1", handover, "c1", "c2"),
+			isGlobalNamespace: true,
+		},
+		{
+			name:              "fail over during handover",
+			oldConfig:         replicationConfig("c1", handover, "c1", "c2"),
+			newConfig:         replicationConfig("c2", handover, "c1", "c2"),
+			isGlobalNamespace: true,
+		},
+		{
+			name:              "complete handover",
+			oldConfig:         replicationConfig("c2", handover, "c1", "c2"),
+			newConfig:         replicationConfig("c2", normal, "c1", "c2"),
+			isGlobalNamespace: true,
+		},
+		{
+			name:              "active cluster not in cluster list",
+			oldConfig:         replicationConfig("c1", normal, "c1", "c2"),
+			newConfig:         replicationConfig("c3", normal, "c1", "c2"),
+			isGlobalNamespace: true,
+			expectedError:     "active cluster c3 is not one of the replication clusters",
+		},
+		{
+			name:              "add cluster and fail over to it",
+			oldConfig:         replicationConfig("c1", normal, "c1"),
+			newConfig:         replicationConfig("c2", normal, "c1", "c2"),
+			isGlobalNamespace: true,
+		},
+		{
+			name:              "replace clusters without the active cluster",
+			oldConfig:         replicationConfig("c1", normal, "c1", "c2"),
+			newConfig:         replicationConfig("c2", normal, "c1", "c3"),
+			isGlobalNamespace: true,
+			expectedError:     "active cluster c2 is not one of the replication clusters [c1, c3]",
+		},
+		{
+			name:              "remove active cluster",
+			oldConfig:         replicationConfig("c1", normal, "c1", "c2"),
+			newConfig:         replicationConfig("c1", normal, "c2"),
+			isGlobalNamespace: true,
+			expectedError:     "cannot remove active cluster c1",
+		},
+		{
+			name:              "remove active cluster and fail over",
+			oldConfig:         replicationConfig("c1", normal, "c1", "c2"),
+			newConfig:         replicationConfig("c2", normal, "c2"),
+			isGlobalNamespace: true,
+		},
+		{
+			name:              "remove active cluster and fail over to a removed cluster",
+			oldConfig:         replicationConfig("c1", normal, "c1", "c2", "c3"),
+			newConfig:         replicationConfig("c3", normal, "c2"),
+			isGlobalNamespace: true,
+			expectedError:     "active cluster c3 is not one of the replication clusters [c2]",
+		},
+		{
+			name:              "handover with single cluster",
+			oldConfig:         replicationConfig("c1", normal, "c1"),
+			newConfig:         replicationConfig("c1", handover, "c1"),
+			isGlobalNamespace: true,
+			expectedError:     "require more than one replication clusters",
+		},
+		{
+			name:              "remove clusters during handover",
+			oldConfig:         replicationConfig("c1", handover, "c1", "c2"),
+			newConfig:         replicationConfig("c1", handover, "c1"),
+			isGlobalNamespace: true,
+			expectedError:     "require more than one replication clusters",
+		},
+		{
+			name:          "handover for local namespace",
+			oldConfig:     replicationConfig("c1", normal, "c1", "c2"),
+			newConfig:     replicationConfig("c1", handover, "c1", "c2"),
+			expectedError: "can only be set for global namespace",
+		},
+		{
+			name:              "replication state update of deprecated namespace",
+			oldConfig:         replicationConfig("c1", normal, "c1", "c2"),
+			newConfig:         replicationConfig("c1", handover, "c1", "c2"),
+			namespaceState:    enumspb.NAMESPACE_STATE_DEPRECATED,
+			isGlobalNamespace: true,
+			expectedError:     "only supported when namespace is in",
+		},
+		{
+			name:              "replication state update of deleted namespace",
+			oldConfig:         replicationConfig("c1", handover, "c1", "c2"),
+			newConfig:         replicationConfig("c1", normal, "c1", "c2"),
+			namespaceState:    enumspb.NAMESPACE_STATE_DELETED,
+			isGlobalNamespace: true,
+			expectedError:     "only supported when namespace is in",
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			namespaceState := tc.namespaceState
+			if namespaceState == enumspb.NAMESPACE_STATE_UNSPECIFIED {
+				namespaceState = enumspb.NAMESPACE_STATE_REGISTERED
+			}
+			err := validateReplicationTransition(tc.oldConfig, tc.newConfig, namespaceState, tc.isGlobalNamespace)
+			if tc.expectedError == "" {
+				s.NoError(err)
+				return
+			}
+			var invalidArgument *serviceerror.InvalidArgument
+			s.ErrorAs(err, &invalidArgument)
+			s.ErrorContains(err, tc.expectedError)
+		})
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_RemoveActiveCluster() {
+	namespace := s.getRandomNamespace()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestAllClusterInfo).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).Times(2)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:    uuid.New(),
+						Name:  namespace,
+						State: enumspb.NAMESPACE_STATE_REGISTERED,
+					},
+					Config: &persistencespb.NamespaceConfig{},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName, cluster.TestAlternativeClusterName},
+					},
+				},
+				IsGlobalNamespace: true,
+			}, nil
+		},
+	).Times(2)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace: namespace,
+		ReplicationConfig: &replicationpb.NamespaceReplicationConfig{
+			Clusters: []*replicationpb.ClusterReplicationConfig{{ClusterName: cluster.TestAlternativeClusterName}},
+		},
+	})
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+	s.ErrorContains(err, "cannot remove active cluster")
+
+	// failing over in the same request keeps the namespace active on a replication cluster, but a global
+	// namespace fails over separately from other changes
+	_, err = s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace: namespace,
+		ReplicationConfig: &replicationpb.NamespaceReplicationConfig{
+			ActiveClusterName: cluster.TestAlternativeClusterName,
+			Clusters:          []*replicationpb.ClusterReplicationConfig{{ClusterName: cluster.TestAlternativeClusterName}},
+		},
+	})
+	s.Equal(errCannotDoNamespaceFailoverAndUpdate, err)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_ActiveClusterNotInClusters() {
+	namespace := s.getRandomNamespace()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info: &persistencespb.NamespaceInfo{
+						Id:    uuid.New(),
+						Name:  namespace,
+						State: enumspb.NAMESPACE_STATE_REGISTERED,
+					},
+					Config: &persistencespb.NamespaceConfig{},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: cluster.TestCurrentClusterName,
+						Clusters:          []string{cluster.TestCurrentClusterName, cluster.TestAlternativeClusterName},
+					},
+				},
+				IsGlobalNamespace: true,
+			}, nil
+		},
+	).AnyTimes()
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Times(0)
+
+	testCases := []struct {
+		name              string
+		replicationConfig *replicationpb.NamespaceReplicationConfig
+		expectedError     string
+	}{
+		{
+			name: "active cluster changed alone",
+			replicationConfig: &replicationpb.NamespaceReplicationConfig{
+				ActiveClusterName: "unknown-cluster",
+			},
+			expectedError: fmt.Sprintf(
+				"active cluster unknown-cluster is not one of the replication clusters [%s, %s]",
+				cluster.TestCurrentClusterName,
+				cluster.TestAlternativeClusterName,
+			),
+		},
+		{
+			name: "active cluster and clusters changed together",
+			replicationConfig: &replicationpb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestAlternativeClusterName,
+				Clusters:          []*replicationpb.ClusterReplicationConfig{{ClusterName: cluster.TestCurrentClusterName}},
+			},
+			expectedError: fmt.Sprintf(
+				"active cluster %s is not one of the replication clusters [%s]",
+				cluster.TestAlternativeClusterName,
+				cluster.TestCurrentClusterName,
+			),
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			_, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+				Namespace:         namespace,
+				ReplicationConfig: tc.replicationConfig,
+			})
+			var invalidArgument *serviceerror.InvalidArgument
+			s.ErrorAs(err, &invalidArgument)
+			s.ErrorContains(err, tc.expectedError)
+		})
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestFailoverGlobalNamespace_CustomFailoverVersionAllocator() {
+	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).AnyTimes()
+	namespace := s.getRandomNamespace()
+	clusterName1 := "cluster1"
+	clusterName2 := "cluster2"
+	s.config.FailoverVersionAllocator = dc.GetStringPropertyFnFilteredByNamespace("reserved-range")
+	s.failoverVersionAllocators = FailoverVersionAllocators{
+		"reserved-range": failoverVersionAllocatorFunc(func(ns string, activeClusterName string, currentVersion int64) (int64, error) {
+			s.Equal(namespace, ns)
+			s.Equal(clusterName2, activeClusterName)
+			s.Equal(int64(1), currentVersion)
+			return 1002, nil
+		}),
+	}
+	s.handler = s.newHandler()
+
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: 100,
+	}, nil)
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		clusterName1: {Enabled: true, InitialFailoverVersion: 1},
+		clusterName2: {Enabled: true, InitialFailoverVersion: 2},
+	}).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(clusterName1).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetNextFailoverVersion(clusterName2, int64(1)).Return(int64(2))
+	s.mockClusterMetadata.EXPECT().IsVersionFromSameCluster(int64(1002), int64(2)).Return(true)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:   uuid.New(),
+				Name: namespace,
+			},
+			Config: &persistencespb.NamespaceConfig{},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: clusterName1,
+				Clusters:          []string{clusterName1, clusterName2},
+			},
+			FailoverVersion: 1,
+		},
+		IsGlobalNamespace: true,
+	}, nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			s.Equal(int64(1002), request.Namespace.FailoverVersion)
+			s.Equal(clusterName2, request.Namespace.ReplicationConfig.ActiveClusterName)
+			return nil
+		},
+	)
+
+	resp, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace: namespace,
+		ReplicationConfig: &replicationpb.NamespaceReplicationConfig{
+			ActiveClusterName: clusterName2,
+		},
+	})
+	s.NoError(err)
+	s.Equal(int64(1002), resp.GetFailoverVersion())
+}
+
+func (s *namespaceHandlerCommonSuite) TestNextFailoverVersion() {
+	namespace := s.getRandomNamespace()
+	clusterName := "cluster2"
+	allocatorErr := errors.New("allocator failure")
+	s.failoverVersionAllocators = FailoverVersionAllocators{
+		"fixed": failoverVersionAllocatorFunc(func(string, string, int64) (int64, error) {
+			return 1002, nil
+		}),
+		"failing": failoverVersionAllocatorFunc(func(string, string, int64) (int64, error) {
+			return 0, allocatorErr
+		}),
+	}
+	s.handler = s.newHandler()
+
+	testCases := []struct {
+		name           string
+		allocator      string
+		currentVersion int64
+		// sameCluster is the cluster check result, nil if the version is not checked against the cluster.
+		sameCluster *bool
+		expected    int64
+		expectedErr error
+		internalErr bool
+	}{
+		{name: "cluster scheme", allocator: "", currentVersion: 1, expected: 2},
+		{name: "custom allocator", allocator: "fixed", currentVersion: 1, sameCluster: util.Ptr(true), expected: 1002},
+		{name: "allocator error", allocator: "failing", currentVersion: 1, expectedErr: allocatorErr},
+		{name: "unregistered allocator", allocator: "missing", currentVersion: 1, internalErr: true},
+		{name: "version of another cluster", allocator: "fixed", currentVersion: 1, sameCluster: util.Ptr(false), internalErr: true},
+		{name: "version below current", allocator: "fixed", currentVersion: 2001, internalErr: true},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.config.FailoverVersionAllocator = dc.GetStringPropertyFnFilteredByNamespace(tc.allocator)
+			clusterVersion := tc.currentVersion + 1
+			s.mockClusterMetadata.EXPECT().GetNextFailoverVersion(clusterName, tc.currentVersion).Return(clusterVersion)
+			if tc.sameCluster != nil {
+				s.mockClusterMetadata.EXPECT().IsVersionFromSameCluster(int64(1002), clusterVersion).Return(*tc.sameCluster)
+			}
+
+			version, err := s.handler.nextFailoverVersion(namespace, clusterName, tc.currentVersion)
+			switch {
+			case tc.expectedErr != nil:
+				s.ErrorIs(err, tc.expectedErr)
+			case tc.internalErr:
+				var internalErr *serviceerror.Internal
+				s.ErrorAs(err, &internalErr)
+			default:
+				s.NoError(err)
+				s.Equal(tc.expected, version)
+			}
+		})
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestRegisterNamespace_NamePattern() {
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+
+	testCases := []struct {
+		name      string
+		pattern   string
+		namespace string
+		conforms  bool
+	}{
+		{name: "unset pattern", pattern: "", namespace: "Any_Name.1", conforms: true},
+		{name: "conforming name", pattern: "[a-z]+-(dev|prod)-[a-z0-9-]+", namespace: "payments-prod-billing", conforms: true},
+		{name: "non-conforming name", pattern: "[a-z]+-(dev|prod)-[a-z0-9-]+", namespace: "payments", conforms: false},
+		{name: "partial match", pattern: "[a-z]+-(dev|prod)-[a-z0-9-]+", namespace: "x-payments-prod-billing!", conforms: false},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.config.NamespaceNamePattern = dc.GetStringPropertyFn(tc.pattern)
+			if tc.conforms {
+				// The name is validated before the namespace is looked up, so an existing namespace proves it conforms.
+				s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: tc.namespace}).
+					Return(&persistence.GetNamespaceResponse{}, nil)
+			}
+
+			_, err := s.handler.RegisterNamespace(context.Background(), &workflowservice.RegisterNamespaceRequest{
+				Namespace:                        tc.namespace,
+				WorkflowExecutionRetentionPeriod: durationpb.New(24 * time.Hour),
+			})
+			if tc.conforms {
+				var alreadyExistsErr *serviceerror.NamespaceAlreadyExists
+				s.ErrorAs(err, &alreadyExistsErr)
+			} else {
+				var invalidArgErr *serviceerror.InvalidArgument
+				s.ErrorAs(err, &invalidArgErr)
+				s.ErrorContains(err, tc.pattern)
+			}
+		})
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestBatchFailoverNamespaces() {
+	clusterName1 := "cluster1"
+	clusterName2 := "cluster2"
+	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		clusterName1: {Enabled: true, InitialFailoverVersion: 1},
+		clusterName2: {Enabled: true, InitialFailoverVersion: 2},
+	}).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(clusterName1).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetNextFailoverVersion(clusterName2, int64(1)).Return(int64(2))
+
+	namespaceDetail := func(name string, activeCluster string, state enumspb.ReplicationState, failoverVersion int64) *persistence.GetNamespaceResponse {
+		return &persistence.GetNamespaceResponse{
+			Namespace: &persistencespb.NamespaceDetail{
+				Info:   &persistencespb.NamespaceInfo{Id: uuid.New(), Name: name},
+				Config: &persistencespb.NamespaceConfig{},
+				ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+					ActiveClusterName: activeCluster,
+					Clusters:          []string{clusterName1, clusterName2},
+					State:             state,
+				},
+				FailoverVersion: failoverVersion,
+			},
+			IsGlobalNamespace: true,
+		}
+	}
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "to-fail-over"}).
+		Return(namespaceDetail("to-fail-over", clusterName1, enumspb.REPLICATION_STATE_NORMAL, 1), nil).Times(2)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "already-active"}).
+		Return(namespaceDetail("already-active", clusterName2, enumspb.REPLICATION_STATE_NORMAL, 12), nil)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "in-handover"}).
+		Return(namespaceDetail("in-handover", clusterName1, enumspb.REPLICATION_STATE_HANDOVER, 1), nil)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "missing"}).
+		Return(nil, serviceerror.NewNamespaceNotFound("missing"))
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{NotificationVersion: 100}, nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			s.Equal("to-fail-over", request.Namespace.Info.Name)
+			s.Equal(clusterName2, request.Namespace.ReplicationConfig.ActiveClusterName)
+			s.Equal(int64(2), request.Namespace.FailoverVersion)
+			s.Len(request.Namespace.ReplicationConfig.FailoverHistory, 1)
+			return nil
+		},
+	)
+
+	results, err := s.handler.BatchFailoverNamespaces(
+		context.Background(),
+		[]string{"to-fail-over", "already-active", "in-handover", "missing", "to-fail-over"},
+		clusterName2,
+	)
+	s.NoError(err)
+	s.Len(results, 4)
+	s.Equal("to-fail-over", results[0].Namespace)
+	s.NoError(results[0].Err)
+	s.Equal(int64(2), results[0].FailoverVersion)
+	s.Equal("already-active", results[1].Namespace)
+	s.NoError(results[1].Err)
+	s.Equal(int64(12), results[1].FailoverVersion)
+	s.Equal("in-handover", results[2].Namespace)
+	var failedPreconditionErr *serviceerror.FailedPrecondition
+	s.ErrorAs(results[2].Err, &failedPreconditionErr)
+	s.Equal("missing", results[3].Namespace)
+	var notFoundErr *serviceerror.NamespaceNotFound
+	s.ErrorAs(results[3].Err, &notFoundErr)
+}
+
+func (s *namespaceHandlerCommonSuite) TestBatchFailoverNamespaces_NotMaster() {
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(false).AnyTimes()
+
+	_, err := s.handler.BatchFailoverNamespaces(context.Background(), []string{"ns"}, "cluster2")
+	s.ErrorIs(err, errNotMasterCluster)
+}
+
+func (s *namespaceHandlerCommonSuite) TestBatchFailoverNamespaces_UnknownTargetCluster() {
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		"cluster1": {Enabled: true, InitialFailoverVersion: 1},
+	}).AnyTimes()
+
+	_, err := s.handler.BatchFailoverNamespaces(context.Background(), []string{"ns"}, "cluster2")
+	var invalidArgErr *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgErr)
+}
+
+func (s *namespaceHandlerCommonSuite) TestFailoverNamespace() {
+	clusterName1 := "cluster1"
+	clusterName2 := "cluster2"
+	s.fakeClock.Update(time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC))
+	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		clusterName1: {Enabled: true, InitialFailoverVersion: 1},
+		clusterName2: {Enabled: true, InitialFailoverVersion: 2},
+	}).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(clusterName1).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetNextFailoverVersion(clusterName2, int64(11)).Return(int64(12))
+
+	previousFailover := &persistencespb.FailoverStatus{
+		FailoverTime:    timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		FailoverVersion: 11,
+	}
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "ns"}).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info:   &persistencespb.NamespaceInfo{Id: uuid.New(), Name: "ns"},
+					Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: clusterName1,
+						Clusters:          []string{clusterName1, clusterName2},
+						FailoverHistory:   []*persistencespb.FailoverStatus{previousFailover},
+					},
+					ConfigVersion:   3,
+					FailoverVersion: 11,
+				},
+				IsGlobalNamespace: true,
+			}, nil
+		},
+	).Times(2)
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{NotificationVersion: 100}, nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			s.Equal(clusterName2, request.Namespace.ReplicationConfig.ActiveClusterName)
+			s.Equal(int64(12), request.Namespace.FailoverVersion)
+			s.Equal(int64(100), request.Namespace.FailoverNotificationVersion)
+			// the failover does not change the configuration
+			s.Equal(int64(3), request.Namespace.ConfigVersion)
+			protoassert.ProtoSliceEqual(s.T(), []*persistencespb.FailoverStatus{
+				previousFailover,
+				{FailoverTime: timestamppb.New(s.fakeClock.Now()), FailoverVersion: 12},
+			}, request.Namespace.ReplicationConfig.FailoverHistory)
+			return nil
+		},
+	)
+
+	failoverVersion, err := s.handler.FailoverNamespace(context.Background(), "ns", clusterName2)
+	s.NoError(err)
+	s.Equal(int64(12), failoverVersion)
+
+	_, err = s.handler.FailoverNamespace(context.Background(), "ns", "cluster3")
+	var invalidArgErr *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgErr)
+}
+
+// setupGracefulFailoverTest mocks a global namespace active in cluster1 and replicated to cluster2, whose detail
+// is updated by the namespace updates unless failUpdate returns an error for the update. It returns the namespace
+// ID and a function returning the namespace detail.
+func (s *namespaceHandlerCommonSuite) setupGracefulFailoverTest(
+	failUpdate func(*persistence.UpdateNamespaceRequest) error,
+) (string, func() *persistencespb.NamespaceDetail) {
+	s.mockProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsMasterCluster().Return(true).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		"cluster1": {Enabled: true, InitialFailoverVersion: 1},
+		"cluster2": {Enabled: true, InitialFailoverVersion: 2},
+	}).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return("cluster1").AnyTimes()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{NotificationVersion: 100}, nil).AnyTimes()
+	s.config.NumHistoryShards = 2
+
+	nsID := uuid.New()
+	var lock sync.Mutex
+	detail := &persistencespb.NamespaceDetail{
+		Info:   &persistencespb.NamespaceInfo{Id: nsID, Name: "ns", State: enumspb.NAMESPACE_STATE_REGISTERED},
+		Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+		ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+			ActiveClusterName: "cluster1",
+			Clusters:          []string{"cluster1", "cluster2"},
+			State:             enumspb.REPLICATION_STATE_NORMAL,
+		},
+		FailoverVersion: 11,
+	}
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "ns"}).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return &persistence.GetNamespaceResponse{Namespace: common.CloneProto(detail), IsGlobalNamespace: true}, nil
+		},
+	).AnyTimes()
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			lock.Lock()
+			defer lock.Unlock()
+			if failUpdate != nil {
+				if err := failUpdate(request); err != nil {
+					return err
+				}
+			}
+			detail = request.Namespace
+			return nil
+		},
+	).AnyTimes()
+	return nsID, func() *persistencespb.NamespaceDetail {
+		lock.Lock()
+		defer lock.Unlock()
+		return detail
+	}
+}
+
+// replicationStatus returns the history replication status of two shards, with the given number of replication
+// tasks pending for cluster2 on the second shard.
+func replicationStatus(pendingTasks int64) *historyservice.GetReplicationStatusResponse {
+	return &historyservice.GetReplicationStatusResponse{
+		Shards: []*historyservice.ShardReplicationStatus{
+			{
+				ShardId:              1,
+				MaxReplicationTaskId: 10,
+				RemoteClusters:       map[string]*historyservice.ShardReplicationStatusPerCluster{"cluster2": {AckedTaskId: 10}},
+			},
+			{
+				ShardId:              2,
+				MaxReplicationTaskId: 20,
+				RemoteClusters:       map[string]*historyservice.ShardReplicationStatusPerCluster{"cluster2": {AckedTaskId: 20 - pendingTasks}},
+			},
+		},
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestGracefulFailoverNamespace() {
+	_, namespaceDetail := s.setupGracefulFailoverTest(nil)
+	s.mockClusterMetadata.EXPECT().GetNextFailoverVersion("cluster2", int64(11)).Return(int64(12))
+
+	// the first status check finds a history replication task pending for cluster2, the second finds none
+	gomock.InOrder(
+		s.mockHistoryClient.EXPECT().GetReplicationStatus(gomock.Any(), &historyservice.GetReplicationStatusRequest{
+			RemoteClusters: []string{"cluster2"},
+		}).DoAndReturn(
+			func(_ context.Context, _ *historyservice.GetReplicationStatusRequest, _ ...any) (*historyservice.GetReplicationStatusResponse, error) {
+				s.Equal(enumspb.REPLICATION_STATE_HANDOVER, namespaceDetail().ReplicationConfig.State)
+				return replicationStatus(1), nil
+			},
+		),
+		s.mockHistoryClient.EXPECT().GetReplicationStatus(gomock.Any(), gomock.Any()).Return(replicationStatus(0), nil),
+	)
+
+	failoverVersion, err := s.handler.GracefulFailoverNamespace(context.Background(), &GracefulFailoverNamespaceRequest{
+		Namespace:     "ns",
+		TargetCluster: "cluster2",
+		PollInterval:  time.Millisecond,
+	})
+	s.NoError(err)
+	s.Equal(int64(12), failoverVersion)
+	detail := namespaceDetail()
+	s.Equal("cluster2", detail.ReplicationConfig.ActiveClusterName)
+	s.Equal(enumspb.REPLICATION_STATE_NORMAL, detail.ReplicationConfig.State)
+	s.Equal(int64(12), detail.FailoverVersion)
+}
+
+func (s *namespaceHandlerCommonSuite) TestGracefulFailoverNamespace_Timeout() {
+	_, namespaceDetail := s.setupGracefulFailoverTest(nil)
+
+	// cluster2 never catches up
+	s.mockHistoryClient.EXPECT().GetReplicationStatus(gomock.Any(), gomock.Any()).Return(replicationStatus(1), nil).MinTimes(1)
+
+	_, err := s.handler.GracefulFailoverNamespace(context.Background(), &GracefulFailoverNamespaceRequest{
+		Namespace:     "ns",
+		TargetCluster: "cluster2",
+		Timeout:       50 * time.Millisecond,
+		PollInterval:  time.Millisecond,
+	})
+	var deadlineExceeded *serviceerror.DeadlineExceeded
+	s.ErrorAs(err, &deadlineExceeded)
+	// the handover is aborted
+	detail := namespaceDetail()
+	s.Equal("cluster1", detail.ReplicationConfig.ActiveClusterName)
+	s.Equal(enumspb.REPLICATION_STATE_NORMAL, detail.ReplicationConfig.State)
+	s.Equal(int64(11), detail.FailoverVersion)
+}
+
+// mockGracefulFailoverCaughtUp mocks cluster2 having no pending replication tasks.
+func (s *namespaceHandlerCommonSuite) mockGracefulFailoverCaughtUp() {
+	s.mockHistoryClient.EXPECT().GetReplicationStatus(gomock.Any(), gomock.Any()).Return(replicationStatus(0), nil).AnyTimes()
+}
+
+func (s *namespaceHandlerCommonSuite) TestGracefulFailoverNamespace_FailoverUpdateFailed() {
+	_, namespaceDetail := s.setupGracefulFailoverTest(func(request *persistence.UpdateNamespaceRequest) error {
+		if request.Namespace.ReplicationConfig.ActiveClusterName == "cluster2" {
+			return serviceerror.NewUnavailable("persistence unavailable")
+		}
+		return nil
+	})
+	s.mockClusterMetadata.EXPECT().GetNextFailoverVersion("cluster2", int64(11)).Return(int64(12))
+	s.mockGracefulFailoverCaughtUp()
+
+	_, err := s.handler.GracefulFailoverNamespace(context.Background(), &GracefulFailoverNamespaceRequest{
+		Namespace:     "ns",
+		TargetCluster: "cluster2",
+		PollInterval:  time.Millisecond,
+	})
+	var unavailable *serviceerror.Unavailable
+	s.ErrorAs(err, &unavailable)
+	// the handover is aborted
+	detail := namespaceDetail()
+	s.Equal("cluster1", detail.ReplicationConfig.ActiveClusterName)
+	s.Equal(enumspb.REPLICATION_STATE_NORMAL, detail.ReplicationConfig.State)
+	s.Equal(int64(11), detail.FailoverVersion)
+}
+
+func (s *namespaceHandlerCommonSuite) TestGracefulFailoverNamespace_ResetStateFailed() {
+	resetFailed := false
+	_, namespaceDetail := s.setupGracefulFailoverTest(func(request *persistence.UpdateNamespaceRequest) error {
+		if request.Namespace.ReplicationConfig.State == enumspb.REPLICATION_STATE_NORMAL && !resetFailed {
+			resetFailed = true
+			return serviceerror.NewUnavailable("persistence unavailable")
+		}
+		return nil
+	})
+	s.mockClusterMetadata.EXPECT().GetNextFailoverVersion("cluster2", int64(11)).Return(int64(12))
+	s.mockGracefulFailoverCaughtUp()
+
+	_, err := s.handler.GracefulFailoverNamespace(context.Background(), &GracefulFailoverNamespaceRequest{
+		Namespace:     "ns",
+		TargetCluster: "cluster2",
+		PollInterval:  time.Millisecond,
+	})
+	var unavailable *serviceerror.Unavailable
+	s.ErrorAs(err, &unavailable)
+	s.True(resetFailed)
+	// the namespace is failed over, and is not left in handover
+	detail := namespaceDetail()
+	s.Equal("cluster2", detail.ReplicationConfig.ActiveClusterName)
+	s.Equal(enumspb.REPLICATION_STATE_NORMAL, detail.ReplicationConfig.State)
+}
+
+func (s *namespaceHandlerCommonSuite) TestGracefulFailoverNamespace_ConfigChangeRateLimit() {
+	s.config.NamespaceConfigChangeMaxCount = dc.GetIntPropertyFnFilteredByNamespace(1)
+	s.config.NamespaceConfigChangeWindow = dc.GetDurationPropertyFnFilteredByNamespace(time.Minute)
+	s.handler = s.newHandler()
+	_, namespaceDetail := s.setupGracefulFailoverTest(nil)
+	s.mockClusterMetadata.EXPECT().GetNextFailoverVersion("cluster2", int64(11)).Return(int64(12))
+	s.mockGracefulFailoverCaughtUp()
+
+	// the steps of the handover are not throttled, the failover counts as one config change
+	failoverVersion, err := s.handler.GracefulFailoverNamespace(context.Background(), &GracefulFailoverNamespaceRequest{
+		Namespace:     "ns",
+		TargetCluster: "cluster2",
+		PollInterval:  time.Millisecond,
+	})
+	s.NoError(err)
+	s.Equal(int64(12), failoverVersion)
+
+	_, err = s.handler.GracefulFailoverNamespace(context.Background(), &GracefulFailoverNamespaceRequest{
+		Namespace:     "ns",
+		TargetCluster: "cluster1",
+		PollInterval:  time.Millisecond,
+	})
+	var resourceExhausted *serviceerror.ResourceExhausted
+	s.ErrorAs(err, &resourceExhausted)
+	// the throttled failover does not start the handover
+	detail := namespaceDetail()
+	s.Equal("cluster2", detail.ReplicationConfig.ActiveClusterName)
+	s.Equal(enumspb.REPLICATION_STATE_NORMAL, detail.ReplicationConfig.State)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDescribeNamespaceReplicationHealth() {
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return("active").AnyTimes()
+	s.config.ReplicationHealthThresholds = dc.GetTypedPropertyFn(dc.ReplicationHealthThresholds{
+		DegradedLatency:       100,
+		UnhealthyLatency:      1000,
+		DegradedErrorRatio:    0.1,
+		UnhealthyErrorRatio:   0.5,
+		DegradedPendingTasks:  1,
+		UnhealthyPendingTasks: 3,
+	})
+
+	testCases := []struct {
+		name            string
+		latency         time.Duration
+		err             error
+		standbyAckLevel *int64
+		pendingTasks    int
+		expectedStatus  ReplicationHealthStatus
+		expectedFactors int
+	}{
+		{
+			name:            "healthy",
+			latency:         10 * time.Millisecond,
+			standbyAckLevel: util.Ptr(int64(10)),
+			expectedStatus:  ReplicationHealthStatusHealthy,
+		},
+		{
+			name:            "degraded by pending tasks",
+			latency:         10 * time.Millisecond,
+			standbyAckLevel: util.Ptr(int64(10)),
+			pendingTasks:    2,
+			expectedStatus:  ReplicationHealthStatusDegraded,
+			expectedFactors: 1,
+		},
+		{
+			name:            "unhealthy by latency",
+			latency:         2 * time.Second,
+			standbyAckLevel: util.Ptr(int64(10)),
+			pendingTasks:    2,
+			expectedStatus:  ReplicationHealthStatusUnhealthy,
+			expectedFactors: 2,
+		},
+		{
+			name:            "unhealthy by errors",
+			latency:         10 * time.Millisecond,
+			err:             &persistence.TimeoutError{Msg: "timeout"},
+			standbyAckLevel: util.Ptr(int64(10)),
+			expectedStatus:  ReplicationHealthStatusUnhealthy,
+			expectedFactors: 1,
+		},
+		{
+			name:            "degraded by unknown pending tasks",
+			latency:         10 * time.Millisecond,
+			expectedStatus:  ReplicationHealthStatusDegraded,
+			expectedFactors: 1,
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			namespace := s.getRandomNamespace()
+			nid := uuid.New()
+			healthSignals := persistence.NewHealthSignalAggregator(
+				true,
+				time.Minute,
+				100,
+				dc.GetDurationPropertyFn(0),
+				dc.GetFloatPropertyFn(0),
+				dc.GetIntPropertyFn(100),
+				dc.GetIntPropertyFn(0),
+				dc.GetIntPropertyFn(0),
+				nil,
+				metrics.NoopMetricsHandler,
+				log.NewNoopLogger(),
+			)
+			healthSignals.Record(persistence.CallerSegmentMissing, "", "", persistence.OperationClassWrite, tc.latency, tc.err)
+			s.healthSignals = healthSignals
+			s.handler = s.newHandler()
+
+			s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+				Namespace: &persistencespb.NamespaceDetail{
+					Info:   &persistencespb.NamespaceInfo{Id: nid, Name: namespace},
+					Config: &persistencespb.NamespaceConfig{},
+					ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+						ActiveClusterName: "active",
+						Clusters:          []string{"active", "standby"},
+					},
+				},
+				IsGlobalNamespace: true,
+			}, nil)
+			ackLevels := map[string]int64{}
+			if tc.standbyAckLevel != nil {
+				ackLevels["standby"] = *tc.standbyAckLevel
+				var tasks []*replicationspb.ReplicationTask
+				for range tc.pendingTasks {
+					tasks = append(tasks, &replicationspb.ReplicationTask{
+						TaskType:   enumsspb.REPLICATION_TASK_TYPE_NAMESPACE_TASK,
+						Attributes: &replicationspb.ReplicationTask_NamespaceTaskAttributes{NamespaceTaskAttributes: &replicationspb.NamespaceTaskAttributes{Id: nid}},
+					})
+				}
+				s.mockProducer.EXPECT().GetReplicationMessages(gomock.Any(), *tc.standbyAckLevel, gomock.Any()).Return(tasks, int64(20), nil)
+				if len(tasks) > 0 {
+					s.mockProducer.EXPECT().GetReplicationMessages(gomock.Any(), int64(20), gomock.Any()).Return(nil, int64(20), nil)
+				}
+			}
+			s.mockProducer.EXPECT().GetAckLevels(gomock.Any()).Return(ackLevels, nil)
+
+			health, err := s.handler.DescribeNamespaceReplicationHealth(context.Background(), namespace)
+			s.NoError(err)
+			s.Equal(tc.expectedStatus, health.Status)
+			s.Len(health.Factors, tc.expectedFactors, health.Factors)
+			s.InDelta(float64(tc.latency.Milliseconds()), health.PersistenceAverageLatency, 0.001)
+			s.Equal(int64(1), health.PersistenceRequestCount)
+			s.Len(health.ReplicationStatus.Clusters, 2)
+		})
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestDescribeNamespaceDetail_LastFailover() {
+	s.config.DescribeNamespaceCacheEnabled = dc.GetBoolPropertyFn(true)
+	s.handler = s.newHandler()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.fakeClock.Update(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	newNamespaceResponse := func(name string, failoverHistory []*persistencespb.FailoverStatus) *persistence.GetNamespaceResponse {
+		return &persistence.GetNamespaceResponse{
+			Namespace: &persistencespb.NamespaceDetail{
+				Info:   &persistencespb.NamespaceInfo{Id: uuid.New(), Name: name},
+				Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+				ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+					ActiveClusterName: cluster.TestCurrentClusterName,
+					Clusters:          []string{cluster.TestCurrentClusterName},
+					FailoverHistory:   failoverHistory,
+				},
+			},
+		}
+	}
+	// the failed over namespace is only read once, the second description is served from the cache
+	lastFailoverTime := timestamppb.New(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "failed-over"}).Return(
+		newNamespaceResponse("failed-over", []*persistencespb.FailoverStatus{
+			{FailoverTime: timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), FailoverVersion: 1},
+			{FailoverTime: lastFailoverTime, FailoverVersion: 11},
+		}), nil,
+	).Times(1)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "never-failed-over"}).Return(
+		newNamespaceResponse("never-failed-over", nil), nil,
+	)
+
+	describeRequest := &workflowservice.DescribeNamespaceRequest{Namespace: "failed-over"}
+	description, err := s.handler.DescribeNamespaceDetail(context.Background(), describeRequest)
+	s.NoError(err)
+	protoassert.ProtoEqual(s.T(), lastFailoverTime, description.LastFailoverTime)
+	s.Equal(29*24*time.Hour, description.TimeSinceLastFailover.AsDuration())
+
+	s.fakeClock.Advance(time.Second)
+	description, err = s.handler.DescribeNamespaceDetail(context.Background(), describeRequest)
+	s.NoError(err)
+	protoassert.ProtoEqual(s.T(), lastFailoverTime, description.LastFailoverTime)
+	s.Equal(29*24*time.Hour+time.Second, description.TimeSinceLastFailover.AsDuration())
+
+	description, err = s.handler.DescribeNamespaceDetail(
+		context.Background(), &workflowservice.DescribeNamespaceRequest{Namespace: "never-failed-over"},
+	)
+	s.NoError(err)
+	s.Nil(description.LastFailoverTime)
+	s.Nil(description.TimeSinceLastFailover)
+}
+
+func (s *namespaceHandlerCommonSuite) TestDescribeEffectiveNamespaceConfig() {
+	s.config.EnableEagerWorkflowStart = dc.GetBoolPropertyFnFilteredByNamespace(true)
+	s.config.EnableUpdateWorkflowExecution = dc.GetBoolPropertyFnFilteredByNamespace(false)
+	s.config.EnableUpdateWorkflowExecutionAsyncAccepted = dc.GetBoolPropertyFnFilteredByNamespace(true)
+	s.archivalMetadata = archiver.NewArchivalMetadata(
+		dc.NewNoopCollection(),
+		"enabled",
+		true,
+		"",
+		false,
+		&config.ArchivalNamespaceDefaults{},
+	)
+	s.handler = s.newHandler()
+
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "ns"}).Return(
+		&persistence.GetNamespaceResponse{
+			Namespace: &persistencespb.NamespaceDetail{
+				Info: &persistencespb.NamespaceInfo{Id: uuid.New(), Name: "ns"},
+				Config: &persistencespb.NamespaceConfig{
+					Retention:               durationpb.New(24 * time.Hour),
+					HistoryArchivalState:    enumspb.ARCHIVAL_STATE_ENABLED,
+					HistoryArchivalUri:      "file:///history",
+					VisibilityArchivalState: enumspb.ARCHIVAL_STATE_ENABLED,
+					VisibilityArchivalUri:   "file:///visibility",
+					AsyncUpdateOverride:     &persistencespb.AsyncUpdateOverride{Enabled: false},
+				},
+				ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+					ActiveClusterName: cluster.TestCurrentClusterName,
+					Clusters:          []string{cluster.TestCurrentClusterName},
+				},
+			},
+		}, nil,
+	)
+
+	effective, err := s.handler.DescribeEffectiveNamespaceConfig(context.Background(), "ns")
+	s.NoError(err)
+	s.Equal(&EffectiveNamespaceConfig{
+		Retention: EffectiveConfigValue[time.Duration]{Value: 24 * time.Hour, Source: ConfigSourceNamespace},
+		HistoryArchivalState: EffectiveConfigValue[enumspb.ArchivalState]{
+			Value: enumspb.ARCHIVAL_STATE_ENABLED, Source: ConfigSourceNamespace,
+		},
+		HistoryArchivalURI: EffectiveConfigValue[string]{Value: "file:///history", Source: ConfigSourceNamespace},
+		// the cluster is not configured for visibility archival
+		VisibilityArchivalState: EffectiveConfigValue[enumspb.ArchivalState]{
+			Value: enumspb.ARCHIVAL_STATE_DISABLED, Source: ConfigSourceClusterDefault,
+		},
+		VisibilityArchivalURI: EffectiveConfigValue[string]{Source: ConfigSourceClusterDefault},
+		EagerWorkflowStart:    EffectiveConfigValue[bool]{Value: true, Source: ConfigSourceClusterDefault},
+		SyncUpdate:            EffectiveConfigValue[bool]{Value: false, Source: ConfigSourceClusterDefault},
+		AsyncUpdate:           EffectiveConfigValue[bool]{Value: false, Source: ConfigSourceNamespace},
+		Schedules:             EffectiveConfigValue[bool]{Value: true, Source: ConfigSourceClusterDefault},
+	}, effective)
+
+	_, err = s.handler.DescribeEffectiveNamespaceConfig(context.Background(), "")
+	s.ErrorIs(err, errNamespaceNotSet)
+}
+
+func (s *namespaceHandlerCommonSuite) TestRequestMetrics() {
+	captureHandler := metricstest.NewCaptureHandler()
+	capture := captureHandler.StartCapture()
+	defer captureHandler.StopCapture(capture)
+	s.metricsHandler = captureHandler
+	s.handler = s.newHandler()
+
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "ns"}).Return(
+		&persistence.GetNamespaceResponse{
+			Namespace: &persistencespb.NamespaceDetail{
+				Info:   &persistencespb.NamespaceInfo{Id: uuid.New(), Name: "ns"},
+				Config: &persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+				ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+					ActiveClusterName: cluster.TestCurrentClusterName,
+					Clusters:          []string{cluster.TestCurrentClusterName},
+				},
+			},
+		}, nil,
+	)
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "missing"}).Return(
+		nil, serviceerror.NewNamespaceNotFound("missing"),
+	)
+
+	_, err := s.handler.DescribeEffectiveNamespaceConfig(context.Background(), "ns")
+	s.NoError(err)
+	_, err = s.handler.DescribeEffectiveNamespaceConfig(context.Background(), "missing")
+	s.Error(err)
+
+	snapshot := capture.Snapshot()
+	requests := snapshot[metrics.NamespaceHandlerRequests.Name()]
+	s.Len(requests, 2)
+	s.Equal(map[string]string{
+		"operation": "DescribeEffectiveNamespaceConfig",
+		"namespace": "ns",
+		"outcome":   "success",
+	}, requests[0].Tags)
+	s.Equal(map[string]string{
+		"operation": "DescribeEffectiveNamespaceConfig",
+		"namespace": "missing",
+		"outcome":   "error",
+	}, requests[1].Tags)
+
+	s.Len(snapshot[metrics.NamespaceHandlerLatency.Name()], 2)
+
+	errors := snapshot[metrics.NamespaceHandlerErrorWithType.Name()]
+	s.Len(errors, 1)
+	s.Equal(int64(1), errors[0].Value)
+	s.Equal("missing", errors[0].Tags["namespace"])
+	s.Equal("serviceerror.NamespaceNotFound", errors[0].Tags[metrics.ErrorTypeTagName])
+}
+
+func (s *namespaceHandlerCommonSuite) TestValidateArchivalUpdate() {
+	s.archivalMetadata = archiver.NewArchivalMetadata(
+		dc.NewNoopCollection(),
+		"enabled",
+		true,
+		"",
+		false,
+		&config.ArchivalNamespaceDefaults{},
+	)
+	s.handler = s.newHandler()
+	nsID := uuid.New()
+
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: "ns"}).Return(
+		&persistence.GetNamespaceResponse{
+			Namespace: &persistencespb.NamespaceDetail{
+				Info: &persistencespb.NamespaceInfo{Id: nsID, Name: "ns"},
+				Config: &persistencespb.NamespaceConfig{
+					Retention:               durationpb.New(24 * time.Hour),
+					HistoryArchivalState:    enumspb.ARCHIVAL_STATE_ENABLED,
+					HistoryArchivalUri:      "file:///old-history",
+					VisibilityArchivalState: enumspb.ARCHIVAL_STATE_DISABLED,
+					VisibilityArchivalUri:   "file:///old-visibility",
+				},
+				ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+					ActiveClusterName: cluster.TestCurrentClusterName,
+					Clusters:          []string{cluster.TestCurrentClusterName},
+				},
+			},
+		}, nil,
+	)
+
+	oldHistoryArchiver := archiver.NewMockHistoryArchiver(s.controller)
+	newHistoryArchiver := archiver.NewMockHistoryArchiver(s.controller)
+	visibilityArchiver := archiver.NewMockVisibilityArchiver(s.controller)
+	s.mockArchiverProvider.EXPECT().GetHistoryArchiver("file").Return(oldHistoryArchiver, nil).AnyTimes()
+	s.mockArchiverProvider.EXPECT().GetHistoryArchiver("s3").Return(newHistoryArchiver, nil).AnyTimes()
+	s.mockArchiverProvider.EXPECT().GetVisibilityArchiver("file").Return(visibilityArchiver, nil).AnyTimes()
+	newHistoryArchiver.EXPECT().ValidateURI(gomock.Any()).Return(nil).AnyTimes()
+	// the old history URI is readable, the probed workflow does not exist
+	oldHistoryArchiver.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, uri archiver.URI, request *archiver.GetHistoryRequest) (*archiver.GetHistoryResponse, error) {
+			s.Equal("file:///old-history", uri.String())
+			s.Equal(nsID, request.NamespaceID)
+			return nil, serviceerror.NewNotFound("history not found")
+		},
+	)
+	visibilityArchiver.EXPECT().ValidateURI(gomock.Any()).Return(nil)
+	visibilityArchiver.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, serviceerror.NewUnavailable("permission denied"))
+
+	report, err := s.handler.ValidateArchivalUpdate(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace: "ns",
+		Config: &namespacepb.NamespaceConfig{
+			HistoryArchivalState:    enumspb.ARCHIVAL_STATE_ENABLED,
+			HistoryArchivalUri:      "s3://new-history",
+			VisibilityArchivalState: enumspb.ARCHIVAL_STATE_ENABLED,
+			VisibilityArchivalUri:   "file:///new-visibility",
+		},
+	})
+	s.NoError(err)
+
+	s.Equal("file:///old-history", report.History.OldURI)
+	s.Equal("s3://new-history", report.History.NewURI)
+	s.NoError(report.History.NewURIErr)
+	s.NoError(report.History.OldURIReadErr)
+	// the archival URI of a namespace cannot be changed once set
+	s.Error(report.History.UpdateErr)
+
+	s.Equal("file:///old-visibility", report.Visibility.OldURI)
+	s.NoError(report.Visibility.NewURIErr)
+	s.ErrorAs(report.Visibility.OldURIReadErr, new(*serviceerror.Unavailable))
+	s.Equal(errClusterIsNotConfiguredForVisibilityArchival, report.Visibility.UpdateErr)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_DisableArchivalPreservesURI() {
+	// the cluster has no default URI, the namespace URI is the only one to fall back on
+	s.archivalMetadata = archiver.NewArchivalMetadata(
+		dc.NewNoopCollection(),
+		"enabled",
+		true,
+		"",
+		false,
+		&config.ArchivalNamespaceDefaults{},
+	)
+	s.handler = s.newHandler()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	historyArchiver := archiver.NewMockHistoryArchiver(s.controller)
+	s.mockArchiverProvider.EXPECT().GetHistoryArchiver("file").Return(historyArchiver, nil).AnyTimes()
+	historyArchiver.EXPECT().ValidateURI(gomock.Any()).Return(nil).AnyTimes()
+
+	namespace := s.getRandomNamespace()
+	detail := &persistencespb.NamespaceDetail{
+		Info: &persistencespb.NamespaceInfo{Id: uuid.New(), Name: namespace},
+		Config: &persistencespb.NamespaceConfig{
+			Retention:            durationpb.New(24 * time.Hour),
+			HistoryArchivalState: enumspb.ARCHIVAL_STATE_ENABLED,
+			HistoryArchivalUri:   "file:///history",
+		},
+		ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+			ActiveClusterName: cluster.TestCurrentClusterName,
+			Clusters:          []string{cluster.TestCurrentClusterName},
+		},
+	}
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *persistence.GetNamespaceRequest) (*persistence.GetNamespaceResponse, error) {
+			return &persistence.GetNamespaceResponse{Namespace: common.CloneProto(detail)}, nil
+		},
+	).Times(2)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			detail = request.Namespace
+			return nil
+		},
+	).Times(2)
+
+	updateArchivalState := func(state enumspb.ArchivalState) {
+		_, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+			Namespace: namespace,
+			Config:    &namespacepb.NamespaceConfig{HistoryArchivalState: state},
+		})
+		s.NoError(err)
+	}
+
+	updateArchivalState(enumspb.ARCHIVAL_STATE_DISABLED)
+	s.Equal(enumspb.ARCHIVAL_STATE_DISABLED, detail.Config.HistoryArchivalState)
+	s.Equal("file:///history", detail.Config.HistoryArchivalUri)
+
+	updateArchivalState(enumspb.ARCHIVAL_STATE_ENABLED)
+	s.Equal(enumspb.ARCHIVAL_STATE_ENABLED, detail.Config.HistoryArchivalState)
+	s.Equal("file:///history", detail.Config.HistoryArchivalUri)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_ValidationFieldPath() {
+	s.archivalMetadata = archiver.NewArchivalMetadata(
+		dc.NewNoopCollection(),
+		"enabled",
+		true,
+		"",
+		false,
+		&config.ArchivalNamespaceDefaults{},
+	)
+	s.handler = s.newHandler()
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: int64(100),
+	}, nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(cluster.TestSingleDCClusterInfo).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	historyArchiver := archiver.NewMockHistoryArchiver(s.controller)
+	s.mockArchiverProvider.EXPECT().GetHistoryArchiver("file").Return(historyArchiver, nil).AnyTimes()
+	historyArchiver.EXPECT().ValidateURI(gomock.Any()).Return(nil).AnyTimes()
+
+	namespace := s.getRandomNamespace()
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{Id: uuid.New(), Name: namespace},
+			Config: &persistencespb.NamespaceConfig{
+				Retention:            durationpb.New(24 * time.Hour),
+				HistoryArchivalState: enumspb.ARCHIVAL_STATE_ENABLED,
+				HistoryArchivalUri:   "file:///history",
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters:          []string{cluster.TestCurrentClusterName},
+			},
+		},
+	}, nil).AnyTimes()
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Times(0)
+	s.mockVisibilityMgr.EXPECT().HasStoreName("unknown-store").Return(false).AnyTimes()
+
+	testCases := []struct {
+		name      string
+		request   *workflowservice.UpdateNamespaceRequest
+		options   []UpdateNamespaceOption
+		fieldPath string
+	}{
+		{
+			name: "retention",
+			request: &workflowservice.UpdateNamespaceRequest{
+				Config: &namespacepb.NamespaceConfig{WorkflowExecutionRetentionTtl: durationpb.New(time.Millisecond)},
+			},
+			fieldPath: "config.workflowExecutionRetentionTtl",
+		},
+		{
+			name: "archival",
+			request: &workflowservice.UpdateNamespaceRequest{
+				Config: &namespacepb.NamespaceConfig{
+					HistoryArchivalState: enumspb.ARCHIVAL_STATE_ENABLED,
+					HistoryArchivalUri:   "file:///other-history",
+				},
+			},
+			fieldPath: "config.historyArchivalUri",
+		},
+		{
+			name: "replication",
+			request: &workflowservice.UpdateNamespaceRequest{
+				ReplicationConfig: &replicationpb.NamespaceReplicationConfig{
+					Clusters: []*replicationpb.ClusterReplicationConfig{
+						{ClusterName: cluster.TestCurrentClusterName},
+						{ClusterName: cluster.TestAlternativeClusterName},
+					},
+				},
+			},
+			fieldPath: "replicationConfig",
+		},
+		{
+			name:      "data",
+			request:   &workflowservice.UpdateNamespaceRequest{},
+			options:   []UpdateNamespaceOption{WithRemoveNamespaceData("missing-key")},
+			fieldPath: "updateInfo.data",
+		},
+		{
+			name:      "custom search attribute aliases",
+			request:   &workflowservice.UpdateNamespaceRequest{},
+			options:   []UpdateNamespaceOption{WithRemoveCustomSearchAttributeAliases("Keyword01")},
+			fieldPath: "config.customSearchAttributeAliases",
+		},
+		{
+			name:      "bad binary",
+			request:   &workflowservice.UpdateNamespaceRequest{DeleteBadBinary: "missing-checksum"},
+			fieldPath: "deleteBadBinary",
+		},
+		{
+			name:      "visibility store",
+			request:   &workflowservice.UpdateNamespaceRequest{},
+			options:   []UpdateNamespaceOption{WithVisibilityStore("unknown-store", false)},
+			fieldPath: "config.visibilityStore",
+		},
+		{
+			name:      "signal rps",
+			request:   &workflowservice.UpdateNamespaceRequest{},
+			options:   []UpdateNamespaceOption{WithMaxSignalRPS(-1)},
+			fieldPath: "config.maxSignalRps",
+		},
+		{
+			name:      "query rps",
+			request:   &workflowservice.UpdateNamespaceRequest{},
+			options:   []UpdateNamespaceOption{WithMaxQueryRPS(-1)},
+			fieldPath: "config.maxQueryRps",
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			tc.request.Namespace = namespace
+			_, err := s.handler.UpdateNamespace(context.Background(), tc.request, tc.options...)
+			var invalidArgument *serviceerror.InvalidArgument
+			s.ErrorAs(err, &invalidArgument)
+			s.NotEmpty(invalidArgument.Message)
+			s.Equal([]string{tc.fieldPath}, fieldViolationPaths(err))
+		})
+	}
+}
+
+func (s *namespaceHandlerCommonSuite) TestGetNamespaceFailoverHistory() {
+	failoverTime := func(day int) *timestamppb.Timestamp {
+		return timestamppb.New(time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC))
+	}
+	newFailoverLog := func(versions ...int64) []*persistencespb.FailoverStatus {
+		var failoverLog []*persistencespb.FailoverStatus
+		for _, version := range versions {
+			failoverLog = append(failoverLog, &persistencespb.FailoverStatus{
+				FailoverTime:    failoverTime(int(version)),
+				FailoverVersion: version,
+			})
+		}
+		return failoverLog
+	}
+	// the last failover is only in the failover history, e.g. because it was recorded by an older server
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), gomock.Any()).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info:   &persistencespb.NamespaceInfo{Id: uuid.New(), Name: "test-namespace"},
+			Config: &persistencespb.NamespaceConfig{},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				FailoverHistory: newFailoverLog(4, 5, 6),
+				FailoverLog:     newFailoverLog(1, 2, 3, 4, 5),
+			},
+		},
+	}, nil).AnyTimes()
+	failoverVersions := func(response *GetNamespaceFailoverHistoryResponse) []int64 {
+		var versions []int64
+		for _, status := range response.FailoverHistory {
+			versions = append(versions, status.GetFailoverVersion())
+		}
+		return versions
+	}
+
+	request := &GetNamespaceFailoverHistoryRequest{Namespace: "test-namespace", PageSize: 4}
+	response, err := s.handler.GetNamespaceFailoverHistory(context.Background(), request)
+	s.NoError(err)
+	s.Equal([]int64{1, 2, 3, 4}, failoverVersions(response))
+	protoassert.ProtoEqual(s.T(), failoverTime(1), response.FailoverHistory[0].GetFailoverTime())
+	s.NotNil(response.NextPageToken)
+
+	request.NextPageToken = response.NextPageToken
+	response, err = s.handler.GetNamespaceFailoverHistory(context.Background(), request)
+	s.NoError(err)
+	s.Equal([]int64{5, 6}, failoverVersions(response))
+	s.Nil(response.NextPageToken)
+
+	// the start time is inclusive and the end time exclusive
+	response, err = s.handler.GetNamespaceFailoverHistory(context.Background(), &GetNamespaceFailoverHistoryRequest{
+		Namespace: "test-namespace",
+		StartTime: failoverTime(2).AsTime(),
+		EndTime:   failoverTime(5).AsTime(),
+		PageSize:  2,
+	})
+	s.NoError(err)
+	s.Equal([]int64{2, 3}, failoverVersions(response))
+	response, err = s.handler.GetNamespaceFailoverHistory(context.Background(), &GetNamespaceFailoverHistoryRequest{
+		Namespace:     "test-namespace",
+		StartTime:     failoverTime(2).AsTime(),
+		EndTime:       failoverTime(5).AsTime(),
+		PageSize:      2,
+		NextPageToken: response.NextPageToken,
+	})
+	s.NoError(err)
+	s.Equal([]int64{4}, failoverVersions(response))
+	s.Nil(response.NextPageToken)
+
+	var invalidArgument *serviceerror.InvalidArgument
+	_, err = s.handler.GetNamespaceFailoverHistory(context.Background(), &GetNamespaceFailoverHistoryRequest{
+		Namespace:     "test-namespace",
+		NextPageToken: []byte("invalid"),
+	})
+	s.ErrorAs(err, &invalidArgument)
+	_, err = s.handler.GetNamespaceFailoverHistory(context.Background(), &GetNamespaceFailoverHistoryRequest{
+		Namespace: "test-namespace",
+		StartTime: failoverTime(5).AsTime(),
+		EndTime:   failoverTime(2).AsTime(),
+	})
+	s.ErrorAs(err, &invalidArgument)
+}
+
+// fieldViolationPaths returns the fields of the BadRequest field violations of err.
+func fieldViolationPaths(err error) []string {
+	var paths []string
+	for _, detail := range serviceerror.ToStatus(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				paths = append(paths, violation.GetField())
+			}
+		}
+	}
+	return paths
 }
@@ -603,6 +603,13 @@ func (h *OperatorHandlerImpl) DeleteNamespace(
 		return nil, delnserrors.ToServiceError(err, run.GetID(), run.GetRunID())
 	}
 
+	if len(wfResult.OrphanedArchivalURIs) > 0 {
+		h.logger.Info("Namespace deleted with orphaned archival URIs.",
+			tag.WorkflowNamespace(request.GetNamespace()),
+			tag.WorkflowNamespaceID(wfResult.DeletedNamespaceID.String()),
+			tag.NewStringsTag("archival-uris", wfResult.OrphanedArchivalURIs))
+	}
+
 	return &operatorservice.DeleteNamespaceResponse{
 		DeletedNamespace: wfResult.DeletedNamespace.String(),
 	}, nil
@@ -80,6 +80,19 @@ type Config struct {
 
 	MaxBadBinaries dynamicconfig.IntPropertyFnWithNamespaceFilter
 
+	MaxReplicationClustersPerNamespace dynamicconfig.IntPropertyFnWithNamespaceFilter
+
+	// guard against fat-fingered retention reductions
+	RetentionReductionGuardEnabled dynamicconfig.BoolPropertyFnWithNamespaceFilter
+	MaxRetentionReductionPercent   dynamicconfig.IntPropertyFnWithNamespaceFilter
+	MaxRetentionReduction          dynamicconfig.DurationPropertyFnWithNamespaceFilter
+
+	// limits of the data of a namespace
+	NamespaceDataMaxKeys        dynamicconfig.IntPropertyFnWithNamespaceFilter
+	NamespaceDataMaxKeyLength   dynamicconfig.IntPropertyFnWithNamespaceFilter
+	NamespaceDataMaxValueLength dynamicconfig.IntPropertyFnWithNamespaceFilter
+	NamespaceDataMaxSize        dynamicconfig.IntPropertyFnWithNamespaceFilter
+
 	// security protection settings
 	DisableListVisibilityByFilter dynamicconfig.BoolPropertyFnWithNamespaceFilter
 
@@ -205,6 +218,29 @@ type Config struct {
 	WorkflowRulesAPIsEnabled     dynamicconfig.BoolPropertyFnWithNamespaceFilter
 	MaxWorkflowRulesPerNamespace dynamicconfig.IntPropertyFnWithNamespaceFilter
 
+	DescribeNamespaceRPS             dynamicconfig.IntPropertyFn
+	DescribeNamespacePerNamespaceRPS dynamicconfig.IntPropertyFnWithNamespaceFilter
+	ListNamespacesRPS                dynamicconfig.IntPropertyFn
+	DescribeNamespaceCacheEnabled    dynamicconfig.BoolPropertyFn
+	DescribeNamespaceCacheTTL        dynamicconfig.DurationPropertyFn
+	NamespaceOperationTimeout        dynamicconfig.DurationPropertyFn
+
+	ListWorkflowRulesAcrossNamespacesRPS dynamicconfig.IntPropertyFn
+	ListNamespacesByArchivalStateRPS     dynamicconfig.IntPropertyFn
+	BatchFailoverNamespacesRPS           dynamicconfig.IntPropertyFn
+
+	NamespaceMaxConcurrentOpenWorkflowsCeiling dynamicconfig.IntPropertyFn
+	NamespaceMaxSignalRPSCeiling               dynamicconfig.FloatPropertyFn
+	NamespaceMaxQueryRPSCeiling                dynamicconfig.FloatPropertyFn
+	FailoverVersionAllocator                   dynamicconfig.StringPropertyFnWithNamespaceFilter
+	NamespaceNamePattern                       dynamicconfig.StringPropertyFn
+	ReplicationHealthThresholds                dynamicconfig.TypedPropertyFn[dynamicconfig.ReplicationHealthThresholds]
+	AllowNamespaceUndeprecation                dynamicconfig.BoolPropertyFnWithNamespaceFilter
+	NamespaceConfigChangeMaxCount              dynamicconfig.IntPropertyFnWithNamespaceFilter
+	NamespaceConfigChangeWindow                dynamicconfig.DurationPropertyFnWithNamespaceFilter
+	MaxNamespaceFailoverHistorySize            dynamicconfig.IntPropertyFn
+	EnableNamespaceNameReuse                   dynamicconfig.BoolPropertyFn
+
 	WorkerHeartbeatsEnabled dynamicconfig.BoolPropertyFnWithNamespaceFilter
 	ListWorkersEnabled      dynamicconfig.BoolPropertyFnWithNamespaceFilter
 	WorkerCommandsEnabled   dynamicconfig.BoolPropertyFnWithNamespaceFilter
@@ -267,7 +303,15 @@ func NewConfig(
 		ReachabilityCacheOpenWFsTTL:              dynamicconfig.ReachabilityCacheOpenWFsTTL.Get(dc),
 		ReachabilityCacheClosedWFsTTL:            dynamicconfig.ReachabilityCacheClosedWFsTTL.Get(dc),
 		ReachabilityQuerySetDurationSinceDefault: dynamicconfig.ReachabilityQuerySetDurationSinceDefault.Get(dc),
+		MaxReplicationClustersPerNamespace:       dynamicconfig.MaxReplicationClustersPerNamespace.Get(dc),
+		RetentionReductionGuardEnabled:           dynamicconfig.FrontendRetentionReductionGuardEnabled.Get(dc),
+		MaxRetentionReductionPercent:             dynamicconfig.FrontendMaxRetentionReductionPercent.Get(dc),
+		MaxRetentionReduction:                    dynamicconfig.FrontendMaxRetentionReduction.Get(dc),
 		MaxBadBinaries:                           dynamicconfig.FrontendMaxBadBinaries.Get(dc),
+		NamespaceDataMaxKeys:                     dynamicconfig.FrontendNamespaceDataMaxKeys.Get(dc),
+		NamespaceDataMaxKeyLength:                dynamicconfig.FrontendNamespaceDataMaxKeyLength.Get(dc),
+		NamespaceDataMaxValueLength:              dynamicconfig.FrontendNamespaceDataMaxValueLength.Get(dc),
+		NamespaceDataMaxSize:                     dynamicconfig.FrontendNamespaceDataMaxSize.Get(dc),
 		DisableListVisibilityByFilter:            dynamicconfig.DisableListVisibilityByFilter.Get(dc),
 		BlobSizeLimitError:                       dynamicconfig.BlobSizeLimitError.Get(dc),
 		BlobSizeLimitWarn:                        dynamicconfig.BlobSizeLimitWarn.Get(dc),
@@ -345,6 +389,29 @@ func NewConfig(
 		ListWorkersEnabled:             dynamicconfig.ListWorkersEnabled.Get(dc),
 		WorkerCommandsEnabled:          dynamicconfig.WorkerCommandsEnabled.Get(dc),
 
+		DescribeNamespaceRPS:             dynamicconfig.FrontendDescribeNamespaceRPS.Get(dc),
+		DescribeNamespacePerNamespaceRPS: dynamicconfig.FrontendDescribeNamespacePerNamespaceRPS.Get(dc),
+		ListNamespacesRPS:                dynamicconfig.FrontendListNamespacesRPS.Get(dc),
+		DescribeNamespaceCacheEnabled:    dynamicconfig.FrontendDescribeNamespaceCacheEnabled.Get(dc),
+		DescribeNamespaceCacheTTL:        dynamicconfig.FrontendDescribeNamespaceCacheTTL.Get(dc),
+		NamespaceOperationTimeout:        dynamicconfig.FrontendNamespaceOperationTimeout.Get(dc),
+
+		ListWorkflowRulesAcrossNamespacesRPS: dynamicconfig.FrontendListWorkflowRulesAcrossNamespacesRPS.Get(dc),
+		ListNamespacesByArchivalStateRPS:     dynamicconfig.FrontendListNamespacesByArchivalStateRPS.Get(dc),
+		BatchFailoverNamespacesRPS:           dynamicconfig.FrontendBatchFailoverNamespacesRPS.Get(dc),
+
+		NamespaceMaxConcurrentOpenWorkflowsCeiling: dynamicconfig.FrontendNamespaceMaxConcurrentOpenWorkflowsCeiling.Get(dc),
+		NamespaceMaxSignalRPSCeiling:               dynamicconfig.FrontendNamespaceMaxSignalRPSCeiling.Get(dc),
+		NamespaceMaxQueryRPSCeiling:                dynamicconfig.FrontendNamespaceMaxQueryRPSCeiling.Get(dc),
+		FailoverVersionAllocator:                   dynamicconfig.FrontendFailoverVersionAllocator.Get(dc),
+		NamespaceNamePattern:                       dynamicconfig.FrontendNamespaceNamePattern.Get(dc),
+		ReplicationHealthThresholds:                dynamicconfig.FrontendReplicationHealthThresholds.Get(dc),
+		AllowNamespaceUndeprecation:                dynamicconfig.FrontendAllowNamespaceUndeprecation.Get(dc),
+		NamespaceConfigChangeMaxCount:              dynamicconfig.FrontendNamespaceConfigChangeMaxCount.Get(dc),
+		NamespaceConfigChangeWindow:                dynamicconfig.FrontendNamespaceConfigChangeWindow.Get(dc),
+		MaxNamespaceFailoverHistorySize:            dynamicconfig.FrontendMaxNamespaceFailoverHistorySize.Get(dc),
+		EnableNamespaceNameReuse:                   dynamicconfig.FrontendEnableNamespaceNameReuse.Get(dc),
+
 		HTTPAllowedHosts: dynamicconfig.FrontendHTTPAllowedHosts.Get(dc),
 	}
 }
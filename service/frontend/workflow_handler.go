@@ -156,6 +156,7 @@ func NewWorkflowHandler(
 	persistenceExecutionName string,
 	clusterMetadataManager persistence.ClusterMetadataManager,
 	persistenceMetadataManager persistence.MetadataManager,
+	healthSignals persistence.HealthSignalAggregator,
 	historyClient historyservice.HistoryServiceClient,
 	matchingClient matchingservice.MatchingServiceClient,
 	deploymentStoreClient deployment.DeploymentStoreClient,
@@ -173,6 +174,8 @@ func NewWorkflowHandler(
 	healthInterceptor *interceptor.HealthInterceptor,
 	scheduleSpecBuilder *scheduler.SpecBuilder,
 	httpEnabled bool,
+	failoverVersionAllocators FailoverVersionAllocators,
+	metricsHandler metrics.Handler,
 ) *WorkflowHandler {
 	handler := &WorkflowHandler{
 		status:          common.DaemonStatusInitialized,
@@ -182,12 +185,21 @@ func NewWorkflowHandler(
 		namespaceHandler: newNamespaceHandler(
 			logger,
 			persistenceMetadataManager,
+			namespaceRegistry,
 			clusterMetadata,
 			nsreplication.NewReplicator(namespaceReplicationQueue, logger),
 			archivalMetadata,
 			archiverProvider,
 			timeSource,
 			config,
+			visibilityMgr,
+			saProvider,
+			saMapperProvider,
+			namespaceReplicationQueue,
+			historyClient,
+			healthSignals,
+			failoverVersionAllocators,
+			metricsHandler,
 		),
 		getDefaultWorkflowRetrySettings: config.DefaultWorkflowRetryPolicy,
 		visibilityMgr:                   visibilityMgr,
@@ -367,7 +379,7 @@ func (wh *WorkflowHandler) DeprecateNamespace(ctx context.Context, request *work
 	if err != nil {
 		return nil, err
 	}
-	return resp, err
+	return resp.DeprecateNamespaceResponse, nil
 }
 
 // StartWorkflowExecution starts a new workflow instance (a "workflow execution").  It will create the instance with
@@ -4202,13 +4214,26 @@ func (wh *WorkflowHandler) prepareUpdateWorkflowRequest(
 	}
 
 	if request.WaitPolicy.LifecycleStage == enumspb.UPDATE_WORKFLOW_EXECUTION_LIFECYCLE_STAGE_ACCEPTED &&
-		!wh.config.EnableUpdateWorkflowExecutionAsyncAccepted(request.Namespace) {
+		!wh.asyncUpdateAcceptedEnabled(request.Namespace) {
 		return errUpdateWorkflowExecutionAsyncAcceptedNotAllowed
 	}
 
 	return nil
 }
 
+// asyncUpdateAcceptedEnabled returns whether updates may be accepted asynchronously in the namespace, honoring the
+// namespace override of the cluster default.
+func (wh *WorkflowHandler) asyncUpdateAcceptedEnabled(nsName string) bool {
+	clusterDefault := wh.config.EnableUpdateWorkflowExecutionAsyncAccepted(nsName)
+	nsEntry, err := wh.namespaceRegistry.GetNamespace(namespace.Name(nsName))
+	if err != nil {
+		// The lookup is repeated, and the error returned, when the update is routed to history.
+		return clusterDefault
+	}
+	enabled, _ := effectiveAsyncUpdate(nsEntry.AsyncUpdateOverride(), clusterDefault)
+	return enabled
+}
+
 func (wh *WorkflowHandler) PollWorkflowExecutionUpdate(
 	ctx context.Context,
 	request *workflowservice.PollWorkflowExecutionUpdateRequest,
@@ -5937,7 +5962,7 @@ func (wh *WorkflowHandler) DeleteWorkflowRule(
 		return nil, serviceerror.NewInvalidArgument("Workflow Rule ID is not set.")
 	}
 
-	err := wh.namespaceHandler.DeleteWorkflowRule(ctx, request.GetRuleId(), request.GetNamespace())
+	_, err := wh.namespaceHandler.DeleteWorkflowRule(ctx, request.GetRuleId(), request.GetNamespace())
 	if err != nil {
 		return nil, err
 	}
@@ -5959,11 +5984,11 @@ func (wh *WorkflowHandler) ListWorkflowRules(
 		return nil, errRequestNotSet
 	}
 
-	workflowRules, err := wh.namespaceHandler.ListWorkflowRules(ctx, request.GetNamespace())
+	resp, err := wh.namespaceHandler.ListWorkflowRules(ctx, request.GetNamespace(), 0, request.GetNextPageToken())
 	if err != nil {
 		return nil, err
 	}
-	return &workflowservice.ListWorkflowRulesResponse{Rules: workflowRules}, nil
+	return &workflowservice.ListWorkflowRulesResponse{Rules: resp.Rules, NextPageToken: resp.NextPageToken}, nil
 }
 
 // RecordWorkerHeartbeat receive heartbeat request from the worker
@@ -172,6 +172,7 @@ func (s *WorkflowHandlerSuite) getWorkflowHandler(config *Config) *WorkflowHandl
 		s.mockResource.GetExecutionManager().GetName(),
 		s.mockResource.GetClusterMetadataManager(),
 		s.mockResource.GetMetadataManager(),
+		persistence.NoopHealthSignalAggregator,
 		s.mockResource.GetHistoryClient(),
 		s.mockResource.GetMatchingClient(),
 		nil,
@@ -189,6 +190,8 @@ func (s *WorkflowHandlerSuite) getWorkflowHandler(config *Config) *WorkflowHandl
 		healthInterceptor,
 		scheduler.NewSpecBuilder(),
 		true,
+		nil,
+		s.mockResource.MetricsHandler,
 	)
 }
 
@@ -1900,6 +1903,31 @@ func (s *WorkflowHandlerSuite) TestListArchivedVisibility_Failure_ClusterNotConf
 	s.Error(err)
 }
 
+func (s *WorkflowHandlerSuite) TestAsyncUpdateAcceptedEnabled_NamespaceOverride() {
+	config := s.newConfig()
+	config.EnableUpdateWorkflowExecutionAsyncAccepted = dc.GetBoolPropertyFnFilteredByNamespace(false)
+	wh := s.getWorkflowHandler(config)
+
+	s.mockNamespaceCache.EXPECT().GetNamespace(namespace.Name("override-ns")).Return(namespace.NewLocalNamespaceForTest(
+		&persistencespb.NamespaceInfo{Name: "override-ns"},
+		&persistencespb.NamespaceConfig{
+			AsyncUpdateOverride: &persistencespb.AsyncUpdateOverride{Enabled: true},
+		},
+		"",
+	), nil)
+	s.True(wh.asyncUpdateAcceptedEnabled("override-ns"))
+
+	s.mockNamespaceCache.EXPECT().GetNamespace(namespace.Name("default-ns")).Return(namespace.NewLocalNamespaceForTest(
+		&persistencespb.NamespaceInfo{Name: "default-ns"},
+		&persistencespb.NamespaceConfig{},
+		"",
+	), nil)
+	s.False(wh.asyncUpdateAcceptedEnabled("default-ns"))
+
+	s.mockNamespaceCache.EXPECT().GetNamespace(namespace.Name("missing-ns")).Return(nil, serviceerror.NewNamespaceNotFound("missing-ns"))
+	s.False(wh.asyncUpdateAcceptedEnabled("missing-ns"))
+}
+
 func (s *WorkflowHandlerSuite) TestListArchivedVisibility_Failure_NamespaceCacheEntryError() {
 	s.mockNamespaceCache.EXPECT().GetNamespace(gomock.Any()).Return(nil, errors.New("error getting namespace"))
 	s.mockArchivalMetadata.EXPECT().GetVisibilityConfig().Return(archiver.NewArchivalConfig("enabled", dc.GetStringPropertyFn("enabled"), dc.GetBoolPropertyFn(true), "disabled", "random URI")).Times(2)
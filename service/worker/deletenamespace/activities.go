@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	enumspb "go.temporal.io/api/enums/v1"
 	"go.temporal.io/api/serviceerror"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
 	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/dynamicconfig"
 	"go.temporal.io/server/common/headers"
@@ -24,7 +26,9 @@ type (
 		metadataManager      persistence.MetadataManager
 		clusterMetadata      cluster.Metadata
 		nexusEndpointManager persistence.NexusEndpointManager
-		logger               log.Logger
+		// orphanedArchivalStore is nil if no store is configured.
+		orphanedArchivalStore OrphanedArchivalStore
+		logger                log.Logger
 
 		protectedNamespaces                       dynamicconfig.TypedPropertyFn[[]string]
 		allowDeleteNamespaceIfNexusEndpointTarget dynamicconfig.BoolPropertyFn
@@ -37,6 +41,23 @@ type (
 		Clusters       []string
 		ActiveCluster  string
 		CurrentCluster string
+		// ArchivalURIs are the history and visibility archival URIs configured for the namespace.
+		ArchivalURIs []string
+	}
+
+	// OrphanedArchivalStore persists the archival URIs of deleted namespaces. Archived data is not deleted
+	// with the namespace, a separate cleanup job reads the store to reclaim it.
+	OrphanedArchivalStore interface {
+		// RecordOrphanedArchival must be idempotent, it is retried with the same entry.
+		RecordOrphanedArchival(ctx context.Context, orphanedArchival OrphanedArchival) error
+	}
+
+	// OrphanedArchival is the archival data left behind by a deleted namespace.
+	OrphanedArchival struct {
+		NamespaceID  namespace.ID
+		Namespace    namespace.Name
+		ArchivalURIs []string
+		DeleteTime   time.Time
 	}
 )
 
@@ -44,17 +65,19 @@ func newLocalActivities(
 	metadataManager persistence.MetadataManager,
 	clusterMetadata cluster.Metadata,
 	nexusEndpointManager persistence.NexusEndpointManager,
+	orphanedArchivalStore OrphanedArchivalStore,
 	logger log.Logger,
 	protectedNamespaces dynamicconfig.TypedPropertyFn[[]string],
 	allowDeleteNamespaceIfNexusEndpointTarget dynamicconfig.BoolPropertyFn,
 	nexusEndpointListDefaultPageSize dynamicconfig.IntPropertyFn,
 ) *localActivities {
 	return &localActivities{
-		metadataManager:      metadataManager,
-		clusterMetadata:      clusterMetadata,
-		nexusEndpointManager: nexusEndpointManager,
-		logger:               logger,
-		protectedNamespaces:  protectedNamespaces,
+		metadataManager:       metadataManager,
+		clusterMetadata:       clusterMetadata,
+		nexusEndpointManager:  nexusEndpointManager,
+		orphanedArchivalStore: orphanedArchivalStore,
+		logger:                logger,
+		protectedNamespaces:   protectedNamespaces,
 		allowDeleteNamespaceIfNexusEndpointTarget: allowDeleteNamespaceIfNexusEndpointTarget,
 		nexusEndpointListDefaultPageSize:          nexusEndpointListDefaultPageSize,
 	}
@@ -93,9 +116,22 @@ func (a *localActivities) GetNamespaceInfoActivity(ctx context.Context, nsID nam
 		// CurrentCluster is not technically a "namespace info", but since all cluster data is here,
 		// it is convenient to have the current cluster name here too.
 		CurrentCluster: a.clusterMetadata.GetCurrentClusterName(),
+		ArchivalURIs:   archivalURIs(getNamespaceResponse.Namespace.Config),
 	}, nil
 }
 
+// archivalURIs returns the archival URIs set on the namespace config. URIs are returned even if
+// archival is currently disabled because data archived while it was enabled is still in the store.
+func archivalURIs(config *persistencespb.NamespaceConfig) []string {
+	var uris []string
+	for _, uri := range []string{config.GetHistoryArchivalUri(), config.GetVisibilityArchivalUri()} {
+		if uri != "" && !slices.Contains(uris, uri) {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
 func (a *localActivities) ValidateProtectedNamespacesActivity(_ context.Context, nsName namespace.Name) error {
 	if slices.Contains(a.protectedNamespaces(), nsName.String()) {
 		return errors.NewFailedPrecondition(fmt.Sprintf("namespace %s is protected from deletion", nsName), nil)
@@ -168,6 +204,29 @@ func (a *localActivities) MarkNamespaceDeletedActivity(ctx context.Context, nsNa
 	return nil
 }
 
+// RecordOrphanedArchivalActivity persists the archival URIs of the deleted namespace for the cleanup job.
+// If no store is configured, the URIs are only returned in the workflow result.
+func (a *localActivities) RecordOrphanedArchivalActivity(ctx context.Context, orphanedArchival OrphanedArchival) error {
+	logger := log.With(a.logger,
+		tag.WorkflowNamespace(orphanedArchival.Namespace.String()),
+		tag.WorkflowNamespaceID(orphanedArchival.NamespaceID.String()),
+		tag.NewStringsTag("archival-uris", orphanedArchival.ArchivalURIs))
+
+	if a.orphanedArchivalStore == nil {
+		logger.Warn("Orphaned archival URIs are not persisted because no orphaned archival store is configured.")
+		return nil
+	}
+
+	ctx = headers.SetCallerName(ctx, orphanedArchival.Namespace.String())
+
+	if err := a.orphanedArchivalStore.RecordOrphanedArchival(ctx, orphanedArchival); err != nil {
+		logger.Error("Unable to record orphaned archival URIs.", tag.Error(err))
+		return err
+	}
+	logger.Info("Orphaned archival URIs recorded.")
+	return nil
+}
+
 func (a *localActivities) GenerateDeletedNamespaceNameActivity(ctx context.Context, nsID namespace.ID, nsName namespace.Name) (namespace.Name, error) {
 	ctx = headers.SetCallerName(ctx, nsName.String())
 
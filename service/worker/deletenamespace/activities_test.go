@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	enumspb "go.temporal.io/api/enums/v1"
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/temporal"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence"
@@ -94,3 +97,82 @@ func Test_ValidateNexusEndpointsActivity(t *testing.T) {
 
 	ctrl.Finish()
 }
+
+func Test_GetNamespaceInfoActivity_ArchivalURIs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	metadataManager := persistence.NewMockMetadataManager(ctrl)
+	clusterMetadata := cluster.NewMockMetadata(ctrl)
+	clusterMetadata.EXPECT().GetCurrentClusterName().Return("active").AnyTimes()
+
+	a := &localActivities{
+		metadataManager: metadataManager,
+		clusterMetadata: clusterMetadata,
+		logger:          log.NewTestLogger(),
+	}
+
+	metadataManager.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{
+		Name: "namespace",
+	}).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{Id: "namespace-id", Name: "namespace"},
+			Config: &persistencespb.NamespaceConfig{
+				HistoryArchivalState:    enumspb.ARCHIVAL_STATE_DISABLED,
+				HistoryArchivalUri:      "s3://bucket/history",
+				VisibilityArchivalState: enumspb.ARCHIVAL_STATE_ENABLED,
+				VisibilityArchivalUri:   "s3://bucket/visibility",
+			},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{ActiveClusterName: "active"},
+		},
+	}, nil)
+	nsInfo, err := a.GetNamespaceInfoActivity(context.Background(), namespace.EmptyID, "namespace")
+	require.NoError(t, err)
+	require.Equal(t, []string{"s3://bucket/history", "s3://bucket/visibility"}, nsInfo.ArchivalURIs)
+
+	metadataManager.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{
+		Name: "namespace",
+	}).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info:              &persistencespb.NamespaceInfo{Id: "namespace-id", Name: "namespace"},
+			Config:            &persistencespb.NamespaceConfig{},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{ActiveClusterName: "active"},
+		},
+	}, nil)
+	nsInfo, err = a.GetNamespaceInfoActivity(context.Background(), namespace.EmptyID, "namespace")
+	require.NoError(t, err)
+	require.Empty(t, nsInfo.ArchivalURIs)
+}
+
+type testOrphanedArchivalStore struct {
+	recorded []OrphanedArchival
+	err      error
+}
+
+func (s *testOrphanedArchivalStore) RecordOrphanedArchival(_ context.Context, orphanedArchival OrphanedArchival) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.recorded = append(s.recorded, orphanedArchival)
+	return nil
+}
+
+func Test_RecordOrphanedArchivalActivity(t *testing.T) {
+	orphanedArchival := OrphanedArchival{
+		NamespaceID:  "namespace-id",
+		Namespace:    "namespace",
+		ArchivalURIs: []string{"s3://bucket/history", "s3://bucket/visibility"},
+		DeleteTime:   time.Now(),
+	}
+
+	// Without a store, URIs are not persisted and deletion is not blocked.
+	a := &localActivities{logger: log.NewTestLogger()}
+	require.NoError(t, a.RecordOrphanedArchivalActivity(context.Background(), orphanedArchival))
+
+	store := &testOrphanedArchivalStore{}
+	a.orphanedArchivalStore = store
+	require.NoError(t, a.RecordOrphanedArchivalActivity(context.Background(), orphanedArchival))
+	require.Equal(t, []OrphanedArchival{orphanedArchival}, store.recorded)
+
+	storeErr := errors.New("store is unavailable")
+	store.err = storeErr
+	require.ErrorIs(t, a.RecordOrphanedArchivalActivity(context.Background(), orphanedArchival), storeErr)
+}
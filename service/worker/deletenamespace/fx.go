@@ -28,9 +28,11 @@ type (
 		metadataManager      persistence.MetadataManager
 		clusterMetadata      cluster.Metadata
 		nexusEndpointManager persistence.NexusEndpointManager
-		historyClient        resource.HistoryClient
-		metricsHandler       metrics.Handler
-		logger               log.Logger
+		// orphanedArchivalStore is nil if no store is configured.
+		orphanedArchivalStore OrphanedArchivalStore
+		historyClient         resource.HistoryClient
+		metricsHandler        metrics.Handler
+		logger                log.Logger
 
 		protectedNamespaces                       dynamicconfig.TypedPropertyFn[[]string]
 		allowDeleteNamespaceIfNexusEndpointTarget dynamicconfig.BoolPropertyFn
@@ -45,9 +47,11 @@ type (
 		MetadataManager      persistence.MetadataManager
 		ClusterMetadata      cluster.Metadata
 		NexusEndpointManager persistence.NexusEndpointManager
-		HistoryClient        resource.HistoryClient
-		MetricsHandler       metrics.Handler
-		Logger               log.Logger
+		// OrphanedArchivalStore is optional, without it orphaned archival URIs are only returned in the workflow result.
+		OrphanedArchivalStore OrphanedArchivalStore `optional:"true"`
+		HistoryClient         resource.HistoryClient
+		MetricsHandler        metrics.Handler
+		Logger                log.Logger
 	}
 )
 
@@ -57,15 +61,16 @@ func newComponent(
 	params componentParams,
 ) workercommon.WorkerComponent {
 	return &deleteNamespaceComponent{
-		atWorkerCfg:          dynamicconfig.WorkerDeleteNamespaceActivityLimits.Get(params.DynamicCollection)(),
-		visibilityManager:    params.VisibilityManager,
-		metadataManager:      params.MetadataManager,
-		clusterMetadata:      params.ClusterMetadata,
-		nexusEndpointManager: params.NexusEndpointManager,
-		historyClient:        params.HistoryClient,
-		metricsHandler:       params.MetricsHandler,
-		logger:               params.Logger,
-		protectedNamespaces:  dynamicconfig.ProtectedNamespaces.Get(params.DynamicCollection),
+		atWorkerCfg:           dynamicconfig.WorkerDeleteNamespaceActivityLimits.Get(params.DynamicCollection)(),
+		visibilityManager:     params.VisibilityManager,
+		metadataManager:       params.MetadataManager,
+		clusterMetadata:       params.ClusterMetadata,
+		nexusEndpointManager:  params.NexusEndpointManager,
+		orphanedArchivalStore: params.OrphanedArchivalStore,
+		historyClient:         params.HistoryClient,
+		metricsHandler:        params.MetricsHandler,
+		logger:                params.Logger,
+		protectedNamespaces:   dynamicconfig.ProtectedNamespaces.Get(params.DynamicCollection),
 		allowDeleteNamespaceIfNexusEndpointTarget: dynamicconfig.AllowDeleteNamespaceIfNexusEndpointTarget.Get(params.DynamicCollection),
 		nexusEndpointListDefaultPageSize:          dynamicconfig.NexusEndpointListDefaultPageSize.Get(params.DynamicCollection),
 		deleteActivityRPS:                         dynamicconfig.DeleteNamespaceDeleteActivityRPS.Subscribe(params.DynamicCollection),
@@ -112,6 +117,7 @@ func (wc *deleteNamespaceComponent) deleteNamespaceLocalActivities() *localActiv
 		wc.metadataManager,
 		wc.clusterMetadata,
 		wc.nexusEndpointManager,
+		wc.orphanedArchivalStore,
 		wc.logger,
 		wc.protectedNamespaces,
 		wc.allowDeleteNamespaceIfNexusEndpointTarget,
@@ -10,6 +10,7 @@ import (
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/primitives"
 	"go.temporal.io/server/service/worker/deletenamespace/deleteexecutions"
@@ -38,6 +39,10 @@ type (
 	DeleteNamespaceWorkflowResult struct {
 		DeletedNamespaceID namespace.ID
 		DeletedNamespace   namespace.Name
+		// OrphanedArchivalURIs are the archival URIs of the deleted namespace. Archived data is not
+		// deleted with the namespace and must be reclaimed from these URIs by a separate cleanup job,
+		// which reads them from the OrphanedArchivalStore.
+		OrphanedArchivalURIs []string
 	}
 )
 
@@ -156,6 +161,28 @@ func DeleteNamespaceWorkflow(ctx workflow.Context, params DeleteNamespaceWorkflo
 	}
 
 	result.DeletedNamespaceID = params.NamespaceID
+	result.OrphanedArchivalURIs = namespaceInfo.ArchivalURIs
+	if len(result.OrphanedArchivalURIs) > 0 {
+		logger.Info("Namespace archival URIs are orphaned.", tag.NewStringsTag("archival-uris", result.OrphanedArchivalURIs))
+
+		// Step 2.1. Record orphaned archival URIs for the cleanup job. This is best effort: the namespace is already
+		// marked as deleted, and failing here would leave it neither usable nor deleted. The URIs are still returned
+		// in the workflow result.
+		if v := workflow.GetVersion(ctx, "record-orphaned-archival", workflow.DefaultVersion, 0); v != workflow.DefaultVersion {
+			ctx21 := workflow.WithLocalActivityOptions(ctx, localActivityOptions)
+			err = workflow.ExecuteLocalActivity(ctx21, la.RecordOrphanedArchivalActivity, OrphanedArchival{
+				NamespaceID:  params.NamespaceID,
+				Namespace:    params.Namespace,
+				ArchivalURIs: result.OrphanedArchivalURIs,
+				DeleteTime:   workflow.Now(ctx),
+			}).Get(ctx, nil)
+			if err != nil {
+				logger.Error("Unable to record orphaned archival URIs.", tag.Error(err))
+				workflow.GetMetricsHandler(ctx).WithTags(map[string]string{"namespace": params.Namespace.String()}).
+					Counter(metrics.DeleteNamespaceRecordOrphanedArchivalFailureCount.Name()).Inc(1)
+			}
+		}
+	}
 
 	// Step 3. Rename namespace.
 	ctx3 := workflow.WithLocalActivityOptions(ctx, localActivityOptions)
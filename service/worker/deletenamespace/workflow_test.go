@@ -19,16 +19,22 @@ func Test_DeleteNamespaceWorkflow_ByName(t *testing.T) {
 	testSuite := &testsuite.WorkflowTestSuite{}
 	testSuite.SetLogger(log.NewSdkLogger(log.NewTestLogger()))
 	env := testSuite.NewTestWorkflowEnvironment()
-	var la *localActivities
+	store := &testOrphanedArchivalStore{}
+	la := &localActivities{
+		orphanedArchivalStore: store,
+		logger:                log.NewTestLogger(),
+	}
 
 	env.OnActivity(la.GetNamespaceInfoActivity, mock.Anything, namespace.EmptyID, namespace.Name("namespace")).Return(
 		getNamespaceInfoResult{
-			NamespaceID: "namespace-id",
-			Namespace:   "namespace",
+			NamespaceID:  "namespace-id",
+			Namespace:    "namespace",
+			ArchivalURIs: []string{"file:///tmp/archival"},
 		}, nil).Once()
 	env.OnActivity(la.ValidateProtectedNamespacesActivity, mock.Anything, mock.Anything).Return(nil).Once()
 	env.OnActivity(la.ValidateNexusEndpointsActivity, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 	env.OnActivity(la.MarkNamespaceDeletedActivity, mock.Anything, namespace.Name("namespace")).Return(nil).Once()
+	env.RegisterActivity(la.RecordOrphanedArchivalActivity)
 	env.OnActivity(la.GenerateDeletedNamespaceNameActivity, mock.Anything, namespace.ID("namespace-id"), namespace.Name("namespace")).Return(namespace.Name("namespace-delete-220878"), nil).Once()
 	env.OnActivity(la.RenameNamespaceActivity, mock.Anything, namespace.Name("namespace"), namespace.Name("namespace-delete-220878")).Return(nil).Once()
 
@@ -59,6 +65,50 @@ func Test_DeleteNamespaceWorkflow_ByName(t *testing.T) {
 	require.NoError(t, env.GetWorkflowResult(&result))
 	require.Equal(t, namespace.Name("namespace-delete-220878"), result.DeletedNamespace)
 	require.Equal(t, namespace.ID("namespace-id"), result.DeletedNamespaceID)
+	require.Equal(t, []string{"file:///tmp/archival"}, result.OrphanedArchivalURIs)
+
+	// Orphaned archival URIs are persisted for the cleanup job.
+	require.Len(t, store.recorded, 1)
+	require.Equal(t, namespace.ID("namespace-id"), store.recorded[0].NamespaceID)
+	require.Equal(t, namespace.Name("namespace"), store.recorded[0].Namespace)
+	require.Equal(t, []string{"file:///tmp/archival"}, store.recorded[0].ArchivalURIs)
+	require.False(t, store.recorded[0].DeleteTime.IsZero())
+}
+
+func Test_DeleteNamespaceWorkflow_RecordOrphanedArchivalFailed(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	testSuite.SetLogger(log.NewSdkLogger(log.NewTestLogger()))
+	env := testSuite.NewTestWorkflowEnvironment()
+	var la *localActivities
+
+	env.OnActivity(la.GetNamespaceInfoActivity, mock.Anything, namespace.EmptyID, namespace.Name("namespace")).Return(
+		getNamespaceInfoResult{
+			NamespaceID:  "namespace-id",
+			Namespace:    "namespace",
+			ArchivalURIs: []string{"file:///tmp/archival"},
+		}, nil).Once()
+	env.OnActivity(la.ValidateProtectedNamespacesActivity, mock.Anything, mock.Anything).Return(nil).Once()
+	env.OnActivity(la.ValidateNexusEndpointsActivity, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	env.OnActivity(la.MarkNamespaceDeletedActivity, mock.Anything, namespace.Name("namespace")).Return(nil).Once()
+	env.OnActivity(la.RecordOrphanedArchivalActivity, mock.Anything, mock.Anything).
+		Return(temporal.NewNonRetryableApplicationError("store is unavailable", "Unavailable", nil)).
+		Once()
+	env.OnActivity(la.GenerateDeletedNamespaceNameActivity, mock.Anything, namespace.ID("namespace-id"), namespace.Name("namespace")).Return(namespace.Name("namespace-delete-220878"), nil).Once()
+	env.OnActivity(la.RenameNamespaceActivity, mock.Anything, namespace.Name("namespace"), namespace.Name("namespace-delete-220878")).Return(nil).Once()
+	env.RegisterWorkflow(reclaimresources.ReclaimResourcesWorkflow)
+	env.OnWorkflow(reclaimresources.ReclaimResourcesWorkflow, mock.Anything, mock.Anything).Return(reclaimresources.ReclaimResourcesResult{}, nil).Once()
+
+	env.ExecuteWorkflow(DeleteNamespaceWorkflow, DeleteNamespaceWorkflowParams{
+		Namespace: "namespace",
+	})
+
+	// The namespace is already marked as deleted, so the deletion goes on and the URIs are still returned.
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result DeleteNamespaceWorkflowResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, namespace.Name("namespace-delete-220878"), result.DeletedNamespace)
+	require.Equal(t, []string{"file:///tmp/archival"}, result.OrphanedArchivalURIs)
 }
 
 func Test_DeleteNamespaceWorkflow_ByID(t *testing.T) {
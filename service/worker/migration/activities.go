@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/pkg/errors"
 	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
 	replicationpb "go.temporal.io/api/replication/v1"
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/api/workflowservice/v1"
@@ -17,16 +19,21 @@ import (
 	"go.temporal.io/server/api/adminservice/v1"
 	enumsspb "go.temporal.io/server/api/enums/v1"
 	"go.temporal.io/server/api/historyservice/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
 	replicationspb "go.temporal.io/server/api/replication/v1"
 	serverClient "go.temporal.io/server/client"
 	"go.temporal.io/server/common"
+	hlc "go.temporal.io/server/common/clock/hybrid_logical_clock"
+	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/dynamicconfig"
 	"go.temporal.io/server/common/headers"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
 	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/payloads"
 	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/versionhistory"
 	"go.temporal.io/server/common/quotas"
 	"go.temporal.io/server/common/rpc/interceptor"
 	"google.golang.org/grpc/metadata"
@@ -42,6 +49,10 @@ type (
 		NextIndex                        int
 		CheckPoint                       time.Time
 		LastNotVerifiedWorkflowExecution *commonpb.WorkflowExecution
+		// StartTime is when the first attempt started verifying, the replication tasks of the executions were
+		// generated by then.
+		StartTime         time.Time
+		MaxReplicationLag time.Duration
 	}
 
 	verifyStatus int
@@ -54,6 +65,8 @@ type (
 		Executions    []*commonpb.WorkflowExecution
 		NextPageToken []byte
 		Error         error
+		// Total history size of the listed executions.
+		HistorySizeBytes int64
 
 		// These can be used to help report progress of the force-replication scan
 		LastCloseTime time.Time
@@ -70,6 +83,19 @@ type (
 		RPS              float64
 		GetParentInfoRPS float64
 		TargetClusters   []string
+		// SkipCorrupted skips executions which fail with an unrecoverable error instead of failing the activity.
+		SkipCorrupted bool
+		// ShardCount of the source cluster, used to report the progress per source shard. Zero disables it.
+		ShardCount int32
+		// TransformName is the ForceReplicationTransform applied to each execution, empty applies no transform.
+		TransformName string
+	}
+
+	generateReplicationTasksResponse struct {
+		// CorruptedExecutions are the executions skipped because of an unrecoverable error, only set with SkipCorrupted.
+		CorruptedExecutions []*commonpb.WorkflowExecution
+		// ShardProgress is the progress of this activity by source shard ID, only set with ShardCount.
+		ShardProgress map[int32]*ShardReplicationProgress
 	}
 
 	verifyReplicationTasksRequest struct {
@@ -83,15 +109,115 @@ type (
 
 	verifyReplicationTasksResponse struct {
 		VerifiedWorkflowCount int64
+		// MaxReplicationLag is the longest time from StartTime until the target cluster had a workflow, up to
+		// VerifyInterval late. Skipped workflows are not counted.
+		MaxReplicationLag time.Duration
+	}
+
+	repairReplicationTasksRequest struct {
+		Namespace         string
+		NamespaceID       string
+		TargetClusterName string
+		Executions        []*commonpb.WorkflowExecution
+		RPS               float64
+		// MinEventGap is the number of events the target cluster must be behind for a workflow to be repaired.
+		MinEventGap int64
+	}
+
+	repairReplicationTasksResponse struct {
+		RepairedWorkflowCount int64
+		BackfilledEventCount  int64
+	}
+
+	verifyTaskQueueUserDataRequest struct {
+		Namespace         string
+		TargetClusterName string
+		PageSize          int
+	}
+
+	verifyTaskQueueUserDataResponse struct {
+		MissingTaskQueues     []string // capped at maxReportedMissingTaskQueues
+		MissingTaskQueueCount int64
 	}
 
 	metadataRequest struct {
 		Namespace string
+		// TransformName is validated to be a registered ForceReplicationTransform if set.
+		TransformName string
 	}
 
 	metadataResponse struct {
 		ShardCount  int32
 		NamespaceID string
+		// Namespace replication filter, see persistencespb.ReplicationWorkflowTypeFilter.
+		IncludedWorkflowTypes []string
+		ExcludedWorkflowTypes []string
+		// RPC addresses of the enabled clusters by name, to check the target cluster against.
+		ClusterRPCAddresses map[string]string
+	}
+
+	// ForceReplicationTransform is applied by ForceReplicationWorkflow to each workflow right before its replication
+	// task is generated, see ForceReplicationParams.TransformName. Replication tasks are generated by the history
+	// service from the current state of the workflow, so a transform which needs to rewrite replicated data, e.g.
+	// remap a search attribute or a namespace name embedded in the memo, does so by updating the workflow through
+	// the server APIs. It can also change the clusters the task is generated for. An error fails the workflow
+	// like a history service error would, NotFound errors skip it.
+	//
+	// Generation is retried and resumed from heartbeats, and a migration can be run again over the same
+	// workflows, so a transform can be applied to a workflow more than once, possibly by different workers. It
+	// must be deterministic, i.e. transform a workflow the same way on every attempt regardless of time or worker
+	// state, and idempotent, i.e. applying it to an already transformed workflow must be a no-op.
+	ForceReplicationTransform interface {
+		Transform(ctx context.Context, task *ForceReplicationTransformTask) error
+	}
+
+	// ForceReplicationTransformTask is the replication task generation of a single workflow. A transform may only
+	// change TargetClusters.
+	ForceReplicationTransformTask struct {
+		NamespaceName  string
+		NamespaceID    string
+		Execution      *commonpb.WorkflowExecution
+		TargetClusters []string
+	}
+
+	// ForceReplicationTransforms are the transforms which can be selected with ForceReplicationParams.TransformName,
+	// keyed by name.
+	ForceReplicationTransforms map[string]ForceReplicationTransform
+
+	noopForceReplicationTransform struct{}
+
+	// ForceReplicationCheckpointStore durably stores force replication progress keyed by namespace, so that a new
+	// force replication run can resume after the previous run was terminated, e.g. because the whole worker
+	// fleet was restarted.
+	ForceReplicationCheckpointStore interface {
+		SaveCheckpoint(ctx context.Context, namespace string, checkpoint *ForceReplicationCheckpoint) error
+		// LoadCheckpoint returns nil if there is no checkpoint for the namespace.
+		LoadCheckpoint(ctx context.Context, namespace string) (*ForceReplicationCheckpoint, error)
+	}
+
+	// ForceReplicationCheckpoint is the progress of a force replication run, it is only valid for the same query.
+	ForceReplicationCheckpoint struct {
+		Query                   string
+		OrderByWorkflowID       bool
+		PageTokenForRestart     []byte
+		PageOffsetForRestart    int
+		WorkflowIDForRestart    string
+		ContinuedAsNewCount     int
+		TotalWorkflowCount      int64
+		ReplicatedWorkflowCount int64
+		CorruptedWorkflowCount  int64
+		CheckpointTime          time.Time
+
+		TaskQueueUserDataReplicationStatus TaskQueueUserDataReplicationStatus
+	}
+
+	saveCheckpointRequest struct {
+		Namespace  string
+		Checkpoint *ForceReplicationCheckpoint
+	}
+
+	loadCheckpointRequest struct {
+		Namespace string
 	}
 
 	waitCatchupRequest struct {
@@ -110,14 +236,19 @@ type (
 		adminClient                      adminservice.AdminServiceClient
 		clientFactory                    serverClient.Factory
 		clientBean                       serverClient.Bean
+		clusterMetadata                  cluster.Metadata
 		logger                           log.Logger
 		metricsHandler                   metrics.Handler
 		forceReplicationMetricsHandler   metrics.Handler
 		namespaceReplicationQueue        persistence.NamespaceReplicationQueue
 		generateMigrationTaskViaFrontend dynamicconfig.BoolPropertyFn
+		checkpointStore                  ForceReplicationCheckpointStore // optional
+		replicationTransforms            ForceReplicationTransforms
 	}
 )
 
+var errNoCheckpointStore = temporal.NewNonRetryableApplicationError("no force replication checkpoint store is configured", "FailedPrecondition", nil)
+
 const (
 	reasonZombieWorkflow           = "Zombie workflow"
 	reasonWorkflowNotFound         = "Workflow not found"
@@ -141,14 +272,42 @@ func (r verifyResult) isVerified() bool {
 
 // GetMetadata returns history shard count and namespaceID for requested namespace.
 func (a *activities) GetMetadata(_ context.Context, request metadataRequest) (*metadataResponse, error) {
+	if _, err := a.replicationTransform(request.TransformName); err != nil {
+		return nil, err
+	}
 	nsEntry, err := a.namespaceRegistry.GetNamespace(namespace.Name(request.Namespace))
-	if err != nil {
+	switch err.(type) {
+	case nil:
+	case *serviceerror.NamespaceNotFound:
+		// retrying won't make the namespace appear, fail the workflow with a clear error instead
+		return nil, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("InvalidArgument: namespace %q not found on the source cluster", request.Namespace), "InvalidArgument", err)
+	default:
 		return nil, err
 	}
+	if !nsEntry.IsGlobalNamespace() {
+		return nil, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("InvalidArgument: namespace %q is not a global namespace and can't be replicated", request.Namespace), "InvalidArgument", nil)
+	}
+	if nsEntry.State() == enumspb.NAMESPACE_STATE_DELETED {
+		return nil, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("InvalidArgument: namespace %q is deleted", request.Namespace), "InvalidArgument", nil)
+	}
+
+	clusterRPCAddresses := make(map[string]string)
+	for clusterName, clusterInfo := range a.clusterMetadata.GetAllClusterInfo() {
+		if clusterInfo.Enabled {
+			clusterRPCAddresses[clusterName] = clusterInfo.RPCAddress
+		}
+	}
 
+	filter := nsEntry.ReplicationWorkflowTypeFilter()
 	return &metadataResponse{
-		ShardCount:  a.historyShardCount,
-		NamespaceID: string(nsEntry.ID()),
+		ShardCount:            a.historyShardCount,
+		NamespaceID:           string(nsEntry.ID()),
+		IncludedWorkflowTypes: filter.GetIncludedWorkflowTypes(),
+		ExcludedWorkflowTypes: filter.GetExcludedWorkflowTypes(),
+		ClusterRPCAddresses:   clusterRPCAddresses,
 	}, nil
 }
 
@@ -456,10 +615,12 @@ func (a *activities) ListWorkflows(ctx context.Context, request *workflowservice
 		return nil, err
 	}
 	var lastCloseTime, lastStartTime time.Time
+	var historySizeBytes int64
 
 	executions := make([]*commonpb.WorkflowExecution, len(resp.Executions))
 	for i, e := range resp.Executions {
 		executions[i] = e.Execution
+		historySizeBytes += e.HistorySizeBytes
 
 		if e.CloseTime != nil {
 			lastCloseTime = e.CloseTime.AsTime()
@@ -469,7 +630,13 @@ func (a *activities) ListWorkflows(ctx context.Context, request *workflowservice
 			lastStartTime = e.StartTime.AsTime()
 		}
 	}
-	return &listWorkflowsResponse{Executions: executions, NextPageToken: resp.NextPageToken, LastCloseTime: lastCloseTime, LastStartTime: lastStartTime}, nil
+	return &listWorkflowsResponse{
+		Executions:       executions,
+		NextPageToken:    resp.NextPageToken,
+		LastCloseTime:    lastCloseTime,
+		LastStartTime:    lastStartTime,
+		HistorySizeBytes: historySizeBytes,
+	}, nil
 }
 
 func (a *activities) CountWorkflow(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*countWorkflowResponse, error) {
@@ -486,7 +653,21 @@ func (a *activities) CountWorkflow(ctx context.Context, request *workflowservice
 	}, nil
 }
 
-func (a *activities) GenerateReplicationTasks(ctx context.Context, request *generateReplicationTasksRequest) error {
+func (a *activities) SaveCheckpoint(ctx context.Context, request *saveCheckpointRequest) error {
+	if a.checkpointStore == nil {
+		return errNoCheckpointStore
+	}
+	return a.checkpointStore.SaveCheckpoint(ctx, request.Namespace, request.Checkpoint)
+}
+
+func (a *activities) LoadCheckpoint(ctx context.Context, request *loadCheckpointRequest) (*ForceReplicationCheckpoint, error) {
+	if a.checkpointStore == nil {
+		return nil, errNoCheckpointStore
+	}
+	return a.checkpointStore.LoadCheckpoint(ctx, request.Namespace)
+}
+
+func (a *activities) GenerateReplicationTasks(ctx context.Context, request *generateReplicationTasksRequest) (*generateReplicationTasksResponse, error) {
 	ctx = a.setCallerInfoForServerAPI(ctx, namespace.ID(request.NamespaceID))
 	rateLimiter := quotas.NewRateLimiter(request.RPS, int(math.Ceil(request.RPS)))
 
@@ -495,52 +676,292 @@ func (a *activities) GenerateReplicationTasks(ctx context.Context, request *gene
 		a.forceReplicationMetricsHandler.Timer(metrics.GenerateReplicationTasksLatency.Name()).Record(time.Since(start))
 	}()
 
+	// The heartbeat details are the index of the last processed execution in the page, followed by the corrupted
+	// executions and the shard progress so far. Heartbeats of older workers only have the index, new values must
+	// only be appended so that a retry on a newer worker resumes from the last processed execution.
 	startIndex := 0
+	var corruptedExecutions []*commonpb.WorkflowExecution
+	shardProgress := make(map[int32]*ShardReplicationProgress)
 	if activity.HasHeartbeatDetails(ctx) {
-		if err := activity.GetHeartbeatDetails(ctx, &startIndex); err == nil {
+		if err := activity.GetHeartbeatDetails(ctx, &startIndex, &corruptedExecutions, &shardProgress); err == nil {
 			startIndex = startIndex + 1 // start from next one
+		} else {
+			// the progress of the skipped executions would be lost, so the page is processed from the start
+			startIndex = 0
+			corruptedExecutions = nil
+			shardProgress = make(map[int32]*ShardReplicationProgress)
 		}
 	}
 
 	namespaceName, err := a.namespaceRegistry.GetNamespaceName(namespace.ID(request.NamespaceID))
 	if err != nil {
 		a.logger.Error("force-replication failed to translate namespaceID to name", tag.WorkflowNamespaceID(request.NamespaceID))
-		return err
+		return nil, err
+	}
+
+	transform, err := a.replicationTransform(request.TransformName)
+	if err != nil {
+		return nil, err
 	}
 
 	generateViaFrontend := a.generateMigrationTaskViaFrontend()
 	for i := startIndex; i < len(request.Executions); i++ {
 		we := request.Executions[i]
-		if err := a.generateWorkflowReplicationTask(
-			ctx,
-			rateLimiter,
-			namespaceName.String(),
-			request.NamespaceID,
-			we,
-			request.TargetClusters,
-			generateViaFrontend,
-		); err != nil {
-			if !common.IsNotFoundError(err) {
+		shard := a.newShardProgressRecorder(request, namespaceName, we, shardProgress)
+		task := &ForceReplicationTransformTask{
+			NamespaceName:  namespaceName.String(),
+			NamespaceID:    request.NamespaceID,
+			Execution:      we,
+			TargetClusters: slices.Clone(request.TargetClusters),
+		}
+		err := transform.Transform(ctx, task)
+		if err == nil {
+			err = a.generateWorkflowReplicationTask(
+				ctx,
+				rateLimiter,
+				namespaceName.String(),
+				request.NamespaceID,
+				we,
+				task.TargetClusters,
+				generateViaFrontend,
+			)
+		}
+		if err != nil {
+			switch {
+			case common.IsNotFoundError(err):
+				a.logger.Warn("force-replication ignore replication task due to NotFoundServiceError",
+					tag.WorkflowNamespaceID(request.NamespaceID),
+					tag.WorkflowID(we.GetWorkflowId()),
+					tag.WorkflowRunID(we.GetRunId()),
+					tag.Error(err))
+				shard.skipped()
+			case request.SkipCorrupted && isCorruptedWorkflowError(err):
+				a.logger.Error("force-replication skipped corrupted workflow",
+					tag.WorkflowNamespaceID(request.NamespaceID),
+					tag.WorkflowID(we.GetWorkflowId()),
+					tag.WorkflowRunID(we.GetRunId()),
+					tag.Error(err))
+				corruptedExecutions = append(corruptedExecutions, we)
+				shard.skipped()
+			default:
 				a.logger.Error("force-replication failed to generate replication task",
 					tag.WorkflowNamespaceID(request.NamespaceID),
 					tag.WorkflowID(we.GetWorkflowId()),
 					tag.WorkflowRunID(we.GetRunId()),
 					tag.Error(err))
-				return err
+				shard.failed(err)
+				return nil, err
 			}
-
-			a.logger.Warn("force-replication ignore replication task due to NotFoundServiceError",
-				tag.WorkflowNamespaceID(request.NamespaceID),
-				tag.WorkflowID(we.GetWorkflowId()),
-				tag.WorkflowRunID(we.GetRunId()),
-				tag.Error(err))
+		} else {
+			shard.generated()
 		}
-		activity.RecordHeartbeat(ctx, i)
+		activity.RecordHeartbeat(ctx, i, corruptedExecutions, shardProgress)
 	}
 
+	response := &generateReplicationTasksResponse{CorruptedExecutions: corruptedExecutions}
+	if request.ShardCount > 0 {
+		response.ShardProgress = shardProgress
+	}
+	return response, nil
+}
+
+// replicationTransform returns the transform registered with the given name, or a no-op transform if the name
+// is empty.
+func (a *activities) replicationTransform(name string) (ForceReplicationTransform, error) {
+	if name == "" {
+		return noopForceReplicationTransform{}, nil
+	}
+	transform, ok := a.replicationTransforms[name]
+	if !ok {
+		return nil, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("InvalidArgument: unknown force replication transform %q", name), "InvalidArgument", nil,
+		)
+	}
+	return transform, nil
+}
+
+func (noopForceReplicationTransform) Transform(context.Context, *ForceReplicationTransformTask) error {
 	return nil
 }
 
+// shardProgressRecorder records the outcome of generating the replication tasks of a workflow in the progress of its
+// source shard, and in the metrics of its namespace. The per-shard breakdown is only reported by the force replication
+// status query, as tagging the metrics with the shard ID would multiply their cardinality by the shard count.
+type shardProgressRecorder struct {
+	progress       *ShardReplicationProgress
+	metricsHandler metrics.Handler
+}
+
+func (a *activities) newShardProgressRecorder(
+	request *generateReplicationTasksRequest,
+	namespaceName namespace.Name,
+	we *commonpb.WorkflowExecution,
+	shardProgress map[int32]*ShardReplicationProgress,
+) shardProgressRecorder {
+	if request.ShardCount <= 0 {
+		return shardProgressRecorder{}
+	}
+	shardID := common.WorkflowIDToHistoryShard(request.NamespaceID, we.GetWorkflowId(), request.ShardCount)
+	progress, ok := shardProgress[shardID]
+	if !ok {
+		progress = &ShardReplicationProgress{}
+		shardProgress[shardID] = progress
+	}
+	return shardProgressRecorder{
+		progress:       progress,
+		metricsHandler: a.forceReplicationMetricsHandler.WithTags(metrics.NamespaceTag(namespaceName.String())),
+	}
+}
+
+func (r shardProgressRecorder) generated() {
+	if r.progress == nil {
+		return
+	}
+	r.progress.GeneratedWorkflowCount++
+	r.metricsHandler.Counter(metrics.ForceReplicationTaskGenerated.Name()).Record(1)
+}
+
+func (r shardProgressRecorder) skipped() {
+	if r.progress == nil {
+		return
+	}
+	r.progress.SkippedWorkflowCount++
+	r.metricsHandler.Counter(metrics.ForceReplicationTaskSkipped.Name()).Record(1)
+}
+
+// failed only records the failure in the metrics, as the progress of a failed activity is lost.
+func (r shardProgressRecorder) failed(err error) {
+	if r.progress == nil {
+		return
+	}
+	r.metricsHandler.Counter(metrics.ForceReplicationTaskFailed.Name()).Record(1, metrics.ServiceErrorTypeTag(err))
+}
+
+// RepairReplicationTasks compares the last event of each execution on the source and the target cluster, and
+// generates replication tasks only for executions which the target cluster is missing events of. The target
+// cluster backfills just the missing events when it applies the task.
+func (a *activities) RepairReplicationTasks(ctx context.Context, request *repairReplicationTasksRequest) (*repairReplicationTasksResponse, error) {
+	ctx = a.setCallerInfoForServerAPI(ctx, namespace.ID(request.NamespaceID))
+	rateLimiter := quotas.NewRateLimiter(request.RPS, int(math.Ceil(request.RPS)))
+
+	startIndex := 0
+	response := &repairReplicationTasksResponse{}
+	if activity.HasHeartbeatDetails(ctx) {
+		if err := activity.GetHeartbeatDetails(ctx, &startIndex, response); err == nil {
+			startIndex = startIndex + 1 // start from next one
+		} else {
+			startIndex = 0
+			response = &repairReplicationTasksResponse{}
+		}
+	}
+
+	remoteAdminClient, err := a.clientBean.GetRemoteAdminClient(request.TargetClusterName)
+	if err != nil {
+		return nil, err
+	}
+	remoteCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs(interceptor.DCRedirectionContextHeaderName, "false"))
+
+	minEventGap := max(request.MinEventGap, 1)
+	generateViaFrontend := a.generateMigrationTaskViaFrontend()
+	for i := startIndex; i < len(request.Executions); i++ {
+		we := request.Executions[i]
+		eventGap, err := a.replicationEventGap(ctx, remoteCtx, request, remoteAdminClient, we)
+		if err != nil {
+			return nil, err
+		}
+		if eventGap >= minEventGap {
+			err := a.generateWorkflowReplicationTask(
+				ctx,
+				rateLimiter,
+				request.Namespace,
+				request.NamespaceID,
+				we,
+				[]string{request.TargetClusterName},
+				generateViaFrontend,
+			)
+			switch {
+			case err == nil:
+				response.RepairedWorkflowCount++
+				response.BackfilledEventCount += eventGap
+			case common.IsNotFoundError(err):
+				// the execution was deleted after its gap was detected
+			default:
+				return nil, err
+			}
+		}
+		activity.RecordHeartbeat(ctx, i, response)
+	}
+
+	return response, nil
+}
+
+// replicationEventGap returns how many events of the execution the target cluster is missing. It is zero if the
+// execution no longer exists on the source cluster.
+func (a *activities) replicationEventGap(
+	ctx context.Context,
+	remoteCtx context.Context,
+	request *repairReplicationTasksRequest,
+	remoteAdminClient adminservice.AdminServiceClient,
+	we *commonpb.WorkflowExecution,
+) (int64, error) {
+	sourceResp, err := a.historyClient.DescribeMutableState(ctx, &historyservice.DescribeMutableStateRequest{
+		NamespaceId:     request.NamespaceID,
+		Execution:       we,
+		SkipForceReload: true,
+	})
+	if err != nil {
+		if common.IsNotFoundError(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	sourceLastEventID, err := lastEventID(sourceResp.GetDatabaseMutableState())
+	if err != nil {
+		return 0, err
+	}
+
+	var targetLastEventID int64
+	targetResp, err := remoteAdminClient.DescribeMutableState(remoteCtx, &adminservice.DescribeMutableStateRequest{
+		Namespace:       request.Namespace,
+		Execution:       we,
+		SkipForceReload: true,
+	})
+	switch err.(type) {
+	case nil:
+		if targetLastEventID, err = lastEventID(targetResp.GetDatabaseMutableState()); err != nil {
+			return 0, err
+		}
+	case *serviceerror.NotFound:
+		// the whole execution is missing on the target cluster
+	case *serviceerror.NamespaceNotFound:
+		return 0, temporal.NewNonRetryableApplicationError("failed to describe workflow from the remote cluster", "NamespaceNotFound", err)
+	default:
+		return 0, errors.WithMessage(err, "failed to describe workflow from the remote cluster")
+	}
+
+	return max(sourceLastEventID-targetLastEventID, 0), nil
+}
+
+// lastEventID returns the last event ID of the current branch of the mutable state.
+func lastEventID(mutableState *persistencespb.WorkflowMutableState) (int64, error) {
+	currentHistory, err := versionhistory.GetCurrentVersionHistory(mutableState.GetExecutionInfo().GetVersionHistories())
+	if err != nil {
+		return 0, err
+	}
+	lastItem, err := versionhistory.GetLastVersionHistoryItem(currentHistory)
+	if err != nil {
+		return 0, err
+	}
+	return lastItem.GetEventId(), nil
+}
+
+// isCorruptedWorkflowError returns true if generating replication tasks for the workflow can never succeed,
+// e.g. because its history is missing or cannot be deserialized. Other errors are considered transient.
+func isCorruptedWorkflowError(err error) bool {
+	var dataLossErr *serviceerror.DataLoss
+	return errors.As(err, &dataLossErr)
+}
+
 func (a *activities) setCallerInfoForServerAPI(
 	ctx context.Context,
 	namespaceID namespace.ID,
@@ -561,7 +982,7 @@ type seedReplicationQueueWithUserDataEntriesHeartbeatDetails struct {
 	IndexInPage   int
 }
 
-func (a *activities) SeedReplicationQueueWithUserDataEntries(ctx context.Context, params TaskQueueUserDataReplicationParamsWithNamespace) error {
+func (a *activities) SeedReplicationQueueWithUserDataEntries(ctx context.Context, params TaskQueueUserDataReplicationParamsWithNamespace) (retErr error) {
 	if len(params.Namespace) == 0 {
 		return temporal.NewNonRetryableApplicationError("namespace is required", "InvalidArgument", nil)
 	}
@@ -587,6 +1008,12 @@ func (a *activities) SeedReplicationQueueWithUserDataEntries(ctx context.Context
 			return temporal.NewNonRetryableApplicationError("failed to load previous heartbeat details", "TypeError", err)
 		}
 	}
+	defer func() {
+		// report where a failed attempt stopped, the next attempt resumes from there
+		if retErr != nil {
+			a.signalSeedingProgress(ctx, params, heartbeatDetails)
+		}
+	}()
 
 	for {
 		if err := rateLimiter.Wait(ctx); err != nil {
@@ -609,6 +1036,9 @@ func (a *activities) SeedReplicationQueueWithUserDataEntries(ctx context.Context
 			}
 			heartbeatDetails.IndexInPage = idx
 			activity.RecordHeartbeat(ctx, heartbeatDetails)
+			if err := rateLimiter.Wait(ctx); err != nil {
+				return err
+			}
 			err = a.namespaceReplicationQueue.Publish(ctx, &replicationspb.ReplicationTask{
 				TaskType: enumsspb.REPLICATION_TASK_TYPE_TASK_QUEUE_USER_DATA,
 				Attributes: &replicationspb.ReplicationTask_TaskQueueUserDataAttributes{
@@ -630,6 +1060,107 @@ func (a *activities) SeedReplicationQueueWithUserDataEntries(ctx context.Context
 		heartbeatDetails.NextPageToken = response.NextPageToken
 		heartbeatDetails.IndexInPage = 0
 		activity.RecordHeartbeat(ctx, heartbeatDetails)
+		a.signalSeedingProgress(ctx, params, heartbeatDetails)
+	}
+}
+
+// signalSeedingProgress reports the seeding progress to params.ProgressWorkflowID so it shows up in the force
+// replication status. The progress is informational, failures are only logged.
+func (a *activities) signalSeedingProgress(
+	ctx context.Context,
+	params TaskQueueUserDataReplicationParamsWithNamespace,
+	progress seedReplicationQueueWithUserDataEntriesHeartbeatDetails,
+) {
+	if len(params.ProgressWorkflowID) == 0 {
+		return
+	}
+	input, err := payloads.Encode(progress)
+	if err != nil {
+		a.logger.Warn("Failed to encode task queue user data replication progress", tag.Error(err))
+		return
+	}
+	_, err = a.frontendClient.SignalWorkflowExecution(ctx, &workflowservice.SignalWorkflowExecutionRequest{
+		Namespace:         activity.GetInfo(ctx).WorkflowNamespace,
+		WorkflowExecution: &commonpb.WorkflowExecution{WorkflowId: params.ProgressWorkflowID},
+		SignalName:        taskQueueUserDataReplicationProgressSignalType,
+		Input:             input,
+	})
+	if err != nil {
+		a.logger.Warn("Failed to signal task queue user data replication progress", tag.WorkflowID(params.ProgressWorkflowID), tag.Error(err))
+	}
+}
+
+type verifyTaskQueueUserDataHeartbeatDetails struct {
+	NextPageToken []byte
+	Response      verifyTaskQueueUserDataResponse
+}
+
+// VerifyTaskQueueUserData checks that the target cluster has the user data of every task queue of the namespace, as
+// seeded by SeedReplicationQueueWithUserDataEntries. The user data of a task queue is replicated when the clock of
+// the target's user data is not behind the clock of the source's, which the target reports as the conflict token of
+// GetWorkerVersioningRules.
+func (a *activities) VerifyTaskQueueUserData(ctx context.Context, request *verifyTaskQueueUserDataRequest) (*verifyTaskQueueUserDataResponse, error) {
+	if request.PageSize == 0 {
+		request.PageSize = defaultPageSizeForTaskQueueUserDataReplication
+	}
+
+	describeResponse, err := a.frontendClient.DescribeNamespace(ctx, &workflowservice.DescribeNamespaceRequest{
+		Namespace: request.Namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, remoteFrontendClient, err := a.clientBean.GetRemoteFrontendClient(request.TargetClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var details verifyTaskQueueUserDataHeartbeatDetails
+	if activity.HasHeartbeatDetails(ctx) {
+		if err := activity.GetHeartbeatDetails(ctx, &details); err != nil {
+			return nil, temporal.NewNonRetryableApplicationError("failed to load previous heartbeat details", "TypeError", err)
+		}
+	}
+
+	// The namespace is usually still active on the source cluster, the target must answer itself.
+	remoteCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs(interceptor.DCRedirectionContextHeaderName, "false"))
+	for {
+		response, err := a.taskManager.ListTaskQueueUserDataEntries(ctx, &persistence.ListTaskQueueUserDataEntriesRequest{
+			NamespaceID:   describeResponse.GetNamespaceInfo().GetId(),
+			NextPageToken: details.NextPageToken,
+			PageSize:      request.PageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range response.Entries {
+			sourceClock := entry.UserData.GetData().GetClock()
+			if sourceClock == nil {
+				continue
+			}
+			rulesResponse, err := remoteFrontendClient.GetWorkerVersioningRules(remoteCtx, &workflowservice.GetWorkerVersioningRulesRequest{
+				Namespace: request.Namespace,
+				TaskQueue: entry.TaskQueue,
+			})
+			if err != nil {
+				return nil, err
+			}
+			targetClock := &hlc.Clock{}
+			if err := targetClock.Unmarshal(rulesResponse.GetConflictToken()); err != nil {
+				return nil, err
+			}
+			if hlc.Less(targetClock, sourceClock) {
+				details.Response.MissingTaskQueueCount++
+				if len(details.Response.MissingTaskQueues) < maxReportedMissingTaskQueues {
+					details.Response.MissingTaskQueues = append(details.Response.MissingTaskQueues, entry.TaskQueue)
+				}
+			}
+		}
+		if len(response.NextPageToken) == 0 {
+			return &details.Response, nil
+		}
+		details.NextPageToken = response.NextPageToken
+		activity.RecordHeartbeat(ctx, details)
 	}
 }
 
@@ -768,6 +1299,9 @@ func (a *activities) verifyReplicationTasks(
 			details.LastNotVerifiedWorkflowExecution = we
 			return false, nil
 		}
+		if r.status == verified {
+			details.MaxReplicationLag = max(details.MaxReplicationLag, time.Since(details.StartTime))
+		}
 
 		heartbeat(*details)
 		progress = true
@@ -790,8 +1324,13 @@ func (a *activities) VerifyReplicationTasks(ctx context.Context, request *verify
 	} else {
 		details.NextIndex = 0
 		details.CheckPoint = time.Now()
+		details.StartTime = details.CheckPoint
 		activity.RecordHeartbeat(ctx, details)
 	}
+	if details.StartTime.IsZero() {
+		// heartbeat of an attempt started before the lag was measured
+		details.StartTime = details.CheckPoint
+	}
 
 	remotAdminClient, err := a.clientBean.GetRemoteAdminClient(request.TargetClusterName)
 	if err != nil {
@@ -829,6 +1368,7 @@ func (a *activities) VerifyReplicationTasks(ctx context.Context, request *verify
 
 		if verified {
 			response.VerifiedWorkflowCount = int64(len(request.Executions))
+			response.MaxReplicationLag = details.MaxReplicationLag
 			return response, nil
 		}
 
@@ -7,28 +7,37 @@ import (
 
 	"github.com/stretchr/testify/suite"
 	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	namespacepb "go.temporal.io/api/namespace/v1"
 	replicationpb "go.temporal.io/api/replication/v1"
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/testsuite"
 	"go.temporal.io/sdk/worker"
 	"go.temporal.io/server/api/adminservice/v1"
 	"go.temporal.io/server/api/adminservicemock/v1"
 	enumsspb "go.temporal.io/server/api/enums/v1"
+	historyspb "go.temporal.io/server/api/history/v1"
 	"go.temporal.io/server/api/historyservice/v1"
 	"go.temporal.io/server/api/historyservicemock/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
 	"go.temporal.io/server/client"
+	"go.temporal.io/server/common"
+	hlc "go.temporal.io/server/common/clock/hybrid_logical_clock"
+	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/dynamicconfig"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/versionhistory"
 	"go.temporal.io/server/common/testing/mockapi/workflowservicemock/v1"
 	"go.temporal.io/server/common/testing/protoassert"
 	"go.temporal.io/server/common/testing/protomock"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -44,6 +53,7 @@ type activitiesSuite struct {
 	mockNamespaceRegistry         *namespace.MockRegistry
 	mockClientFactory             *client.MockFactory
 	mockClientBean                *client.MockBean
+	mockClusterMetadata           *cluster.MockMetadata
 
 	mockFrontendClient    *workflowservicemock.MockWorkflowServiceClient
 	mockAdminClient       *adminservicemock.MockAdminServiceClient
@@ -89,7 +99,7 @@ var (
 		},
 	}
 
-	testNamespace = namespace.Namespace{}
+	testNamespace = *namespace.NewGlobalNamespaceForTest(nil, nil, nil, 0)
 )
 
 func TestActivitiesSuite(t *testing.T) {
@@ -103,6 +113,7 @@ func (s *activitiesSuite) SetupTest() {
 	s.mockNamespaceReplicationQueue = persistence.NewMockNamespaceReplicationQueue(s.controller)
 	s.mockNamespaceRegistry = namespace.NewMockRegistry(s.controller)
 	s.mockClientBean = client.NewMockBean(s.controller)
+	s.mockClusterMetadata = cluster.NewMockMetadata(s.controller)
 
 	s.mockFrontendClient = workflowservicemock.NewMockWorkflowServiceClient(s.controller)
 	s.mockAdminClient = adminservicemock.NewMockAdminServiceClient(s.controller)
@@ -119,12 +130,18 @@ func (s *activitiesSuite) SetupTest() {
 		Return(namespace.Name(mockedNamespace), nil).AnyTimes()
 	s.mockNamespaceRegistry.EXPECT().GetNamespace(gomock.Any()).
 		Return(&testNamespace, nil).AnyTimes()
+	s.mockClusterMetadata.EXPECT().GetAllClusterInfo().Return(map[string]cluster.ClusterInformation{
+		"active":      {Enabled: true, RPCAddress: "active-address"},
+		remoteCluster: {Enabled: true, RPCAddress: "remote-address"},
+		"disabled":    {Enabled: false, RPCAddress: "disabled-address"},
+	}).AnyTimes()
 
 	s.a = &activities{
 		namespaceRegistry:                s.mockNamespaceRegistry,
 		namespaceReplicationQueue:        s.mockNamespaceReplicationQueue,
 		clientFactory:                    s.mockClientFactory,
 		clientBean:                       s.mockClientBean,
+		clusterMetadata:                  s.mockClusterMetadata,
 		taskManager:                      s.mockTaskManager,
 		frontendClient:                   s.mockFrontendClient,
 		adminClient:                      s.mockAdminClient,
@@ -305,6 +322,31 @@ func (s *activitiesSuite) TestVerifyReplicationTasks_FailedNotFound() {
 	s.Equal(execution1, lastHeartBeat.LastNotVerifiedWorkflowExecution)
 }
 
+func (s *activitiesSuite) TestVerifyReplicationTasks_ReplicationLag() {
+	env, _ := s.initEnv()
+	request := verifyReplicationTasksRequest{
+		Namespace:         mockedNamespace,
+		NamespaceID:       mockedNamespaceID,
+		TargetClusterName: remoteCluster,
+		Executions:        []*commonpb.WorkflowExecution{execution1},
+	}
+
+	s.mockRemoteAdminClient.EXPECT().DescribeMutableState(gomock.Any(), gomock.Any()).Return(&adminservice.DescribeMutableStateResponse{}, nil)
+
+	// a previous attempt started verifying a minute ago
+	env.SetHeartbeatDetails(&replicationTasksHeartbeatDetails{
+		CheckPoint: time.Now(),
+		StartTime:  time.Now().Add(-time.Minute),
+	})
+
+	f, err := env.ExecuteActivity(s.a.VerifyReplicationTasks, &request)
+	s.NoError(err)
+	var output verifyReplicationTasksResponse
+	s.NoError(f.Get(&output))
+	s.GreaterOrEqual(output.MaxReplicationLag, time.Minute)
+	s.Less(output.MaxReplicationLag, 2*time.Minute)
+}
+
 func (s *activitiesSuite) TestVerifyReplicationTasks_AlreadyVerified() {
 	env, iceptor := s.initEnv()
 	request := verifyReplicationTasksRequest{
@@ -667,6 +709,57 @@ func (s *activitiesSuite) TestGenerateReplicationTasks_Failed() {
 	s.Equal(0, lastHeartBeat)
 }
 
+func (s *activitiesSuite) TestGenerateReplicationTasks_SkipCorrupted() {
+	env, _ := s.initEnv()
+
+	request := generateReplicationTasksRequest{
+		NamespaceID:      mockedNamespaceID,
+		RPS:              10,
+		GetParentInfoRPS: 10,
+		Executions:       []*commonpb.WorkflowExecution{execution1, execution2},
+		SkipCorrupted:    true,
+	}
+
+	s.mockHistoryClient.EXPECT().GenerateLastHistoryReplicationTasks(gomock.Any(), protomock.Eq(&historyservice.GenerateLastHistoryReplicationTasksRequest{
+		NamespaceId: mockedNamespaceID,
+		Execution:   execution1,
+	})).Return(nil, serviceerror.NewDataLoss("corrupted history")).Times(1)
+
+	s.mockHistoryClient.EXPECT().GenerateLastHistoryReplicationTasks(gomock.Any(), protomock.Eq(&historyservice.GenerateLastHistoryReplicationTasksRequest{
+		NamespaceId: mockedNamespaceID,
+		Execution:   execution2,
+	})).Return(&historyservice.GenerateLastHistoryReplicationTasksResponse{}, nil).Times(1)
+
+	val, err := env.ExecuteActivity(s.a.GenerateReplicationTasks, &request)
+	s.NoError(err)
+
+	var response generateReplicationTasksResponse
+	s.NoError(val.Get(&response))
+	s.Len(response.CorruptedExecutions, 1)
+	s.Equal(execution1.GetWorkflowId(), response.CorruptedExecutions[0].GetWorkflowId())
+	s.Equal(execution1.GetRunId(), response.CorruptedExecutions[0].GetRunId())
+}
+
+func (s *activitiesSuite) TestGenerateReplicationTasks_SkipCorrupted_TransientError() {
+	env, _ := s.initEnv()
+
+	request := generateReplicationTasksRequest{
+		NamespaceID:      mockedNamespaceID,
+		RPS:              10,
+		GetParentInfoRPS: 10,
+		Executions:       []*commonpb.WorkflowExecution{execution1, execution2},
+		SkipCorrupted:    true,
+	}
+
+	s.mockHistoryClient.EXPECT().GenerateLastHistoryReplicationTasks(gomock.Any(), protomock.Eq(&historyservice.GenerateLastHistoryReplicationTasksRequest{
+		NamespaceId: mockedNamespaceID,
+		Execution:   execution1,
+	})).Return(nil, serviceerror.NewUnavailable("")).Times(1)
+
+	_, err := env.ExecuteActivity(s.a.GenerateReplicationTasks, &request)
+	s.Error(err)
+}
+
 func (s *activitiesSuite) TestGenerateReplicationTasks_Success_ViaFrontend() {
 	env, iceptor := s.initEnv()
 	s.a.generateMigrationTaskViaFrontend = dynamicconfig.GetBoolPropertyFn(true)
@@ -698,6 +791,233 @@ func (s *activitiesSuite) TestGenerateReplicationTasks_Success_ViaFrontend() {
 	s.Equal(1, lastHeartBeat)
 }
 
+func (s *activitiesSuite) TestGenerateReplicationTasks_ResumeMidPage() {
+	env, iceptor := s.initEnv()
+
+	execution3 := &commonpb.WorkflowExecution{WorkflowId: "workflow3", RunId: "run3"}
+	request := generateReplicationTasksRequest{
+		NamespaceID:      mockedNamespaceID,
+		RPS:              10,
+		GetParentInfoRPS: 10,
+		Executions:       []*commonpb.WorkflowExecution{execution1, execution2, execution3},
+	}
+
+	// the first attempt fails on the second execution, the retry doesn't generate the first one again
+	var generated []string
+	failed := false
+	s.mockHistoryClient.EXPECT().GenerateLastHistoryReplicationTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *historyservice.GenerateLastHistoryReplicationTasksRequest, _ ...grpc.CallOption) (*historyservice.GenerateLastHistoryReplicationTasksResponse, error) {
+			if request.GetExecution().GetWorkflowId() == execution2.GetWorkflowId() && !failed {
+				failed = true
+				return nil, serviceerror.NewUnavailable("")
+			}
+			generated = append(generated, request.GetExecution().GetWorkflowId())
+			return &historyservice.GenerateLastHistoryReplicationTasksResponse{}, nil
+		},
+	).Times(4)
+
+	_, err := env.ExecuteActivity(s.a.GenerateReplicationTasks, &request)
+	s.Error(err)
+	s.Equal([]int{0}, iceptor.generateReplicationRecordedHeartbeats)
+
+	env.SetHeartbeatDetails(iceptor.generateReplicationRecordedHeartbeats[0])
+	_, err = env.ExecuteActivity(s.a.GenerateReplicationTasks, &request)
+	s.NoError(err)
+	s.Equal([]int{0, 1, 2}, iceptor.generateReplicationRecordedHeartbeats)
+	s.Equal([]string{execution1.GetWorkflowId(), execution2.GetWorkflowId(), execution3.GetWorkflowId()}, generated)
+}
+
+func (s *activitiesSuite) TestGenerateReplicationTasks_ShardProgress() {
+	env, _ := s.initEnv()
+
+	execution3 := &commonpb.WorkflowExecution{WorkflowId: "workflow3", RunId: "run3"}
+	request := generateReplicationTasksRequest{
+		NamespaceID:      mockedNamespaceID,
+		RPS:              10,
+		GetParentInfoRPS: 10,
+		Executions:       []*commonpb.WorkflowExecution{execution1, execution2, execution3},
+		ShardCount:       4,
+	}
+
+	s.mockHistoryClient.EXPECT().GenerateLastHistoryReplicationTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *historyservice.GenerateLastHistoryReplicationTasksRequest, _ ...grpc.CallOption) (*historyservice.GenerateLastHistoryReplicationTasksResponse, error) {
+			if request.GetExecution().GetWorkflowId() == execution2.GetWorkflowId() {
+				return nil, serviceerror.NewNotFound("")
+			}
+			return &historyservice.GenerateLastHistoryReplicationTasksResponse{}, nil
+		},
+	).Times(3)
+
+	val, err := env.ExecuteActivity(s.a.GenerateReplicationTasks, &request)
+	s.NoError(err)
+
+	expected := make(map[int32]*ShardReplicationProgress)
+	for _, we := range request.Executions {
+		shardID := common.WorkflowIDToHistoryShard(mockedNamespaceID, we.GetWorkflowId(), request.ShardCount)
+		if _, ok := expected[shardID]; !ok {
+			expected[shardID] = &ShardReplicationProgress{}
+		}
+		if we == execution2 {
+			expected[shardID].SkippedWorkflowCount++
+		} else {
+			expected[shardID].GeneratedWorkflowCount++
+		}
+	}
+	var response generateReplicationTasksResponse
+	s.NoError(val.Get(&response))
+	s.Equal(expected, response.ShardProgress)
+}
+
+type testReplicationTransform func(ctx context.Context, task *ForceReplicationTransformTask) error
+
+func (t testReplicationTransform) Transform(ctx context.Context, task *ForceReplicationTransformTask) error {
+	return t(ctx, task)
+}
+
+func (s *activitiesSuite) TestGenerateReplicationTasks_Transform() {
+	env, _ := s.initEnv()
+
+	var transformed []string
+	s.a.replicationTransforms = ForceReplicationTransforms{
+		"test-transform": testReplicationTransform(func(_ context.Context, task *ForceReplicationTransformTask) error {
+			s.Equal(mockedNamespace, task.NamespaceName)
+			s.Equal(mockedNamespaceID, task.NamespaceID)
+			transformed = append(transformed, task.Execution.GetWorkflowId())
+			if task.Execution.GetWorkflowId() == execution2.GetWorkflowId() {
+				task.TargetClusters = task.TargetClusters[:1]
+			}
+			return nil
+		}),
+	}
+	request := generateReplicationTasksRequest{
+		NamespaceID:      mockedNamespaceID,
+		RPS:              10,
+		GetParentInfoRPS: 10,
+		Executions:       []*commonpb.WorkflowExecution{execution1, execution2},
+		TargetClusters:   []string{"cluster-a", "cluster-b"},
+		TransformName:    "test-transform",
+	}
+
+	s.mockHistoryClient.EXPECT().GenerateLastHistoryReplicationTasks(gomock.Any(), protomock.Eq(&historyservice.GenerateLastHistoryReplicationTasksRequest{
+		NamespaceId:    mockedNamespaceID,
+		Execution:      execution1,
+		TargetClusters: []string{"cluster-a", "cluster-b"},
+	})).Return(&historyservice.GenerateLastHistoryReplicationTasksResponse{}, nil).Times(1)
+	s.mockHistoryClient.EXPECT().GenerateLastHistoryReplicationTasks(gomock.Any(), protomock.Eq(&historyservice.GenerateLastHistoryReplicationTasksRequest{
+		NamespaceId:    mockedNamespaceID,
+		Execution:      execution2,
+		TargetClusters: []string{"cluster-a"},
+	})).Return(&historyservice.GenerateLastHistoryReplicationTasksResponse{}, nil).Times(1)
+
+	_, err := env.ExecuteActivity(s.a.GenerateReplicationTasks, &request)
+	s.NoError(err)
+	s.Equal([]string{execution1.GetWorkflowId(), execution2.GetWorkflowId()}, transformed)
+
+	// unknown transforms are rejected by GetMetadata at workflow start
+	_, err = env.ExecuteActivity(s.a.GetMetadata, metadataRequest{Namespace: mockedNamespace, TransformName: "missing"})
+	s.ErrorContains(err, `unknown force replication transform "missing"`)
+	_, err = env.ExecuteActivity(s.a.GetMetadata, metadataRequest{Namespace: mockedNamespace, TransformName: "test-transform"})
+	s.NoError(err)
+}
+
+func (s *activitiesSuite) TestGetMetadata_ClusterRPCAddresses() {
+	env, _ := s.initEnv()
+
+	f, err := env.ExecuteActivity(s.a.GetMetadata, metadataRequest{Namespace: mockedNamespace})
+	s.NoError(err)
+	var output metadataResponse
+	s.NoError(f.Get(&output))
+	s.Equal(map[string]string{
+		"active":      "active-address",
+		remoteCluster: "remote-address",
+	}, output.ClusterRPCAddresses)
+}
+
+func (s *activitiesSuite) TestGetMetadata_InvalidNamespace() {
+	env, _ := s.initEnv()
+	// the registry of SetupTest returns testNamespace for any name
+	s.mockNamespaceRegistry = namespace.NewMockRegistry(s.controller)
+	s.a.namespaceRegistry = s.mockNamespaceRegistry
+	s.mockNamespaceRegistry.EXPECT().GetNamespace(namespace.Name("missing")).Return(nil, serviceerror.NewNamespaceNotFound("missing"))
+	s.mockNamespaceRegistry.EXPECT().GetNamespace(namespace.Name("local")).Return(namespace.NewLocalNamespaceForTest(nil, nil, "active"), nil)
+	s.mockNamespaceRegistry.EXPECT().GetNamespace(namespace.Name("deleted")).Return(namespace.NewGlobalNamespaceForTest(
+		&persistencespb.NamespaceInfo{State: enumspb.NAMESPACE_STATE_DELETED}, nil, nil, 0,
+	), nil)
+
+	for name, expectedError := range map[string]string{
+		"missing": `namespace "missing" not found on the source cluster`,
+		"local":   `namespace "local" is not a global namespace and can't be replicated`,
+		"deleted": `namespace "deleted" is deleted`,
+	} {
+		_, err := env.ExecuteActivity(s.a.GetMetadata, metadataRequest{Namespace: name})
+		var applicationErr *temporal.ApplicationError
+		s.ErrorAs(err, &applicationErr)
+		s.Equal("InvalidArgument", applicationErr.Type())
+		s.True(applicationErr.NonRetryable())
+		s.ErrorContains(err, expectedError)
+	}
+}
+
+func (s *activitiesSuite) TestRepairReplicationTasks() {
+	env, iceptor := s.initEnv()
+
+	execution3 := &commonpb.WorkflowExecution{WorkflowId: "workflow3", RunId: "run3"}
+	execution4 := &commonpb.WorkflowExecution{WorkflowId: "workflow4", RunId: "run4"}
+	request := repairReplicationTasksRequest{
+		Namespace:         mockedNamespace,
+		NamespaceID:       mockedNamespaceID,
+		TargetClusterName: remoteCluster,
+		Executions:        []*commonpb.WorkflowExecution{execution1, execution2, execution3, execution4},
+		RPS:               10,
+		MinEventGap:       3,
+	}
+	mutableState := func(lastEventID int64) *persistencespb.WorkflowMutableState {
+		return &persistencespb.WorkflowMutableState{
+			ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+				VersionHistories: versionhistory.NewVersionHistories(versionhistory.NewVersionHistory(nil, []*historyspb.VersionHistoryItem{
+					versionhistory.NewVersionHistoryItem(lastEventID, 1),
+				})),
+			},
+		}
+	}
+	expectEventIDs := func(we *commonpb.WorkflowExecution, sourceLastEventID int64, targetLastEventID int64) {
+		s.mockHistoryClient.EXPECT().DescribeMutableState(gomock.Any(), protomock.Eq(&historyservice.DescribeMutableStateRequest{
+			NamespaceId:     mockedNamespaceID,
+			Execution:       we,
+			SkipForceReload: true,
+		})).Return(&historyservice.DescribeMutableStateResponse{DatabaseMutableState: mutableState(sourceLastEventID)}, nil)
+		remoteDescribe := s.mockRemoteAdminClient.EXPECT().DescribeMutableState(gomock.Any(), protomock.Eq(&adminservice.DescribeMutableStateRequest{
+			Namespace:       mockedNamespace,
+			Execution:       we,
+			SkipForceReload: true,
+		}))
+		if targetLastEventID == 0 {
+			remoteDescribe.Return(nil, serviceerror.NewNotFound(""))
+		} else {
+			remoteDescribe.Return(&adminservice.DescribeMutableStateResponse{DatabaseMutableState: mutableState(targetLastEventID)}, nil)
+		}
+	}
+	expectEventIDs(execution1, 10, 5)  // behind by 5 events, repaired
+	expectEventIDs(execution2, 10, 10) // up to date
+	expectEventIDs(execution3, 10, 8)  // behind by less than MinEventGap
+	expectEventIDs(execution4, 4, 0)   // missing on the target cluster, repaired
+
+	for _, we := range []*commonpb.WorkflowExecution{execution1, execution4} {
+		s.mockHistoryClient.EXPECT().GenerateLastHistoryReplicationTasks(gomock.Any(), protomock.Eq(&historyservice.GenerateLastHistoryReplicationTasksRequest{
+			NamespaceId:    mockedNamespaceID,
+			Execution:      we,
+			TargetClusters: []string{remoteCluster},
+		})).Return(&historyservice.GenerateLastHistoryReplicationTasksResponse{}, nil)
+	}
+
+	f, err := env.ExecuteActivity(s.a.RepairReplicationTasks, &request)
+	s.NoError(err)
+	var response repairReplicationTasksResponse
+	s.NoError(f.Get(&response))
+	s.Equal(repairReplicationTasksResponse{RepairedWorkflowCount: 2, BackfilledEventCount: 9}, response)
+	s.Equal([]int{0, 1, 2, 3}, iceptor.generateReplicationRecordedHeartbeats)
+}
+
 func (s *activitiesSuite) TestCountWorkflows() {
 	env, _ := s.initEnv()
 
@@ -773,3 +1093,97 @@ func (s *activitiesSuite) TestWaitCatchUp() {
 	_, err := env.ExecuteActivity(s.a.WaitCatchup, request)
 	s.NoError(err)
 }
+
+func (s *activitiesSuite) TestVerifyTaskQueueUserData_Replicated() {
+	env, _ := s.initEnv()
+	mockRemoteFrontendClient := workflowservicemock.NewMockWorkflowServiceClient(s.controller)
+	sourceClock := &hlc.Clock{WallClock: 10, Version: 1, ClusterId: 1}
+	targetClock, err := (&hlc.Clock{WallClock: 10, Version: 1, ClusterId: 1}).Marshal()
+	s.NoError(err)
+
+	s.mockFrontendClient.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any()).Return(&workflowservice.DescribeNamespaceResponse{
+		NamespaceInfo: &namespacepb.NamespaceInfo{Id: mockedNamespaceID},
+	}, nil)
+	s.mockClientBean.EXPECT().GetRemoteFrontendClient(remoteCluster).Return(nil, mockRemoteFrontendClient, nil)
+	s.mockTaskManager.EXPECT().ListTaskQueueUserDataEntries(gomock.Any(), gomock.Any()).Return(&persistence.ListTaskQueueUserDataEntriesResponse{
+		Entries: []*persistence.TaskQueueUserDataEntry{
+			{TaskQueue: "tq-1", UserData: &persistencespb.VersionedTaskQueueUserData{Data: &persistencespb.TaskQueueUserData{Clock: sourceClock}}},
+			{TaskQueue: "tq-2", UserData: &persistencespb.VersionedTaskQueueUserData{Data: &persistencespb.TaskQueueUserData{Clock: sourceClock}}},
+		},
+	}, nil)
+	mockRemoteFrontendClient.EXPECT().GetWorkerVersioningRules(gomock.Any(), gomock.Any()).Return(&workflowservice.GetWorkerVersioningRulesResponse{
+		ConflictToken: targetClock,
+	}, nil).Times(2)
+
+	f, err := env.ExecuteActivity(s.a.VerifyTaskQueueUserData, &verifyTaskQueueUserDataRequest{
+		Namespace:         mockedNamespace,
+		TargetClusterName: remoteCluster,
+	})
+	s.NoError(err)
+	var output verifyTaskQueueUserDataResponse
+	s.NoError(f.Get(&output))
+	s.Equal(int64(0), output.MissingTaskQueueCount)
+	s.Empty(output.MissingTaskQueues)
+}
+
+func (s *activitiesSuite) TestVerifyTaskQueueUserData_Missing() {
+	env, iceptor := s.initEnv()
+	mockRemoteFrontendClient := workflowservicemock.NewMockWorkflowServiceClient(s.controller)
+	sourceClock := &hlc.Clock{WallClock: 10, Version: 1, ClusterId: 1}
+	targetClock, err := sourceClock.Marshal()
+	s.NoError(err)
+
+	s.mockFrontendClient.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any()).Return(&workflowservice.DescribeNamespaceResponse{
+		NamespaceInfo: &namespacepb.NamespaceInfo{Id: mockedNamespaceID},
+	}, nil)
+	s.mockClientBean.EXPECT().GetRemoteFrontendClient(remoteCluster).Return(nil, mockRemoteFrontendClient, nil)
+	s.mockTaskManager.EXPECT().ListTaskQueueUserDataEntries(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, request *persistence.ListTaskQueueUserDataEntriesRequest) (*persistence.ListTaskQueueUserDataEntriesResponse, error) {
+			s.Equal(mockedNamespaceID, request.NamespaceID)
+			if len(request.NextPageToken) == 0 {
+				return &persistence.ListTaskQueueUserDataEntriesResponse{
+					NextPageToken: []byte("page-2"),
+					Entries: []*persistence.TaskQueueUserDataEntry{
+						{TaskQueue: "tq-1", UserData: &persistencespb.VersionedTaskQueueUserData{Data: &persistencespb.TaskQueueUserData{Clock: sourceClock}}},
+						// without a clock there is nothing to compare against
+						{TaskQueue: "tq-2", UserData: &persistencespb.VersionedTaskQueueUserData{Data: &persistencespb.TaskQueueUserData{}}},
+					},
+				}, nil
+			}
+			return &persistence.ListTaskQueueUserDataEntriesResponse{
+				Entries: []*persistence.TaskQueueUserDataEntry{
+					{TaskQueue: "tq-3", UserData: &persistencespb.VersionedTaskQueueUserData{Data: &persistencespb.TaskQueueUserData{Clock: sourceClock}}},
+					{TaskQueue: "tq-4", UserData: &persistencespb.VersionedTaskQueueUserData{Data: &persistencespb.TaskQueueUserData{Clock: sourceClock}}},
+				},
+			}, nil
+		},
+	).Times(2)
+	mockRemoteFrontendClient.EXPECT().GetWorkerVersioningRules(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, request *workflowservice.GetWorkerVersioningRulesRequest, opts ...grpc.CallOption) (*workflowservice.GetWorkerVersioningRulesResponse, error) {
+			switch request.TaskQueue {
+			case "tq-1":
+				return &workflowservice.GetWorkerVersioningRulesResponse{ConflictToken: targetClock}, nil
+			case "tq-3":
+				// the target has older user data
+				olderClock, err := (&hlc.Clock{WallClock: 5, Version: 1, ClusterId: 1}).Marshal()
+				s.NoError(err)
+				return &workflowservice.GetWorkerVersioningRulesResponse{ConflictToken: olderClock}, nil
+			default:
+				// the target has no user data
+				return &workflowservice.GetWorkerVersioningRulesResponse{}, nil
+			}
+		},
+	).Times(3)
+
+	f, err := env.ExecuteActivity(s.a.VerifyTaskQueueUserData, &verifyTaskQueueUserDataRequest{
+		Namespace:         mockedNamespace,
+		TargetClusterName: remoteCluster,
+	})
+	s.NoError(err)
+	var output verifyTaskQueueUserDataResponse
+	s.NoError(f.Get(&output))
+	s.Equal(int64(2), output.MissingTaskQueueCount)
+	s.Equal([]string{"tq-3", "tq-4"}, output.MissingTaskQueues)
+	s.Len(iceptor.verifyUserDataRecordedHeartbeats, 1)
+	s.Equal([]byte("page-2"), iceptor.verifyUserDataRecordedHeartbeats[0].NextPageToken)
+}
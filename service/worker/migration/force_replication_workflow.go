@@ -1,7 +1,11 @@
 package migration
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	commonpb "go.temporal.io/api/common/v1"
@@ -9,14 +13,17 @@ import (
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
+	"go.temporal.io/server/common/log/tag"
 	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/searchattribute"
 )
 
 type (
 	TaskQueueUserDataReplicationParams struct {
 		// PageSize for the SeedReplicationQueueWithUserDataEntries activity
 		PageSize int
-		// RPS limits the number of task queue user data entries pages requested per second.
+		// RPS limits the number of task queue user data entries published to the replication queue per second,
+		// together with the pages of entries requested.
 		RPS float64
 	}
 
@@ -25,6 +32,9 @@ type (
 		TaskQueueUserDataReplicationParams
 		// Namespace name
 		Namespace string
+		// ProgressWorkflowID is the workflow signaled with the seeding progress, set by
+		// ForceTaskQueueUserDataReplicationWorkflow to its parent.
+		ProgressWorkflowID string
 	}
 
 	ForceReplicationParams struct {
@@ -35,30 +45,120 @@ type (
 		GetParentInfoRPS        float64 // RPS for getting parent child info
 		ListWorkflowsPageSize   int     // PageSize of ListWorkflow, will paginate through results.
 		PageCountPerExecution   int     // number of pages to be processed before continue as new, max is 1000.
-		NextPageToken           []byte  // used by continue as new
+		NextPageToken           []byte  // used by continue as new, or by a new run restarting from PageTokenForRestart
+		// NextPageOffset is the index in the page of NextPageToken of the first workflow to replicate, set by a new run
+		// restarting from PageTokenForRestart and PageOffsetForRestart. The page is listed again, so the restarted run
+		// must use the same Query and ListWorkflowsPageSize.
+		NextPageOffset int `validate:"gte=0"`
+
+		// ExcludeWorkflowTypes skips workflows of the given types. It is combined with Query and the workflow type
+		// filter of the namespace replication config using AND, so it can only narrow down the listed workflows.
+		ExcludeWorkflowTypes []string
+
+		// Number of executions per GenerateReplicationTasks activity, pages are split into batches of this size.
+		// Together with ConcurrentActivityCount this bounds the executions held by in-flight activities
+		// regardless of ListWorkflowsPageSize, and a canceled run can be restarted from any batch of a page.
+		// Zero uses a single batch per page.
+		GenerateTasksBatchSize int `validate:"gte=0"`
+
+		// Used for listing workflows in workflow ID order, so that reruns process executions in a stable order.
+		// In this mode LastWorkflowID is used as the cursor instead of NextPageToken. Requires the visibility
+		// store to support ORDER BY.
+		OrderByWorkflowID bool
+		LastWorkflowID    string
+
+		// MaxConsecutiveEmptyPages fails the migration when ListWorkflows returns that many empty pages in a row which
+		// still have a next page token, so that a visibility store paging forever doesn't keep it running. Zero
+		// means unlimited.
+		MaxConsecutiveEmptyPages  int `validate:"gte=0"`
+		ConsecutiveEmptyPageCount int // carried over continue-as-new
 
 		// Used for verifying workflow executions were replicated successfully on target cluster.
 		EnableVerification      bool
 		TargetClusterEndpoint   string
 		TargetClusterName       string
 		VerifyIntervalInSeconds int `validate:"gte=0"`
+		// RPS of the workflows verified against the target cluster, VerifyReplicationTasks activities are
+		// dispatched no faster than this. Zero uses OverallRps.
+		VerifyRps float64
+		// BackpressureReplicationLag halves ConcurrentActivityCount and OverallRps for the next continue-as-new
+		// run when a run verified a workflow with a higher replication lag, so that a target cluster falling
+		// behind gets to catch up. Requires EnableVerification, zero disables it.
+		BackpressureReplicationLag time.Duration
+		BackpressureCount          int           // number of times the rates were reduced
+		MaxReplicationLagInRun     time.Duration // reset by each run
+
+		// Used for aborting the migration when the target cluster keeps failing GenerateReplicationTasks or
+		// VerifyReplicationTasks. Zero values disable the corresponding check.
+		MaxTargetErrorRatio    float64 // max ratio of failed activities within the last TargetErrorWindowSize activities
+		MaxConsecutiveFailures int     // max number of consecutive failed activities
+		TargetErrorWindowSize  int     // number of most recent activities considered for MaxTargetErrorRatio
+		TargetErrorBudget      TargetErrorBudgetState
+
+		// SkipCorrupted skips source workflows which can never be replicated, e.g. because their history is
+		// corrupted, instead of failing the migration. Transient errors are still retried. Skipped workflows
+		// are not verified.
+		SkipCorrupted bool
+
+		// Used for persisting progress to the ForceReplicationCheckpointStore every CheckpointIntervalInSeconds and
+		// on continue-as-new, zero disables checkpointing. A new run started with ResumeFromCheckpoint continues
+		// from the last checkpoint of the namespace, which must have been taken with the same query.
+		CheckpointIntervalInSeconds int `validate:"gte=0"`
+		ResumeFromCheckpoint        bool
+
+		// RepairMode only generates replication tasks for workflows which TargetClusterName is missing events of,
+		// so that the target cluster backfills just the missing events. It is much cheaper than replicating all
+		// workflows when the clusters drifted slightly. RepairMinEventGap is the number of events the target
+		// cluster must be behind for a workflow to be repaired, zero means any missing event.
+		RepairMode        bool
+		RepairMinEventGap int64 `validate:"gte=0"`
+
+		// DryRun only estimates the cost of the migration without generating any replication tasks. The average
+		// history size is sampled from the first DryRunSamplePageCount pages of workflows, the estimate is returned
+		// as the workflow result and by the status query.
+		DryRun                bool
+		DryRunSamplePageCount int `validate:"gte=0"`
+
+		// TransformName selects the ForceReplicationTransform applied to each workflow before its replication task
+		// is generated. The transform must be registered with the worker, empty applies no transform.
+		TransformName string
+
+		// Optional workflow which is signaled with the final ForceReplicationStatus when the migration
+		// completes or fails. Signaling is best-effort and does not affect the migration result.
+		CompletionSignalTarget *CompletionSignalTarget
+
+		// CancelDrainTimeoutInSeconds bounds how long a run stopped by the cancel signal waits for its in-flight
+		// activities before completing, see forceReplicationCancelSignalType.
+		CancelDrainTimeoutInSeconds int `validate:"gte=0"`
 
 		// Used by query handler to indicate overall progress of replication
 		LastCloseTime                      time.Time
 		LastStartTime                      time.Time
 		ContinuedAsNewCount                int
+		ListedPageCount                    int   // pages listed by all runs, not restored from checkpoints
+		ListedWorkflowCount                int64 // workflows listed by all runs, not restored from checkpoints
+		MaxReplicationLag                  time.Duration
 		TaskQueueUserDataReplicationParams TaskQueueUserDataReplicationParams
 		ReplicatedWorkflowCount            int64
 		TotalForceReplicateWorkflowCount   int64
 		ReplicatedWorkflowCountPerSecond   float64
+		CorruptedWorkflowCount             int64
+		CorruptedWorkflows                 []*commonpb.WorkflowExecution // capped at maxReportedCorruptedWorkflows
+		RepairedWorkflowCount              int64
+		BackfilledEventCount               int64
+		ShardProgress                      map[int32]*ShardReplicationProgress // keyed by source shard ID
 
 		// Used to calculate QPS
 		QPSQueue QPSQueue
-		// Queue size is determined by Multiplier * Concurrency
+		// Queue size is determined by Multiplier * Concurrency. Zero uses defaultEstimationMultiplier, negative
+		// values and values above maxEstimationMultiplier are rejected.
 		EstimationMultiplier int
 
 		// Carry over the replication status after continue-as-new.
 		TaskQueueUserDataReplicationStatus TaskQueueUserDataReplicationStatus
+		// Set once a run started the task queue user data replication child workflow, so that the runs it
+		// continues as new don't start it again. Runs restarted from a checkpoint or a status start it again.
+		TaskQueueUserDataReplicationStarted bool
 	}
 
 	QPSQueue struct {
@@ -71,12 +171,74 @@ type (
 		Timestamp time.Time
 	}
 
+	// ShardReplicationProgress is the progress of generating replication tasks for the workflows of a source
+	// history shard. Failures are only reported by the force_replication_task_failed metric.
+	ShardReplicationProgress struct {
+		GeneratedWorkflowCount int64
+		SkippedWorkflowCount   int64 // workflows which were not found or skipped as corrupted
+	}
+
+	// listedBatch is a batch of the workflows of a page listed by listWorkflowsForReplication, with the position it
+	// was listed from: the page token and the offset of the batch in the page, or the last workflow ID before the
+	// batch when listing in workflow ID order.
+	listedBatch struct {
+		Executions     []*commonpb.WorkflowExecution
+		PageToken      []byte
+		PageOffset     int
+		LastWorkflowID string
+	}
+
+	// forceReplicationCancellation tracks the cancel signal of a run.
+	forceReplicationCancellation struct {
+		requested bool
+		// ready once CancelDrainTimeoutInSeconds passed since the cancel signal
+		drainDeadline workflow.Future
+		// set by enqueueReplicationTasks
+		notDispatched *listedBatch // first batch no replication tasks were generated for
+		drainTimedOut bool
+	}
+
 	ForceReplicationOutput struct {
+		// Only set by dry runs.
+		DryRunEstimate *ForceReplicationEstimate
+	}
+
+	// ForceReplicationEstimate is the projected cost of replicating the remaining workflows of a migration.
+	ForceReplicationEstimate struct {
+		WorkflowCount               int64
+		SampledWorkflowCount        int64
+		AverageHistorySizeInBytes   int64
+		EstimatedHistorySizeInBytes int64
+		EstimatedDuration           time.Duration // at OverallRps
+	}
+
+	// CompletionSignalTarget identifies the workflow, in the namespace of the force replication workflow,
+	// to signal on completion.
+	CompletionSignalTarget struct {
+		WorkflowID string
+		SignalName string
+	}
+
+	// TargetErrorBudgetState tracks target cluster failures, it is carried over on continue-as-new.
+	TargetErrorBudgetState struct {
+		RecentFailures      []bool // outcome of the most recent activities, true means failed
+		ConsecutiveFailures int
+		ErrorRatio          float64
+		AbortReason         string
 	}
 
 	TaskQueueUserDataReplicationStatus struct {
 		Done           bool
 		FailureMessage string
+		// Last seeding progress reported by SeedReplicationQueueWithUserDataEntries, the position in the task queue
+		// user data entries it would resume from.
+		SeedingNextPageToken []byte
+		SeedingIndexInPage   int
+		// Set with EnableVerification once the target cluster was checked for the seeded user data. Task queues
+		// still missing after userDataVerificationMaxAttempts checks are reported here but don't fail the migration.
+		Verified              bool
+		MissingTaskQueues     []string // capped at maxReportedMissingTaskQueues
+		MissingTaskQueueCount int64
 	}
 
 	ForceReplicationStatus struct {
@@ -88,6 +250,27 @@ type (
 		ReplicatedWorkflowCount            int64
 		ReplicatedWorkflowCountPerSecond   float64
 		PageTokenForRestart                []byte
+		PageOffsetForRestart               int    // index in the page of PageTokenForRestart of the first workflow not replicated
+		WorkflowIDForRestart               string // set instead of PageTokenForRestart when OrderByWorkflowID is enabled
+		TargetErrorRatio                   float64
+		TargetConsecutiveFailures          int
+		AbortReason                        string
+		CorruptedWorkflowCount             int64
+		CorruptedWorkflows                 []*commonpb.WorkflowExecution
+		RepairedWorkflowCount              int64
+		BackfilledEventCount               int64
+		FailureMessage                     string        // only set in the completion signal of a failed run
+		CurrentPageIndex                   int           // index of the next page to list, counted across runs
+		PagesProcessedInExecution          int           // pages listed by the current run
+		EstimatedRemainingWorkflowCount    int64         // workflows after LastStartTime/LastCloseTime in the listing order
+		MaxReplicationLag                  time.Duration // longest wait for the target to have a verified workflow
+		BackpressureCount                  int           // times ConcurrentActivityCount and OverallRps were halved
+		ConcurrentActivityCount            int
+		OverallRps                         float64
+		DryRun                             bool // the estimate is only set once the dry run completed
+		Canceled                           bool // stopped by the cancel signal, see forceReplicationCancelSignalType
+		DryRunEstimate                     *ForceReplicationEstimate
+		ShardProgress                      map[int32]*ShardReplicationProgress // keyed by source shard ID
 	}
 )
 
@@ -102,26 +285,55 @@ var (
 )
 
 const (
-	forceReplicationWorkflowName               = "force-replication"
-	forceTaskQueueUserDataReplicationWorkflow  = "force-task-queue-user-data-replication"
-	forceReplicationStatusQueryType            = "force-replication-status"
-	taskQueueUserDataReplicationDoneSignalType = "task-queue-user-data-replication-done"
-	taskQueueUserDataReplicationVersionMarker  = "replicate-task-queue-user-data"
+	forceReplicationWorkflowName              = "force-replication"
+	forceTaskQueueUserDataReplicationWorkflow = "force-task-queue-user-data-replication"
+	forceReplicationStatusQueryType           = "force-replication-status"
+	// forceReplicationCancelSignalType stops a run safely: no more pages are listed or dispatched, and activities
+	// which already started are waited for, up to CancelDrainTimeoutInSeconds. The run then completes with
+	// PageTokenForRestart and PageOffsetForRestart / WorkflowIDForRestart pointing to the first batch not replicated.
+	forceReplicationCancelSignalType                 = "cancel"
+	taskQueueUserDataReplicationDoneSignalType       = "task-queue-user-data-replication-done"
+	taskQueueUserDataReplicationProgressSignalType   = "task-queue-user-data-replication-progress"
+	taskQueueUserDataReplicationVersionMarker        = "replicate-task-queue-user-data"
+	workflowTypeFilterVersionMarker                  = "replication-workflow-type-filter"
+	verifyRpsVersionMarker                           = "verify-rps"
+	verifyTaskQueueUserDataVersionMarker             = "verify-task-queue-user-data"
+	taskQueueUserDataReplicationStartedVersionMarker = "task-queue-user-data-replication-started"
+	inPageRestartVersionMarker                       = "in-page-restart"
 
 	defaultListWorkflowsPageSize                   = 1000
 	defaultPageCountPerExecution                   = 200
 	maxPageCountPerExecution                       = 1000
 	defaultPageSizeForTaskQueueUserDataReplication = 20
-	defaultRPSForTaskQueueUserDataReplication      = 1.0
+	defaultRPSForTaskQueueUserDataReplication      = 20.0
 	defaultVerifyIntervalInSeconds                 = 5
+	defaultCancelDrainTimeoutInSeconds             = 600
+	minBackpressureRps                             = 1.0
+	defaultTargetErrorWindowSize                   = 20
+	defaultDryRunSamplePageCount                   = 3
+	defaultEstimationMultiplier                    = 2
+	maxEstimationMultiplier                        = 100
+	checkpointActivityMaxAttempts                  = 3
+	// when the error budget is enabled, activities are retried by the workflow so that failures can be counted
+	targetErrorBudgetActivityMaxAttempts = 3
+	// bounds the skipped workflows carried over on continue-as-new, CorruptedWorkflowCount is always exact
+	maxReportedCorruptedWorkflows = 1000
+	maxReportedMissingTaskQueues  = 1000
+	// the seeded user data is replicated asynchronously, so the target is checked again after VerifyIntervalInSeconds
+	userDataVerificationMaxAttempts = 5
 )
 
-func ForceReplicationWorkflow(ctx workflow.Context, params ForceReplicationParams) error {
+func ForceReplicationWorkflow(ctx workflow.Context, params ForceReplicationParams) (_ ForceReplicationOutput, retErr error) {
 	// For now, we'll return the initial page token for simplicity.
 	// If we want this to be more precise, we could track processed pages.
 	startPageToken := params.NextPageToken
+	startPageOffset := params.NextPageOffset
+	startWorkflowID := params.LastWorkflowID
+	var dryRunEstimate *ForceReplicationEstimate
+	startListedPageCount := params.ListedPageCount
+	var cancellation forceReplicationCancellation
 
-	_ = workflow.SetQueryHandler(ctx, forceReplicationStatusQueryType, func() (ForceReplicationStatus, error) {
+	getStatus := func() ForceReplicationStatus {
 		return ForceReplicationStatus{
 			LastCloseTime:                      params.LastCloseTime,
 			LastStartTime:                      params.LastStartTime,
@@ -131,75 +343,260 @@ func ForceReplicationWorkflow(ctx workflow.Context, params ForceReplicationParam
 			ReplicatedWorkflowCount:            params.ReplicatedWorkflowCount,
 			ReplicatedWorkflowCountPerSecond:   params.ReplicatedWorkflowCountPerSecond,
 			PageTokenForRestart:                startPageToken,
-		}, nil
+			PageOffsetForRestart:               startPageOffset,
+			WorkflowIDForRestart:               startWorkflowID,
+			TargetErrorRatio:                   params.TargetErrorBudget.ErrorRatio,
+			TargetConsecutiveFailures:          params.TargetErrorBudget.ConsecutiveFailures,
+			AbortReason:                        params.TargetErrorBudget.AbortReason,
+			CorruptedWorkflowCount:             params.CorruptedWorkflowCount,
+			CorruptedWorkflows:                 params.CorruptedWorkflows,
+			RepairedWorkflowCount:              params.RepairedWorkflowCount,
+			BackfilledEventCount:               params.BackfilledEventCount,
+			DryRun:                             params.DryRun,
+			Canceled:                           cancellation.requested,
+			DryRunEstimate:                     dryRunEstimate,
+			ShardProgress:                      params.ShardProgress,
+			CurrentPageIndex:                   params.ListedPageCount,
+			PagesProcessedInExecution:          params.ListedPageCount - startListedPageCount,
+			EstimatedRemainingWorkflowCount:    estimateRemainingWorkflowCount(params),
+			MaxReplicationLag:                  params.MaxReplicationLag,
+			BackpressureCount:                  params.BackpressureCount,
+			ConcurrentActivityCount:            params.ConcurrentActivityCount,
+			OverallRps:                         params.OverallRps,
+		}
+	}
+	_ = workflow.SetQueryHandler(ctx, forceReplicationStatusQueryType, func() (ForceReplicationStatus, error) {
+		return getStatus(), nil
 	})
 
 	if err := validateAndSetForceReplicationParams(ctx, &params); err != nil {
-		return err
+		return ForceReplicationOutput{}, err
+	}
+
+	var drainDeadline workflow.Settable
+	cancellation.drainDeadline, drainDeadline = workflow.NewFuture(ctx)
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		// We don't care if there's more data to receive
+		_ = workflow.GetSignalChannel(ctx, forceReplicationCancelSignalType).Receive(ctx, nil)
+		cancellation.requested = true
+		if workflow.Sleep(ctx, time.Duration(params.CancelDrainTimeoutInSeconds)*time.Second) == nil {
+			drainDeadline.Set(nil, nil)
+		}
+	})
+
+	if params.ResumeFromCheckpoint {
+		if err := resumeFromCheckpoint(ctx, &params); err != nil {
+			return ForceReplicationOutput{}, err
+		}
+		startPageToken = params.NextPageToken
+		startPageOffset = params.NextPageOffset
+		startWorkflowID = params.LastWorkflowID
+	}
+
+	defer func() {
+		signalCompletion(ctx, params.CompletionSignalTarget, getStatus(), retErr)
+	}()
+
+	getCheckpoint := func() *ForceReplicationCheckpoint {
+		return &ForceReplicationCheckpoint{
+			Query:                   params.Query,
+			OrderByWorkflowID:       params.OrderByWorkflowID,
+			PageTokenForRestart:     startPageToken,
+			PageOffsetForRestart:    startPageOffset,
+			WorkflowIDForRestart:    startWorkflowID,
+			ContinuedAsNewCount:     params.ContinuedAsNewCount,
+			TotalWorkflowCount:      params.TotalForceReplicateWorkflowCount,
+			ReplicatedWorkflowCount: params.ReplicatedWorkflowCount,
+			CorruptedWorkflowCount:  params.CorruptedWorkflowCount,
+			CheckpointTime:          workflow.Now(ctx),
+
+			TaskQueueUserDataReplicationStatus: params.TaskQueueUserDataReplicationStatus,
+		}
+	}
+	// Dry runs don't make progress, so they must not overwrite the checkpoint of a real run.
+	if params.CheckpointIntervalInSeconds > 0 && !params.DryRun {
+		workflow.Go(ctx, func(ctx workflow.Context) {
+			for workflow.Sleep(ctx, time.Duration(params.CheckpointIntervalInSeconds)*time.Second) == nil {
+				saveCheckpoint(ctx, params.Namespace, getCheckpoint())
+			}
+		})
+	}
+
+	var metadataResp metadataResponse
+	var err error
+	query := params.Query
+	workflowTypeFilterVersion := workflow.GetVersion(ctx, workflowTypeFilterVersionMarker, workflow.DefaultVersion, 1)
+	if workflowTypeFilterVersion > workflow.DefaultVersion {
+		// The namespace replication filter applies to counting workflows as well, so the metadata is needed first.
+		metadataResp, err = getClusterMetadata(ctx, params)
+		if err != nil {
+			return ForceReplicationOutput{}, err
+		}
+		excludedWorkflowTypes := append(slices.Clone(metadataResp.ExcludedWorkflowTypes), params.ExcludeWorkflowTypes...)
+		query = workflowTypeFilterQuery(params.Query, metadataResp.IncludedWorkflowTypes, excludedWorkflowTypes)
+	} else if len(params.ExcludeWorkflowTypes) > 0 {
+		query = workflowTypeFilterQuery(params.Query, nil, params.ExcludeWorkflowTypes)
 	}
 
 	if params.TotalForceReplicateWorkflowCount == 0 {
-		wfCount, err := countWorkflowForReplication(ctx, params)
+		wfCount, err := countWorkflowForReplication(ctx, params, query)
 		if err != nil {
-			return err
+			return ForceReplicationOutput{}, err
 		}
 		params.TotalForceReplicateWorkflowCount = wfCount
 	}
 
-	metadataResp, err := getClusterMetadata(ctx, params)
-	if err != nil {
-		return err
+	if params.DryRun {
+		dryRunEstimate, err = estimateReplicationCost(ctx, params, query)
+		if err != nil {
+			return ForceReplicationOutput{}, err
+		}
+		return ForceReplicationOutput{DryRunEstimate: dryRunEstimate}, nil
+	}
+
+	if workflowTypeFilterVersion == workflow.DefaultVersion {
+		metadataResp, err = getClusterMetadata(ctx, params)
+		if err != nil {
+			return ForceReplicationOutput{}, err
+		}
+	}
+	if err := resolveTargetCluster(&params, metadataResp.ClusterRPCAddresses); err != nil {
+		return ForceReplicationOutput{}, err
 	}
 
 	if !params.TaskQueueUserDataReplicationStatus.Done {
 		err = maybeKickoffTaskQueueUserDataReplication(ctx, params, func(failureReason string) {
 			params.TaskQueueUserDataReplicationStatus.FailureMessage = failureReason
 			params.TaskQueueUserDataReplicationStatus.Done = true
+		}, func(progress seedReplicationQueueWithUserDataEntriesHeartbeatDetails) {
+			params.TaskQueueUserDataReplicationStatus.SeedingNextPageToken = progress.NextPageToken
+			params.TaskQueueUserDataReplicationStatus.SeedingIndexInPage = progress.IndexInPage
 		})
 		if err != nil {
-			return err
+			return ForceReplicationOutput{}, err
 		}
+		params.TaskQueueUserDataReplicationStarted = true
 	}
 
-	workflowExecutionsCh := workflow.NewBufferedChannel(ctx, params.PageCountPerExecution)
+	// Large enough for all batches of the run, so that listing is never blocked by the batches not dispatched yet.
+	batchCountPerPage := 1
+	if params.GenerateTasksBatchSize > 0 {
+		batchCountPerPage = (params.ListWorkflowsPageSize + params.GenerateTasksBatchSize - 1) / params.GenerateTasksBatchSize
+	}
+	workflowExecutionsCh := workflow.NewBufferedChannel(ctx, params.PageCountPerExecution*batchCountPerPage)
 	var listWorkflowsErr error
 	workflow.Go(ctx, func(ctx workflow.Context) {
-		listWorkflowsErr = listWorkflowsForReplication(ctx, workflowExecutionsCh, &params)
+		listWorkflowsErr = listWorkflowsForReplication(ctx, workflowExecutionsCh, &params, query, &cancellation)
 
 		// enqueueReplicationTasks only returns when workflowExecutionsCh is closed (or if it encounters an error).
 		// Therefore, listWorkflowsErr will be set prior to their use and params will be updated.
 		workflowExecutionsCh.Close()
 	})
 
-	if err := enqueueReplicationTasks(ctx, workflowExecutionsCh, metadataResp.NamespaceID, &params); err != nil {
-		return err
+	if err := enqueueReplicationTasks(ctx, workflowExecutionsCh, metadataResp, &params, &cancellation); err != nil {
+		return ForceReplicationOutput{}, err
 	}
 
 	if listWorkflowsErr != nil {
-		return listWorkflowsErr
+		return ForceReplicationOutput{}, listWorkflowsErr
+	}
+
+	if cancellation.requested {
+		switch {
+		case cancellation.drainTimedOut:
+			// Activities of any batch of this run may not have completed, so it is restarted from its start.
+		case cancellation.notDispatched != nil:
+			startPageToken = cancellation.notDispatched.PageToken
+			startPageOffset = 0
+			if !params.OrderByWorkflowID {
+				startPageOffset = cancellation.notDispatched.PageOffset
+			}
+			startWorkflowID = cancellation.notDispatched.LastWorkflowID
+		default:
+			startPageToken = params.NextPageToken
+			startPageOffset = params.NextPageOffset
+			startWorkflowID = params.LastWorkflowID
+		}
+		if params.CheckpointIntervalInSeconds > 0 {
+			saveCheckpoint(ctx, params.Namespace, getCheckpoint())
+		}
+		return ForceReplicationOutput{}, nil
 	}
 
 	if params.NextPageToken == nil {
 		if workflow.GetVersion(ctx, taskQueueUserDataReplicationVersionMarker, workflow.DefaultVersion, 1) > workflow.DefaultVersion {
-			err := workflow.Await(ctx, func() bool { return params.TaskQueueUserDataReplicationStatus.Done })
+			err := workflow.Await(ctx, func() bool {
+				return params.TaskQueueUserDataReplicationStatus.Done || cancellation.requested
+			})
 			if err != nil {
-				return err
+				return ForceReplicationOutput{}, err
+			}
+			if cancellation.requested && !params.TaskQueueUserDataReplicationStatus.Done {
+				// all workflows were replicated, only the task queue user data is left
+				startPageToken = nil
+				startPageOffset = 0
+				startWorkflowID = ""
+				return ForceReplicationOutput{}, nil
 			}
 			if params.TaskQueueUserDataReplicationStatus.FailureMessage != "" {
-				return fmt.Errorf("task queue user data replication failed: %v", params.TaskQueueUserDataReplicationStatus.FailureMessage)
+				return ForceReplicationOutput{}, fmt.Errorf("task queue user data replication failed: %v", params.TaskQueueUserDataReplicationStatus.FailureMessage)
+			}
+			if params.EnableVerification &&
+				workflow.GetVersion(ctx, verifyTaskQueueUserDataVersionMarker, workflow.DefaultVersion, 1) > workflow.DefaultVersion {
+				if err := verifyTaskQueueUserData(ctx, &params); err != nil {
+					return ForceReplicationOutput{}, err
+				}
 			}
 		}
-		return nil
+		return ForceReplicationOutput{}, nil
 	}
 
 	params.ContinuedAsNewCount++
+	if params.BackpressureReplicationLag > 0 && params.MaxReplicationLagInRun > params.BackpressureReplicationLag {
+		params.applyBackpressure()
+	}
+	params.MaxReplicationLagInRun = 0
+
+	if params.CheckpointIntervalInSeconds > 0 {
+		// Progress made by this run is only covered by the next run's periodic checkpoints otherwise.
+		startPageToken = params.NextPageToken
+		startPageOffset = params.NextPageOffset
+		startWorkflowID = params.LastWorkflowID
+		saveCheckpoint(ctx, params.Namespace, getCheckpoint())
+	}
 
 	// There are still more workflows to replicate. Continue-as-new to process on a new run.
 	// This prevents history size from exceeding the server-defined limit
-	return workflow.NewContinueAsNewError(ctx, ForceReplicationWorkflow, params)
+	return ForceReplicationOutput{}, workflow.NewContinueAsNewError(ctx, ForceReplicationWorkflow, params)
 }
 
-func maybeKickoffTaskQueueUserDataReplication(ctx workflow.Context, params ForceReplicationParams, onDone func(failureReason string)) error {
+// signalCompletion sends the final status to the completion signal target, unless the workflow is continuing as new.
+// Failures are logged and otherwise ignored so they don't fail the migration.
+func signalCompletion(ctx workflow.Context, target *CompletionSignalTarget, status ForceReplicationStatus, err error) {
+	if target == nil || workflow.IsContinueAsNewError(err) {
+		return
+	}
+	if err != nil {
+		status.FailureMessage = err.Error()
+	}
+
+	// The workflow context may already be canceled when the workflow fails due to cancellation.
+	ctx, cancel := workflow.NewDisconnectedContext(ctx)
+	defer cancel()
+	if err := workflow.SignalExternalWorkflow(ctx, target.WorkflowID, "", target.SignalName, status).Get(ctx, nil); err != nil {
+		workflow.GetLogger(ctx).Warn("Failed to signal force replication completion.",
+			tag.WorkflowID(target.WorkflowID),
+			tag.NewStringTag("signal-name", target.SignalName),
+			tag.Error(err))
+	}
+}
+
+func maybeKickoffTaskQueueUserDataReplication(
+	ctx workflow.Context,
+	params ForceReplicationParams,
+	onDone func(failureReason string),
+	onProgress func(progress seedReplicationQueueWithUserDataEntriesHeartbeatDetails),
+) error {
 	if workflow.GetVersion(ctx, taskQueueUserDataReplicationVersionMarker, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
 		return nil
 	}
@@ -211,9 +608,24 @@ func maybeKickoffTaskQueueUserDataReplication(ctx workflow.Context, params Force
 		_ = taskQueueUserDataReplicationDoneCh.Receive(ctx, &errStr)
 		onDone(errStr)
 	})
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		taskQueueUserDataReplicationProgressCh := workflow.GetSignalChannel(ctx, taskQueueUserDataReplicationProgressSignalType)
+		for {
+			var progress seedReplicationQueueWithUserDataEntriesHeartbeatDetails
+			if !taskQueueUserDataReplicationProgressCh.Receive(ctx, &progress) {
+				return
+			}
+			onProgress(progress)
+		}
+	})
 
-	// We only start the child workflow before we continue as new to avoid starting the child workflow more than once.
-	if params.ContinuedAsNewCount > 0 {
+	// We only start the child workflow once, runs continued as new don't start it again. Runs recorded before
+	// TaskQueueUserDataReplicationStarted only started it when they were not continued as new.
+	started := params.TaskQueueUserDataReplicationStarted
+	if workflow.GetVersion(ctx, taskQueueUserDataReplicationStartedVersionMarker, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		started = params.ContinuedAsNewCount > 0
+	}
+	if started {
 		return nil
 	}
 
@@ -233,9 +645,47 @@ func maybeKickoffTaskQueueUserDataReplication(ctx workflow.Context, params Force
 	var childExecution workflow.Execution
 	// Wait for the child workflow to be started.
 	err := child.GetChildWorkflowExecution().Get(ctx, &childExecution)
+	if temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+		// The child of a previous run with the same workflow ID, e.g. a run restarted from a checkpoint, is still
+		// replicating the user data. It signals its progress and completion to this run.
+		return nil
+	}
 	return err
 }
 
+// verifyTaskQueueUserData checks that the target cluster has the seeded task queue user data and records the result
+// in the TaskQueueUserDataReplicationStatus.
+func verifyTaskQueueUserData(ctx workflow.Context, params *ForceReplicationParams) error {
+	ao := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Hour,
+		HeartbeatTimeout:    time.Second * 30,
+		RetryPolicy:         forceReplicationActivityRetryPolicy,
+	}
+
+	actx := workflow.WithActivityOptions(ctx, ao)
+	var a *activities
+	status := &params.TaskQueueUserDataReplicationStatus
+	for attempt := 1; ; attempt++ {
+		var response verifyTaskQueueUserDataResponse
+		if err := workflow.ExecuteActivity(actx, a.VerifyTaskQueueUserData, &verifyTaskQueueUserDataRequest{
+			Namespace:         params.Namespace,
+			TargetClusterName: params.TargetClusterName,
+			PageSize:          params.TaskQueueUserDataReplicationParams.PageSize,
+		}).Get(ctx, &response); err != nil {
+			return err
+		}
+		status.Verified = true
+		status.MissingTaskQueues = response.MissingTaskQueues
+		status.MissingTaskQueueCount = response.MissingTaskQueueCount
+		if response.MissingTaskQueueCount == 0 || attempt >= userDataVerificationMaxAttempts {
+			return nil
+		}
+		if err := workflow.Sleep(ctx, time.Duration(params.VerifyIntervalInSeconds)*time.Second); err != nil {
+			return err
+		}
+	}
+}
+
 func ForceTaskQueueUserDataReplicationWorkflow(ctx workflow.Context, params TaskQueueUserDataReplicationParamsWithNamespace) error {
 	ao := workflow.ActivityOptions{
 		// This shouldn't take "too long", just set an arbitrary long timeout here and rely on heartbeats for liveness detection.
@@ -250,6 +700,7 @@ func ForceTaskQueueUserDataReplicationWorkflow(ctx workflow.Context, params Task
 
 	actx := workflow.WithActivityOptions(ctx, ao)
 	var a *activities
+	params.ProgressWorkflowID = workflow.GetInfo(ctx).ParentWorkflowExecution.ID
 	err := workflow.ExecuteActivity(actx, a.SeedReplicationQueueWithUserDataEntries, params).Get(ctx, nil)
 	errStr := ""
 	if err != nil {
@@ -268,16 +719,109 @@ func validateAndSetForceReplicationParams(ctx workflow.Context, params *ForceRep
 		return temporal.NewNonRetryableApplicationError("InvalidArgument: TargetClusterEndpoint or TargetClusterName is required with verification enabled", "InvalidArgument", nil)
 	}
 
+	if params.OrderByWorkflowID && strings.Contains(strings.ToUpper(params.Query), "ORDER BY") {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: Query must not contain ORDER BY when OrderByWorkflowID is enabled", "InvalidArgument", nil)
+	}
+
+	// Runs continued as new carry the page token of ListWorkflows in both listing modes.
+	if len(params.NextPageToken) > 0 && workflow.GetInfo(ctx).ContinuedExecutionRunID == "" {
+		if params.OrderByWorkflowID {
+			return temporal.NewNonRetryableApplicationError("InvalidArgument: NextPageToken is not supported with OrderByWorkflowID, restart from LastWorkflowID instead", "InvalidArgument", nil)
+		}
+		if params.ResumeFromCheckpoint {
+			return temporal.NewNonRetryableApplicationError("InvalidArgument: NextPageToken and ResumeFromCheckpoint are mutually exclusive", "InvalidArgument", nil)
+		}
+	}
+
+	if params.CompletionSignalTarget != nil && (len(params.CompletionSignalTarget.WorkflowID) == 0 || len(params.CompletionSignalTarget.SignalName) == 0) {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: CompletionSignalTarget requires WorkflowID and SignalName", "InvalidArgument", nil)
+	}
+
+	if params.MaxTargetErrorRatio < 0 || params.MaxTargetErrorRatio > 1 {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: MaxTargetErrorRatio must be between 0 and 1", "InvalidArgument", nil)
+	}
+
+	if params.MaxConsecutiveFailures < 0 {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: MaxConsecutiveFailures must not be negative", "InvalidArgument", nil)
+	}
+
+	if params.RepairMode && len(params.TargetClusterName) == 0 {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: TargetClusterName is required with RepairMode", "InvalidArgument", nil)
+	}
+
+	if params.RepairMode && params.EnableVerification {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: EnableVerification is not supported with RepairMode", "InvalidArgument", nil)
+	}
+
+	if params.RepairMinEventGap < 0 {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: RepairMinEventGap must not be negative", "InvalidArgument", nil)
+	}
+
+	if params.GenerateTasksBatchSize < 0 {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: GenerateTasksBatchSize must not be negative", "InvalidArgument", nil)
+	}
+
+	if params.NextPageOffset < 0 {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: NextPageOffset must not be negative", "InvalidArgument", nil)
+	}
+
+	if params.NextPageOffset > 0 && params.OrderByWorkflowID {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: NextPageOffset is not supported with OrderByWorkflowID, restart from LastWorkflowID instead", "InvalidArgument", nil)
+	}
+
+	if params.CheckpointIntervalInSeconds < 0 {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: CheckpointIntervalInSeconds must not be negative", "InvalidArgument", nil)
+	}
+
+	if params.BackpressureReplicationLag < 0 {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: BackpressureReplicationLag must not be negative", "InvalidArgument", nil)
+	}
+
+	if params.BackpressureReplicationLag > 0 && !params.EnableVerification {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: BackpressureReplicationLag requires EnableVerification", "InvalidArgument", nil)
+	}
+
+	if params.CancelDrainTimeoutInSeconds < 0 {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: CancelDrainTimeoutInSeconds must not be negative", "InvalidArgument", nil)
+	}
+
+	if slices.Contains(params.ExcludeWorkflowTypes, "") {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: ExcludeWorkflowTypes must not contain empty workflow types", "InvalidArgument", nil)
+	}
+
+	if params.MaxConsecutiveEmptyPages < 0 {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: MaxConsecutiveEmptyPages must not be negative", "InvalidArgument", nil)
+	}
+
+	if params.DryRunSamplePageCount < 0 {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: DryRunSamplePageCount must not be negative", "InvalidArgument", nil)
+	}
+
+	if params.EstimationMultiplier < 0 || params.EstimationMultiplier > maxEstimationMultiplier {
+		return temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("InvalidArgument: EstimationMultiplier must be between 1 and %d, or 0 for the default", maxEstimationMultiplier),
+			"InvalidArgument",
+			nil,
+		)
+	}
+
 	if params.ConcurrentActivityCount <= 0 {
 		params.ConcurrentActivityCount = 1
 	}
 
+	if params.TargetErrorWindowSize <= 0 {
+		params.TargetErrorWindowSize = defaultTargetErrorWindowSize
+	}
+
 	if params.OverallRps <= 0 {
 		params.OverallRps = float64(params.ConcurrentActivityCount)
 	}
 	if params.GetParentInfoRPS <= 0 {
 		params.GetParentInfoRPS = float64(params.ConcurrentActivityCount)
 	}
+	if params.VerifyRps <= 0 {
+		params.VerifyRps = params.OverallRps
+	}
 
 	if params.ListWorkflowsPageSize <= 0 {
 		params.ListWorkflowsPageSize = defaultListWorkflowsPageSize
@@ -295,12 +839,20 @@ func validateAndSetForceReplicationParams(ctx workflow.Context, params *ForceRep
 		params.VerifyIntervalInSeconds = defaultVerifyIntervalInSeconds
 	}
 
+	if params.CancelDrainTimeoutInSeconds == 0 {
+		params.CancelDrainTimeoutInSeconds = defaultCancelDrainTimeoutInSeconds
+	}
+
+	if params.DryRun && params.DryRunSamplePageCount == 0 {
+		params.DryRunSamplePageCount = defaultDryRunSamplePageCount
+	}
+
 	if params.ReplicatedWorkflowCountPerSecond <= 0 {
 		params.ReplicatedWorkflowCountPerSecond = params.OverallRps
 	}
 
-	if params.EstimationMultiplier <= 0 {
-		params.EstimationMultiplier = 2
+	if params.EstimationMultiplier == 0 {
+		params.EstimationMultiplier = defaultEstimationMultiplier
 	}
 
 	if params.QPSQueue.Data == nil {
@@ -311,6 +863,69 @@ func validateAndSetForceReplicationParams(ctx workflow.Context, params *ForceRep
 	return nil
 }
 
+// resumeFromCheckpoint loads the last checkpoint of the namespace and continues from it. Starting from the
+// beginning is only done when there is no checkpoint.
+func resumeFromCheckpoint(ctx workflow.Context, params *ForceReplicationParams) error {
+	ao := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         forceReplicationActivityRetryPolicy,
+	}
+
+	var a *activities
+	var checkpoint *ForceReplicationCheckpoint
+	if err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, ao),
+		a.LoadCheckpoint,
+		&loadCheckpointRequest{Namespace: params.Namespace},
+	).Get(ctx, &checkpoint); err != nil {
+		return err
+	}
+
+	// The checkpoint is only loaded by the first run, later runs continue from their own progress.
+	params.ResumeFromCheckpoint = false
+	if checkpoint == nil {
+		return nil
+	}
+	if checkpoint.Query != params.Query || checkpoint.OrderByWorkflowID != params.OrderByWorkflowID {
+		return temporal.NewNonRetryableApplicationError("InvalidArgument: checkpoint was taken with a different Query or OrderByWorkflowID", "InvalidArgument", nil)
+	}
+
+	params.NextPageToken = checkpoint.PageTokenForRestart
+	params.NextPageOffset = checkpoint.PageOffsetForRestart
+	params.LastWorkflowID = checkpoint.WorkflowIDForRestart
+	params.ContinuedAsNewCount = checkpoint.ContinuedAsNewCount
+	params.TotalForceReplicateWorkflowCount = checkpoint.TotalWorkflowCount
+	params.ReplicatedWorkflowCount = checkpoint.ReplicatedWorkflowCount
+	params.CorruptedWorkflowCount = checkpoint.CorruptedWorkflowCount
+	// The task queue user data is replicated again unless it already was, a failed replication is retried.
+	if userDataStatus := checkpoint.TaskQueueUserDataReplicationStatus; userDataStatus.Done && userDataStatus.FailureMessage == "" {
+		params.TaskQueueUserDataReplicationStatus = userDataStatus
+	}
+	params.QPSQueue = NewQPSQueue(params.ConcurrentActivityCount, params.EstimationMultiplier)
+	params.QPSQueue.Enqueue(ctx, params.ReplicatedWorkflowCount)
+	return nil
+}
+
+// saveCheckpoint persists the checkpoint, failures are logged and otherwise ignored so they don't fail the
+// migration.
+func saveCheckpoint(ctx workflow.Context, namespace string, checkpoint *ForceReplicationCheckpoint) {
+	retryPolicy := *forceReplicationActivityRetryPolicy
+	retryPolicy.MaximumAttempts = checkpointActivityMaxAttempts
+	ao := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         &retryPolicy,
+	}
+
+	var a *activities
+	if err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, ao),
+		a.SaveCheckpoint,
+		&saveCheckpointRequest{Namespace: namespace, Checkpoint: checkpoint},
+	).Get(ctx, nil); err != nil {
+		workflow.GetLogger(ctx).Warn("Failed to save force replication checkpoint", tag.WorkflowNamespace(namespace), tag.Error(err))
+	}
+}
+
 func getClusterMetadata(ctx workflow.Context, params ForceReplicationParams) (metadataResponse, error) {
 	// Get cluster metadata, we need namespace ID for history API call.
 	// TODO: remove this step.
@@ -321,13 +936,55 @@ func getClusterMetadata(ctx workflow.Context, params ForceReplicationParams) (me
 
 	actx := workflow.WithLocalActivityOptions(ctx, lao)
 	var metadataResp metadataResponse
-	metadataRequest := metadataRequest{Namespace: params.Namespace}
+	metadataRequest := metadataRequest{Namespace: params.Namespace, TransformName: params.TransformName}
 	var a *activities
 	err := workflow.ExecuteLocalActivity(actx, a.GetMetadata, metadataRequest).Get(ctx, &metadataResp)
 	return metadataResp, err
 }
 
-func listWorkflowsForReplication(ctx workflow.Context, workflowExecutionsCh workflow.Channel, params *ForceReplicationParams) error {
+// resolveTargetCluster checks that TargetClusterName and TargetClusterEndpoint refer to the same cluster, and fills in
+// TargetClusterName when only TargetClusterEndpoint is given and it is the address of a known cluster.
+// clusterRPCAddresses is nil in GetMetadata results recorded before they included it, nothing is checked then.
+func resolveTargetCluster(params *ForceReplicationParams, clusterRPCAddresses map[string]string) error {
+	if clusterRPCAddresses == nil {
+		return nil
+	}
+
+	if len(params.TargetClusterName) == 0 {
+		if len(params.TargetClusterEndpoint) == 0 {
+			return nil
+		}
+		// sorted for determinism, in case more than one cluster has the address
+		clusterNames := slices.Sorted(maps.Keys(clusterRPCAddresses))
+		for _, clusterName := range clusterNames {
+			if clusterRPCAddresses[clusterName] == params.TargetClusterEndpoint {
+				params.TargetClusterName = clusterName
+				break
+			}
+		}
+		return nil
+	}
+
+	rpcAddress, ok := clusterRPCAddresses[params.TargetClusterName]
+	if !ok {
+		return temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("InvalidArgument: TargetClusterName %q is not a known cluster", params.TargetClusterName), "InvalidArgument", nil)
+	}
+	if len(params.TargetClusterEndpoint) > 0 && rpcAddress != params.TargetClusterEndpoint {
+		return temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("InvalidArgument: TargetClusterEndpoint %q doesn't match the address %q of TargetClusterName %q",
+				params.TargetClusterEndpoint, rpcAddress, params.TargetClusterName), "InvalidArgument", nil)
+	}
+	return nil
+}
+
+func listWorkflowsForReplication(
+	ctx workflow.Context,
+	workflowExecutionsCh workflow.Channel,
+	params *ForceReplicationParams,
+	query string,
+	cancellation *forceReplicationCancellation,
+) error {
 	ao := workflow.ActivityOptions{
 		StartToCloseTimeout: time.Hour,
 		HeartbeatTimeout:    time.Second * 30,
@@ -336,37 +993,192 @@ func listWorkflowsForReplication(ctx workflow.Context, workflowExecutionsCh work
 
 	actx := workflow.WithActivityOptions(ctx, ao)
 	var a *activities
-	for i := 0; i < params.PageCountPerExecution; i++ {
-		listFuture := workflow.ExecuteActivity(
-			actx,
-			a.ListWorkflows,
-			&workflowservice.ListWorkflowExecutionsRequest{
-				Namespace:     params.Namespace,
-				PageSize:      int32(params.ListWorkflowsPageSize),
-				NextPageToken: params.NextPageToken,
-				Query:         params.Query,
-			})
+	for i := 0; i < params.PageCountPerExecution && !cancellation.requested; i++ {
+		request := newListWorkflowsRequest(params, query, params.NextPageToken, params.LastWorkflowID)
+		listFuture := workflow.ExecuteActivity(actx, a.ListWorkflows, request)
 
 		var listResp listWorkflowsResponse
 		if err := listFuture.Get(ctx, &listResp); err != nil {
 			return err
 		}
 
-		workflowExecutionsCh.Send(ctx, listResp.Executions)
+		// A run restarted within a page skips the workflows of the page which were already replicated.
+		skippedCount := min(params.NextPageOffset, len(listResp.Executions))
+		params.NextPageOffset = 0
+		pageOffset := skippedCount
+		lastWorkflowID := params.LastWorkflowID
+		for _, batch := range pageBatches(listResp.Executions[pageOffset:], params.GenerateTasksBatchSize) {
+			workflowExecutionsCh.Send(ctx, listedBatch{
+				Executions:     batch,
+				PageToken:      params.NextPageToken,
+				PageOffset:     pageOffset,
+				LastWorkflowID: lastWorkflowID,
+			})
+			pageOffset += len(batch)
+			if params.OrderByWorkflowID && len(batch) > 0 {
+				lastWorkflowID = batch[len(batch)-1].GetWorkflowId()
+			}
+		}
 
+		params.ListedPageCount++
+		params.ListedWorkflowCount += int64(len(listResp.Executions) - skippedCount)
 		params.NextPageToken = listResp.NextPageToken
-		params.LastCloseTime = listResp.LastCloseTime
-		params.LastStartTime = listResp.LastStartTime
+		// empty pages keep the progress of the previous page, or of the restarted run
+		if !listResp.LastCloseTime.IsZero() {
+			params.LastCloseTime = listResp.LastCloseTime
+		}
+		if !listResp.LastStartTime.IsZero() {
+			params.LastStartTime = listResp.LastStartTime
+		}
+		if params.OrderByWorkflowID && len(listResp.Executions) > 0 {
+			params.LastWorkflowID = listResp.Executions[len(listResp.Executions)-1].GetWorkflowId()
+		}
 
 		if params.NextPageToken == nil {
 			break
 		}
+
+		if len(listResp.Executions) > 0 {
+			params.ConsecutiveEmptyPageCount = 0
+		} else {
+			params.ConsecutiveEmptyPageCount++
+			if params.MaxConsecutiveEmptyPages > 0 && params.ConsecutiveEmptyPageCount >= params.MaxConsecutiveEmptyPages {
+				return temporal.NewNonRetryableApplicationError(
+					fmt.Sprintf("ListWorkflows returned %d consecutive empty pages which still have a next page token", params.ConsecutiveEmptyPageCount),
+					"ListWorkflowsNoProgress",
+					nil,
+				)
+			}
+		}
 	}
 
 	return nil
 }
 
-func countWorkflowForReplication(ctx workflow.Context, params ForceReplicationParams) (int64, error) {
+// pageBatches splits the executions of a page into batches of batchSize executions, or a single batch if batchSize
+// is zero.
+func pageBatches(executions []*commonpb.WorkflowExecution, batchSize int) [][]*commonpb.WorkflowExecution {
+	if batchSize <= 0 {
+		return [][]*commonpb.WorkflowExecution{executions}
+	}
+	return slices.Collect(slices.Chunk(executions, batchSize))
+}
+
+// estimateRemainingWorkflowCount returns the number of workflows which are not listed yet. Replicated workflows are
+// always listed, so they bound the listed workflows of a run resumed from a checkpoint.
+func estimateRemainingWorkflowCount(params ForceReplicationParams) int64 {
+	listed := max(params.ListedWorkflowCount, params.ReplicatedWorkflowCount)
+	return max(params.TotalForceReplicateWorkflowCount-listed, 0)
+}
+
+func newListWorkflowsRequest(
+	params *ForceReplicationParams,
+	query string,
+	nextPageToken []byte,
+	lastWorkflowID string,
+) *workflowservice.ListWorkflowExecutionsRequest {
+	request := &workflowservice.ListWorkflowExecutionsRequest{
+		Namespace:     params.Namespace,
+		PageSize:      int32(params.ListWorkflowsPageSize),
+		NextPageToken: nextPageToken,
+		Query:         query,
+	}
+	if params.OrderByWorkflowID {
+		// Each page is a new query starting after the last listed workflow ID, the page token returned
+		// by ListWorkflows is only used to tell whether there are more pages.
+		request.NextPageToken = nil
+		request.Query = orderedByWorkflowIDQuery(query, lastWorkflowID)
+	}
+	return request
+}
+
+// estimateReplicationCost projects the cost of replicating the remaining workflows from the history size of the
+// workflows on the next DryRunSamplePageCount pages. It doesn't update the listing progress of params.
+func estimateReplicationCost(ctx workflow.Context, params ForceReplicationParams, query string) (*ForceReplicationEstimate, error) {
+	ao := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Hour,
+		HeartbeatTimeout:    time.Second * 30,
+		RetryPolicy:         forceReplicationActivityRetryPolicy,
+	}
+
+	actx := workflow.WithActivityOptions(ctx, ao)
+	var a *activities
+	estimate := &ForceReplicationEstimate{
+		WorkflowCount: max(params.TotalForceReplicateWorkflowCount-params.ReplicatedWorkflowCount, 0),
+	}
+	var sampledHistorySize int64
+	nextPageToken := params.NextPageToken
+	lastWorkflowID := params.LastWorkflowID
+	for i := 0; i < params.DryRunSamplePageCount; i++ {
+		request := newListWorkflowsRequest(&params, query, nextPageToken, lastWorkflowID)
+		var listResp listWorkflowsResponse
+		if err := workflow.ExecuteActivity(actx, a.ListWorkflows, request).Get(ctx, &listResp); err != nil {
+			return nil, err
+		}
+
+		estimate.SampledWorkflowCount += int64(len(listResp.Executions))
+		sampledHistorySize += listResp.HistorySizeBytes
+		nextPageToken = listResp.NextPageToken
+		if len(listResp.Executions) > 0 {
+			lastWorkflowID = listResp.Executions[len(listResp.Executions)-1].GetWorkflowId()
+		}
+		if nextPageToken == nil {
+			break
+		}
+	}
+
+	if estimate.SampledWorkflowCount > 0 {
+		estimate.AverageHistorySizeInBytes = sampledHistorySize / estimate.SampledWorkflowCount
+	}
+	estimate.EstimatedHistorySizeInBytes = estimate.AverageHistorySizeInBytes * estimate.WorkflowCount
+	estimate.EstimatedDuration = time.Duration(float64(estimate.WorkflowCount) / params.OverallRps * float64(time.Second))
+	return estimate, nil
+}
+
+// orderedByWorkflowIDQuery returns the query listing workflows after lastWorkflowID, in workflow ID order.
+func orderedByWorkflowIDQuery(query string, lastWorkflowID string) string {
+	var filters []string
+	if query != "" {
+		filters = append(filters, "("+query+")")
+	}
+	if lastWorkflowID != "" {
+		filters = append(filters, fmt.Sprintf("%s > %q", searchattribute.WorkflowID, lastWorkflowID))
+	}
+	orderBy := "ORDER BY " + searchattribute.WorkflowID
+	if len(filters) == 0 {
+		return orderBy
+	}
+	return strings.Join(filters, " AND ") + " " + orderBy
+}
+
+// workflowTypeFilterQuery restricts the query to the workflow types selected by the namespace replication filter.
+// Excluded types take precedence over included types.
+func workflowTypeFilterQuery(query string, includedWorkflowTypes []string, excludedWorkflowTypes []string) string {
+	var filters []string
+	if query != "" {
+		filters = append(filters, "("+query+")")
+	}
+	if len(includedWorkflowTypes) > 0 {
+		filters = append(filters, fmt.Sprintf("%s IN (%s)", searchattribute.WorkflowType, quotedList(includedWorkflowTypes)))
+	}
+	if len(excludedWorkflowTypes) > 0 {
+		filters = append(filters, fmt.Sprintf("%s NOT IN (%s)", searchattribute.WorkflowType, quotedList(excludedWorkflowTypes)))
+	}
+	if len(filters) == 1 && query != "" {
+		return query
+	}
+	return strings.Join(filters, " AND ")
+}
+
+func quotedList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func countWorkflowForReplication(ctx workflow.Context, params ForceReplicationParams, query string) (int64, error) {
 	ao := workflow.ActivityOptions{
 		StartToCloseTimeout: 2 * time.Minute,
 		RetryPolicy:         forceReplicationActivityRetryPolicy,
@@ -379,7 +1191,7 @@ func countWorkflowForReplication(ctx workflow.Context, params ForceReplicationPa
 		a.CountWorkflow,
 		&workflowservice.CountWorkflowExecutionsRequest{
 			Namespace: params.Namespace,
-			Query:     params.Query,
+			Query:     query,
 		}).Get(ctx, &output); err != nil {
 		return 0, err
 	}
@@ -387,7 +1199,14 @@ func countWorkflowForReplication(ctx workflow.Context, params ForceReplicationPa
 	return output.WorkflowCount, nil
 }
 
-func enqueueReplicationTasks(ctx workflow.Context, workflowExecutionsCh workflow.Channel, namespaceID string, params *ForceReplicationParams) error {
+func enqueueReplicationTasks(
+	ctx workflow.Context,
+	workflowExecutionsCh workflow.Channel,
+	metadataResp metadataResponse,
+	params *ForceReplicationParams,
+	cancellation *forceReplicationCancellation,
+) error {
+	namespaceID := metadataResp.NamespaceID
 	selector := workflow.NewSelector(ctx)
 	pendingGenerateTasks := 0
 	pendingVerifyTasks := 0
@@ -397,9 +1216,14 @@ func enqueueReplicationTasks(ctx workflow.Context, workflowExecutionsCh workflow
 		HeartbeatTimeout:    time.Second * 60,
 		RetryPolicy:         forceReplicationActivityRetryPolicy,
 	}
+	if params.targetErrorBudgetEnabled() {
+		retryPolicy := *forceReplicationActivityRetryPolicy
+		retryPolicy.MaximumAttempts = targetErrorBudgetActivityMaxAttempts
+		ao.RetryPolicy = &retryPolicy
+	}
 
 	actx := workflow.WithActivityOptions(ctx, ao)
-	var workflowExecutions []*commonpb.WorkflowExecution
+	var batch listedBatch
 	var lastActivityErr error
 	var a *activities
 
@@ -408,61 +1232,156 @@ func enqueueReplicationTasks(ctx workflow.Context, workflowExecutionsCh workflow
 		targetClusters = []string{params.TargetClusterName}
 	}
 
-	for workflowExecutionsCh.Receive(ctx, &workflowExecutions) {
+	// Verification is throttled separately from generation, so that the load on the target cluster can be tuned
+	// with VerifyRps. Runs started before VerifyRps existed don't wait.
+	throttleVerification := params.EnableVerification &&
+		workflow.GetVersion(ctx, verifyRpsVersionMarker, workflow.DefaultVersion, 1) > workflow.DefaultVersion
+	var nextVerifyTime time.Time
+	waitForVerifyRps := func(executionCount int) {
+		if !throttleVerification {
+			return
+		}
+		now := workflow.Now(ctx)
+		if nextVerifyTime.After(now) {
+			// a canceled sleep fails the activity scheduled next, which fails the workflow
+			_ = workflow.Sleep(ctx, nextVerifyTime.Sub(now))
+			now = nextVerifyTime
+		}
+		nextVerifyTime = now.Add(time.Duration(float64(executionCount) / params.VerifyRps * float64(time.Second)))
+	}
+
+	var generateReplicationTasks, verifyReplicationTasks func(executions []*commonpb.WorkflowExecution)
+	generateReplicationTasks = func(executions []*commonpb.WorkflowExecution) {
 		generateTaskFuture := workflow.ExecuteActivity(
 			actx,
 			a.GenerateReplicationTasks,
 			&generateReplicationTasksRequest{
 				NamespaceID:      namespaceID,
-				Executions:       workflowExecutions,
+				Executions:       executions,
 				RPS:              params.OverallRps / float64(params.ConcurrentActivityCount),
 				GetParentInfoRPS: params.GetParentInfoRPS / float64(params.ConcurrentActivityCount),
 				TargetClusters:   targetClusters,
+				SkipCorrupted:    params.SkipCorrupted,
+				ShardCount:       metadataResp.ShardCount,
+				TransformName:    params.TransformName,
 			})
 
 		pendingGenerateTasks++
 		selector.AddFuture(generateTaskFuture, func(f workflow.Future) {
 			pendingGenerateTasks--
 
-			if err := f.Get(ctx, nil); err != nil {
+			var generateTaskResponse generateReplicationTasksResponse
+			retry, err := recordTargetActivityResult(params, f.Get(ctx, &generateTaskResponse))
+			if err != nil {
 				lastActivityErr = err
+			} else if retry {
+				generateReplicationTasks(executions)
+			} else {
+				params.recordShardProgress(generateTaskResponse.ShardProgress)
+				if params.SkipCorrupted {
+					params.recordCorruptedWorkflows(generateTaskResponse.CorruptedExecutions)
+					if params.EnableVerification {
+						// Corrupted workflows are never replicated, so they are only known to be excluded from
+						// verification after their replication tasks were generated.
+						verifyReplicationTasks(excludeExecutions(executions, generateTaskResponse.CorruptedExecutions))
+					}
+				}
 			}
 		})
+	}
 
-		if params.EnableVerification {
-			verifyTaskFuture := workflow.ExecuteActivity(
-				actx,
-				a.VerifyReplicationTasks,
-				&verifyReplicationTasksRequest{
-					TargetClusterEndpoint: params.TargetClusterEndpoint,
-					TargetClusterName:     params.TargetClusterName,
-					Namespace:             params.Namespace,
-					NamespaceID:           namespaceID,
-					Executions:            workflowExecutions,
-					VerifyInterval:        time.Duration(params.VerifyIntervalInSeconds) * time.Second,
-				})
-
-			pendingVerifyTasks++
-			selector.AddFuture(verifyTaskFuture, func(f workflow.Future) {
-				pendingVerifyTasks--
-
-				var verifyTaskResponse verifyReplicationTasksResponse
-				if err := f.Get(ctx, &verifyTaskResponse); err != nil {
-					lastActivityErr = err
-				} else {
-					// Update replication status
-					params.ReplicatedWorkflowCount += int64(verifyTaskResponse.VerifiedWorkflowCount)
-					params.QPSQueue.Enqueue(ctx, params.ReplicatedWorkflowCount)
-					params.ReplicatedWorkflowCountPerSecond = params.QPSQueue.CalculateQPS()
-
-					// Report new QPS to metrics
-					tags := map[string]string{
-						metrics.OperationTagName: metrics.MigrationWorkflowScope,
-						NamespaceTagName:         params.Namespace,
-					}
-					workflow.GetMetricsHandler(ctx).WithTags(tags).Gauge(ForceReplicationRpsTagName).Update(params.ReplicatedWorkflowCountPerSecond)
-				}
+	var repairReplicationTasks func(executions []*commonpb.WorkflowExecution)
+	repairReplicationTasks = func(executions []*commonpb.WorkflowExecution) {
+		repairTaskFuture := workflow.ExecuteActivity(
+			actx,
+			a.RepairReplicationTasks,
+			&repairReplicationTasksRequest{
+				Namespace:         params.Namespace,
+				NamespaceID:       namespaceID,
+				TargetClusterName: params.TargetClusterName,
+				Executions:        executions,
+				RPS:               params.OverallRps / float64(params.ConcurrentActivityCount),
+				MinEventGap:       params.RepairMinEventGap,
+			})
+
+		pendingGenerateTasks++
+		selector.AddFuture(repairTaskFuture, func(f workflow.Future) {
+			pendingGenerateTasks--
+
+			var repairTaskResponse repairReplicationTasksResponse
+			retry, err := recordTargetActivityResult(params, f.Get(ctx, &repairTaskResponse))
+			if err != nil {
+				lastActivityErr = err
+			} else if retry {
+				repairReplicationTasks(executions)
+			} else {
+				params.RepairedWorkflowCount += repairTaskResponse.RepairedWorkflowCount
+				params.BackfilledEventCount += repairTaskResponse.BackfilledEventCount
+			}
+		})
+	}
+
+	verifyReplicationTasks = func(executions []*commonpb.WorkflowExecution) {
+		waitForVerifyRps(len(executions))
+		verifyTaskFuture := workflow.ExecuteActivity(
+			actx,
+			a.VerifyReplicationTasks,
+			&verifyReplicationTasksRequest{
+				TargetClusterEndpoint: params.TargetClusterEndpoint,
+				TargetClusterName:     params.TargetClusterName,
+				Namespace:             params.Namespace,
+				NamespaceID:           namespaceID,
+				Executions:            executions,
+				VerifyInterval:        time.Duration(params.VerifyIntervalInSeconds) * time.Second,
 			})
+
+		pendingVerifyTasks++
+		selector.AddFuture(verifyTaskFuture, func(f workflow.Future) {
+			pendingVerifyTasks--
+
+			var verifyTaskResponse verifyReplicationTasksResponse
+			retry, err := recordTargetActivityResult(params, f.Get(ctx, &verifyTaskResponse))
+			if err != nil {
+				lastActivityErr = err
+			} else if retry {
+				verifyReplicationTasks(executions)
+			} else {
+				// Update replication status
+				params.ReplicatedWorkflowCount += int64(verifyTaskResponse.VerifiedWorkflowCount)
+				params.MaxReplicationLag = max(params.MaxReplicationLag, verifyTaskResponse.MaxReplicationLag)
+				params.MaxReplicationLagInRun = max(params.MaxReplicationLagInRun, verifyTaskResponse.MaxReplicationLag)
+				params.QPSQueue.Enqueue(ctx, params.ReplicatedWorkflowCount)
+				params.ReplicatedWorkflowCountPerSecond = params.QPSQueue.CalculateQPS()
+
+				// Report new QPS to metrics
+				tags := map[string]string{
+					metrics.OperationTagName: metrics.MigrationWorkflowScope,
+					NamespaceTagName:         params.Namespace,
+				}
+				workflow.GetMetricsHandler(ctx).WithTags(tags).Gauge(ForceReplicationRpsTagName).Update(params.ReplicatedWorkflowCountPerSecond)
+			}
+		})
+	}
+
+	selector.AddFuture(cancellation.drainDeadline, func(workflow.Future) {
+		cancellation.drainTimedOut = true
+	})
+
+	// Runs started before they could be restarted within a page only stop at page boundaries.
+	inPageRestart := workflow.GetVersion(ctx, inPageRestartVersionMarker, workflow.DefaultVersion, 1) > workflow.DefaultVersion
+	for workflowExecutionsCh.Receive(ctx, &batch) {
+		if cancellation.requested && (inPageRestart || batch.PageOffset == 0) {
+			cancellation.notDispatched = &batch
+			break
+		}
+		if params.RepairMode {
+			repairReplicationTasks(batch.Executions)
+		} else {
+			generateReplicationTasks(batch.Executions)
+		}
+
+		if params.EnableVerification && !params.SkipCorrupted {
+			verifyReplicationTasks(batch.Executions)
 		}
 
 		for pendingGenerateTasks >= params.ConcurrentActivityCount || pendingVerifyTasks >= params.ConcurrentActivityCount {
@@ -470,6 +1389,9 @@ func enqueueReplicationTasks(ctx workflow.Context, workflowExecutionsCh workflow
 			if lastActivityErr != nil {
 				return lastActivityErr
 			}
+			if cancellation.drainTimedOut {
+				return nil
+			}
 		}
 	}
 
@@ -478,11 +1400,110 @@ func enqueueReplicationTasks(ctx workflow.Context, workflowExecutionsCh workflow
 		if lastActivityErr != nil {
 			return lastActivityErr
 		}
+		if cancellation.drainTimedOut {
+			return nil
+		}
 	}
 
 	return nil
 }
 
+// applyBackpressure halves the rate of the next run, down to a single activity at minBackpressureRps.
+func (p *ForceReplicationParams) applyBackpressure() {
+	p.ConcurrentActivityCount = max(p.ConcurrentActivityCount/2, 1)
+	p.OverallRps = max(p.OverallRps/2, min(p.OverallRps, minBackpressureRps))
+	p.BackpressureCount++
+}
+
+func (p *ForceReplicationParams) recordCorruptedWorkflows(executions []*commonpb.WorkflowExecution) {
+	p.CorruptedWorkflowCount += int64(len(executions))
+	for _, execution := range executions {
+		if len(p.CorruptedWorkflows) >= maxReportedCorruptedWorkflows {
+			break
+		}
+		p.CorruptedWorkflows = append(p.CorruptedWorkflows, execution)
+	}
+}
+
+func (p *ForceReplicationParams) recordShardProgress(shardProgress map[int32]*ShardReplicationProgress) {
+	for shardID, progress := range shardProgress {
+		if p.ShardProgress == nil {
+			p.ShardProgress = make(map[int32]*ShardReplicationProgress)
+		}
+		total, ok := p.ShardProgress[shardID]
+		if !ok {
+			total = &ShardReplicationProgress{}
+			p.ShardProgress[shardID] = total
+		}
+		total.GeneratedWorkflowCount += progress.GeneratedWorkflowCount
+		total.SkippedWorkflowCount += progress.SkippedWorkflowCount
+	}
+}
+
+// excludeExecutions returns the executions which are not in excluded.
+func excludeExecutions(executions []*commonpb.WorkflowExecution, excluded []*commonpb.WorkflowExecution) []*commonpb.WorkflowExecution {
+	if len(excluded) == 0 {
+		return executions
+	}
+	excludedSet := make(map[string]struct{}, len(excluded))
+	for _, execution := range excluded {
+		excludedSet[execution.GetWorkflowId()+"/"+execution.GetRunId()] = struct{}{}
+	}
+	result := make([]*commonpb.WorkflowExecution, 0, len(executions))
+	for _, execution := range executions {
+		if _, ok := excludedSet[execution.GetWorkflowId()+"/"+execution.GetRunId()]; !ok {
+			result = append(result, execution)
+		}
+	}
+	return result
+}
+
+func (p *ForceReplicationParams) targetErrorBudgetEnabled() bool {
+	return p.MaxTargetErrorRatio > 0 || p.MaxConsecutiveFailures > 0
+}
+
+// recordTargetActivityResult accounts the result of an activity against the target cluster error budget.
+// It returns whether the failed activity should be retried, or the error the workflow should fail with.
+func recordTargetActivityResult(params *ForceReplicationParams, activityErr error) (bool, error) {
+	if !params.targetErrorBudgetEnabled() {
+		return false, activityErr
+	}
+
+	var appErr *temporal.ApplicationError
+	if errors.As(activityErr, &appErr) && appErr.NonRetryable() {
+		return false, activityErr
+	}
+
+	budget := &params.TargetErrorBudget
+	failed := activityErr != nil
+	budget.RecentFailures = append(budget.RecentFailures, failed)
+	if len(budget.RecentFailures) > params.TargetErrorWindowSize {
+		budget.RecentFailures = budget.RecentFailures[len(budget.RecentFailures)-params.TargetErrorWindowSize:]
+	}
+	failureCount := 0
+	for _, f := range budget.RecentFailures {
+		if f {
+			failureCount++
+		}
+	}
+	budget.ErrorRatio = float64(failureCount) / float64(len(budget.RecentFailures))
+	if !failed {
+		budget.ConsecutiveFailures = 0
+		return false, nil
+	}
+	budget.ConsecutiveFailures++
+
+	if params.MaxConsecutiveFailures > 0 && budget.ConsecutiveFailures > params.MaxConsecutiveFailures {
+		budget.AbortReason = fmt.Sprintf("target cluster unhealthy, aborting migration: %d consecutive failures", budget.ConsecutiveFailures)
+	} else if params.MaxTargetErrorRatio > 0 && len(budget.RecentFailures) >= params.TargetErrorWindowSize &&
+		budget.ErrorRatio > params.MaxTargetErrorRatio {
+		budget.AbortReason = fmt.Sprintf("target cluster unhealthy, aborting migration: error ratio %.2f", budget.ErrorRatio)
+	} else {
+		return true, nil
+	}
+	return false, temporal.NewNonRetryableApplicationError(budget.AbortReason, "TargetClusterUnhealthy", activityErr)
+}
+
 // NewQPSQueue initializes a QPSQueue to collect data points for each workflow execution.
 // The queue size is set to concurrency + 1 to account for up to 'concurrency' activities
 // running simultaneously and the initial starting point.
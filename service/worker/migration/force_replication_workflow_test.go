@@ -17,6 +17,8 @@ import (
 	commonpb "go.temporal.io/api/common/v1"
 	namespacepb "go.temporal.io/api/namespace/v1"
 	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
 	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/testsuite"
@@ -24,9 +26,11 @@ import (
 	"go.temporal.io/sdk/workflow"
 	replicationspb "go.temporal.io/server/api/replication/v1"
 	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/payloads"
 	"go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/testing/mockapi/workflowservicemock/v1"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
 )
 
 func TestForceReplicationWorkflow(t *testing.T) {
@@ -64,10 +68,16 @@ func TestForceReplicationWorkflow(t *testing.T) {
 			LastCloseTime: closeTime,
 		}, nil
 	}).Times(totalPageCount)
-	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil).Times(totalPageCount)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *generateReplicationTasksRequest) (*generateReplicationTasksResponse, error) {
+		assert.Equal(t, int32(4), request.ShardCount)
+		return &generateReplicationTasksResponse{
+			ShardProgress: map[int32]*ShardReplicationProgress{2: {GeneratedWorkflowCount: 1}},
+		}, nil
+	}).Times(totalPageCount)
 	env.OnActivity(a.VerifyReplicationTasks, mock.Anything, mock.Anything).Return(verifyReplicationTasksResponse{VerifiedWorkflowCount: 1}, nil).Times(totalPageCount)
 
 	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil).Times(1)
+	env.OnActivity(a.VerifyTaskQueueUserData, mock.Anything, mock.Anything).Return(&verifyTaskQueueUserDataResponse{}, nil).Times(1)
 	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
 		Namespace:               "test-ns",
 		Query:                   "",
@@ -94,9 +104,12 @@ func TestForceReplicationWorkflow(t *testing.T) {
 	assert.Equal(t, closeTime, status.LastCloseTime)
 	assert.True(t, status.TaskQueueUserDataReplicationStatus.Done)
 	assert.Equal(t, "", status.TaskQueueUserDataReplicationStatus.FailureMessage)
+	assert.True(t, status.TaskQueueUserDataReplicationStatus.Verified)
+	assert.Equal(t, int64(0), status.TaskQueueUserDataReplicationStatus.MissingTaskQueueCount)
 	assert.Equal(t, int64(4), status.TotalWorkflowCount)
 	assert.Equal(t, int64(4), status.ReplicatedWorkflowCount)
 	assert.Equal(t, []byte(nil), status.PageTokenForRestart)
+	assert.Equal(t, map[int32]*ShardReplicationProgress{2: {GeneratedWorkflowCount: 4}}, status.ShardProgress)
 }
 
 func TestForceReplicationWorkflow_ContinueAsNew(t *testing.T) {
@@ -126,27 +139,34 @@ func TestForceReplicationWorkflow_ContinueAsNew(t *testing.T) {
 	}
 
 	expectedContinueAsNewParams := ForceReplicationParams{
-		Namespace:               "test-ns",
-		Query:                   "",
-		ConcurrentActivityCount: 2,
-		OverallRps:              10,
-		GetParentInfoRPS:        2.0,
-		ListWorkflowsPageSize:   1,
-		PageCountPerExecution:   testMaxPageCountPerExecution,
-		NextPageToken:           []byte("fake-page-token-2"),
-		EnableVerification:      true,
-		TargetClusterEndpoint:   "test-target",
-		TargetClusterName:       "",
-		VerifyIntervalInSeconds: defaultVerifyIntervalInSeconds,
-		LastCloseTime:           closeTime,
-		LastStartTime:           startTime,
-		ContinuedAsNewCount:     1,
+		Namespace:                   "test-ns",
+		Query:                       "",
+		ConcurrentActivityCount:     2,
+		OverallRps:                  10,
+		GetParentInfoRPS:            2.0,
+		ListWorkflowsPageSize:       1,
+		PageCountPerExecution:       testMaxPageCountPerExecution,
+		NextPageToken:               []byte("fake-page-token-2"),
+		EnableVerification:          true,
+		TargetClusterEndpoint:       "test-target",
+		TargetClusterName:           "",
+		VerifyIntervalInSeconds:     defaultVerifyIntervalInSeconds,
+		VerifyRps:                   10,
+		TargetErrorWindowSize:       defaultTargetErrorWindowSize,
+		CancelDrainTimeoutInSeconds: defaultCancelDrainTimeoutInSeconds,
+		LastCloseTime:               closeTime,
+		LastStartTime:               startTime,
+		ContinuedAsNewCount:         1,
+		ListedPageCount:             2,
+		// the pages are empty, but MaxConsecutiveEmptyPages is unlimited
+		ConsecutiveEmptyPageCount: 2,
 		TaskQueueUserDataReplicationParams: TaskQueueUserDataReplicationParams{
 			PageSize: 0,
 			RPS:      0,
 		},
-		ReplicatedWorkflowCount:          0,
-		TotalForceReplicateWorkflowCount: 10,
+		ReplicatedWorkflowCount:             0,
+		TotalForceReplicateWorkflowCount:    10,
+		TaskQueueUserDataReplicationStarted: true,
 	}
 
 	expectContinueAsNew := true
@@ -177,12 +197,12 @@ func TestForceReplicationWorkflow_ContinueAsNew(t *testing.T) {
 	// ForceTaskQueueUserDataReplicationWorkflow is a child workflow that runs in parallel
 	// and may span many ContinueAsNew'd executions of force replication:
 	//
-	// - It is started on the first execution as a child workflow (when ContinueAsNewCount == 0).
-	// - It is not started on subsequent executions (when ContinueAsNewCount > 0)
+	// - It is started on the first execution as a child workflow (when TaskQueueUserDataReplicationStarted is false).
+	// - It is not started on subsequent executions (when TaskQueueUserDataReplicationStarted is true)
 	// - Only the final execution waits for it to complete (when NextPageToken == nil)
 	//
 	// To keep this test simple and to resolve past test flakes, we do not test all cases.
-	// We test with ContinueAsNewCount > 0 and NextPageToken != nil, so that:
+	// We test with TaskQueueUserDataReplicationStarted and NextPageToken != nil, so that:
 	//
 	// - ForceTaskQueueUserDataReplicationWorkflow is not started
 	// - Force replication does not get stuck waiting for some previous execution of
@@ -200,6 +220,48 @@ func TestForceReplicationWorkflow_ContinueAsNew(t *testing.T) {
 	assert.Equal(t, startTime, queryStatus.LastStartTime)
 	assert.Equal(t, 1, queryStatus.ContinuedAsNewCount)
 	assert.Equal(t, []byte("fake-initial-page-token"), queryStatus.PageTokenForRestart)
+	assert.Equal(t, 2, queryStatus.CurrentPageIndex)
+	assert.Equal(t, 2, queryStatus.PagesProcessedInExecution)
+	assert.Equal(t, int64(10), queryStatus.EstimatedRemainingWorkflowCount)
+}
+
+func TestForceReplicationWorkflow_PageProgress(t *testing.T) {
+	currentPageCount := 0
+	mockListWorkflows := func(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*listWorkflowsResponse, error) {
+		currentPageCount++
+		return &listWorkflowsResponse{
+			Executions:    []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}, {WorkflowId: "wf-2"}},
+			NextPageToken: []byte(fmt.Sprintf("fake-page-token-%d", currentPageCount)),
+		}, nil
+	}
+
+	// the previous runs listed 4 pages
+	continueAsNewInput, queryStatus := testRunForceReplicationForContinueAsNew(t,
+		mockListWorkflows,
+		ForceReplicationParams{
+			Namespace:                        "test-ns",
+			ConcurrentActivityCount:          2,
+			OverallRps:                       10,
+			ListWorkflowsPageSize:            2,
+			PageCountPerExecution:            3,
+			EnableVerification:               true,
+			TargetClusterEndpoint:            "test-target",
+			NextPageToken:                    []byte("fake-initial-page-token"),
+			ContinuedAsNewCount:              1,
+			ListedPageCount:                  4,
+			ListedWorkflowCount:              8,
+			TotalForceReplicateWorkflowCount: 20,
+		},
+		true,
+		3,
+	)
+	require.NotNil(t, continueAsNewInput)
+	assert.Equal(t, 7, continueAsNewInput.ListedPageCount)
+	assert.Equal(t, int64(14), continueAsNewInput.ListedWorkflowCount)
+
+	assert.Equal(t, 7, queryStatus.CurrentPageIndex)
+	assert.Equal(t, 3, queryStatus.PagesProcessedInExecution)
+	assert.Equal(t, int64(6), queryStatus.EstimatedRemainingWorkflowCount)
 }
 
 func testRunForceReplicationForContinueAsNew(t *testing.T,
@@ -219,7 +281,7 @@ func testRunForceReplicationForContinueAsNew(t *testing.T,
 	}
 	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
 	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(mockListWorkflows).Times(expMaxPageCountPerExecution)
-	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil).Times(expMaxPageCountPerExecution)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil).Times(expMaxPageCountPerExecution)
 	env.OnActivity(a.VerifyReplicationTasks, mock.Anything, mock.Anything).Return(verifyReplicationTasksResponse{}, nil).Times(expMaxPageCountPerExecution)
 	// ForceTaskQueueUserDataReplicationWorkflow runs in parallel as a child and may span many ContinueAsNew'd
 	// executions of ForceReplication. The SeedReplicationQueueWithUserDataEntries activity will eventually run
@@ -267,6 +329,41 @@ func TestForceReplicationWorkflow_InvalidInput(t *testing.T) {
 			Namespace:          uuid.New(),
 			EnableVerification: true,
 		},
+		{
+			Namespace:            uuid.New(),
+			EstimationMultiplier: -1,
+		},
+		{
+			Namespace:         uuid.New(),
+			OrderByWorkflowID: true,
+			NextPageToken:     []byte("page-token"),
+		},
+		{
+			Namespace:                uuid.New(),
+			MaxConsecutiveEmptyPages: -1,
+		},
+		{
+			Namespace:            uuid.New(),
+			ExcludeWorkflowTypes: []string{"order", ""},
+		},
+		{
+			Namespace:            uuid.New(),
+			ResumeFromCheckpoint: true,
+			NextPageToken:        []byte("page-token"),
+		},
+		{
+			Namespace:            uuid.New(),
+			EstimationMultiplier: maxEstimationMultiplier + 1,
+		},
+		{
+			Namespace:                   uuid.New(),
+			CancelDrainTimeoutInSeconds: -1,
+		},
+		{
+			// BackpressureReplicationLag without EnableVerification
+			Namespace:                  uuid.New(),
+			BackpressureReplicationLag: time.Minute,
+		},
 	} {
 		env := testSuite.NewTestWorkflowEnvironment()
 		env.ExecuteWorkflow(ForceReplicationWorkflow, invalidInput)
@@ -280,6 +377,91 @@ func TestForceReplicationWorkflow_InvalidInput(t *testing.T) {
 	}
 }
 
+func TestForceReplicationWorkflow_NamespaceNotFound(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	var a *activities
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(nil,
+		temporal.NewNonRetryableApplicationError(`InvalidArgument: namespace "test-ns" not found on the source cluster`, "InvalidArgument", nil),
+	).Once()
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:             "test-ns",
+		ListWorkflowsPageSize: 1,
+		PageCountPerExecution: 1,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	var applicationErr *temporal.ApplicationError
+	require.ErrorAs(t, err, &applicationErr)
+	assert.Equal(t, "InvalidArgument", applicationErr.Type())
+	assert.ErrorContains(t, err, `namespace "test-ns" not found`)
+	env.AssertExpectations(t)
+}
+
+func TestForceReplicationWorkflow_RestartFromPageToken(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+	startTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// CountWorkflow is not mocked, the total count of the restarted run is kept
+	var a *activities
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	var listedPageTokens []string
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*listWorkflowsResponse, error) {
+			listedPageTokens = append(listedPageTokens, string(request.NextPageToken))
+			if string(request.NextPageToken) == "page-3" {
+				return &listWorkflowsResponse{
+					Executions:    []*commonpb.WorkflowExecution{{WorkflowId: "wf-5"}, {WorkflowId: "wf-6"}},
+					NextPageToken: []byte("page-4"),
+				}, nil
+			}
+			return &listWorkflowsResponse{}, nil
+		},
+	).Times(2)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil).Times(2)
+	env.OnActivity(a.VerifyReplicationTasks, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, request *verifyReplicationTasksRequest) (verifyReplicationTasksResponse, error) {
+			return verifyReplicationTasksResponse{VerifiedWorkflowCount: int64(len(request.Executions))}, nil
+		},
+	).Times(2)
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.VerifyTaskQueueUserData, mock.Anything, mock.Anything).Return(&verifyTaskQueueUserDataResponse{}, nil)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:                        "test-ns",
+		ConcurrentActivityCount:          1,
+		OverallRps:                       10,
+		ListWorkflowsPageSize:            2,
+		PageCountPerExecution:            4,
+		EnableVerification:               true,
+		TargetClusterEndpoint:            "test-target",
+		NextPageToken:                    []byte("page-3"),
+		ReplicatedWorkflowCount:          4,
+		TotalForceReplicateWorkflowCount: 6,
+		LastStartTime:                    startTime,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+	assert.Equal(t, []string{"page-3", "page-4"}, listedPageTokens)
+
+	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+	require.NoError(t, err)
+	var status ForceReplicationStatus
+	require.NoError(t, envValue.Get(&status))
+	assert.Equal(t, []byte("page-3"), status.PageTokenForRestart)
+	assert.Equal(t, int64(6), status.TotalWorkflowCount)
+	assert.Equal(t, int64(6), status.ReplicatedWorkflowCount)
+	assert.Equal(t, startTime, status.LastStartTime)
+}
+
 func TestForceReplicationWorkflow_ListWorkflowsError(t *testing.T) {
 	testSuite := &testsuite.WorkflowTestSuite{}
 	env := testSuite.NewTestWorkflowEnvironment()
@@ -311,6 +493,48 @@ func TestForceReplicationWorkflow_ListWorkflowsError(t *testing.T) {
 	env.AssertExpectations(t)
 }
 
+func TestForceReplicationWorkflow_MaxConsecutiveEmptyPages(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 1}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil)
+
+	// a page with executions resets the count
+	pageCount := 0
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*listWorkflowsResponse, error) {
+			pageCount++
+			response := &listWorkflowsResponse{NextPageToken: []byte(fmt.Sprintf("page-token-%d", pageCount))}
+			if pageCount == 2 {
+				response.Executions = []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}}
+			}
+			return response, nil
+		},
+	).Times(5)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:                "test-ns",
+		ConcurrentActivityCount:  1,
+		OverallRps:               10,
+		ListWorkflowsPageSize:    1,
+		PageCountPerExecution:    10,
+		MaxConsecutiveEmptyPages: 3,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	var applicationErr *temporal.ApplicationError
+	require.ErrorAs(t, env.GetWorkflowError(), &applicationErr)
+	require.Equal(t, "ListWorkflowsNoProgress", applicationErr.Type())
+	require.ErrorContains(t, applicationErr, "3 consecutive empty pages")
+	env.AssertExpectations(t)
+}
+
 func TestForceReplicationWorkflow_GenerateReplicationTaskRetryableError(t *testing.T) {
 	testSuite := &testsuite.WorkflowTestSuite{}
 	env := testSuite.NewTestWorkflowEnvironment()
@@ -339,7 +563,7 @@ func TestForceReplicationWorkflow_GenerateReplicationTaskRetryableError(t *testi
 		}, nil
 	}).Times(totalPageCount)
 
-	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(errors.New("mock generate replication tasks error"))
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, errors.New("mock generate replication tasks error"))
 
 	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
 
@@ -391,6 +615,7 @@ func TestForceReplicationWorkflow_GenerateReplicationTaskNonRetryableError(t *te
 	// Only expect GenerateReplicationTasks to execute once and workflow will then fail because of
 	// non-retryable error.
 	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(
+		nil,
 		temporal.NewNonRetryableApplicationError(errMsg, "", nil),
 	).Times(1)
 
@@ -444,7 +669,7 @@ func TestForceReplicationWorkflow_VerifyReplicationTaskNonRetryableError(t *test
 
 	var errMsg = "mock verify replication tasks error"
 	// GenerateReplicationTasks and VerifyReplicationTasks runs in paralle. GenerateReplicationTasks may not start before VerifyReplicationTasks failed.
-	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 	env.OnActivity(a.VerifyReplicationTasks, mock.Anything, mock.Anything).Return(
 		verifyReplicationTasksResponse{},
 		temporal.NewNonRetryableApplicationError(errMsg, "", nil),
@@ -470,114 +695,1446 @@ func TestForceReplicationWorkflow_VerifyReplicationTaskNonRetryableError(t *test
 	env.AssertExpectations(t)
 }
 
-func TestForceReplicationWorkflow_TaskQueueReplicationFailure(t *testing.T) {
+func TestForceReplicationWorkflow_TargetErrorBudgetExceeded(t *testing.T) {
 	testSuite := &testsuite.WorkflowTestSuite{}
 	env := testSuite.NewTestWorkflowEnvironment()
 	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
 	namespaceID := uuid.New()
 
 	var a *activities
-	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 10}, nil)
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 1}, nil)
 	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+		Executions:    []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}},
+		NextPageToken: nil, // last page
+	}, nil)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, errors.New("mock target cluster error"))
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		Query:                   "",
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   1,
+		PageCountPerExecution:   1,
+		MaxConsecutiveFailures:  2,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "target cluster unhealthy, aborting migration")
+
+	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+	require.NoError(t, err)
+	var status ForceReplicationStatus
+	require.NoError(t, envValue.Get(&status))
+	assert.Equal(t, 3, status.TargetConsecutiveFailures)
+	assert.Equal(t, 1.0, status.TargetErrorRatio)
+	assert.Contains(t, status.AbortReason, "target cluster unhealthy")
+}
+
+func TestForceReplicationWorkflow_TargetErrorBudgetNotExceeded(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
 
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 1}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
 	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
-		Executions:    []*commonpb.WorkflowExecution{},
+		Executions:    []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}},
 		NextPageToken: nil, // last page
 	}, nil)
-	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil)
-	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(
-		temporal.NewNonRetryableApplicationError("namespace is required", "InvalidArgument", nil),
-	)
+	// every attempt of the first activity fails, the workflow retries it once more within the budget
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, errors.New("mock target cluster error")).Times(targetErrorBudgetActivityMaxAttempts)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil).Once()
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
 
 	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
 		Namespace:               "test-ns",
 		Query:                   "",
-		ConcurrentActivityCount: 2,
+		ConcurrentActivityCount: 1,
 		OverallRps:              10,
 		ListWorkflowsPageSize:   1,
-		PageCountPerExecution:   maxPageCountPerExecution,
+		PageCountPerExecution:   1,
+		MaxConsecutiveFailures:  2,
 	})
 
 	require.True(t, env.IsWorkflowCompleted())
-	require.Error(t, env.GetWorkflowError())
+	require.NoError(t, env.GetWorkflowError())
 	env.AssertExpectations(t)
 
 	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
 	require.NoError(t, err)
-
 	var status ForceReplicationStatus
-	err = envValue.Get(&status)
-	require.NoError(t, err)
-	assert.True(t, status.TaskQueueUserDataReplicationStatus.Done)
-	assert.Contains(t, status.TaskQueueUserDataReplicationStatus.FailureMessage, "namespace is required")
-	assert.Equal(t, []byte(nil), status.PageTokenForRestart)
+	require.NoError(t, envValue.Get(&status))
+	assert.Equal(t, 0, status.TargetConsecutiveFailures)
+	assert.Equal(t, 0.5, status.TargetErrorRatio)
+	assert.Empty(t, status.AbortReason)
 }
 
-func TestSeedReplicationQueueWithUserDataEntries_Heartbeats(t *testing.T) {
+func TestForceReplicationWorkflow_OrderByWorkflowID(t *testing.T) {
 	testSuite := &testsuite.WorkflowTestSuite{}
-	env := testSuite.NewTestActivityEnvironment()
-
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
 	namespaceID := uuid.New()
 
-	ctrl := gomock.NewController(t)
-	mockFrontendClient := workflowservicemock.NewMockWorkflowServiceClient(ctrl)
-	mockTaskManager := persistence.NewMockTaskManager(ctrl)
-	mockNamespaceReplicationQueue := persistence.NewMockNamespaceReplicationQueue(ctrl)
-	a := &activities{
-		namespaceReplicationQueue: mockNamespaceReplicationQueue,
-		taskManager:               mockTaskManager,
-		frontendClient:            mockFrontendClient,
-		logger:                    log.NewCLILogger(),
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 2}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+
+	pages := []*listWorkflowsResponse{
+		{Executions: []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}}, NextPageToken: []byte("fake-page-token")},
+		{Executions: []*commonpb.WorkflowExecution{{WorkflowId: "wf-2"}}, NextPageToken: []byte("fake-page-token")},
+		{Executions: []*commonpb.WorkflowExecution{}, NextPageToken: nil},
+	}
+	expectedQueries := []string{
+		`(WorkflowType = "test-type") ORDER BY WorkflowId`,
+		`(WorkflowType = "test-type") AND WorkflowId > "wf-1" ORDER BY WorkflowId`,
+		`(WorkflowType = "test-type") AND WorkflowId > "wf-2" ORDER BY WorkflowId`,
 	}
+	currentPage := 0
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*listWorkflowsResponse, error) {
+		assert.Nil(t, request.NextPageToken)
+		assert.Equal(t, expectedQueries[currentPage], request.Query)
+		resp := pages[currentPage]
+		currentPage++
+		return resp, nil
+	}).Times(len(pages))
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil).Times(len(pages))
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
 
-	// Once per attempt
-	mockFrontendClient.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any()).Times(2).Return(&workflowservice.DescribeNamespaceResponse{NamespaceInfo: &namespacepb.NamespaceInfo{Id: namespaceID}}, nil)
-	// Twice for the first page due to expected failure of the first activity attempt, once for the second page
-	mockTaskManager.EXPECT().ListTaskQueueUserDataEntries(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(
-		func(ctx context.Context, request *persistence.ListTaskQueueUserDataEntriesRequest) (*persistence.ListTaskQueueUserDataEntriesResponse, error) {
-			if len(request.NextPageToken) == 0 {
-				return &persistence.ListTaskQueueUserDataEntriesResponse{
-					NextPageToken: []byte{0xac, 0xdc},
-					Entries:       []*persistence.TaskQueueUserDataEntry{{TaskQueue: "a"}, {TaskQueue: "b"}},
-				}, nil
-			}
-			return &persistence.ListTaskQueueUserDataEntriesResponse{
-				NextPageToken: []byte{},
-				Entries:       make([]*persistence.TaskQueueUserDataEntry, 0),
-			}, nil
-		},
-	)
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		Query:                   `WorkflowType = "test-type"`,
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   1,
+		PageCountPerExecution:   4,
+		OrderByWorkflowID:       true,
+	})
 
-	numCalls := 0
-	mockNamespaceReplicationQueue.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(func(ctx context.Context, task *replicationspb.ReplicationTask) error {
-		assert.Equal(t, namespaceID, task.GetTaskQueueUserDataAttributes().NamespaceId)
-		numCalls++
-		if numCalls == 1 {
-			assert.Equal(t, "a", task.GetTaskQueueUserDataAttributes().TaskQueueName)
-		} else {
-			// b is published twice
-			assert.Equal(t, "b", task.GetTaskQueueUserDataAttributes().TaskQueueName)
-		}
-		if numCalls == 2 {
-			return errors.New("some random error")
-		}
-		return nil
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
+
+func TestForceReplicationWorkflow_WorkflowTypeFilter(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+	expectedQuery := `(StartTime > "2024-01-01T00:00:00Z") AND WorkflowType IN ("order", "payment") AND WorkflowType NOT IN ("payment")`
+
+	var a *activities
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{
+		ShardCount:            4,
+		NamespaceID:           namespaceID,
+		IncludedWorkflowTypes: []string{"order", "payment"},
+		ExcludedWorkflowTypes: []string{"payment"},
+	}, nil)
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*countWorkflowResponse, error) {
+		assert.Equal(t, expectedQuery, request.Query)
+		return &countWorkflowResponse{WorkflowCount: 1}, nil
+	}).Once()
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*listWorkflowsResponse, error) {
+		assert.Equal(t, expectedQuery, request.Query)
+		return &listWorkflowsResponse{
+			Executions:    []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}},
+			NextPageToken: nil, // last page
+		}, nil
+	}).Once()
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil)
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		Query:                   `StartTime > "2024-01-01T00:00:00Z"`,
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   1,
+		PageCountPerExecution:   4,
 	})
-	iceptor := heartbeatRecordingInterceptor{T: t}
-	env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{&iceptor}})
-	env.RegisterActivity(a)
-	params := TaskQueueUserDataReplicationParamsWithNamespace{
-		TaskQueueUserDataReplicationParams: TaskQueueUserDataReplicationParams{PageSize: 10, RPS: 1},
-		Namespace:                          "foo",
-	}
-	_, err := env.ExecuteActivity(a.SeedReplicationQueueWithUserDataEntries, params)
-	assert.Error(t, err)
-	assert.Equal(t, len(iceptor.seedRecordedHeartbeats), 2)
-	assert.Equal(t, []byte(nil), iceptor.seedRecordedHeartbeats[1].NextPageToken)
-	assert.Equal(t, 1, iceptor.seedRecordedHeartbeats[1].IndexInPage)
-	env.SetHeartbeatDetails(iceptor.seedRecordedHeartbeats[1])
-	_, err = env.ExecuteActivity(a.SeedReplicationQueueWithUserDataEntries, params)
-	assert.NoError(t, err)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
+
+func TestForceReplicationWorkflow_ExcludeWorkflowTypes(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+	// the exclusions of the namespace replication filter and of the params are combined
+	expectedQuery := `(StartTime > "2024-01-01T00:00:00Z") AND WorkflowType NOT IN ("payment", "temporal-sys-scheduler", "temporal-sys-batch")`
+
+	var a *activities
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{
+		ShardCount:            4,
+		NamespaceID:           namespaceID,
+		ExcludedWorkflowTypes: []string{"payment"},
+	}, nil)
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*countWorkflowResponse, error) {
+		assert.Equal(t, expectedQuery, request.Query)
+		return &countWorkflowResponse{WorkflowCount: 1}, nil
+	}).Once()
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*listWorkflowsResponse, error) {
+		assert.Equal(t, expectedQuery, request.Query)
+		return &listWorkflowsResponse{
+			Executions:    []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}},
+			NextPageToken: nil, // last page
+		}, nil
+	}).Once()
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil)
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		Query:                   `StartTime > "2024-01-01T00:00:00Z"`,
+		ExcludeWorkflowTypes:    []string{"temporal-sys-scheduler", "temporal-sys-batch"},
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   1,
+		PageCountPerExecution:   4,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
+
+func TestWorkflowTypeFilterQuery(t *testing.T) {
+	assert.Equal(t, "", workflowTypeFilterQuery("", nil, nil))
+	assert.Equal(t, "WorkflowType = 'a'", workflowTypeFilterQuery("WorkflowType = 'a'", nil, nil))
+	assert.Equal(t, `WorkflowType IN ("a")`, workflowTypeFilterQuery("", []string{"a"}, nil))
+	assert.Equal(t, `WorkflowType NOT IN ("a", "b")`, workflowTypeFilterQuery("", nil, []string{"a", "b"}))
+	assert.Equal(t, `(WorkflowId = 'x') AND WorkflowType NOT IN ("a")`, workflowTypeFilterQuery("WorkflowId = 'x'", nil, []string{"a"}))
+}
+
+func TestForceReplicationWorkflow_OrderByWorkflowIDRejectsOrderByQuery(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:         "test-ns",
+		Query:             "order by StartTime",
+		OrderByWorkflowID: true,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.ErrorContains(t, env.GetWorkflowError(), "InvalidArgument")
+}
+
+func TestForceReplicationWorkflow_SkipCorrupted(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	healthy := &commonpb.WorkflowExecution{WorkflowId: "wf-1", RunId: "run-1"}
+	corrupted := &commonpb.WorkflowExecution{WorkflowId: "wf-2", RunId: "run-2"}
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 2}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+		Executions:    []*commonpb.WorkflowExecution{healthy, corrupted},
+		NextPageToken: nil, // last page
+	}, nil)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *generateReplicationTasksRequest) (*generateReplicationTasksResponse, error) {
+		assert.True(t, request.SkipCorrupted)
+		return &generateReplicationTasksResponse{CorruptedExecutions: []*commonpb.WorkflowExecution{corrupted}}, nil
+	}).Once()
+	env.OnActivity(a.VerifyReplicationTasks, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *verifyReplicationTasksRequest) (verifyReplicationTasksResponse, error) {
+		assert.Len(t, request.Executions, 1)
+		assert.Equal(t, healthy.GetWorkflowId(), request.Executions[0].GetWorkflowId())
+		return verifyReplicationTasksResponse{VerifiedWorkflowCount: 1}, nil
+	}).Once()
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.VerifyTaskQueueUserData, mock.Anything, mock.Anything).Return(&verifyTaskQueueUserDataResponse{}, nil)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   2,
+		PageCountPerExecution:   4,
+		EnableVerification:      true,
+		TargetClusterEndpoint:   "test-target",
+		SkipCorrupted:           true,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+	require.NoError(t, err)
+	var status ForceReplicationStatus
+	require.NoError(t, envValue.Get(&status))
+	assert.Equal(t, int64(1), status.ReplicatedWorkflowCount)
+	assert.Equal(t, int64(1), status.CorruptedWorkflowCount)
+	require.Len(t, status.CorruptedWorkflows, 1)
+	assert.Equal(t, corrupted.GetWorkflowId(), status.CorruptedWorkflows[0].GetWorkflowId())
+	assert.Equal(t, corrupted.GetRunId(), status.CorruptedWorkflows[0].GetRunId())
+}
+
+func TestForceReplicationWorkflow_GenerateTasksBatchSize(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 5}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+		Executions: []*commonpb.WorkflowExecution{
+			{WorkflowId: "wf-1"}, {WorkflowId: "wf-2"}, {WorkflowId: "wf-3"}, {WorkflowId: "wf-4"}, {WorkflowId: "wf-5"},
+		},
+	}, nil).Once()
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
+
+	// The second batch fails once, only that batch is retried and the rest of the page is not processed again.
+	var generatedBatches [][]string
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, request *generateReplicationTasksRequest) (*generateReplicationTasksResponse, error) {
+			var workflowIDs []string
+			for _, execution := range request.Executions {
+				workflowIDs = append(workflowIDs, execution.GetWorkflowId())
+			}
+			generatedBatches = append(generatedBatches, workflowIDs)
+			if len(generatedBatches) == 2 {
+				return nil, errors.New("transient error")
+			}
+			return nil, nil
+		},
+	).Times(4)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		Query:                   "",
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   5,
+		PageCountPerExecution:   4,
+		GenerateTasksBatchSize:  2,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+	assert.Equal(t, [][]string{{"wf-1", "wf-2"}, {"wf-3", "wf-4"}, {"wf-3", "wf-4"}, {"wf-5"}}, generatedBatches)
+}
+
+func TestForceReplicationWorkflow_VerifyRps(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 6}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+		Executions: []*commonpb.WorkflowExecution{
+			{WorkflowId: "wf-1"}, {WorkflowId: "wf-2"}, {WorkflowId: "wf-3"}, {WorkflowId: "wf-4"}, {WorkflowId: "wf-5"}, {WorkflowId: "wf-6"},
+		},
+	}, nil).Once()
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.VerifyTaskQueueUserData, mock.Anything, mock.Anything).Return(&verifyTaskQueueUserDataResponse{}, nil)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil).Times(3)
+	env.OnActivity(a.VerifyReplicationTasks, mock.Anything, mock.Anything).Return(verifyReplicationTasksResponse{VerifiedWorkflowCount: 2}, nil).Times(3)
+
+	var verifyStartTimes []time.Time
+	env.SetOnActivityStartedListener(func(info *activity.Info, _ context.Context, _ converter.EncodedValues) {
+		if info.ActivityType.Name == "VerifyReplicationTasks" {
+			verifyStartTimes = append(verifyStartTimes, env.Now())
+		}
+	})
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		ConcurrentActivityCount: 3,
+		OverallRps:              100,
+		VerifyRps:               1,
+		ListWorkflowsPageSize:   6,
+		PageCountPerExecution:   4,
+		GenerateTasksBatchSize:  2,
+		EnableVerification:      true,
+		TargetClusterEndpoint:   "test-target",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	// each batch of 2 workflows takes 2 seconds at 1 RPS
+	require.Len(t, verifyStartTimes, 3)
+	for i := 1; i < len(verifyStartTimes); i++ {
+		assert.GreaterOrEqual(t, verifyStartTimes[i].Sub(verifyStartTimes[i-1]), 2*time.Second)
+	}
+}
+
+func TestForceReplicationWorkflow_MaxReplicationLag(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 3}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+		Executions: []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}, {WorkflowId: "wf-2"}, {WorkflowId: "wf-3"}},
+	}, nil).Once()
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.VerifyTaskQueueUserData, mock.Anything, mock.Anything).Return(&verifyTaskQueueUserDataResponse{}, nil)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil).Times(3)
+	lags := map[string]time.Duration{"wf-1": 3 * time.Second, "wf-2": 7 * time.Second, "wf-3": 5 * time.Second}
+	env.OnActivity(a.VerifyReplicationTasks, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, request *verifyReplicationTasksRequest) (verifyReplicationTasksResponse, error) {
+			return verifyReplicationTasksResponse{
+				VerifiedWorkflowCount: 1,
+				MaxReplicationLag:     lags[request.Executions[0].GetWorkflowId()],
+			}, nil
+		},
+	).Times(3)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   3,
+		PageCountPerExecution:   4,
+		GenerateTasksBatchSize:  1,
+		EnableVerification:      true,
+		TargetClusterEndpoint:   "test-target",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+	require.NoError(t, err)
+	var status ForceReplicationStatus
+	require.NoError(t, envValue.Get(&status))
+	assert.Equal(t, 7*time.Second, status.MaxReplicationLag)
+}
+
+func TestForceReplicationWorkflow_Backpressure(t *testing.T) {
+	for _, tc := range []struct {
+		name                            string
+		replicationLag                  time.Duration
+		expectedConcurrentActivityCount int
+		expectedOverallRps              float64
+		expectedBackpressureCount       int
+	}{
+		{
+			name:                            "lag above threshold",
+			replicationLag:                  2 * time.Minute,
+			expectedConcurrentActivityCount: 2,
+			expectedOverallRps:              5,
+			expectedBackpressureCount:       1,
+		},
+		{
+			name:                            "lag below threshold",
+			replicationLag:                  30 * time.Second,
+			expectedConcurrentActivityCount: 4,
+			expectedOverallRps:              10,
+			expectedBackpressureCount:       0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			testSuite := &testsuite.WorkflowTestSuite{}
+			env := testSuite.NewTestWorkflowEnvironment()
+			env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+
+			var a *activities
+			env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 10}, nil)
+			env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: uuid.New()}, nil)
+			env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+				Executions:    []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}},
+				NextPageToken: []byte("page-2"),
+			}, nil).Once()
+			env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil).Once()
+			env.OnActivity(a.VerifyReplicationTasks, mock.Anything, mock.Anything).Return(verifyReplicationTasksResponse{
+				VerifiedWorkflowCount: 1,
+				MaxReplicationLag:     tc.replicationLag,
+			}, nil).Once()
+			env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+				Namespace:                  "test-ns",
+				ConcurrentActivityCount:    4,
+				OverallRps:                 10,
+				ListWorkflowsPageSize:      1,
+				PageCountPerExecution:      1,
+				EnableVerification:         true,
+				TargetClusterEndpoint:      "test-target",
+				BackpressureReplicationLag: time.Minute,
+			})
+
+			require.True(t, env.IsWorkflowCompleted())
+			env.AssertExpectations(t)
+
+			var continueAsNewErr *workflow.ContinueAsNewError
+			require.ErrorAs(t, env.GetWorkflowError(), &continueAsNewErr)
+			var params ForceReplicationParams
+			payloads := continueAsNewErr.Input.GetPayloads()
+			require.Len(t, payloads, 1)
+			require.NoError(t, json.Unmarshal(payloads[0].GetData(), &params))
+			assert.Equal(t, tc.expectedConcurrentActivityCount, params.ConcurrentActivityCount)
+			assert.Equal(t, tc.expectedOverallRps, params.OverallRps)
+			assert.Equal(t, tc.expectedBackpressureCount, params.BackpressureCount)
+			assert.Equal(t, tc.replicationLag, params.MaxReplicationLag)
+			assert.Zero(t, params.MaxReplicationLagInRun)
+		})
+	}
+}
+
+func TestForceReplicationParams_ApplyBackpressure(t *testing.T) {
+	params := ForceReplicationParams{ConcurrentActivityCount: 3, OverallRps: 1.5}
+	params.applyBackpressure()
+	assert.Equal(t, 1, params.ConcurrentActivityCount)
+	assert.Equal(t, minBackpressureRps, params.OverallRps)
+
+	// already below the minimum
+	params = ForceReplicationParams{ConcurrentActivityCount: 1, OverallRps: 0.5}
+	params.applyBackpressure()
+	assert.Equal(t, 1, params.ConcurrentActivityCount)
+	assert.Equal(t, 0.5, params.OverallRps)
+	assert.Equal(t, 1, params.BackpressureCount)
+}
+
+func TestForceReplicationWorkflow_RepairMode(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 4}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	pages := []*listWorkflowsResponse{
+		{Executions: []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}, {WorkflowId: "wf-2"}}, NextPageToken: []byte("fake-page-token")},
+		{Executions: []*commonpb.WorkflowExecution{{WorkflowId: "wf-3"}, {WorkflowId: "wf-4"}}},
+	}
+	currentPage := 0
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*listWorkflowsResponse, error) {
+		resp := pages[currentPage]
+		currentPage++
+		return resp, nil
+	}).Times(len(pages))
+	env.OnActivity(a.RepairReplicationTasks, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, request *repairReplicationTasksRequest) (*repairReplicationTasksResponse, error) {
+			assert.Equal(t, "test-target", request.TargetClusterName)
+			assert.Equal(t, int64(5), request.MinEventGap)
+			return &repairReplicationTasksResponse{RepairedWorkflowCount: 1, BackfilledEventCount: 7}, nil
+		},
+	).Times(len(pages))
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		Query:                   "",
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   2,
+		PageCountPerExecution:   4,
+		TargetClusterName:       "test-target",
+		RepairMode:              true,
+		RepairMinEventGap:       5,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+	require.NoError(t, err)
+	var status ForceReplicationStatus
+	require.NoError(t, envValue.Get(&status))
+	assert.Equal(t, int64(2), status.RepairedWorkflowCount)
+	assert.Equal(t, int64(14), status.BackfilledEventCount)
+}
+
+func TestForceReplicationWorkflow_ResumeFromCheckpoint(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.LoadCheckpoint, mock.Anything, &loadCheckpointRequest{Namespace: "test-ns"}).Return(&ForceReplicationCheckpoint{
+		Query:                   `WorkflowType = "test-type"`,
+		PageTokenForRestart:     []byte("checkpoint-page-token"),
+		ContinuedAsNewCount:     3,
+		TotalWorkflowCount:      10,
+		ReplicatedWorkflowCount: 6,
+	}, nil).Once()
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+
+	var listedPageTokens []string
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*listWorkflowsResponse, error) {
+		listedPageTokens = append(listedPageTokens, string(request.NextPageToken))
+		return &listWorkflowsResponse{
+			Executions:    []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}},
+			NextPageToken: []byte(fmt.Sprintf("page-token-%d", len(listedPageTokens))),
+		}, nil
+	}).Times(2)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil).Times(2)
+	// the user data replication child is started by the resumed run, and abandoned when it continues as new
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	var checkpoints []*ForceReplicationCheckpoint
+	env.OnActivity(a.SaveCheckpoint, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *saveCheckpointRequest) error {
+		assert.Equal(t, "test-ns", request.Namespace)
+		checkpoints = append(checkpoints, request.Checkpoint)
+		return nil
+	})
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:                   "test-ns",
+		Query:                       `WorkflowType = "test-type"`,
+		ConcurrentActivityCount:     1,
+		OverallRps:                  10,
+		ListWorkflowsPageSize:       1,
+		PageCountPerExecution:       2,
+		CheckpointIntervalInSeconds: 3600,
+		ResumeFromCheckpoint:        true,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	var continueAsNewErr *workflow.ContinueAsNewError
+	require.ErrorAs(t, env.GetWorkflowError(), &continueAsNewErr)
+	env.AssertExpectations(t)
+	assert.Equal(t, []string{"checkpoint-page-token", "page-token-1"}, listedPageTokens)
+
+	var params ForceReplicationParams
+	payloads := continueAsNewErr.Input.GetPayloads()
+	require.Len(t, payloads, 1)
+	require.NoError(t, json.Unmarshal(payloads[0].GetData(), &params))
+	assert.False(t, params.ResumeFromCheckpoint)
+	assert.Equal(t, 4, params.ContinuedAsNewCount)
+	assert.True(t, params.TaskQueueUserDataReplicationStarted)
+	assert.Equal(t, int64(10), params.TotalForceReplicateWorkflowCount)
+
+	// The checkpoint taken on continue-as-new points to where the next run starts.
+	require.NotEmpty(t, checkpoints)
+	lastCheckpoint := checkpoints[len(checkpoints)-1]
+	assert.Equal(t, []byte("page-token-2"), lastCheckpoint.PageTokenForRestart)
+	assert.Equal(t, `WorkflowType = "test-type"`, lastCheckpoint.Query)
+	assert.Equal(t, 4, lastCheckpoint.ContinuedAsNewCount)
+	assert.Equal(t, int64(10), lastCheckpoint.TotalWorkflowCount)
+	assert.Equal(t, int64(6), lastCheckpoint.ReplicatedWorkflowCount)
+}
+
+func TestForceReplicationWorkflow_ResumeFromCheckpointToCompletion(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userDataStatus TaskQueueUserDataReplicationStatus
+		expectSeeding  bool
+	}{
+		{
+			// the run which took the checkpoint had started the user data replication, the resumed run starts it
+			// again as it is not its continuation
+			name:          "user data not replicated",
+			expectSeeding: true,
+		},
+		{
+			name:           "user data replication failed",
+			userDataStatus: TaskQueueUserDataReplicationStatus{Done: true, FailureMessage: "failed"},
+			expectSeeding:  true,
+		},
+		{
+			name:           "user data replicated",
+			userDataStatus: TaskQueueUserDataReplicationStatus{Done: true},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testSuite := &testsuite.WorkflowTestSuite{}
+			env := testSuite.NewTestWorkflowEnvironment()
+			env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+			namespaceID := uuid.New()
+
+			var a *activities
+			env.OnActivity(a.LoadCheckpoint, mock.Anything, &loadCheckpointRequest{Namespace: "test-ns"}).Return(&ForceReplicationCheckpoint{
+				PageTokenForRestart:                []byte("checkpoint-page-token"),
+				ContinuedAsNewCount:                3,
+				TotalWorkflowCount:                 10,
+				ReplicatedWorkflowCount:            9,
+				TaskQueueUserDataReplicationStatus: tc.userDataStatus,
+			}, nil).Once()
+			env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+			env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+				Executions: []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}},
+			}, nil).Once()
+			env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil).Once()
+			if tc.expectSeeding {
+				env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+				Namespace:               "test-ns",
+				ConcurrentActivityCount: 1,
+				OverallRps:              10,
+				ListWorkflowsPageSize:   1,
+				PageCountPerExecution:   2,
+				ResumeFromCheckpoint:    true,
+			})
+
+			require.True(t, env.IsWorkflowCompleted())
+			require.NoError(t, env.GetWorkflowError())
+			env.AssertExpectations(t)
+
+			envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+			require.NoError(t, err)
+			var status ForceReplicationStatus
+			require.NoError(t, envValue.Get(&status))
+			assert.Equal(t, 3, status.ContinuedAsNewCount)
+			assert.True(t, status.TaskQueueUserDataReplicationStatus.Done)
+			assert.Empty(t, status.TaskQueueUserDataReplicationStatus.FailureMessage)
+		})
+	}
+}
+
+func TestForceReplicationWorkflow_ResumeFromCheckpointQueryMismatch(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	var a *activities
+	env.OnActivity(a.LoadCheckpoint, mock.Anything, mock.Anything).Return(&ForceReplicationCheckpoint{
+		Query:               `WorkflowType = "other-type"`,
+		PageTokenForRestart: []byte("checkpoint-page-token"),
+	}, nil).Once()
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:            "test-ns",
+		Query:                `WorkflowType = "test-type"`,
+		ResumeFromCheckpoint: true,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "checkpoint was taken with a different Query")
+	env.AssertExpectations(t)
+}
+
+func TestForceReplicationWorkflow_DryRun(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 100}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+
+	var listedPageTokens []string
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*listWorkflowsResponse, error) {
+		listedPageTokens = append(listedPageTokens, string(request.NextPageToken))
+		return &listWorkflowsResponse{
+			Executions:       []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}, {WorkflowId: "wf-2"}},
+			NextPageToken:    []byte(fmt.Sprintf("page-token-%d", len(listedPageTokens))),
+			HistorySizeBytes: 3000,
+		}, nil
+	}).Times(2)
+	var activityTypes []string
+	env.SetOnActivityStartedListener(func(info *activity.Info, _ context.Context, _ converter.EncodedValues) {
+		activityTypes = append(activityTypes, info.ActivityType.Name)
+	})
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   2,
+		DryRun:                  true,
+		DryRunSamplePageCount:   2,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+	assert.Equal(t, []string{"", "page-token-1"}, listedPageTokens)
+	// no replication tasks are generated or verified, and the task queue user data is not replicated. GetMetadata
+	// is a local activity and not reported to the listener.
+	assert.Equal(t, []string{"CountWorkflow", "ListWorkflows", "ListWorkflows"}, activityTypes)
+
+	expectedEstimate := &ForceReplicationEstimate{
+		WorkflowCount:               100,
+		SampledWorkflowCount:        4,
+		AverageHistorySizeInBytes:   1500,
+		EstimatedHistorySizeInBytes: 150000,
+		EstimatedDuration:           10 * time.Second,
+	}
+	var output ForceReplicationOutput
+	require.NoError(t, env.GetWorkflowResult(&output))
+	assert.Equal(t, expectedEstimate, output.DryRunEstimate)
+
+	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+	require.NoError(t, err)
+	var status ForceReplicationStatus
+	require.NoError(t, envValue.Get(&status))
+	assert.True(t, status.DryRun)
+	assert.Equal(t, expectedEstimate, status.DryRunEstimate)
+	assert.Equal(t, int64(100), status.TotalWorkflowCount)
+	assert.Equal(t, int64(0), status.ReplicatedWorkflowCount)
+}
+
+func TestForceReplicationWorkflow_CompletionSignal(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 1}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+		Executions:    []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}},
+		NextPageToken: nil, // last page
+	}, nil)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil)
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
+
+	var completionStatus ForceReplicationStatus
+	env.OnSignalExternalWorkflow(mock.Anything, "orchestrator", "", "force-replication-done", mock.Anything).Return(
+		func(namespace, workflowID, runID, signalName string, arg interface{}) error {
+			completionStatus = arg.(ForceReplicationStatus)
+			return nil
+		}).Once()
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   1,
+		PageCountPerExecution:   4,
+		CompletionSignalTarget: &CompletionSignalTarget{
+			WorkflowID: "orchestrator",
+			SignalName: "force-replication-done",
+		},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+	assert.Equal(t, int64(1), completionStatus.TotalWorkflowCount)
+	assert.Empty(t, completionStatus.FailureMessage)
+}
+
+func TestForceReplicationWorkflow_CompletionSignalOnFailure(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 1}, nil).Maybe()
+	env.OnActivity(a.GetMetadata, mock.Anything, mock.Anything).Return(
+		nil, temporal.NewNonRetryableApplicationError("namespace not found", "NotFound", nil))
+
+	var completionStatus ForceReplicationStatus
+	// A failed signal must not change the workflow result.
+	env.OnSignalExternalWorkflow(mock.Anything, "orchestrator", "", "force-replication-done", mock.Anything).Return(
+		func(namespace, workflowID, runID, signalName string, arg interface{}) error {
+			completionStatus = arg.(ForceReplicationStatus)
+			return errors.New("orchestrator not found")
+		}).Once()
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace: "test-ns",
+		CompletionSignalTarget: &CompletionSignalTarget{
+			WorkflowID: "orchestrator",
+			SignalName: "force-replication-done",
+		},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.ErrorContains(t, env.GetWorkflowError(), "namespace not found")
+	env.AssertExpectations(t)
+	assert.Contains(t, completionStatus.FailureMessage, "namespace not found")
+}
+
+func TestForceReplicationWorkflow_TaskQueueUserDataMissing(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 0}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+		Executions:    []*commonpb.WorkflowExecution{},
+		NextPageToken: nil, // last page
+	}, nil)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(&generateReplicationTasksResponse{}, nil)
+	env.OnActivity(a.VerifyReplicationTasks, mock.Anything, mock.Anything).Return(verifyReplicationTasksResponse{}, nil)
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.VerifyTaskQueueUserData, mock.Anything, &verifyTaskQueueUserDataRequest{
+		Namespace:         "test-ns",
+		TargetClusterName: "test-target",
+	}).Return(&verifyTaskQueueUserDataResponse{
+		MissingTaskQueues:     []string{"tq-2"},
+		MissingTaskQueueCount: 1,
+	}, nil).Times(userDataVerificationMaxAttempts)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		ConcurrentActivityCount: 2,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   1,
+		PageCountPerExecution:   maxPageCountPerExecution,
+		EnableVerification:      true,
+		TargetClusterEndpoint:   "test-target",
+		TargetClusterName:       "test-target",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+	require.NoError(t, err)
+
+	var status ForceReplicationStatus
+	err = envValue.Get(&status)
+	require.NoError(t, err)
+	assert.True(t, status.TaskQueueUserDataReplicationStatus.Done)
+	assert.True(t, status.TaskQueueUserDataReplicationStatus.Verified)
+	assert.Equal(t, []string{"tq-2"}, status.TaskQueueUserDataReplicationStatus.MissingTaskQueues)
+	assert.Equal(t, int64(1), status.TaskQueueUserDataReplicationStatus.MissingTaskQueueCount)
+}
+
+func TestForceReplicationWorkflow_TargetCluster(t *testing.T) {
+	clusterRPCAddresses := map[string]string{
+		"active":      "active-address",
+		"test-target": "test-target-address",
+	}
+	testCases := []struct {
+		name                  string
+		targetClusterName     string
+		targetClusterEndpoint string
+		// resolved TargetClusterName, or the expected error
+		expectedName  string
+		expectedError string
+	}{
+		{
+			name:                  "matching",
+			targetClusterName:     "test-target",
+			targetClusterEndpoint: "test-target-address",
+			expectedName:          "test-target",
+		},
+		{
+			name:                  "mismatching",
+			targetClusterName:     "test-target",
+			targetClusterEndpoint: "active-address",
+			expectedError:         `TargetClusterEndpoint "active-address" doesn't match the address "test-target-address" of TargetClusterName "test-target"`,
+		},
+		{
+			name:              "name only",
+			targetClusterName: "test-target",
+			expectedName:      "test-target",
+		},
+		{
+			name:              "unknown name",
+			targetClusterName: "missing",
+			expectedError:     `TargetClusterName "missing" is not a known cluster`,
+		},
+		{
+			name:                  "endpoint only",
+			targetClusterEndpoint: "test-target-address",
+			expectedName:          "test-target",
+		},
+		{
+			name:                  "unknown endpoint",
+			targetClusterEndpoint: "missing-address",
+			expectedName:          "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testSuite := &testsuite.WorkflowTestSuite{}
+			env := testSuite.NewTestWorkflowEnvironment()
+			env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+
+			var a *activities
+			env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 0}, nil)
+			env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{
+				ShardCount:          4,
+				NamespaceID:         uuid.New(),
+				ClusterRPCAddresses: clusterRPCAddresses,
+			}, nil)
+			env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+				Executions:    []*commonpb.WorkflowExecution{},
+				NextPageToken: nil, // last page
+			}, nil).Maybe()
+			env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(&generateReplicationTasksResponse{}, nil).Maybe()
+			env.OnActivity(a.VerifyReplicationTasks, mock.Anything, mock.MatchedBy(func(request *verifyReplicationTasksRequest) bool {
+				return request.TargetClusterName == tc.expectedName
+			})).Return(verifyReplicationTasksResponse{}, nil)
+			env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(nil).Maybe()
+			env.OnActivity(a.VerifyTaskQueueUserData, mock.Anything, mock.Anything).Return(&verifyTaskQueueUserDataResponse{}, nil).Maybe()
+
+			env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+				Namespace:               "test-ns",
+				ConcurrentActivityCount: 2,
+				OverallRps:              10,
+				ListWorkflowsPageSize:   1,
+				PageCountPerExecution:   maxPageCountPerExecution,
+				EnableVerification:      true,
+				TargetClusterName:       tc.targetClusterName,
+				TargetClusterEndpoint:   tc.targetClusterEndpoint,
+			})
+
+			require.True(t, env.IsWorkflowCompleted())
+			if tc.expectedError != "" {
+				var applicationErr *temporal.ApplicationError
+				require.ErrorAs(t, env.GetWorkflowError(), &applicationErr)
+				assert.Equal(t, "InvalidArgument", applicationErr.Type())
+				assert.Contains(t, applicationErr.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, env.GetWorkflowError())
+			env.AssertExpectations(t)
+		})
+	}
+}
+
+func TestForceReplicationWorkflow_TaskQueueUserDataSeedingProgress(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 0}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+		Executions:    []*commonpb.WorkflowExecution{},
+		NextPageToken: nil, // last page
+	}, nil)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(&generateReplicationTasksResponse{}, nil)
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.MatchedBy(func(params TaskQueueUserDataReplicationParamsWithNamespace) bool {
+		return params.ProgressWorkflowID == "default-test-workflow-id"
+	})).After(time.Minute).Return(nil)
+
+	var midSeedingStatus ForceReplicationStatus
+	env.RegisterDelayedCallback(func() {
+		// sent by the activity while seeding
+		env.SignalWorkflow(taskQueueUserDataReplicationProgressSignalType, seedReplicationQueueWithUserDataEntriesHeartbeatDetails{
+			NextPageToken: []byte("page-2"),
+			IndexInPage:   3,
+		})
+	}, 20*time.Second)
+	env.RegisterDelayedCallback(func() {
+		envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+		require.NoError(t, err)
+		require.NoError(t, envValue.Get(&midSeedingStatus))
+	}, 40*time.Second)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		ConcurrentActivityCount: 2,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   1,
+		PageCountPerExecution:   maxPageCountPerExecution,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	assert.False(t, midSeedingStatus.TaskQueueUserDataReplicationStatus.Done)
+	assert.Equal(t, []byte("page-2"), midSeedingStatus.TaskQueueUserDataReplicationStatus.SeedingNextPageToken)
+	assert.Equal(t, 3, midSeedingStatus.TaskQueueUserDataReplicationStatus.SeedingIndexInPage)
+}
+
+func TestForceReplicationWorkflow_Cancel(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 3}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	nextPageTokens := map[string][]byte{
+		"":       []byte("page-2"),
+		"page-2": []byte("page-3"),
+		"page-3": nil,
+	}
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*listWorkflowsResponse, error) {
+		return &listWorkflowsResponse{
+			Executions:    []*commonpb.WorkflowExecution{{WorkflowId: "wf-" + string(request.NextPageToken)}},
+			NextPageToken: nextPageTokens[string(request.NextPageToken)],
+		}, nil
+	})
+	// The first page is still in-flight when the cancel signal is sent, the other pages are not dispatched.
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).After(time.Minute).Return(&generateReplicationTasksResponse{
+		ShardProgress: map[int32]*ShardReplicationProgress{1: {GeneratedWorkflowCount: 1}},
+	}, nil).Once()
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).After(time.Hour).Return(nil).Maybe()
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(forceReplicationCancelSignalType, nil)
+	}, 30*time.Second)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   1,
+		PageCountPerExecution:   maxPageCountPerExecution,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+	require.NoError(t, err)
+
+	var status ForceReplicationStatus
+	require.NoError(t, envValue.Get(&status))
+	assert.True(t, status.Canceled)
+	assert.Equal(t, []byte("page-2"), status.PageTokenForRestart)
+	assert.Equal(t, map[int32]*ShardReplicationProgress{1: {GeneratedWorkflowCount: 1}}, status.ShardProgress)
+}
+
+func TestForceReplicationWorkflow_CancelWithinPage(t *testing.T) {
+	namespaceID := uuid.New()
+	pageExecutions := []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}, {WorkflowId: "wf-2"}, {WorkflowId: "wf-3"}, {WorkflowId: "wf-4"}}
+	newEnv := func() *testsuite.TestWorkflowEnvironment {
+		testSuite := &testsuite.WorkflowTestSuite{}
+		env := testSuite.NewTestWorkflowEnvironment()
+		env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+
+		var a *activities
+		env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 4}, nil)
+		env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+		env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*listWorkflowsResponse, error) {
+			require.Equal(t, []byte("page-1"), request.NextPageToken)
+			return &listWorkflowsResponse{Executions: pageExecutions}, nil
+		})
+		env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).After(time.Hour).Return(nil).Maybe()
+		return env
+	}
+	params := ForceReplicationParams{
+		Namespace:               "test-ns",
+		ConcurrentActivityCount: 1,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   4,
+		GenerateTasksBatchSize:  1,
+		PageCountPerExecution:   maxPageCountPerExecution,
+		NextPageToken:           []byte("page-1"),
+	}
+
+	// The first batch is still in-flight when the cancel signal is sent, the other batches of the page are not
+	// dispatched.
+	env := newEnv()
+	var a *activities
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).After(time.Minute).Return(func(ctx context.Context, request *generateReplicationTasksRequest) (*generateReplicationTasksResponse, error) {
+		require.Equal(t, pageExecutions[:1], request.Executions)
+		return &generateReplicationTasksResponse{}, nil
+	}).Once()
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(forceReplicationCancelSignalType, nil)
+	}, 30*time.Second)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, params)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+	require.NoError(t, err)
+	var status ForceReplicationStatus
+	require.NoError(t, envValue.Get(&status))
+	assert.True(t, status.Canceled)
+	assert.Equal(t, []byte("page-1"), status.PageTokenForRestart)
+	assert.Equal(t, 1, status.PageOffsetForRestart)
+
+	// A new run restarted from the status skips the replicated workflow of the page.
+	env = newEnv()
+	var replicated []string
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(func(ctx context.Context, request *generateReplicationTasksRequest) (*generateReplicationTasksResponse, error) {
+		for _, execution := range request.Executions {
+			replicated = append(replicated, execution.GetWorkflowId())
+		}
+		return &generateReplicationTasksResponse{}, nil
+	}).Times(3)
+	params.NextPageToken = status.PageTokenForRestart
+	params.NextPageOffset = status.PageOffsetForRestart
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, params)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+	assert.Equal(t, []string{"wf-2", "wf-3", "wf-4"}, replicated)
+}
+
+func TestForceReplicationWorkflow_CancelDrainTimeout(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 3}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+		Executions:    []*commonpb.WorkflowExecution{{WorkflowId: "wf-1"}},
+		NextPageToken: []byte("page-2"),
+	}, nil)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).After(time.Hour).Return(&generateReplicationTasksResponse{}, nil).Maybe()
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).After(time.Hour).Return(nil).Maybe()
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(forceReplicationCancelSignalType, nil)
+	}, 30*time.Second)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:                   "test-ns",
+		ConcurrentActivityCount:     1,
+		OverallRps:                  10,
+		ListWorkflowsPageSize:       1,
+		PageCountPerExecution:       maxPageCountPerExecution,
+		NextPageToken:               []byte("page-1"),
+		CancelDrainTimeoutInSeconds: 10,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+	require.NoError(t, err)
+
+	var status ForceReplicationStatus
+	require.NoError(t, envValue.Get(&status))
+	assert.True(t, status.Canceled)
+	// the in-flight activity didn't complete, so the run restarts from where it started
+	assert.Equal(t, []byte("page-1"), status.PageTokenForRestart)
+}
+
+func TestForceReplicationWorkflow_TaskQueueReplicationFailure(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(ForceTaskQueueUserDataReplicationWorkflow, workflow.RegisterOptions{Name: forceTaskQueueUserDataReplicationWorkflow})
+	namespaceID := uuid.New()
+
+	var a *activities
+	env.OnActivity(a.CountWorkflow, mock.Anything, mock.Anything).Return(&countWorkflowResponse{WorkflowCount: 10}, nil)
+	env.OnActivity(a.GetMetadata, mock.Anything, metadataRequest{Namespace: "test-ns"}).Return(&metadataResponse{ShardCount: 4, NamespaceID: namespaceID}, nil)
+
+	env.OnActivity(a.ListWorkflows, mock.Anything, mock.Anything).Return(&listWorkflowsResponse{
+		Executions:    []*commonpb.WorkflowExecution{},
+		NextPageToken: nil, // last page
+	}, nil)
+	env.OnActivity(a.GenerateReplicationTasks, mock.Anything, mock.Anything).Return(nil, nil)
+	env.OnActivity(a.SeedReplicationQueueWithUserDataEntries, mock.Anything, mock.Anything).Return(
+		temporal.NewNonRetryableApplicationError("namespace is required", "InvalidArgument", nil),
+	)
+
+	env.ExecuteWorkflow(ForceReplicationWorkflow, ForceReplicationParams{
+		Namespace:               "test-ns",
+		Query:                   "",
+		ConcurrentActivityCount: 2,
+		OverallRps:              10,
+		ListWorkflowsPageSize:   1,
+		PageCountPerExecution:   maxPageCountPerExecution,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	envValue, err := env.QueryWorkflow(forceReplicationStatusQueryType)
+	require.NoError(t, err)
+
+	var status ForceReplicationStatus
+	err = envValue.Get(&status)
+	require.NoError(t, err)
+	assert.True(t, status.TaskQueueUserDataReplicationStatus.Done)
+	assert.Contains(t, status.TaskQueueUserDataReplicationStatus.FailureMessage, "namespace is required")
+	assert.Equal(t, []byte(nil), status.PageTokenForRestart)
+}
+
+func TestSeedReplicationQueueWithUserDataEntries_Heartbeats(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	namespaceID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	mockFrontendClient := workflowservicemock.NewMockWorkflowServiceClient(ctrl)
+	mockTaskManager := persistence.NewMockTaskManager(ctrl)
+	mockNamespaceReplicationQueue := persistence.NewMockNamespaceReplicationQueue(ctrl)
+	a := &activities{
+		namespaceReplicationQueue: mockNamespaceReplicationQueue,
+		taskManager:               mockTaskManager,
+		frontendClient:            mockFrontendClient,
+		logger:                    log.NewCLILogger(),
+	}
+
+	// Once per attempt
+	mockFrontendClient.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any()).Times(2).Return(&workflowservice.DescribeNamespaceResponse{NamespaceInfo: &namespacepb.NamespaceInfo{Id: namespaceID}}, nil)
+	// Twice for the first page due to expected failure of the first activity attempt, once for the second page
+	mockTaskManager.EXPECT().ListTaskQueueUserDataEntries(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(
+		func(ctx context.Context, request *persistence.ListTaskQueueUserDataEntriesRequest) (*persistence.ListTaskQueueUserDataEntriesResponse, error) {
+			if len(request.NextPageToken) == 0 {
+				return &persistence.ListTaskQueueUserDataEntriesResponse{
+					NextPageToken: []byte{0xac, 0xdc},
+					Entries:       []*persistence.TaskQueueUserDataEntry{{TaskQueue: "a"}, {TaskQueue: "b"}},
+				}, nil
+			}
+			return &persistence.ListTaskQueueUserDataEntriesResponse{
+				NextPageToken: []byte{},
+				Entries:       make([]*persistence.TaskQueueUserDataEntry, 0),
+			}, nil
+		},
+	)
+
+	numCalls := 0
+	mockNamespaceReplicationQueue.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(func(ctx context.Context, task *replicationspb.ReplicationTask) error {
+		assert.Equal(t, namespaceID, task.GetTaskQueueUserDataAttributes().NamespaceId)
+		numCalls++
+		if numCalls == 1 {
+			assert.Equal(t, "a", task.GetTaskQueueUserDataAttributes().TaskQueueName)
+		} else {
+			// b is published twice
+			assert.Equal(t, "b", task.GetTaskQueueUserDataAttributes().TaskQueueName)
+		}
+		if numCalls == 2 {
+			return errors.New("some random error")
+		}
+		return nil
+	})
+	iceptor := heartbeatRecordingInterceptor{T: t}
+	env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{&iceptor}})
+	env.RegisterActivity(a)
+	params := TaskQueueUserDataReplicationParamsWithNamespace{
+		TaskQueueUserDataReplicationParams: TaskQueueUserDataReplicationParams{PageSize: 10, RPS: 1},
+		Namespace:                          "foo",
+	}
+	_, err := env.ExecuteActivity(a.SeedReplicationQueueWithUserDataEntries, params)
+	assert.Error(t, err)
+	assert.Equal(t, len(iceptor.seedRecordedHeartbeats), 2)
+	assert.Equal(t, []byte(nil), iceptor.seedRecordedHeartbeats[1].NextPageToken)
+	assert.Equal(t, 1, iceptor.seedRecordedHeartbeats[1].IndexInPage)
+	env.SetHeartbeatDetails(iceptor.seedRecordedHeartbeats[1])
+	_, err = env.ExecuteActivity(a.SeedReplicationQueueWithUserDataEntries, params)
+	assert.NoError(t, err)
+}
+
+func TestSeedReplicationQueueWithUserDataEntries_SignalsProgress(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	namespaceID := uuid.New()
+	ctrl := gomock.NewController(t)
+	mockFrontendClient := workflowservicemock.NewMockWorkflowServiceClient(ctrl)
+	mockTaskManager := persistence.NewMockTaskManager(ctrl)
+	mockNamespaceReplicationQueue := persistence.NewMockNamespaceReplicationQueue(ctrl)
+	a := &activities{
+		namespaceReplicationQueue: mockNamespaceReplicationQueue,
+		taskManager:               mockTaskManager,
+		frontendClient:            mockFrontendClient,
+		logger:                    log.NewCLILogger(),
+	}
+
+	mockFrontendClient.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any()).Return(&workflowservice.DescribeNamespaceResponse{NamespaceInfo: &namespacepb.NamespaceInfo{Id: namespaceID}}, nil)
+	mockTaskManager.EXPECT().ListTaskQueueUserDataEntries(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(ctx context.Context, request *persistence.ListTaskQueueUserDataEntriesRequest) (*persistence.ListTaskQueueUserDataEntriesResponse, error) {
+			if len(request.NextPageToken) == 0 {
+				return &persistence.ListTaskQueueUserDataEntriesResponse{
+					NextPageToken: []byte("page-2"),
+					Entries:       []*persistence.TaskQueueUserDataEntry{{TaskQueue: "a"}},
+				}, nil
+			}
+			return &persistence.ListTaskQueueUserDataEntriesResponse{
+				Entries: []*persistence.TaskQueueUserDataEntry{{TaskQueue: "b"}, {TaskQueue: "c"}},
+			}, nil
+		},
+	)
+	mockNamespaceReplicationQueue.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, task *replicationspb.ReplicationTask) error {
+		if task.GetTaskQueueUserDataAttributes().TaskQueueName == "c" {
+			return errors.New("some random error")
+		}
+		return nil
+	}).Times(3)
+
+	var progress []seedReplicationQueueWithUserDataEntriesHeartbeatDetails
+	mockFrontendClient.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, request *workflowservice.SignalWorkflowExecutionRequest, opts ...grpc.CallOption) (*workflowservice.SignalWorkflowExecutionResponse, error) {
+			assert.Equal(t, "parent-wf", request.WorkflowExecution.GetWorkflowId())
+			assert.Equal(t, taskQueueUserDataReplicationProgressSignalType, request.SignalName)
+			var details seedReplicationQueueWithUserDataEntriesHeartbeatDetails
+			require.NoError(t, payloads.Decode(request.Input, &details))
+			progress = append(progress, details)
+			return &workflowservice.SignalWorkflowExecutionResponse{}, nil
+		},
+	).Times(2)
+	env.RegisterActivity(a)
+
+	_, err := env.ExecuteActivity(a.SeedReplicationQueueWithUserDataEntries, TaskQueueUserDataReplicationParamsWithNamespace{
+		TaskQueueUserDataReplicationParams: TaskQueueUserDataReplicationParams{PageSize: 10, RPS: 100},
+		Namespace:                          "foo",
+		ProgressWorkflowID:                 "parent-wf",
+	})
+	require.Error(t, err)
+	// once after the first page, once where the failed attempt stopped
+	assert.Equal(t, []seedReplicationQueueWithUserDataEntriesHeartbeatDetails{
+		{NextPageToken: []byte("page-2"), IndexInPage: 0},
+		{NextPageToken: []byte("page-2"), IndexInPage: 1},
+	}, progress)
+}
+
+func TestSeedReplicationQueueWithUserDataEntries_RateLimited(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+
+	ctrl := gomock.NewController(t)
+	mockFrontendClient := workflowservicemock.NewMockWorkflowServiceClient(ctrl)
+	mockTaskManager := persistence.NewMockTaskManager(ctrl)
+	mockNamespaceReplicationQueue := persistence.NewMockNamespaceReplicationQueue(ctrl)
+	a := &activities{
+		namespaceReplicationQueue: mockNamespaceReplicationQueue,
+		taskManager:               mockTaskManager,
+		frontendClient:            mockFrontendClient,
+		logger:                    log.NewCLILogger(),
+	}
+
+	mockFrontendClient.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any()).Return(&workflowservice.DescribeNamespaceResponse{NamespaceInfo: &namespacepb.NamespaceInfo{Id: uuid.New()}}, nil)
+	var entries []*persistence.TaskQueueUserDataEntry
+	for i := range 8 {
+		entries = append(entries, &persistence.TaskQueueUserDataEntry{TaskQueue: fmt.Sprintf("tq-%d", i)})
+	}
+	mockTaskManager.EXPECT().ListTaskQueueUserDataEntries(gomock.Any(), gomock.Any()).Return(&persistence.ListTaskQueueUserDataEntriesResponse{Entries: entries}, nil)
+	var publishTimes []time.Time
+	mockNamespaceReplicationQueue.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(len(entries)).DoAndReturn(func(ctx context.Context, task *replicationspb.ReplicationTask) error {
+		publishTimes = append(publishTimes, time.Now())
+		return nil
+	})
+	env.RegisterActivity(a)
+
+	// the page request and the first 3 entries use up the burst of 4, the other 5 entries are 250ms apart
+	_, err := env.ExecuteActivity(a.SeedReplicationQueueWithUserDataEntries, TaskQueueUserDataReplicationParamsWithNamespace{
+		TaskQueueUserDataReplicationParams: TaskQueueUserDataReplicationParams{PageSize: 10, RPS: 4},
+		Namespace:                          "foo",
+	})
+	require.NoError(t, err)
+	require.Len(t, publishTimes, len(entries))
+	assert.GreaterOrEqual(t, publishTimes[len(publishTimes)-1].Sub(publishTimes[0]), time.Second)
 }
 
 // The SDK's test environment throttles emitted heartbeat forcing us to use an interceptor to record the heartbeat details
@@ -586,6 +2143,7 @@ type heartbeatRecordingInterceptor struct {
 	interceptor.ActivityInboundInterceptorBase
 	interceptor.ActivityOutboundInterceptorBase
 	seedRecordedHeartbeats                []seedReplicationQueueWithUserDataEntriesHeartbeatDetails
+	verifyUserDataRecordedHeartbeats      []verifyTaskQueueUserDataHeartbeatDetails
 	replicationRecordedHeartbeats         []replicationTasksHeartbeatDetails
 	generateReplicationRecordedHeartbeats []int
 	T                                     *testing.T
@@ -606,6 +2164,8 @@ func (i *heartbeatRecordingInterceptor) RecordHeartbeat(ctx context.Context, det
 		i.seedRecordedHeartbeats = append(i.seedRecordedHeartbeats, d)
 	} else if d, ok := details[0].(replicationTasksHeartbeatDetails); ok {
 		i.replicationRecordedHeartbeats = append(i.replicationRecordedHeartbeats, d)
+	} else if d, ok := details[0].(verifyTaskQueueUserDataHeartbeatDetails); ok {
+		i.verifyUserDataRecordedHeartbeats = append(i.verifyUserDataRecordedHeartbeats, d)
 	} else if d, ok := details[0].(int); ok {
 		i.generateReplicationRecordedHeartbeats = append(i.generateReplicationRecordedHeartbeats, d)
 	} else {
@@ -7,6 +7,7 @@ import (
 	sdkworker "go.temporal.io/sdk/worker"
 	"go.temporal.io/sdk/workflow"
 	serverClient "go.temporal.io/server/client"
+	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/config"
 	"go.temporal.io/server/common/dynamicconfig"
 	"go.temporal.io/server/common/headers"
@@ -30,11 +31,14 @@ type (
 		FrontendClient            workflowservice.WorkflowServiceClient
 		ClientFactory             serverClient.Factory
 		ClientBean                serverClient.Bean
+		ClusterMetadata           cluster.Metadata
 		NamespaceReplicationQueue persistence.NamespaceReplicationQueue
 		TaskManager               persistence.TaskManager
 		Logger                    log.Logger
 		MetricsHandler            metrics.Handler
 		DynamicCollection         *dynamicconfig.Collection
+		CheckpointStore           ForceReplicationCheckpointStore `optional:"true"`
+		ReplicationTransforms     ForceReplicationTransforms      `optional:"true"`
 	}
 
 	fxResult struct {
@@ -95,11 +99,14 @@ func (wc *replicationWorkerComponent) activities() *activities {
 		frontendClient:                   wc.FrontendClient,
 		clientFactory:                    wc.ClientFactory,
 		clientBean:                       wc.ClientBean,
+		clusterMetadata:                  wc.ClusterMetadata,
 		namespaceReplicationQueue:        wc.NamespaceReplicationQueue,
 		taskManager:                      wc.TaskManager,
 		logger:                           wc.Logger,
 		metricsHandler:                   wc.MetricsHandler,
 		forceReplicationMetricsHandler:   wc.MetricsHandler.WithTags(metrics.WorkflowTypeTag(forceReplicationWorkflowName)),
 		generateMigrationTaskViaFrontend: dynamicconfig.WorkerGenerateMigrationTaskViaFrontend.Get(wc.DynamicCollection),
+		checkpointStore:                  wc.CheckpointStore,
+		replicationTransforms:            wc.ReplicationTransforms,
 	}
 }
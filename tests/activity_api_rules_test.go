@@ -653,7 +653,8 @@ func (s *ActivityApiRulesClientTestSuite) createPauseRuleRequest(
 	createRuleRequest := &workflowservice.CreateWorkflowRuleRequest{
 		Namespace: s.Namespace().String(),
 		Spec: &rulespb.WorkflowRuleSpec{
-			Id: ruleID,
+			Id:              ruleID,
+			VisibilityQuery: "ExecutionStatus = 'Running'",
 			Trigger: &rulespb.WorkflowRuleSpec_ActivityStart{
 				ActivityStart: &rulespb.WorkflowRuleSpec_ActivityStartingTrigger{
 					Predicate: fmt.Sprintf("ActivityType = \"%s\"", activityType),